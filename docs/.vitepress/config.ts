@@ -54,6 +54,8 @@ export default defineConfig({
               { text: "VPK 打包", link: "/toolbox/vpk-pack" },
               { text: "崩溃转储查看器", link: "/toolbox/mdmp-report" },
               { text: "喷漆制作", link: "/toolbox/spray-tool" },
+              { text: "战役校验", link: "/toolbox/campaign-validation" },
+              { text: "缺失资源检测", link: "/toolbox/missing-assets" },
             ],
           },
           { text: "设置", link: "/features/settings" },
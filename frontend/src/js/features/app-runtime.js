@@ -22,6 +22,12 @@ import {
   isSprayImportPath,
   openSprayTool,
 } from "./spray/spray-tool.js";
+import { openSprayManager } from "./spray/spray-manager.js";
+import { openCampaignValidation } from "./diagnostics/campaign-validation.js";
+import {
+  configureMissingAssetScan,
+  openMissingAssetScan,
+} from "./diagnostics/missing-asset-scan.js";
 import {
   configureDropImport,
   handleDropImportPaths,
@@ -87,6 +93,7 @@ import {
   resetFilters,
   renderTagFilters,
   refreshFilesKeepFilter,
+  applyVPKFilesChange,
 } from "./file-list/filters.js";
 import { setupSortEvents } from "./file-list/sorting.js";
 import {
@@ -299,6 +306,11 @@ configureModelStatsScan({
   showError,
 });
 
+configureMissingAssetScan({
+  EventsOn,
+  showError,
+});
+
 configureDropImport({
   EventsOn,
   HandleFileDrop,
@@ -582,6 +594,9 @@ function setupSettingsAndAboutListeners() {
         openMDMPReportTool,
         openVPKPackTool,
         openSprayTool,
+        openSprayManager,
+        openCampaignValidation,
+        openMissingAssetScan,
         refreshFilesKeepFilter,
       });
     } else if (page === "about") {
@@ -792,19 +807,19 @@ function setupBatchActionEvents() {
     if (dropdown) dropdown.classList.add("hidden");
   };
 
-  const bindBatchDisableAction = (buttonId, primaryTag = "") => {
+  const bindBatchDisableAction = (buttonId, primaryTags = []) => {
     const button = document.getElementById(buttonId);
     if (!button) return;
 
     button.addEventListener("click", () => {
       closeBatchDisableDropdown();
-      disableAllMods(primaryTag);
+      disableAllMods(primaryTags);
     });
   };
 
   bindBatchDisableAction("disable-all-mods-btn");
-  bindBatchDisableAction("disable-all-character-mods-btn", "人物");
-  bindBatchDisableAction("disable-all-weapon-mods-btn", "武器");
+  bindBatchDisableAction("disable-all-character-mods-btn", ["人物", "感染者"]);
+  bindBatchDisableAction("disable-all-weapon-mods-btn", ["武器"]);
 
   document
     .getElementById("delete-selected-btn")
@@ -1140,6 +1155,10 @@ function setupWailsEvents() {
     refreshFilesKeepFilter();
   });
 
+  EventsOn("vpk_files_changed", (change) => {
+    applyVPKFilesChange(change);
+  });
+
   EventsOn("show_toast", (data) => {
     if (data.type === "error") {
       showError(data.message);
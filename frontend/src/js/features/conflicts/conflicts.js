@@ -194,6 +194,7 @@ function createConflictGroupElement(group) {
   const severity = group.severity || "info";
   const files = group.files || [];
   const fileCount = Number(group.file_count ?? files.length);
+  const pakfileCount = (group.pakfile_files || []).length;
   const groupEl = document.createElement("div");
   groupEl.className = `conflict-group ${severity}`;
 
@@ -236,6 +237,11 @@ function createConflictGroupElement(group) {
                     <div class="conflict-severity-row">
                         <span class="severity-badge ${severity}">${severityText}</span>
                         <span class="conflict-file-count">${fileCount} 个冲突文件</span>
+                        ${
+                          pakfileCount > 0
+                            ? `<span class="conflict-file-count is-pakfile" title="这些文件打包在地图 BSP 的 pakfile 中，只在载入该地图时覆盖其他 Mod">${pakfileCount} 个来自地图内嵌</span>`
+                            : ""
+                        }
                     </div>
                     <div class="conflict-vpk-names">
                         ${vpkListHtml}
@@ -308,7 +314,11 @@ function loadConflictDetails(details, group) {
     const truncatedNote = group.files_truncated
       ? `<div class="file-tree-note">当前仅展示前 ${(group.files || []).length} 个文件，完整冲突数为 ${fileCount} 个。</div>`
       : "";
-    inner.innerHTML = `<div class="file-tree">${truncatedNote}${renderTree(tree)}</div>`;
+    const pakfileFiles = new Set(group.pakfile_files || []);
+    const pakfileNote = pakfileFiles.size
+      ? '<div class="file-tree-note is-pakfile">标记“地图内嵌”的文件打包在地图 BSP 的 pakfile 中，只在载入该地图时生效。</div>'
+      : "";
+    inner.innerHTML = `<div class="file-tree">${truncatedNote}${pakfileNote}${renderTree(tree, pakfileFiles)}</div>`;
     details.dataset.loaded = "true";
     delete details.dataset.loading;
   });
@@ -343,7 +353,7 @@ function buildTree(paths) {
   return root;
 }
 
-function renderTree(nodes) {
+function renderTree(nodes, pakfileFiles) {
   nodes.sort((a, b) => {
     if (a.type !== b.type) return a.type === "folder" ? -1 : 1;
     return a.name.localeCompare(b.name);
@@ -359,7 +369,7 @@ function renderTree(nodes) {
               <span class="tree-node-name">${escapeHtml(node.name)}</span>
             </div>
             <div class="tree-children">
-              ${renderTree(node.children)}
+              ${renderTree(node.children, pakfileFiles)}
             </div>
           </div>
         `;
@@ -370,6 +380,7 @@ function renderTree(nodes) {
         <div class="tree-file">
           <span class="file-tag ${category.className}">${category.label}</span>
           <span class="tree-node-name">${escapeHtml(node.name)}</span>
+          ${pakfileFiles?.has(node.path) ? '<span class="file-tag tag-pakfile">地图内嵌</span>' : ""}
         </div>
       `;
     })
@@ -0,0 +1,165 @@
+import { escapeHtml, getLocationDisplayName } from "../../core/utils.js";
+
+// 与后端 parser.MissionWarning* 代码对应
+const WARNING_LABELS = {
+  parse: "无法解析",
+  no_mission: "缺少 mission",
+  missing_key: "缺少字段",
+  mode: "模式错误",
+  missing_bsp: "缺少 BSP",
+  missing_nav: "缺少 NAV",
+  duplicate_chapter: "章节重复",
+};
+
+let refs = {};
+let loading = false;
+
+// renderMissionWarnings 返回战役问题列表的 HTML，详情弹窗和战役校验共用
+export function renderMissionWarnings(warnings) {
+  return `
+    <ul class="mission-warning-list">
+      ${warnings
+        .map((warning) => {
+          const location = [warning.file, warning.chapter].filter(Boolean).join(" · ");
+          return `
+            <li class="mission-warning-item">
+              <span class="mission-warning-code">${escapeHtml(WARNING_LABELS[warning.code] || warning.code)}</span>
+              <span class="mission-warning-message">${escapeHtml(warning.message)}</span>
+              ${location ? `<span class="mission-warning-source">${escapeHtml(location)}</span>` : ""}
+            </li>
+          `;
+        })
+        .join("")}
+    </ul>
+  `;
+}
+
+export function openCampaignValidation() {
+  ensureValidationModal();
+  refs.modal.classList.remove("hidden");
+  loadValidation();
+}
+
+function ensureValidationModal() {
+  let modal = document.getElementById("campaign-validation-modal");
+  if (modal) {
+    collectRefs(modal);
+    return;
+  }
+
+  modal = el("div", "modal hidden campaign-validation-modal");
+  modal.id = "campaign-validation-modal";
+
+  const content = el("div", "modal-content campaign-validation-content");
+  const header = el("div", "modal-header campaign-validation-header");
+  const titleWrap = el("div", "campaign-validation-title-wrap");
+  const summary = el("p", "");
+  summary.id = "campaign-validation-summary";
+  titleWrap.append(el("h3", "", "战役校验"), summary);
+  const closeBtn = button("campaign-validation-close", "close-btn", "×");
+  closeBtn.setAttribute("aria-label", "关闭");
+  header.append(titleWrap, closeBtn);
+
+  const body = el("div", "modal-body campaign-validation-body");
+  body.id = "campaign-validation-body";
+
+  const footer = el("div", "modal-footer campaign-validation-footer");
+  footer.append(button("campaign-validation-refresh-btn", "btn btn-secondary", "重新检查"));
+
+  content.append(header, body, footer);
+  modal.appendChild(content);
+  document.body.appendChild(modal);
+  collectRefs(modal);
+
+  refs.closeBtn.addEventListener("click", closeCampaignValidation);
+  refs.refreshBtn.addEventListener("click", loadValidation);
+  modal.addEventListener("click", (event) => {
+    if (event.target === modal) closeCampaignValidation();
+  });
+}
+
+function closeCampaignValidation() {
+  refs.modal?.classList.add("hidden");
+}
+
+async function loadValidation() {
+  if (loading) return;
+  loading = true;
+  refs.refreshBtn.disabled = true;
+  refs.summary.textContent = "";
+  refs.body.innerHTML = '<div class="campaign-validation-empty">正在检查...</div>';
+  try {
+    renderResults((await callApp("ValidateCampaigns")) || []);
+  } catch (error) {
+    refs.body.innerHTML = `<div class="campaign-validation-empty is-error">检查失败: ${escapeHtml(formatError(error))}</div>`;
+  } finally {
+    loading = false;
+    refs.refreshBtn.disabled = false;
+  }
+}
+
+function renderResults(results) {
+  if (!results.length) {
+    refs.summary.textContent = "";
+    refs.body.innerHTML = '<div class="campaign-validation-empty">已扫描的地图模组中没有发现战役问题。</div>';
+    return;
+  }
+
+  const total = results.reduce((sum, result) => sum + result.warnings.length, 0);
+  refs.summary.textContent = `${results.length} 个地图模组共 ${total} 个问题，章节重复只统计已启用的模组。`;
+  refs.body.innerHTML = results
+    .map(
+      (result) => `
+        <section class="campaign-validation-item">
+          <div class="campaign-validation-item-header">
+            <span class="campaign-validation-name" title="${escapeHtml(result.addon.path)}">${escapeHtml(
+              result.addon.title || result.addon.name
+            )}</span>
+            <span class="campaign-validation-location">${escapeHtml(getLocationDisplayName(result.addon.location))}</span>
+          </div>
+          <div class="campaign-validation-meta">${escapeHtml(
+            [result.campaign || "未知战役", result.addon.name].join(" · ")
+          )}</div>
+          ${renderMissionWarnings(result.warnings)}
+        </section>
+      `
+    )
+    .join("");
+}
+
+function collectRefs(modal) {
+  refs = {
+    modal,
+    body: document.getElementById("campaign-validation-body"),
+    summary: document.getElementById("campaign-validation-summary"),
+    closeBtn: document.getElementById("campaign-validation-close"),
+    refreshBtn: document.getElementById("campaign-validation-refresh-btn"),
+  };
+}
+
+function button(id, className, text) {
+  const btn = el("button", className, text);
+  btn.type = "button";
+  if (id) btn.id = id;
+  return btn;
+}
+
+function el(tag, className, text) {
+  const node = document.createElement(tag);
+  if (className) node.className = className;
+  if (text != null) node.textContent = text;
+  return node;
+}
+
+function callApp(methodName, ...args) {
+  const method = window?.go?.app?.App?.[methodName];
+  if (typeof method !== "function") {
+    return Promise.reject(new Error(`当前后端不支持 ${methodName}`));
+  }
+  return method(...args);
+}
+
+function formatError(error) {
+  if (error?.message) return error.message;
+  return String(error || "未知错误");
+}
@@ -7,6 +7,9 @@ export async function renderDiagnosticsPage({
   openMDMPReportTool,
   openVPKPackTool,
   openSprayTool,
+  openSprayManager,
+  openCampaignValidation,
+  openMissingAssetScan,
   refreshFilesKeepFilter,
 } = {}) {
   const container = document.getElementById("diagnostics-page-content");
@@ -129,6 +132,9 @@ export async function renderDiagnosticsPage({
 
   appendMDMPReportTool(container, openMDMPReportTool);
   appendSprayTool(container, openSprayTool, refreshFilesKeepFilter);
+  appendSprayManagerTool(container, openSprayManager);
+  appendCampaignValidationTool(container, openCampaignValidation);
+  appendMissingAssetTool(container, openMissingAssetScan);
 
   document
     .getElementById("diagnostics-problem-scan-btn")
@@ -252,6 +258,112 @@ function appendSprayTool(container, openSprayTool, refreshFilesKeepFilter) {
   generalGrid.appendChild(card);
 }
 
+function appendSprayManagerTool(container, openSprayManager) {
+  const grids = container.querySelectorAll(".diagnostics-tool-grid");
+  const generalGrid = grids[1];
+  if (!generalGrid) return;
+
+  const card = document.createElement("section");
+  card.className = "diagnostics-tool-card";
+
+  const icon = document.createElement("div");
+  icon.className = "diagnostics-tool-icon is-spray";
+  icon.appendChild(createSprayIcon());
+
+  const main = document.createElement("div");
+  main.className = "diagnostics-tool-main";
+  const row = document.createElement("div");
+  row.className = "diagnostics-tool-title-row";
+  const title = document.createElement("h3");
+  title.textContent = "喷漆管理";
+  const status = document.createElement("span");
+  status.className = "diagnostics-status";
+  status.textContent = "可使用";
+  row.append(title, status);
+  const desc = document.createElement("p");
+  desc.textContent = "查看游戏目录和已安装 VPK 中的喷漆，切换当前喷漆或删除不需要的喷漆。";
+  main.append(row, desc);
+
+  const button = document.createElement("button");
+  button.type = "button";
+  button.className = "btn btn-primary diagnostics-tool-action";
+  button.textContent = "打开管理";
+  button.addEventListener("click", () => openSprayManager?.());
+
+  card.append(icon, main, button);
+  generalGrid.appendChild(card);
+}
+
+function appendCampaignValidationTool(container, openCampaignValidation) {
+  const diagnosticsGrid = container.querySelector(".diagnostics-tool-grid");
+  if (!diagnosticsGrid) return;
+
+  const card = document.createElement("section");
+  card.className = "diagnostics-tool-card";
+
+  const icon = document.createElement("div");
+  icon.className = "diagnostics-tool-icon is-warning";
+  icon.appendChild(createCampaignIcon());
+
+  const main = document.createElement("div");
+  main.className = "diagnostics-tool-main";
+  const row = document.createElement("div");
+  row.className = "diagnostics-tool-title-row";
+  const title = document.createElement("h3");
+  title.textContent = "战役校验";
+  const status = document.createElement("span");
+  status.className = "diagnostics-status";
+  status.textContent = "可检测";
+  row.append(title, status);
+  const desc = document.createElement("p");
+  desc.textContent = "检查地图模组的 mission 文件、章节 BSP/NAV 是否齐全，以及已启用地图之间重复的章节代码。";
+  main.append(row, desc);
+
+  const button = document.createElement("button");
+  button.type = "button";
+  button.className = "btn btn-primary diagnostics-tool-action";
+  button.textContent = "开始校验";
+  button.addEventListener("click", () => openCampaignValidation?.());
+
+  card.append(icon, main, button);
+  diagnosticsGrid.appendChild(card);
+}
+
+function appendMissingAssetTool(container, openMissingAssetScan) {
+  const diagnosticsGrid = container.querySelector(".diagnostics-tool-grid");
+  if (!diagnosticsGrid) return;
+
+  const card = document.createElement("section");
+  card.className = "diagnostics-tool-card";
+
+  const icon = document.createElement("div");
+  icon.className = "diagnostics-tool-icon is-warning";
+  icon.appendChild(createMissingAssetIcon());
+
+  const main = document.createElement("div");
+  main.className = "diagnostics-tool-main";
+  const row = document.createElement("div");
+  row.className = "diagnostics-tool-title-row";
+  const title = document.createElement("h3");
+  title.textContent = "缺失资源检测";
+  const status = document.createElement("span");
+  status.className = "diagnostics-status";
+  status.textContent = "可检测";
+  row.append(title, status);
+  const desc = document.createElement("p");
+  desc.textContent = "找出 Mod 模型引用但在 Mod、其他 Mod 和游戏本体中都不存在的材质与贴图，这类问题在游戏中会显示为紫黑格子。";
+  main.append(row, desc);
+
+  const button = document.createElement("button");
+  button.type = "button";
+  button.className = "btn btn-primary diagnostics-tool-action";
+  button.textContent = "开始检测";
+  button.addEventListener("click", () => openMissingAssetScan?.());
+
+  card.append(icon, main, button);
+  diagnosticsGrid.appendChild(card);
+}
+
 function createDumpIcon() {
   const svg = document.createElementNS("http://www.w3.org/2000/svg", "svg");
   svg.setAttribute("class", "icon-svg");
@@ -305,6 +417,53 @@ function createSprayIcon() {
   return svg;
 }
 
+function createCampaignIcon() {
+  const svg = document.createElementNS("http://www.w3.org/2000/svg", "svg");
+  svg.setAttribute("class", "icon-svg");
+  svg.setAttribute("viewBox", "0 0 24 24");
+  svg.setAttribute("fill", "none");
+  svg.setAttribute("stroke", "currentColor");
+  svg.setAttribute("stroke-width", "2.3");
+  svg.setAttribute("stroke-linecap", "round");
+  svg.setAttribute("stroke-linejoin", "round");
+  [
+    ["path", { d: "M3 6l6-3 6 3 6-3v15l-6 3-6-3-6 3z" }],
+    ["path", { d: "M9 3v15" }],
+    ["path", { d: "M15 6v15" }],
+  ].forEach(([tag, attrs]) => {
+    const node = document.createElementNS("http://www.w3.org/2000/svg", tag);
+    Object.entries(attrs).forEach(([key, value]) =>
+      node.setAttribute(key, value)
+    );
+    svg.appendChild(node);
+  });
+  return svg;
+}
+
 function packIcon() {
   return `<svg class="icon-svg" viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2.3" stroke-linecap="round" stroke-linejoin="round"><path d="M12 2v8"/><path d="m9 7 3 3 3-3"/><path d="M3 14h18"/><path d="M5 14v5a2 2 0 0 0 2 2h10a2 2 0 0 0 2-2v-5"/></svg>`;
 }
+
+function createMissingAssetIcon() {
+  const svg = document.createElementNS("http://www.w3.org/2000/svg", "svg");
+  svg.setAttribute("class", "icon-svg");
+  svg.setAttribute("viewBox", "0 0 24 24");
+  svg.setAttribute("fill", "none");
+  svg.setAttribute("stroke", "currentColor");
+  svg.setAttribute("stroke-width", "2.3");
+  svg.setAttribute("stroke-linecap", "round");
+  svg.setAttribute("stroke-linejoin", "round");
+  [
+    ["path", { d: "M3 3h8v8H3z" }],
+    ["path", { d: "M13 13h8v8h-8z" }],
+    ["path", { d: "M13 3h8v8" }],
+    ["path", { d: "M3 13v8h8" }],
+  ].forEach(([tag, attrs]) => {
+    const node = document.createElementNS("http://www.w3.org/2000/svg", tag);
+    Object.entries(attrs).forEach(([key, value]) =>
+      node.setAttribute(key, value)
+    );
+    svg.appendChild(node);
+  });
+  return svg;
+}
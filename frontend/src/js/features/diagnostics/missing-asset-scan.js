@@ -0,0 +1,313 @@
+import { showNotification } from "../../core/toast.js";
+import { escapeHtml, getLocationDisplayName } from "../../core/utils.js";
+
+let EventsOn;
+let showError;
+
+let refs = {};
+let progressOff = null;
+let completeOff = null;
+let activeScanId = "";
+let modalOpen = false;
+let awaitingScanStart = false;
+let scanEventSettled = false;
+let lastResult = null;
+
+// 与后端 parser.AssetReference.Kind 对应
+const KIND_LABELS = {
+  material: "材质",
+  texture: "贴图",
+};
+
+export function configureMissingAssetScan(deps = {}) {
+  EventsOn = deps.EventsOn;
+  showError = deps.showError;
+}
+
+export async function openMissingAssetScan() {
+  ensureScanModal();
+  modalOpen = true;
+  activeScanId = "";
+  awaitingScanStart = false;
+  scanEventSettled = false;
+  refs.modal.classList.remove("hidden");
+  registerScanEvents();
+
+  try {
+    const state = await callApp("GetMissingAssetScanState");
+    if (!modalOpen) return;
+    if (state?.running && state.scanId) {
+      activeScanId = state.scanId;
+      renderLoading(state.progress || { current: 0, total: 0, message: "正在检测缺失资源..." });
+      return;
+    }
+  } catch (error) {
+    // 读取状态失败时仍允许手动开始扫描
+  }
+
+  if (lastResult) {
+    renderResults(lastResult);
+  } else {
+    renderIntro();
+  }
+}
+
+async function startScan() {
+  if (awaitingScanStart || activeScanId) return;
+  awaitingScanStart = true;
+  scanEventSettled = false;
+  renderLoading({ current: 0, total: 0, message: "准备检测缺失资源..." });
+
+  try {
+    const started = await callApp("StartMissingAssetScan", {
+      includeBaseGame: refs.includeBaseGame.checked,
+    });
+    if (scanEventSettled) return;
+    if (!activeScanId) activeScanId = started?.scanId || "";
+    awaitingScanStart = false;
+    renderLoading(started?.progress || { current: 0, total: 0, message: "正在检测缺失资源..." });
+  } catch (error) {
+    awaitingScanStart = false;
+    renderError("缺失资源检测失败: " + formatError(error));
+    showError?.("缺失资源检测失败: " + formatError(error));
+  }
+}
+
+function closeMissingAssetScan() {
+  modalOpen = false;
+  activeScanId = "";
+  awaitingScanStart = false;
+  scanEventSettled = false;
+  clearScanEvents();
+  refs.modal?.classList.add("hidden");
+}
+
+function registerScanEvents() {
+  clearScanEvents();
+  if (!EventsOn) return;
+
+  progressOff = EventsOn("missing_asset_scan_progress", (progress) => {
+    if (!shouldAcceptScanEvent(progress?.scanId)) return;
+    if (!activeScanId) activeScanId = progress.scanId;
+    renderLoading(progress);
+  });
+
+  completeOff = EventsOn("missing_asset_scan_complete", (payload) => {
+    if (!shouldAcceptScanEvent(payload?.scanId)) return;
+    awaitingScanStart = false;
+    scanEventSettled = true;
+    activeScanId = "";
+    if (payload.error) {
+      renderError(payload.error);
+      showError?.("缺失资源检测失败: " + payload.error);
+      return;
+    }
+    lastResult = payload.result || null;
+    renderResults(lastResult);
+    showNotification("缺失资源检测完成", "success");
+  });
+}
+
+function shouldAcceptScanEvent(scanId) {
+  if (!modalOpen || !scanId) return false;
+  if (activeScanId) return scanId === activeScanId;
+  return awaitingScanStart;
+}
+
+function clearScanEvents() {
+  if (typeof progressOff === "function") progressOff();
+  if (typeof completeOff === "function") completeOff();
+  progressOff = null;
+  completeOff = null;
+}
+
+function ensureScanModal() {
+  let modal = document.getElementById("missing-asset-scan-modal");
+  if (modal) {
+    collectRefs(modal);
+    return;
+  }
+
+  modal = el("div", "modal hidden missing-asset-modal");
+  modal.id = "missing-asset-scan-modal";
+
+  const content = el("div", "modal-content missing-asset-content");
+  const header = el("div", "modal-header missing-asset-header");
+  const titleWrap = el("div", "missing-asset-title-wrap");
+  const summary = el("p", "", "检查 Mod 中模型引用的材质和材质引用的贴图是否存在");
+  summary.id = "missing-asset-summary";
+  titleWrap.append(el("h3", "", "缺失资源检测"), summary);
+  const closeBtn = button("missing-asset-close", "close-btn", "×");
+  closeBtn.setAttribute("aria-label", "关闭");
+  header.append(titleWrap, closeBtn);
+
+  const body = el("div", "modal-body missing-asset-body");
+  body.id = "missing-asset-body";
+
+  const footer = el("div", "modal-footer missing-asset-footer");
+  const option = el("label", "missing-asset-option");
+  const checkbox = document.createElement("input");
+  checkbox.type = "checkbox";
+  checkbox.id = "missing-asset-include-base";
+  checkbox.checked = true;
+  option.append(checkbox, el("span", "", "同时在游戏本体 VPK 中查找"));
+  footer.append(option, button("missing-asset-start-btn", "btn btn-primary", "开始检测"));
+
+  content.append(header, body, footer);
+  modal.appendChild(content);
+  document.body.appendChild(modal);
+  collectRefs(modal);
+
+  refs.closeBtn.addEventListener("click", closeMissingAssetScan);
+  refs.startBtn.addEventListener("click", startScan);
+  modal.addEventListener("click", (event) => {
+    if (event.target === modal) closeMissingAssetScan();
+  });
+}
+
+function renderIntro() {
+  setScanning(false);
+  refs.body.innerHTML = `
+    <div class="missing-asset-empty">
+      逐个读取已扫描的 Mod，找出在 Mod 自身、其他 Mod 和游戏本体中都不存在的材质与贴图。<br />
+      勾选“同时在游戏本体 VPK 中查找”可避免把原版资源误报为缺失。
+    </div>
+  `;
+}
+
+function renderLoading(progress = {}) {
+  setScanning(true);
+  const current = Number(progress.current || 0);
+  const total = Number(progress.total || 0);
+  const percent = total > 0 ? Math.min(100, Math.round((current / total) * 100)) : 0;
+  const description = progress.message || "正在检测缺失资源...";
+  const metaText = total > 0 ? `${current} / ${total}` : "准备中";
+
+  let shell = refs.body.querySelector(".model-stats-loading");
+  if (!shell) {
+    refs.body.replaceChildren();
+    shell = el("div", "model-stats-loading");
+    const progressWrap = el("div", "model-stats-progress");
+    const bar = el("div", "model-stats-progress-bar");
+    bar.appendChild(el("div", "model-stats-progress-fill"));
+    progressWrap.append(bar, el("div", "model-stats-progress-meta"));
+    shell.append(
+      el("div", "model-stats-spinner"),
+      el("h3", "", "正在检测缺失资源"),
+      el("p", "model-stats-loading-desc"),
+      progressWrap,
+      el("p", "missing-asset-hint", "检测中可关闭弹窗；再次打开会接入正在运行的检测任务。")
+    );
+    refs.body.appendChild(shell);
+  }
+
+  shell.querySelector(".model-stats-loading-desc").textContent = description;
+  shell.querySelector(".model-stats-progress-fill").style.width = `${percent}%`;
+  shell.querySelector(".model-stats-progress-meta").textContent = metaText;
+}
+
+function renderError(message) {
+  setScanning(false);
+  refs.body.innerHTML = `<div class="missing-asset-empty is-error">${escapeHtml(message)}</div>`;
+}
+
+function renderResults(result) {
+  setScanning(false);
+  // 结果包含所有已检查的 Mod，只列出有缺失或读取失败的
+  const items = (result?.items || []).filter((item) => item.missing?.length || item.message);
+  refs.summary.textContent = result
+    ? `共检测 ${result.totalMods} 个 Mod，${result.modsWithMissing} 个存在缺失：材质 ${result.missingMaterials} 个，贴图 ${result.missingTextures} 个${
+        result.baseGameVpks?.length ? `（已在 ${result.baseGameVpks.length} 个游戏本体 VPK 中查找）` : ""
+      }。`
+    : "";
+
+  if (!items.length) {
+    refs.body.innerHTML = '<div class="missing-asset-empty">没有发现缺失的材质或贴图。</div>';
+    return;
+  }
+
+  refs.body.innerHTML = items
+    .map(
+      (item) => `
+        <section class="missing-asset-item">
+          <div class="missing-asset-item-header">
+            <span class="missing-asset-name" title="${escapeHtml(item.path)}">${escapeHtml(item.title || item.name)}</span>
+            <span class="missing-asset-location">${escapeHtml(getLocationDisplayName(item.location))}</span>
+          </div>
+          <div class="missing-asset-meta">${escapeHtml(
+            item.message ||
+              `${item.name} · 引用 ${item.references} 个 · 缺失材质 ${item.missingMaterials} 个 · 缺失贴图 ${item.missingTextures} 个`
+          )}</div>
+          ${renderMissingList(item.missing || [])}
+        </section>
+      `
+    )
+    .join("");
+}
+
+function renderMissingList(missing) {
+  if (!missing.length) return "";
+  return `
+    <details class="missing-asset-details"${missing.length <= 5 ? " open" : ""}>
+      <summary>查看 ${missing.length} 个缺失资源</summary>
+      <ul class="missing-asset-list">
+        ${missing
+          .map((ref) => {
+            const source = [ref.source, ref.param].filter(Boolean).join(" · ");
+            return `
+              <li class="missing-asset-ref">
+                <span class="missing-asset-kind is-${escapeHtml(ref.kind)}">${escapeHtml(KIND_LABELS[ref.kind] || ref.kind)}</span>
+                <span class="missing-asset-ref-name" title="${escapeHtml((ref.candidates || []).join("\n"))}">${escapeHtml(ref.name)}</span>
+                ${source ? `<span class="missing-asset-source">${escapeHtml(source)}</span>` : ""}
+              </li>
+            `;
+          })
+          .join("")}
+      </ul>
+    </details>
+  `;
+}
+
+function setScanning(scanning) {
+  refs.startBtn.disabled = scanning;
+  refs.includeBaseGame.disabled = scanning;
+  refs.startBtn.textContent = lastResult ? "重新检测" : "开始检测";
+}
+
+function collectRefs(modal) {
+  refs = {
+    modal,
+    body: document.getElementById("missing-asset-body"),
+    summary: document.getElementById("missing-asset-summary"),
+    closeBtn: document.getElementById("missing-asset-close"),
+    startBtn: document.getElementById("missing-asset-start-btn"),
+    includeBaseGame: document.getElementById("missing-asset-include-base"),
+  };
+}
+
+function button(id, className, text) {
+  const btn = el("button", className, text);
+  btn.type = "button";
+  if (id) btn.id = id;
+  return btn;
+}
+
+function el(tag, className, text) {
+  const node = document.createElement(tag);
+  if (className) node.className = className;
+  if (text != null) node.textContent = text;
+  return node;
+}
+
+function callApp(methodName, ...args) {
+  const method = window?.go?.app?.App?.[methodName];
+  if (typeof method !== "function") {
+    return Promise.reject(new Error(`当前后端不支持 ${methodName}`));
+  }
+  return method(...args);
+}
+
+function formatError(error) {
+  if (error?.message) return error.message;
+  return String(error || "未知错误");
+}
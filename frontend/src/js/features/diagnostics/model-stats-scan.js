@@ -15,11 +15,27 @@ let scanEventSettled = false;
 let currentView = "mods";
 let currentPage = 1;
 let viewSwitchTimer = 0;
+let budgetFormOpen = false;
 
 const MODEL_STATS_VIEW_MODS = "mods";
 const MODEL_STATS_VIEW_MODELS = "models";
 const MODEL_STATS_VIEW_STRIP_GROUPS = "stripGroups";
 const MODEL_STATS_PAGE_SIZE = 10;
+const MODEL_STATS_MB = 1024 * 1024;
+
+// 与后端 ModelBudgets 字段对应，贴图显存在表单中以 MB 显示
+const MODEL_BUDGET_CATEGORIES = [
+  { key: "survivor", label: "生还者" },
+  { key: "infected", label: "感染者" },
+  { key: "viewmodel", label: "第一人称" },
+];
+const MODEL_BUDGET_FIELDS = [
+  { key: "maxVertices", metric: "vertices", label: "顶点" },
+  { key: "maxTriangles", metric: "triangles", label: "三角形" },
+  { key: "maxStripGroups", metric: "stripGroups", label: "Strip Group" },
+  { key: "maxBones", metric: "bones", label: "骨骼" },
+  { key: "maxTextureMemory", metric: "textureMemory", label: "贴图显存 (MB)", scale: MODEL_STATS_MB },
+];
 
 export function configureModelStatsScan(deps = {}) {
   EventsOn = deps.EventsOn;
@@ -36,6 +52,7 @@ export async function openModelStatsScanModal() {
   activeScanId = "";
   currentView = MODEL_STATS_VIEW_MODS;
   currentPage = 1;
+  budgetFormOpen = false;
   clearViewSwitchTimer();
   awaitingScanStart = false;
   scanEventSettled = false;
@@ -74,6 +91,7 @@ function closeModelStatsScanModal() {
   currentResult = null;
   currentView = MODEL_STATS_VIEW_MODS;
   currentPage = 1;
+  budgetFormOpen = false;
   clearViewSwitchTimer();
   clearScanEvents();
   getBody()?.replaceChildren();
@@ -211,15 +229,145 @@ function createViewToolbar() {
     createViewButton(MODEL_STATS_VIEW_STRIP_GROUPS, "Strip Group 排行"),
   );
 
+  const overBudgetMods = currentResult?.overBudgetMods || 0;
   const note = createEl(
     "span",
     "model-stats-sort-note",
-    getCurrentViewSortNote(),
+    overBudgetMods > 0
+      ? `${getCurrentViewSortNote()} · ${formatNumber(overBudgetMods)} 个 Mod 超出预算`
+      : getCurrentViewSortNote(),
   );
-  toolbar.append(toggle, note);
+
+  const actions = createEl("div", "model-stats-toolbar-actions");
+  actions.append(
+    createToolbarButton("预算设置", openBudgetForm),
+    createToolbarButton("导出 CSV", (button) => exportModelStatsReport("csv", button)),
+    createToolbarButton("导出 JSON", (button) => exportModelStatsReport("json", button)),
+  );
+  toolbar.append(toggle, note, actions);
   return toolbar;
 }
 
+function createToolbarButton(label, onClick) {
+  const button = createEl("button", "btn btn-secondary btn-small", label);
+  button.type = "button";
+  button.addEventListener("click", () => onClick(button));
+  return button;
+}
+
+async function exportModelStatsReport(format, button) {
+  if (!currentResult) return;
+  button.disabled = true;
+  try {
+    const savedPath = await callApp("ExportModelStatsReport", currentResult, format);
+    if (savedPath) {
+      showNotification(`报告已导出到 ${savedPath}`, "success");
+    }
+  } catch (error) {
+    showError?.("导出模型面数报告失败: " + error);
+  } finally {
+    button.disabled = false;
+  }
+}
+
+async function openBudgetForm() {
+  budgetFormOpen = true;
+  try {
+    const budgets = await callApp("GetModelBudgets");
+    if (budgetFormOpen) renderBudgetForm(budgets);
+  } catch (error) {
+    budgetFormOpen = false;
+    showError?.("读取模型预算失败: " + error);
+  }
+}
+
+function closeBudgetForm() {
+  budgetFormOpen = false;
+  renderResults(currentResult);
+}
+
+function renderBudgetForm(budgets = {}) {
+  const body = getBody();
+  const footer = getFooter();
+  if (!body || !footer) return;
+
+  body.replaceChildren();
+  const form = createEl("form", "model-stats-budget-form");
+  form.addEventListener("submit", (event) => event.preventDefault());
+  form.appendChild(createEl(
+    "p",
+    "model-stats-budget-hint",
+    "按模型路径归类：models/survivors 为生还者，models/infected 为感染者，models/v_models 与 v_ 开头的模型为第一人称。填 0 表示不限制，保存后重新扫描生效。",
+  ));
+
+  const grid = createEl("div", "model-stats-budget-grid");
+  grid.appendChild(createEl("span", ""));
+  MODEL_BUDGET_CATEGORIES.forEach((category) => {
+    grid.appendChild(createEl("strong", "model-stats-budget-head", category.label));
+  });
+  MODEL_BUDGET_FIELDS.forEach((field) => {
+    grid.appendChild(createEl("span", "model-stats-budget-label", field.label));
+    MODEL_BUDGET_CATEGORIES.forEach((category) => {
+      const input = createEl("input", "model-stats-budget-input");
+      input.type = "number";
+      input.min = "0";
+      input.step = "1";
+      input.name = `${category.key}.${field.key}`;
+      input.value = String(Math.round(Number(budgets?.[category.key]?.[field.key] || 0) / (field.scale || 1)));
+      input.setAttribute("aria-label", `${category.label} ${field.label}`);
+      grid.appendChild(input);
+    });
+  });
+  form.appendChild(grid);
+  body.appendChild(form);
+
+  footer.replaceChildren();
+  const resetBtn = createEl("button", "btn btn-secondary", "恢复默认");
+  resetBtn.type = "button";
+  resetBtn.addEventListener("click", async () => {
+    try {
+      renderBudgetForm(await callApp("ResetModelBudgets"));
+      showNotification("已恢复默认预算，重新扫描后生效", "success");
+    } catch (error) {
+      showError?.("恢复默认预算失败: " + error);
+    }
+  });
+  const cancelBtn = createEl("button", "btn btn-secondary", "取消");
+  cancelBtn.type = "button";
+  cancelBtn.addEventListener("click", closeBudgetForm);
+  const saveBtn = createEl("button", "btn btn-secondary", "保存");
+  saveBtn.type = "button";
+  saveBtn.addEventListener("click", async () => {
+    if (await saveBudgetForm(form)) closeBudgetForm();
+  });
+  const rescanBtn = createEl("button", "btn btn-primary", "保存并重新扫描");
+  rescanBtn.type = "button";
+  rescanBtn.addEventListener("click", async () => {
+    if (await saveBudgetForm(form)) openModelStatsScanModal();
+  });
+  footer.append(resetBtn, cancelBtn, saveBtn, rescanBtn);
+}
+
+async function saveBudgetForm(form) {
+  const budgets = {};
+  MODEL_BUDGET_CATEGORIES.forEach((category) => {
+    budgets[category.key] = {};
+    MODEL_BUDGET_FIELDS.forEach((field) => {
+      const value = Number(form.elements[`${category.key}.${field.key}`]?.value || 0);
+      budgets[category.key][field.key] = Math.max(0, Math.round((Number.isFinite(value) ? value : 0) * (field.scale || 1)));
+    });
+  });
+
+  try {
+    await callApp("SetModelBudgets", budgets);
+    showNotification("模型预算已保存，重新扫描后生效", "success");
+    return true;
+  } catch (error) {
+    showError?.("保存模型预算失败: " + error);
+    return false;
+  }
+}
+
 function createViewButton(view, label) {
   const button = createEl("button", `model-stats-view-btn${currentView === view ? " is-active" : ""}`, label);
   button.type = "button";
@@ -336,14 +484,19 @@ function createPaginationButton(label, title, disabled, onClick) {
 }
 
 function createModResultRow(item, rankNumber) {
-  const wrapper = createEl("section", `model-stats-mod${isModDisabled(item) ? " is-disabled" : ""}`);
+  const wrapper = createEl("section", `model-stats-mod${isModDisabled(item) ? " is-disabled" : ""}${item.overBudget ? " is-over-budget" : ""}`);
   const header = createEl("div", "model-stats-mod-header");
   header.setAttribute("aria-expanded", "false");
 
   const rank = createEl("span", "model-stats-rank", String(rankNumber));
   const main = createEl("div", "model-stats-mod-main");
   main.append(
-    createTextWithTitle("strong", "model-stats-mod-title", item.title || item.name || "未知 Mod"),
+    createTitleLine(
+      createTextWithTitle("strong", "model-stats-mod-title", item.title || item.name || "未知 Mod"),
+      item.overBudget
+        ? createBudgetPill(`${formatNumber(item.overBudgetModels || 0)} 个模型超预算`, "展开查看超出预算的模型")
+        : null,
+    ),
     createTextWithTitle("span", "model-stats-mod-file", item.name || item.path || ""),
   );
 
@@ -381,7 +534,8 @@ function createModResultRow(item, rankNumber) {
 }
 
 function createModelResultRow(row, rankNumber) {
-  const wrapper = createEl("section", `model-stats-mod model-stats-model-result${isModDisabled(row.mod) ? " is-disabled" : ""}`);
+  const violations = getBudgetViolations(row.mod, row.model);
+  const wrapper = createEl("section", `model-stats-mod model-stats-model-result${isModDisabled(row.mod) ? " is-disabled" : ""}${violations.length ? " is-over-budget" : ""}`);
   const content = createEl("div", "model-stats-mod-header");
   content.setAttribute("aria-expanded", "false");
   const rank = createEl("span", "model-stats-rank", String(rankNumber));
@@ -392,7 +546,10 @@ function createModelResultRow(row, rankNumber) {
   );
   const main = createEl("div", "model-stats-mod-main");
   main.append(
-    createTextWithTitle("strong", "model-stats-mod-title", row.model.path || "未知模型"),
+    createTitleLine(
+      createTextWithTitle("strong", "model-stats-mod-title", row.model.path || "未知模型"),
+      createViolationPill(violations),
+    ),
     createTextWithTitle("span", "model-stats-mod-file", row.model.message || row.model.vtxPath || row.model.vvdPath || "LOD0"),
   );
   const chevron = createEl("span", "model-stats-chevron");
@@ -574,10 +731,10 @@ function renderModelDetails(container, item) {
     return;
   }
 
-  models.forEach((model, index) => container.appendChild(createModelDetailSection(model, index)));
+  models.forEach((model, index) => container.appendChild(createModelDetailSection(model, index, item)));
 }
 
-function createModelDetailSection(model, index) {
+function createModelDetailSection(model, index, item) {
   const section = createEl("section", "model-stats-model-section");
   const row = createEl("button", "model-stats-model-row");
   row.type = "button";
@@ -597,6 +754,10 @@ function createModelDetailSection(model, index) {
   if (model.triangleStripEstimated) {
     values.appendChild(createEl("span", "model-stats-pill is-warning", "估算"));
   }
+  const violationPill = createViolationPill(getBudgetViolations(item, model));
+  if (violationPill) {
+    values.appendChild(violationPill);
+  }
   const chevron = createEl("span", "model-stats-chevron");
   row.append(main, values, chevron);
 
@@ -645,6 +806,38 @@ function createStripGroupCard(group) {
   return card;
 }
 
+function createTitleLine(title, pill) {
+  if (!pill) return title;
+  const line = createEl("div", "model-stats-title-line");
+  line.append(title, pill);
+  return line;
+}
+
+function createBudgetPill(text, title) {
+  const pill = createEl("span", "model-stats-pill is-danger", text);
+  pill.title = title;
+  return pill;
+}
+
+function createViolationPill(violations) {
+  if (!violations.length) return null;
+  return createBudgetPill("超预算", violations.map(formatBudgetViolation).join("\n"));
+}
+
+// getBudgetViolations 返回扫描时记录的该模型超预算项
+function getBudgetViolations(mod, model) {
+  const check = (mod?.budgetChecks || []).find((item) => item.path === model?.path);
+  return check?.violations || [];
+}
+
+function formatBudgetViolation(violation = {}) {
+  const field = MODEL_BUDGET_FIELDS.find((item) => item.metric === violation.metric);
+  if (field?.scale) {
+    return `${field.label}: ${formatNumber(Math.ceil(violation.value / field.scale))} / ${formatNumber(violation.limit / field.scale)}`;
+  }
+  return `${field?.label || violation.metric}: ${formatNumber(violation.value)} / ${formatNumber(violation.limit)}`;
+}
+
 function createTableMetric(label, value, suffix = "") {
   const cell = createEl("span", "model-stats-value model-stats-value-labeled");
   cell.append(createEl("small", "", label), createEl("b", "", formatNumber(value)));
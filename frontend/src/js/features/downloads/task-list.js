@@ -28,8 +28,9 @@ export async function refreshTaskList() {
         selecting_ip: 0,
         downloading: 1,
         pending: 2,
-        failed: 3,
-        completed: 4,
+        insufficient_space: 3,
+        failed: 4,
+        completed: 5,
       };
       if (statusOrder[a.status] !== statusOrder[b.status]) {
         return (statusOrder[a.status] || 99) - (statusOrder[b.status] || 99);
@@ -57,6 +58,7 @@ export function createTaskElement(task) {
     pending: "#ff9800",
     selecting_ip: "#9c27b0",
     downloading: "#2196f3",
+    insufficient_space: "#ff5722",
     completed: "#4caf50",
     failed: "#f44336",
   };
@@ -65,6 +67,7 @@ export function createTaskElement(task) {
     pending: "等待中",
     selecting_ip: "优选线路中...",
     downloading: "下载中",
+    insufficient_space: "磁盘空间不足",
     completed: "已完成",
     failed: "失败",
     cancelled: "已取消",
@@ -88,7 +91,23 @@ export function createTaskElement(task) {
           <line x1="6" y1="6" x2="18" y2="18"></line>
         </svg>
       </button>`;
-  } else if (task.status === "failed" || task.status === "cancelled") {
+  } else if (task.status === "insufficient_space") {
+    actionButtons = `
+      ${copyBtn}
+      <button class="task-action-btn retry-btn retry-task-btn" data-id="${task.id}" title="重新检查磁盘空间">
+        <svg viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round">
+          <path d="M23 4v6h-6"></path>
+          <path d="M1 20v-6h6"></path>
+          <path d="M3.51 9a9 9 0 0 1 14.85-3.36L23 10M1 14l4.64 4.36A9 9 0 0 0 20.49 15"></path>
+        </svg>
+      </button>
+      <button class="task-action-btn cancel-btn cancel-task-btn" data-id="${task.id}" title="取消下载">
+        <svg viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round">
+          <line x1="18" y1="6" x2="6" y2="18"></line>
+          <line x1="6" y1="6" x2="18" y2="18"></line>
+        </svg>
+      </button>`;
+ else if (task.status === "failed" || task.status === "cancelled") {
     actionButtons = `
       ${copyBtn}
       <button class="task-action-btn retry-btn retry-task-btn" data-id="${task.id}" title="重试下载">
@@ -189,7 +208,11 @@ export function createTaskElement(task) {
       e.stopPropagation();
       try {
         await RetryDownloadTask(task.id);
-        showNotification("任务已重试", "success");
+        if (task.status === "insufficient_space") {
+          showNotification("正在重新检查磁盘空间", "info");
+        } else {
+          showNotification("任务已重试", "success");
+        }
       } catch (err) {
         console.error("重试任务失败:", err);
         showError("重试失败: " + err);
@@ -130,12 +130,13 @@ export async function disableSelected() {
   }
 }
 
-export async function disableAllMods(primaryTag = "") {
-  const scopeLabel = primaryTag ? `${primaryTag}mod` : "mod";
+export async function disableAllMods(primaryTags = []) {
+  const tags = [].concat(primaryTags).filter(Boolean);
+  const scopeLabel = tags.length > 0 ? `${tags.join("和")}mod` : "mod";
   const filesToToggle = appState.allVpkFiles
     .filter((file) => {
       if (!file || !file.enabled || file.location !== "root") return false;
-      return primaryTag ? file.primaryTag === primaryTag : true;
+      return tags.length > 0 ? tags.includes(file.primaryTag) : true;
     })
     .map((file) => file.path);
 
@@ -616,12 +616,31 @@ export async function resetFilters() {
   }
 }
 
+// 输入停顿后再搜索，避免输入到一半的表达式（如 size:>）被反复解析
+const SEARCH_INPUT_DEBOUNCE_MS = 250;
+let searchInputTimer = null;
+let searchSequence = 0;
+
 export function handleSearch(event) {
   appState.searchQuery = event.target.value;
-  performSearch();
+  clearTimeout(searchInputTimer);
+  searchInputTimer = setTimeout(performSearch, SEARCH_INPUT_DEBOUNCE_MS);
+}
+
+// setSearchQueryError 在搜索框下方显示表达式错误，不弹出提示
+function setSearchQueryError(message) {
+  const input = document.getElementById("search-input");
+  const hint = document.getElementById("search-error");
+  input?.classList.toggle("invalid", Boolean(message));
+  if (hint) {
+    hint.textContent = message || "";
+    hint.classList.toggle("hidden", !message);
+  }
 }
 
 export async function performSearch() {
+  clearTimeout(searchInputTimer);
+  const sequence = ++searchSequence;
   try {
     console.log(
       "执行搜索，查询词:", appState.searchQuery,
@@ -637,13 +656,27 @@ export async function performSearch() {
     ) {
       appState.vpkFiles = [...appState.allVpkFiles];
     } else {
-      const results = await SearchVPKFiles(
-        appState.searchQuery,
-        appState.selectedPrimaryTag,
-        appState.selectedSecondaryTags
-      );
+      let results;
+      try {
+        results = await SearchVPKFiles(
+          appState.searchQuery,
+          appState.selectedPrimaryTag,
+          appState.selectedSecondaryTags
+        );
+      } catch (error) {
+        // 后端只会因搜索表达式无效而失败，保留当前列表
+        if (sequence === searchSequence) {
+          setSearchQueryError(String(error));
+        }
+        return;
+      }
+      // 较早发出的搜索晚返回时丢弃其结果
+      if (sequence !== searchSequence) {
+        return;
+      }
       appState.vpkFiles = results;
     }
+    setSearchQueryError("");
 
     if (appState.selectedLocations.length > 0) {
       appState.vpkFiles = appState.vpkFiles.filter((file) =>
@@ -688,6 +721,35 @@ export function closeFilterMenus(exceptMenu = null) {
   });
 }
 
+// applyVPKFilesChange 应用目录监听推送的增量变化（vpk_files_changed），不重新扫描
+export async function applyVPKFilesChange(change) {
+  // 正在扫描时，扫描结果已包含这些变化
+  if (!appState.currentDirectory || appState.isLoading) return;
+
+  const removed = new Set(change?.removed || []);
+  const changed = new Map(
+    [...(change?.added || []), ...(change?.updated || [])].map((file) => [file.path, file])
+  );
+  if (removed.size === 0 && changed.size === 0) return;
+
+  const files = appState.allVpkFiles.filter(
+    (file) => !removed.has(file.path) && !changed.has(file.path)
+  );
+  files.push(...changed.values());
+  applySort(files);
+  appState.allVpkFiles = files;
+  removed.forEach((path) => appState.selectedFiles.delete(path));
+
+  try {
+    // 新增或重新解析的文件可能带来新的标签
+    appState.primaryTags = await GetPrimaryTags();
+    await renderTagFilters();
+  } catch (error) {
+    console.error("刷新标签失败:", error);
+  }
+  await performSearch();
+}
+
 export async function refreshFilesKeepFilter() {
   resetBoxSelection();
 
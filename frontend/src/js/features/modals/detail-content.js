@@ -0,0 +1,294 @@
+import { formatFileSize, escapeHtml } from "../../core/utils.js";
+import { showError, showNotification } from "../../core/toast.js";
+import { ExportModelGLB, ListVPKAudioEntries, ListVPKModels, ListVPKTextures } from "../../../../wailsjs/go/app/App";
+import { createModelViewer } from "./model-viewer.js";
+
+// 详情弹窗中的 VPK 内容浏览：按类型分页签，切换到页签时才读取 VPK
+const CONTENT_TABS = {
+  audio: { load: ListVPKAudioEntries, render: renderAudioTab },
+  textures: { load: ListVPKTextures, render: renderTextureTab },
+  models: { load: ListVPKModels, render: renderModelTab },
+};
+
+let contentFilePath = "";
+let contentToken = 0;
+let tabsBound = false;
+let disposeActiveTab = null;
+
+export function resetDetailContent(file) {
+  bindTabs();
+  releaseActiveTab();
+  contentFilePath = file?.path || "";
+  contentToken += 1;
+
+  document.querySelectorAll("#content-browser-section .content-browser-tab").forEach((tab) => {
+    tab.classList.remove("is-active");
+    tab.setAttribute("aria-selected", "false");
+  });
+  setBody('<div class="content-browser-empty">选择要浏览的内容类型</div>');
+}
+
+export function clearDetailContent() {
+  releaseActiveTab();
+  contentFilePath = "";
+  contentToken += 1;
+  setBody("");
+}
+
+function bindTabs() {
+  if (tabsBound) return;
+  tabsBound = true;
+  document.querySelectorAll("#content-browser-section .content-browser-tab").forEach((tab) => {
+    tab.addEventListener("click", () => openTab(tab.dataset.contentTab));
+  });
+}
+
+async function openTab(name) {
+  const tab = CONTENT_TABS[name];
+  if (!tab || !contentFilePath) return;
+
+  releaseActiveTab();
+  const token = ++contentToken;
+  document.querySelectorAll("#content-browser-section .content-browser-tab").forEach((button) => {
+    const active = button.dataset.contentTab === name;
+    button.classList.toggle("is-active", active);
+    button.setAttribute("aria-selected", String(active));
+  });
+  setBody('<div class="content-browser-empty">正在读取...</div>');
+
+  try {
+    const items = (await tab.load(contentFilePath)) || [];
+    if (token !== contentToken) return;
+    const body = getBody();
+    if (body) disposeActiveTab = tab.render(body, items, contentFilePath) || null;
+  } catch (error) {
+    if (token !== contentToken) return;
+    console.error("读取 VPK 内容失败:", error);
+    setBody(`<div class="content-browser-empty is-error">读取失败: ${escapeHtml(String(error))}</div>`);
+  }
+}
+
+function releaseActiveTab() {
+  if (typeof disposeActiveTab === "function") disposeActiveTab();
+  disposeActiveTab = null;
+}
+
+// renderFilterableList 渲染带筛选框的列表，条目较多时按路径筛选
+function renderFilterableList(body, items, { emptyText, listClass, renderItem, onSelect }) {
+  if (items.length === 0) {
+    body.innerHTML = `<div class="content-browser-empty">${emptyText}</div>`;
+    return;
+  }
+
+  body.innerHTML = `
+    <div class="content-browser-toolbar">
+      <input type="text" class="content-browser-filter" placeholder="筛选路径...">
+      <span class="content-browser-count"></span>
+    </div>
+    <div class="${listClass}"></div>
+  `;
+  const list = body.querySelector(`.${listClass}`);
+  const count = body.querySelector(".content-browser-count");
+
+  const indexed = items.map((item, index) => ({ item, index, key: item.path.toLowerCase() }));
+  const render = (keyword) => {
+    const needle = keyword.trim().toLowerCase();
+    const visible = needle ? indexed.filter((entry) => entry.key.includes(needle)) : indexed;
+    list.innerHTML = visible.map((entry) => renderItem(entry.item, entry.index)).join("");
+    count.textContent = needle ? `${visible.length} / ${items.length}` : `共 ${items.length} 个`;
+  };
+
+  body.querySelector(".content-browser-filter").addEventListener("input", (event) => render(event.target.value));
+  list.addEventListener("click", (event) => {
+    const row = event.target.closest("[data-index]");
+    if (row) onSelect(items[Number(row.dataset.index)], row);
+  });
+  render("");
+}
+
+function renderAudioTab(body, entries) {
+  const player = document.createElement("audio");
+  player.className = "content-audio-player";
+  player.controls = true;
+  player.preload = "none";
+
+  renderFilterableList(body, entries, {
+    emptyText: "此 VPK 中没有可试听的音频",
+    listClass: "content-audio-list",
+    renderItem: (entry, index) => `
+      <button type="button" class="content-audio-item" data-index="${index}" ${entry.url ? "" : "disabled"}>
+        <span class="content-item-path" title="${escapeHtml(entry.path)}">${escapeHtml(entry.path)}</span>
+        <span class="content-item-meta">${formatFileSize(entry.size)}</span>
+      </button>
+    `,
+    onSelect: (entry, row) => {
+      if (!entry.url) return;
+      body.querySelectorAll(".content-audio-item.is-playing").forEach((item) => item.classList.remove("is-playing"));
+      row.classList.add("is-playing");
+      player.src = entry.url;
+      player.play().catch((error) => console.error("播放音频失败:", error));
+    },
+  });
+
+  if (entries.length > 0) {
+    body.prepend(player);
+    if (!entries.some((entry) => entry.url)) {
+      body.insertAdjacentHTML("afterbegin", '<div class="content-browser-empty is-error">预览服务未启动，暂时无法试听</div>');
+    }
+  }
+
+  return () => {
+    player.pause();
+    player.removeAttribute("src");
+    player.load();
+  };
+}
+
+function renderTextureTab(body, textures) {
+  renderFilterableList(body, textures, {
+    emptyText: "此 VPK 中没有 VTF 贴图",
+    listClass: "content-texture-grid",
+    renderItem: (texture, index) => `
+      <button type="button" class="content-texture-item" data-index="${index}" title="${escapeHtml(texture.path)}">
+        <span class="content-texture-thumb">${
+          texture.previewUrl
+            ? `<img src="${texture.previewUrl}" loading="lazy" alt="">`
+            : '<span class="content-texture-placeholder">无法预览</span>'
+        }</span>
+        <span class="content-item-path">${escapeHtml(texture.path.split("/").pop())}</span>
+        <span class="content-item-meta">${texture.width}×${texture.height} ${escapeHtml(texture.format || "")}</span>
+      </button>
+    `,
+    onSelect: (texture, item) => {
+      body.querySelectorAll(".content-texture-item.is-selected").forEach((el) => el.classList.remove("is-selected"));
+      item.classList.add("is-selected");
+      showTextureDetail(body, texture);
+    },
+  });
+}
+
+// showTextureDetail 在列表上方显示选中贴图的原图和 VTF 信息
+function showTextureDetail(body, texture) {
+  let detail = body.querySelector(".content-texture-detail");
+  if (!detail) {
+    detail = document.createElement("div");
+    detail.className = "content-texture-detail";
+    body.prepend(detail);
+  }
+
+  // 列表中的地址是缩略图，详情中改为请求原尺寸
+  const fullUrl = texture.previewUrl ? texture.previewUrl.replace("size=thumb", "size=full") : "";
+  const facts = [
+    ["尺寸", `${texture.width}×${texture.height}${texture.depth > 1 ? `×${texture.depth}` : ""}`],
+    ["格式", texture.format || "未知"],
+    ["版本", texture.version || "未知"],
+    ["Mipmap", texture.mipCount],
+    ["帧数", texture.frames],
+    ["面数", texture.faces],
+    ["图像数据", formatFileSize(texture.memorySize)],
+    ["文件大小", formatFileSize(texture.fileSize)],
+  ];
+  detail.innerHTML = `
+    <div class="content-texture-preview">${
+      fullUrl ? `<img src="${fullUrl}" alt="">` : '<span class="content-texture-placeholder">该格式暂不支持预览</span>'
+    }</div>
+    <div class="content-texture-facts">
+      <div class="content-item-path" title="${escapeHtml(texture.path)}">${escapeHtml(texture.path)}</div>
+      ${facts.map(([label, value]) => `<div><label>${label}</label><span>${escapeHtml(String(value))}</span></div>`).join("")}
+    </div>
+  `;
+}
+
+function renderModelTab(body, models, vpkPath) {
+  let viewer = null;
+  const releaseViewer = () => {
+    viewer?.dispose();
+    viewer = null;
+  };
+
+  renderFilterableList(body, models, {
+    emptyText: "此 VPK 中没有 MDL 模型",
+    listClass: "content-model-list",
+    renderItem: (model, index) => `
+      <button type="button" class="content-model-item" data-index="${index}">
+        <span class="content-item-path" title="${escapeHtml(model.path)}">${escapeHtml(model.path)}</span>
+      </button>
+    `,
+    onSelect: (model, row) => {
+      body.querySelectorAll(".content-model-item.is-selected").forEach((item) => item.classList.remove("is-selected"));
+      row.classList.add("is-selected");
+      releaseViewer();
+      viewer = showModelDetail(body, model, vpkPath);
+    },
+  });
+
+  return releaseViewer;
+}
+
+// showModelDetail 在列表上方显示选中模型的 3D 预览，返回的查看器由调用方释放
+function showModelDetail(body, model, vpkPath) {
+  let detail = body.querySelector(".content-model-detail");
+  if (!detail) {
+    detail = document.createElement("div");
+    detail.className = "content-model-detail";
+    body.prepend(detail);
+  }
+
+  detail.innerHTML = `
+    <div class="content-model-header">
+      <span class="content-item-path" title="${escapeHtml(model.path)}">${escapeHtml(model.path)}</span>
+      <button type="button" class="btn btn-secondary btn-small content-model-export">导出 GLB</button>
+    </div>
+    <div class="content-model-stage">
+      <canvas class="content-model-canvas"></canvas>
+      <div class="content-model-status">正在转换模型...</div>
+    </div>
+    <div class="content-model-hint">拖动旋转，滚轮缩放</div>
+  `;
+  const status = detail.querySelector(".content-model-status");
+  const exportBtn = detail.querySelector(".content-model-export");
+  exportBtn.addEventListener("click", async () => {
+    exportBtn.disabled = true;
+    try {
+      const saved = await ExportModelGLB(vpkPath, model.path);
+      if (saved) showNotification(`已导出到 ${saved}`, "success");
+    } catch (error) {
+      showError("导出模型失败: " + error);
+    } finally {
+      exportBtn.disabled = false;
+    }
+  });
+
+  if (!model.previewUrl) {
+    status.textContent = "预览服务未启动，暂时无法预览，仍可导出";
+    status.classList.add("is-error");
+    return null;
+  }
+
+  let viewer;
+  try {
+    viewer = createModelViewer(detail.querySelector(".content-model-canvas"));
+  } catch (error) {
+    status.textContent = error.message;
+    status.classList.add("is-error");
+    return null;
+  }
+  viewer
+    .load(model.previewUrl)
+    .then(() => status.remove())
+    .catch((error) => {
+      console.error("预览模型失败:", error);
+      status.textContent = "预览失败: " + (error?.message || error);
+      status.classList.add("is-error");
+    });
+  return viewer;
+}
+
+function getBody() {
+  return document.getElementById("content-browser-body");
+}
+
+function setBody(html) {
+  const body = getBody();
+  if (body) body.innerHTML = html;
+}
@@ -3,6 +3,8 @@ import { formatFileSize, getLocationDisplayName } from "../../core/utils.js";
 import { showError } from "../../core/toast.js";
 import { GetVPKPreviewImage, ParseWorkshopID } from "../../../../wailsjs/go/app/App";
 import { handleProtocolWorkshop } from "../workshop/workshop-browser.js";
+import { resetDetailContent, clearDetailContent } from "./detail-content.js";
+import { renderMissionWarnings } from "../diagnostics/campaign-validation.js";
 
 let currentDetailFile = null;
 
@@ -40,24 +42,20 @@ export function showFileDetail(filePath) {
   previewSection.classList.remove("hidden");
   previewImage.style.display = "none";
 
-  if (file.previewImage) {
-    previewImage.src = file.previewImage;
-    previewImage.style.display = "block";
-  } else {
-    GetVPKPreviewImage(file.path)
-      .then((imgData) => {
-        if (imgData) {
-          previewImage.src = imgData;
-          previewImage.style.display = "block";
-        } else {
-          previewSection.classList.add("hidden");
-        }
-      })
-      .catch((err) => {
-        console.error("加载预览图失败:", err);
+  // 列表中的 previewImage 是缩略图，详情中加载原图
+  GetVPKPreviewImage(file.path)
+    .then((imgData) => {
+      if (imgData) {
+        previewImage.src = imgData;
+        previewImage.style.display = "block";
+      } else {
         previewSection.classList.add("hidden");
-      });
-  }
+      }
+    })
+    .catch((err) => {
+      console.error("加载预览图失败:", err);
+      previewSection.classList.add("hidden");
+    });
 
   const tagsContainer = document.getElementById("detail-tags");
   const primaryTagHtml = file.primaryTag
@@ -130,7 +128,8 @@ export function showFileDetail(filePath) {
   })();
 
   const mapInfoSection = document.getElementById("map-info-section");
-  if (file.primaryTag === "地图") {
+  const missionWarnings = file.missionWarnings || [];
+  if (file.primaryTag === "地图" || missionWarnings.length > 0) {
     mapInfoSection.classList.remove("hidden");
 
     const campaignElement = document.getElementById("detail-campaign");
@@ -158,10 +157,18 @@ export function showFileDetail(filePath) {
     } else {
       chaptersListElement.innerHTML = '<div class="no-chapters">无章节信息</div>';
     }
+
+    const warningsElement = document.getElementById("detail-mission-warnings");
+    warningsElement.classList.toggle("hidden", missionWarnings.length === 0);
+    warningsElement.innerHTML = missionWarnings.length
+      ? `<div class="mission-warnings-title">战役问题（${missionWarnings.length}）</div>${renderMissionWarnings(missionWarnings)}`
+      : "";
   } else {
     mapInfoSection.classList.add("hidden");
   }
 
+  resetDetailContent(file);
+
   modal.classList.remove("hidden");
 
   setTimeout(() => {
@@ -176,5 +183,6 @@ export function showFileDetail(filePath) {
 
 export function closeModal() {
   document.getElementById("file-detail-modal").classList.add("hidden");
+  clearDetailContent();
   currentDetailFile = null;
 }
@@ -0,0 +1,387 @@
+// 轻量的 glTF 二进制（.glb）查看器，只覆盖后端 ConvertVPKModelToGLB 的输出：
+// 单个网格，POSITION/NORMAL/TEXCOORD_0 与 32 位索引，材质只有基础色贴图或颜色。
+// 骨骼和节点变换不参与渲染，直接按 Source 的 Z 轴向上显示绑定姿势
+
+const GLB_MAGIC = 0x46546c67;
+const GLB_CHUNK_JSON = 0x4e4f534a;
+const GLB_CHUNK_BIN = 0x004e4942;
+
+const COMPONENT_ARRAYS = {
+  5121: Uint8Array,
+  5123: Uint16Array,
+  5125: Uint32Array,
+  5126: Float32Array,
+};
+const TYPE_SIZES = { SCALAR: 1, VEC2: 2, VEC3: 3, VEC4: 4 };
+
+const VERTEX_SHADER = `#version 300 es
+in vec3 aPosition;
+in vec3 aNormal;
+in vec2 aUV;
+uniform mat4 uViewProjection;
+out vec3 vNormal;
+out vec2 vUV;
+void main() {
+  vNormal = aNormal;
+  vUV = aUV;
+  gl_Position = uViewProjection * vec4(aPosition, 1.0);
+}`;
+
+const FRAGMENT_SHADER = `#version 300 es
+precision mediump float;
+in vec3 vNormal;
+in vec2 vUV;
+uniform sampler2D uTexture;
+uniform bool uHasTexture;
+uniform vec4 uColor;
+uniform float uAlphaCutoff;
+uniform vec3 uLightDir;
+out vec4 outColor;
+void main() {
+  vec4 base = uHasTexture ? texture(uTexture, vUV) * uColor : uColor;
+  if (base.a < uAlphaCutoff) discard;
+  vec3 normal = normalize(gl_FrontFacing ? vNormal : -vNormal);
+  float diffuse = abs(dot(normal, uLightDir));
+  outColor = vec4(base.rgb * (0.35 + 0.65 * diffuse), base.a);
+}`;
+
+export function parseGLB(buffer) {
+  const view = new DataView(buffer);
+  if (buffer.byteLength < 12 || view.getUint32(0, true) !== GLB_MAGIC) {
+    throw new Error("不是 glTF 二进制文件");
+  }
+
+  let json = null;
+  let bin = null;
+  let offset = 12;
+  while (offset + 8 <= buffer.byteLength) {
+    const length = view.getUint32(offset, true);
+    const type = view.getUint32(offset + 4, true);
+    const start = offset + 8;
+    if (type === GLB_CHUNK_JSON) {
+      json = JSON.parse(new TextDecoder().decode(new Uint8Array(buffer, start, length)));
+    } else if (type === GLB_CHUNK_BIN) {
+      bin = buffer.slice(start, start + length);
+    }
+    offset = start + length;
+  }
+  if (!json || !bin) {
+    throw new Error("glTF 文件缺少 JSON 或二进制数据");
+  }
+  return { json, bin };
+}
+
+function readAccessor(json, bin, index) {
+  const accessor = json.accessors[index];
+  const bufferView = json.bufferViews[accessor.bufferView];
+  const ArrayType = COMPONENT_ARRAYS[accessor.componentType];
+  if (!ArrayType) throw new Error(`不支持的分量类型: ${accessor.componentType}`);
+  const start = (bufferView.byteOffset || 0) + (accessor.byteOffset || 0);
+  return new ArrayType(bin, start, accessor.count * TYPE_SIZES[accessor.type]);
+}
+
+export function createModelViewer(canvas) {
+  const gl = canvas.getContext("webgl2", { antialias: true, premultipliedAlpha: false });
+  if (!gl) {
+    throw new Error("当前环境不支持 WebGL2，无法预览模型");
+  }
+
+  const program = createProgram(gl);
+  const locations = {
+    position: gl.getAttribLocation(program, "aPosition"),
+    normal: gl.getAttribLocation(program, "aNormal"),
+    uv: gl.getAttribLocation(program, "aUV"),
+    viewProjection: gl.getUniformLocation(program, "uViewProjection"),
+    texture: gl.getUniformLocation(program, "uTexture"),
+    hasTexture: gl.getUniformLocation(program, "uHasTexture"),
+    color: gl.getUniformLocation(program, "uColor"),
+    alphaCutoff: gl.getUniformLocation(program, "uAlphaCutoff"),
+    lightDir: gl.getUniformLocation(program, "uLightDir"),
+  };
+
+  const resources = { buffers: [], textures: [], vaos: [] };
+  let primitives = [];
+  let center = [0, 0, 0];
+  let radius = 1;
+  const camera = { yaw: -Math.PI / 2, pitch: 0.25, distance: 3 };
+  let frame = 0;
+  let disposed = false;
+
+  const requestRender = () => {
+    if (frame || disposed) return;
+    frame = requestAnimationFrame(() => {
+      frame = 0;
+      render();
+    });
+  };
+
+  let dragging = null;
+  const onPointerDown = (event) => {
+    dragging = { x: event.clientX, y: event.clientY };
+    canvas.setPointerCapture(event.pointerId);
+  };
+  const onPointerMove = (event) => {
+    if (!dragging) return;
+    camera.yaw -= (event.clientX - dragging.x) * 0.01;
+    camera.pitch = Math.max(-1.5, Math.min(1.5, camera.pitch + (event.clientY - dragging.y) * 0.01));
+    dragging = { x: event.clientX, y: event.clientY };
+    requestRender();
+  };
+  const onPointerUp = () => {
+    dragging = null;
+  };
+  const onWheel = (event) => {
+    event.preventDefault();
+    camera.distance = Math.max(0.2, Math.min(20, camera.distance * Math.exp(event.deltaY * 0.001)));
+    requestRender();
+  };
+  canvas.addEventListener("pointerdown", onPointerDown);
+  canvas.addEventListener("pointermove", onPointerMove);
+  canvas.addEventListener("pointerup", onPointerUp);
+  canvas.addEventListener("pointercancel", onPointerUp);
+  canvas.addEventListener("wheel", onWheel, { passive: false });
+  const resizeObserver = new ResizeObserver(requestRender);
+  resizeObserver.observe(canvas);
+
+  async function load(url) {
+    const response = await fetch(url);
+    if (!response.ok) {
+      throw new Error((await response.text()) || `HTTP ${response.status}`);
+    }
+    const { json, bin } = parseGLB(await response.arrayBuffer());
+    if (disposed) return;
+
+    const textures = await Promise.all(
+      (json.textures || []).map((texture) => createTexture(json, bin, texture.source))
+    );
+    if (disposed) return;
+
+    const mesh = json.meshes?.[0];
+    if (!mesh) throw new Error("模型中没有网格");
+
+    const min = [Infinity, Infinity, Infinity];
+    const max = [-Infinity, -Infinity, -Infinity];
+    primitives = mesh.primitives.map((primitive) => {
+      const positionAccessor = json.accessors[primitive.attributes.POSITION];
+      positionAccessor.min?.forEach((value, i) => (min[i] = Math.min(min[i], value)));
+      positionAccessor.max?.forEach((value, i) => (max[i] = Math.max(max[i], value)));
+
+      const vao = gl.createVertexArray();
+      resources.vaos.push(vao);
+      gl.bindVertexArray(vao);
+      bindAttribute(locations.position, readAccessor(json, bin, primitive.attributes.POSITION), 3);
+      bindAttribute(locations.normal, readAccessor(json, bin, primitive.attributes.NORMAL), 3);
+      if (primitive.attributes.TEXCOORD_0 != null) {
+        bindAttribute(locations.uv, readAccessor(json, bin, primitive.attributes.TEXCOORD_0), 2);
+      }
+      const indices = readAccessor(json, bin, primitive.indices);
+      const indexBuffer = gl.createBuffer();
+      resources.buffers.push(indexBuffer);
+      gl.bindBuffer(gl.ELEMENT_ARRAY_BUFFER, indexBuffer);
+      gl.bufferData(gl.ELEMENT_ARRAY_BUFFER, indices, gl.STATIC_DRAW);
+      gl.bindVertexArray(null);
+
+      const material = json.materials?.[primitive.material] || {};
+      const pbr = material.pbrMetallicRoughness || {};
+      return {
+        vao,
+        count: indices.length,
+        indexType: indices instanceof Uint32Array ? gl.UNSIGNED_INT : gl.UNSIGNED_SHORT,
+        texture: pbr.baseColorTexture ? textures[pbr.baseColorTexture.index] : null,
+        color: pbr.baseColorFactor || [1, 1, 1, 1],
+        alphaCutoff: material.alphaMode === "MASK" ? material.alphaCutoff ?? 0.5 : 0,
+        blend: material.alphaMode === "BLEND",
+        doubleSided: Boolean(material.doubleSided),
+      };
+    });
+    // 半透明材质最后绘制
+    primitives.sort((a, b) => Number(a.blend) - Number(b.blend));
+
+    if (Number.isFinite(min[0])) {
+      center = min.map((value, i) => (value + max[i]) / 2);
+      radius = Math.max(Math.hypot(max[0] - min[0], max[1] - min[1], max[2] - min[2]) / 2, 0.001);
+    }
+    camera.distance = 2.6;
+    requestRender();
+  }
+
+  function bindAttribute(location, data, size) {
+    if (location < 0) return;
+    const buffer = gl.createBuffer();
+    resources.buffers.push(buffer);
+    gl.bindBuffer(gl.ARRAY_BUFFER, buffer);
+    gl.bufferData(gl.ARRAY_BUFFER, data, gl.STATIC_DRAW);
+    gl.enableVertexAttribArray(location);
+    gl.vertexAttribPointer(location, size, gl.FLOAT, false, 0, 0);
+  }
+
+  async function createTexture(json, bin, imageIndex) {
+    const image = json.images?.[imageIndex];
+    if (!image) return null;
+    const bufferView = json.bufferViews[image.bufferView];
+    const bytes = new Uint8Array(bin, bufferView.byteOffset || 0, bufferView.byteLength);
+    const bitmap = await createImageBitmap(new Blob([bytes], { type: image.mimeType }));
+    if (disposed) {
+      bitmap.close();
+      return null;
+    }
+    const texture = gl.createTexture();
+    resources.textures.push(texture);
+    gl.bindTexture(gl.TEXTURE_2D, texture);
+    gl.texImage2D(gl.TEXTURE_2D, 0, gl.RGBA, gl.RGBA, gl.UNSIGNED_BYTE, bitmap);
+    gl.generateMipmap(gl.TEXTURE_2D);
+    gl.texParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR_MIPMAP_LINEAR);
+    gl.texParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR);
+    gl.texParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.REPEAT);
+    gl.texParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.REPEAT);
+    bitmap.close();
+    return texture;
+  }
+
+  function render() {
+    const dpr = window.devicePixelRatio || 1;
+    const width = Math.max(1, Math.round(canvas.clientWidth * dpr));
+    const height = Math.max(1, Math.round(canvas.clientHeight * dpr));
+    if (canvas.width !== width || canvas.height !== height) {
+      canvas.width = width;
+      canvas.height = height;
+    }
+
+    gl.viewport(0, 0, width, height);
+    gl.clearColor(0, 0, 0, 0);
+    gl.clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT);
+    if (!primitives.length) return;
+
+    // 相机绕模型中心旋转，Source 坐标系 Z 轴向上
+    const distance = radius * camera.distance;
+    const eyeDir = [
+      Math.cos(camera.pitch) * Math.cos(camera.yaw),
+      Math.cos(camera.pitch) * Math.sin(camera.yaw),
+      Math.sin(camera.pitch),
+    ];
+    const eye = center.map((value, i) => value + eyeDir[i] * distance);
+    const projection = perspective(Math.PI / 4, width / height, distance / 100, distance + radius * 4);
+    const viewProjection = multiply(projection, lookAt(eye, center, [0, 0, 1]));
+
+    gl.useProgram(program);
+    gl.enable(gl.DEPTH_TEST);
+    gl.uniformMatrix4fv(locations.viewProjection, false, viewProjection);
+    gl.uniform3fv(locations.lightDir, eyeDir);
+    gl.uniform1i(locations.texture, 0);
+    gl.activeTexture(gl.TEXTURE0);
+
+    primitives.forEach((primitive) => {
+      if (primitive.doubleSided) gl.disable(gl.CULL_FACE);
+      else gl.enable(gl.CULL_FACE);
+      if (primitive.blend) {
+        gl.enable(gl.BLEND);
+        gl.blendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA);
+        gl.depthMask(false);
+      } else {
+        gl.disable(gl.BLEND);
+        gl.depthMask(true);
+      }
+      gl.bindTexture(gl.TEXTURE_2D, primitive.texture);
+      gl.uniform1i(locations.hasTexture, primitive.texture ? 1 : 0);
+      gl.uniform4fv(locations.color, primitive.color);
+      gl.uniform1f(locations.alphaCutoff, primitive.alphaCutoff);
+      gl.bindVertexArray(primitive.vao);
+      gl.drawElements(gl.TRIANGLES, primitive.count, primitive.indexType, 0);
+    });
+    gl.bindVertexArray(null);
+    gl.depthMask(true);
+  }
+
+  function dispose() {
+    if (disposed) return;
+    disposed = true;
+    if (frame) cancelAnimationFrame(frame);
+    resizeObserver.disconnect();
+    canvas.removeEventListener("pointerdown", onPointerDown);
+    canvas.removeEventListener("pointermove", onPointerMove);
+    canvas.removeEventListener("pointerup", onPointerUp);
+    canvas.removeEventListener("pointercancel", onPointerUp);
+    canvas.removeEventListener("wheel", onWheel);
+    resources.vaos.forEach((vao) => gl.deleteVertexArray(vao));
+    resources.buffers.forEach((buffer) => gl.deleteBuffer(buffer));
+    resources.textures.forEach((texture) => gl.deleteTexture(texture));
+    gl.deleteProgram(program);
+    // 浏览器限制同时存在的 WebGL 上下文数量，切换模型时主动释放
+    gl.getExtension("WEBGL_lose_context")?.loseContext();
+  }
+
+  return { load, dispose };
+}
+
+function createProgram(gl) {
+  const compile = (type, source) => {
+    const shader = gl.createShader(type);
+    gl.shaderSource(shader, source);
+    gl.compileShader(shader);
+    if (!gl.getShaderParameter(shader, gl.COMPILE_STATUS)) {
+      throw new Error(gl.getShaderInfoLog(shader) || "着色器编译失败");
+    }
+    return shader;
+  };
+  const program = gl.createProgram();
+  const vertex = compile(gl.VERTEX_SHADER, VERTEX_SHADER);
+  const fragment = compile(gl.FRAGMENT_SHADER, FRAGMENT_SHADER);
+  gl.attachShader(program, vertex);
+  gl.attachShader(program, fragment);
+  gl.linkProgram(program);
+  gl.deleteShader(vertex);
+  gl.deleteShader(fragment);
+  if (!gl.getProgramParameter(program, gl.LINK_STATUS)) {
+    throw new Error(gl.getProgramInfoLog(program) || "着色器链接失败");
+  }
+  return program;
+}
+
+// 以下矩阵均为列主序，与 WebGL 一致
+function perspective(fovy, aspect, near, far) {
+  const f = 1 / Math.tan(fovy / 2);
+  const nf = 1 / (near - far);
+  return new Float32Array([
+    f / aspect, 0, 0, 0,
+    0, f, 0, 0,
+    0, 0, (far + near) * nf, -1,
+    0, 0, 2 * far * near * nf, 0,
+  ]);
+}
+
+function lookAt(eye, target, up) {
+  const z = normalize(eye.map((value, i) => value - target[i]));
+  const x = normalize(cross(up, z));
+  const y = cross(z, x);
+  return new Float32Array([
+    x[0], y[0], z[0], 0,
+    x[1], y[1], z[1], 0,
+    x[2], y[2], z[2], 0,
+    -dot(x, eye), -dot(y, eye), -dot(z, eye), 1,
+  ]);
+}
+
+function multiply(a, b) {
+  const out = new Float32Array(16);
+  for (let col = 0; col < 4; col++) {
+    for (let row = 0; row < 4; row++) {
+      let sum = 0;
+      for (let k = 0; k < 4; k++) sum += a[k * 4 + row] * b[col * 4 + k];
+      out[col * 4 + row] = sum;
+    }
+  }
+  return out;
+}
+
+function cross(a, b) {
+  return [a[1] * b[2] - a[2] * b[1], a[2] * b[0] - a[0] * b[2], a[0] * b[1] - a[1] * b[0]];
+}
+
+function dot(a, b) {
+  return a[0] * b[0] + a[1] * b[1] + a[2] * b[2];
+}
+
+function normalize(v) {
+  const length = Math.hypot(v[0], v[1], v[2]) || 1;
+  return [v[0] / length, v[1] / length, v[2] / length];
+}
@@ -28,7 +28,7 @@ export async function manualRotate(type) {
       typeName = "武器";
     } else if (type === "character") {
       config.enableCharacters = true;
-      typeName = "人物";
+      typeName = "人物和感染者";
     } else {
       config.enableCharacters = true;
       config.enableWeapons = true;
@@ -90,7 +90,7 @@ export function updateModRotationUI(config) {
     if (charEnabled && weaponEnabled) {
       text = "轮换已启用";
     } else if (charEnabled) {
-      text = "人物轮换已启用";
+      text = "人物和感染者轮换已启用";
     } else if (weaponEnabled) {
       text = "武器轮换已启用";
     }
@@ -124,7 +124,7 @@ export async function toggleModRotation() {
       <p class="rotation-title">请选择要启用的轮换类型：</p>
       <div class="rotation-options">
         <label class="rotation-option-item">
-          <span class="option-label">人物轮换</span>
+          <span class="option-label">人物和感染者轮换</span>
           <div class="rotation-switch">
             <input type="checkbox" id="rotation-char-check" ${currentConfig.enableCharacters ? "checked" : ""}>
             <span class="rotation-slider round"></span>
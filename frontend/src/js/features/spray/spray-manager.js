@@ -0,0 +1,201 @@
+import { showError, showNotification } from "../../core/toast.js";
+import { formatFileSize } from "../../core/utils.js";
+import { showConfirmModal } from "../modals/confirm.js";
+
+let refs = {};
+let loading = false;
+
+export function openSprayManager() {
+  ensureManagerModal();
+  refs.modal.classList.remove("hidden");
+  loadSprays();
+}
+
+function ensureManagerModal() {
+  let modal = document.getElementById("spray-manager-modal");
+  if (modal) {
+    collectRefs(modal);
+    return;
+  }
+
+  modal = el("div", "modal hidden spray-manager-modal");
+  modal.id = "spray-manager-modal";
+
+  const content = el("div", "modal-content spray-manager-content");
+  const header = el("div", "modal-header spray-tool-header");
+  const titleWrap = el("div", "spray-tool-title-wrap");
+  const active = el("p", "spray-manager-active");
+  active.id = "spray-manager-active";
+  titleWrap.append(el("h3", "", "喷漆管理"), active);
+  const closeBtn = button("spray-manager-close", "close-btn", "×");
+  closeBtn.setAttribute("aria-label", "关闭");
+  header.append(titleWrap, closeBtn);
+
+  const body = el("div", "modal-body spray-manager-body");
+  body.id = "spray-manager-body";
+
+  const footer = el("div", "modal-footer spray-tool-footer");
+  const note = el("div", "spray-tool-footer-status", "游戏退出时会写回 config.cfg，请在游戏关闭时切换喷漆。");
+  const actions = el("div", "spray-tool-footer-actions");
+  actions.append(button("spray-manager-refresh-btn", "btn btn-secondary", "刷新"));
+  footer.append(note, actions);
+
+  content.append(header, body, footer);
+  modal.appendChild(content);
+  document.body.appendChild(modal);
+  collectRefs(modal);
+
+  refs.closeBtn.addEventListener("click", closeSprayManager);
+  refs.refreshBtn.addEventListener("click", loadSprays);
+  modal.addEventListener("click", (event) => {
+    if (event.target === modal) closeSprayManager();
+  });
+}
+
+function closeSprayManager() {
+  refs.modal?.classList.add("hidden");
+}
+
+async function loadSprays() {
+  if (loading) return;
+  loading = true;
+  refs.refreshBtn.disabled = true;
+  refs.body.replaceChildren(el("div", "spray-manager-empty", "正在读取喷漆..."));
+  try {
+    const inventory = await callApp("ListSprays");
+    renderInventory(inventory);
+  } catch (error) {
+    refs.active.textContent = "";
+    refs.body.replaceChildren(el("div", "spray-manager-empty is-error", "读取喷漆失败: " + formatError(error)));
+  } finally {
+    loading = false;
+    refs.refreshBtn.disabled = false;
+  }
+}
+
+function renderInventory(inventory) {
+  refs.active.textContent = inventory?.activeSpray
+    ? `当前喷漆（cl_logofile）：${inventory.activeSpray}`
+    : "当前未设置 cl_logofile，游戏使用默认喷漆。";
+
+  const sprays = inventory?.sprays || [];
+  if (!sprays.length) {
+    refs.body.replaceChildren(
+      el("div", "spray-manager-empty", "游戏目录和已安装的 VPK 中都没有找到喷漆。可以在“喷漆制作”中制作后保存到游戏目录。")
+    );
+    return;
+  }
+
+  const grid = el("div", "spray-manager-grid");
+  sprays.forEach((spray) => grid.appendChild(createSprayCard(spray)));
+  refs.body.replaceChildren(grid);
+}
+
+function createSprayCard(spray) {
+  const card = el("section", `spray-manager-card${spray.active ? " is-active" : ""}`);
+
+  const preview = el("div", "spray-manager-preview");
+  if (spray.previewUrl) {
+    const img = document.createElement("img");
+    img.src = spray.previewUrl;
+    img.alt = spray.name;
+    img.loading = "lazy";
+    preview.appendChild(img);
+  } else {
+    preview.appendChild(el("span", "spray-manager-placeholder", "无法预览"));
+  }
+
+  const info = el("div", "spray-manager-info");
+  const title = el("div", "spray-manager-name", spray.name);
+  title.title = spray.entry;
+  const source =
+    spray.source === "vpk"
+      ? `VPK：${spray.vpkPath.split(/[\\/]/).pop()}`
+      : "游戏目录";
+  const details = [
+    `${spray.width}×${spray.height}`,
+    spray.format,
+    spray.frames > 1 ? `${spray.frames} 帧` : "",
+    formatFileSize(spray.size),
+  ].filter(Boolean);
+  info.append(title, el("div", "spray-manager-meta", source), el("div", "spray-manager-meta", details.join(" · ")));
+
+  const actions = el("div", "spray-manager-actions");
+  const activateBtn = button("", "btn btn-primary btn-small", spray.active ? "当前喷漆" : "设为当前");
+  activateBtn.disabled = spray.active;
+  activateBtn.addEventListener("click", () => activateSpray(spray));
+
+  const removeBtn = button("", "btn btn-secondary btn-small", "删除");
+  const removable = spray.source === "loose" || spray.sprayOnly;
+  removeBtn.disabled = !removable;
+  if (!removable) removeBtn.title = "该 VPK 中还包含喷漆以外的文件，请在模组列表中处理";
+  removeBtn.addEventListener("click", () => confirmRemoveSpray(spray));
+
+  actions.append(activateBtn, removeBtn);
+  card.append(preview, info, actions);
+  return card;
+}
+
+async function activateSpray(spray) {
+  try {
+    await callApp("SetActiveSpray", spray.entry);
+    showNotification(`已将 ${spray.name} 设为当前喷漆`, "success");
+    await loadSprays();
+  } catch (error) {
+    showError("设置喷漆失败: " + formatError(error));
+  }
+}
+
+function confirmRemoveSpray(spray) {
+  const target =
+    spray.source === "vpk"
+      ? `VPK 文件 ${spray.vpkPath.split(/[\\/]/).pop()}`
+      : "对应的 VTF/VMT 文件";
+  showConfirmModal("删除喷漆", `确定要删除喷漆 ${spray.name} 吗？${target}将移至回收站。`, async () => {
+    try {
+      await callApp("RemoveSpray", spray);
+      showNotification("喷漆已删除", "success");
+      await loadSprays();
+    } catch (error) {
+      showError("删除喷漆失败: " + formatError(error));
+      return false;
+    }
+  });
+}
+
+function collectRefs(modal) {
+  refs = {
+    modal,
+    body: document.getElementById("spray-manager-body"),
+    active: document.getElementById("spray-manager-active"),
+    closeBtn: document.getElementById("spray-manager-close"),
+    refreshBtn: document.getElementById("spray-manager-refresh-btn"),
+  };
+}
+
+function button(id, className, text) {
+  const btn = el("button", className, text);
+  btn.type = "button";
+  if (id) btn.id = id;
+  return btn;
+}
+
+function el(tag, className, text) {
+  const node = document.createElement(tag);
+  if (className) node.className = className;
+  if (text != null) node.textContent = text;
+  return node;
+}
+
+function callApp(methodName, ...args) {
+  const method = window?.go?.app?.App?.[methodName];
+  if (typeof method !== "function") {
+    return Promise.reject(new Error(`当前后端不支持 ${methodName}`));
+  }
+  return method(...args);
+}
+
+function formatError(error) {
+  if (error?.message) return error.message;
+  return String(error || "未知错误");
+}
@@ -1,39 +1,27 @@
-import {
-  TEXTURE_FORMATS,
-  FORMAT_LABELS,
-  buildVMTText,
-  buildVTFBytes,
-  estimateVTFSize,
-  getMipmapDimensions,
-  shouldShortenOriginalVTF,
-  shouldUseOriginalMipmaps,
-} from "./vtf-core.js";
-
-export {
-  TEXTURE_FORMATS,
-  FORMAT_LABELS,
-  estimateVTFSize,
-  getMipmapDimensions,
-  shouldShortenOriginalVTF,
-  shouldUseOriginalMipmaps,
-};
+export const SPRAY_FORMATS = [
+  ["auto", "自动（推荐）"],
+  ["DXT1", "DXT1"],
+  ["DXT5", "DXT5（透明度更细）"],
+  ["BGRA8888", "BGRA8888（无损）"],
+];
+
+export const MAX_SIZE_PRESETS = [1024, 512, 256, 128, 64, 32, 16];
+export const MAX_FADE_LEVEL = 3;
 
 export const DEFAULT_SPRAY_OPTIONS = {
-  widthMode: "auto",
-  heightMode: "auto",
-  customWidth: 512,
-  customHeight: 512,
-  rescale: true,
-  mipmaps: false,
+  maxSize: 0,
+  format: "auto",
+  mipmaps: true,
   singleFrameAnimation: false,
   singleFrameCount: 8,
-  format: TEXTURE_FORMATS.DXT1,
-  sampling: 0,
-  dither: true,
-  dxtQuality: 2,
+  fadeLevel: 1,
 };
 
 const IMAGE_DECODER_MAX_FRAMES = 4096;
+// 与后端 sprayMaxSourceFrames 一致，超出时均匀抽取后再交给后端编码
+const ENCODE_MAX_SOURCE_FRAMES = 256;
+const SPRAY_MIN_DIMENSION = 16;
+const SPRAY_MAX_DIMENSION = 1024;
 
 export async function decodeFilesAsProject(files, { requestClipOptions } = {}) {
   const list = Array.from(files || []).filter(Boolean);
@@ -55,7 +43,8 @@ export async function decodeFilesAsProject(files, { requestClipOptions } = {}) {
     name: baseName(list[0].name) || "spray",
     sourceNames: list.map((file) => file.name),
     frames,
-    mipOverrides: {},
+    far: null,
+    autoVTF: null,
   };
 }
 
@@ -68,76 +57,96 @@ export async function decodeFilesAsBatch(files, { requestClipOptions } = {}) {
   return projects;
 }
 
-export async function decodeMipmapOverride(files, { requestClipOptions } = {}) {
+export async function decodeFarImage(files, { requestClipOptions } = {}) {
   const project = await decodeFilesAsProject(files, { requestClipOptions });
-  return project.frames;
+  return project.frames[0];
 }
 
-export async function buildSprayOutputs(project, options) {
+// buildSprayEncodeCall 生成交给后端编码的调用：渐变喷漆用 EncodeFadingSpray，多帧用 EncodeAnimatedSpray，
+// 其余用 EncodeSprayImage。各帧统一转为 PNG，后端负责选择分辨率和格式以满足大小限制
+export function buildSprayEncodeCall(project, options) {
   if (!project || !Array.isArray(project.frames) || !project.frames.length) {
     throw new Error("请先导入喷漆素材");
   }
 
   const finalOptions = { ...DEFAULT_SPRAY_OPTIONS, ...(options || {}) };
-  const size = resolveOutputSize(project, finalOptions);
-  const payload = prepareVTFPayload(project, finalOptions, size);
-  let vtfBytes;
-
-  try {
-    vtfBytes = await buildVTFWithWorker(payload);
-  } catch (error) {
-    vtfBytes = buildVTFBytes(payload);
+  const name = getProjectOutputName(project);
+  const encodeOptions = {
+    format: finalOptions.format,
+    maxSize: Number(finalOptions.maxSize) || 0,
+    mipmaps: !!finalOptions.mipmaps,
+  };
+  const payloads = new Map();
+  const toPayload = (frame, index) => {
+    if (!payloads.has(frame.canvas)) {
+      payloads.set(frame.canvas, canvasToPayload(frame.canvas, `${name}-${index}.png`));
+    }
+    return payloads.get(frame.canvas);
+  };
+
+  if (project.far) {
+    return {
+      method: "EncodeFadingSpray",
+      request: {
+        name,
+        near: toPayload(project.frames[0], 0),
+        far: toPayload(project.far, "far"),
+        fadeLevel: Number(finalOptions.fadeLevel) || 1,
+        options: encodeOptions,
+      },
+    };
   }
 
-  const outputName = getProjectOutputName(project);
+  const frames = sampleFrames(getProjectFrames(project, finalOptions), ENCODE_MAX_SOURCE_FRAMES);
+  if (frames.length === 1) {
+    return {
+      method: "EncodeSprayImage",
+      request: { name, base64: toPayload(frames[0], 0).base64, options: encodeOptions },
+    };
+  }
   return {
-    name: outputName,
-    width: size.width,
-    height: size.height,
-    frameCount: payload.frameCount,
-    mipmapCount: payload.mipmaps.length,
-    format: Number(finalOptions.format),
-    shortened: !!payload.shortened,
-    vtfBytes,
-    vtfBase64: bytesToBase64(vtfBytes),
-    vmtText: buildVMTText(outputName),
-    estimatedSize: vtfBytes.length,
+    method: "EncodeAnimatedSpray",
+    request: { name, files: frames.map(toPayload), options: encodeOptions },
   };
 }
 
-export function resolveOutputSize(project, options) {
+// canReuseAutoVTF 导入时后端已按默认选项编码的结果，在选项未改动时可直接使用
+export function canReuseAutoVTF(project, options) {
+  if (!project?.autoVTF || project.far) return false;
+  const finalOptions = { ...DEFAULT_SPRAY_OPTIONS, ...(options || {}) };
+  return finalOptions.format === DEFAULT_SPRAY_OPTIONS.format &&
+    !Number(finalOptions.maxSize) &&
+    finalOptions.mipmaps === DEFAULT_SPRAY_OPTIONS.mipmaps &&
+    getProjectFrames(project, finalOptions).length === project.autoVTF.frames;
+}
+
+// resolvePreviewSize 与后端 sprayTargetDimension 一致：不超过原图 4/3 倍和最长边上限的最大 2 的幂，
+// 实际输出超出大小限制时后端还会继续缩小
+export function resolvePreviewSize(project, options) {
   const frames = project?.frames || [];
-  const maxSource = frames.reduce(
-    (acc, frame) => ({
-      width: Math.max(acc.width, frame.width || frame.canvas?.width || 1),
-      height: Math.max(acc.height, frame.height || frame.canvas?.height || 1),
-    }),
-    { width: 1, height: 1 }
+  const side = frames.reduce(
+    (acc, frame) => Math.max(acc, frame.width || 1, frame.height || 1),
+    1
   );
-  const frameCount = getProjectFrames(project || { frames }, options).length || 1;
-  const largestResolution = getOriginalLargestResolution(
-    maxSource.width,
-    maxSource.height,
-    frameCount
+  const maxSize = Number(options?.maxSize) || SPRAY_MAX_DIMENSION;
+  const limit = Math.min(
+    Math.max(Math.floor((side * 4) / 3), SPRAY_MIN_DIMENSION),
+    Math.min(maxSize, SPRAY_MAX_DIMENSION)
   );
+  let size = SPRAY_MIN_DIMENSION;
+  while (size * 2 <= limit) size *= 2;
+  return size;
+}
 
-  const width =
-    options.widthMode === "custom"
-      ? Number(options.customWidth)
-      : options.widthMode === "auto"
-        ? largestResolution
-        : Number(options.widthMode);
-  const height =
-    options.heightMode === "custom"
-      ? Number(options.customHeight)
-      : options.heightMode === "auto"
-        ? largestResolution
-        : Number(options.heightMode);
-
-  return {
-    width: clampDimension(width),
-    height: clampDimension(height),
-  };
+function getProjectFrames(project, options) {
+  if (
+    options.singleFrameAnimation &&
+    project.frames.length === 1 &&
+    Number(options.singleFrameCount) > 1
+  ) {
+    return Array.from({ length: clamp(Number(options.singleFrameCount), 2, 64) }, () => project.frames[0]);
+  }
+  return project.frames;
 }
 
 export function sanitizeOutputName(value) {
@@ -152,86 +161,35 @@ function getProjectOutputName(project) {
   return sanitizeOutputName(project?.name || baseName(sourceName) || "spray");
 }
 
-export function drawFrameToCanvas(frame, targetCanvas, options = {}) {
+// drawFrameToCanvas 与后端编码一致，等比缩放后居中放入画布
+export function drawFrameToCanvas(frame, targetCanvas) {
   const width = targetCanvas.width;
   const height = targetCanvas.height;
   const ctx = targetCanvas.getContext("2d");
   ctx.clearRect(0, 0, width, height);
-  drawCanvasCentered(ctx, frame.canvas, width, height, options.rescale !== false);
+  drawCanvasCentered(ctx, frame.canvas, width, height);
 }
 
-function prepareVTFPayload(project, options, size) {
-  const baseFrames = getProjectFrames(project, options);
-  const format = Number(options.format);
-  const sampling = Number(options.sampling || 0);
-  const shortened = shouldShortenOriginalVTF(size.width, size.height, baseFrames.length, format);
-  const useMipmaps = !!options.mipmaps &&
-    shouldUseOriginalMipmaps(size.width, size.height, baseFrames.length, format, sampling, shortened);
-  const mipDimensions =
-    useMipmaps
-      ? getMipmapDimensions(size.width, size.height)
-      : [{ width: size.width, height: size.height }];
-
-  const mipmaps = mipDimensions.map((dim, level) => {
-    const override = Array.isArray(project.mipOverrides?.[level])
-      ? project.mipOverrides[level]
-      : null;
-    const sourceFrames = override && override.length ? override : baseFrames;
-    return baseFrames.map((_, frameIndex) => {
-      const source = sourceFrames[Math.min(frameIndex, sourceFrames.length - 1)];
-      return renderFrameImageData(source, dim.width, dim.height, options.rescale);
-    });
-  });
-
+function canvasToPayload(canvas, name) {
+  const dataURL = canvas.toDataURL("image/png");
   return {
-    width: size.width,
-    height: size.height,
-    frameCount: baseFrames.length,
-    format,
-    sampling,
-    dither: !!options.dither,
-    dxtQuality: Number(options.dxtQuality || 2),
-    shortened,
-    mipmaps,
+    name,
+    type: "image/png",
+    base64: dataURL.slice(dataURL.indexOf(",") + 1),
   };
 }
 
-function getProjectFrames(project, options) {
-  if (
-    options.singleFrameAnimation &&
-    project.frames.length === 1 &&
-    Number(options.singleFrameCount) > 1
-  ) {
-    return Array.from({ length: clamp(Number(options.singleFrameCount), 2, 64) }, () => project.frames[0]);
-  }
-  return project.frames;
-}
-
-function renderFrameImageData(frame, width, height, rescale) {
-  const canvas = document.createElement("canvas");
-  canvas.width = width;
-  canvas.height = height;
-  const ctx = canvas.getContext("2d", { willReadFrequently: true });
-  ctx.clearRect(0, 0, width, height);
-  drawCanvasCentered(ctx, frame.canvas, width, height, rescale !== false);
-  const imageData = ctx.getImageData(0, 0, width, height);
-  return {
-    width,
-    height,
-    data: imageData.data,
-  };
+function sampleFrames(frames, limit) {
+  if (frames.length <= limit) return frames;
+  return Array.from({ length: limit }, (_, i) => frames[Math.floor((i * frames.length) / limit)]);
 }
 
-function drawCanvasCentered(ctx, sourceCanvas, width, height, rescale) {
+function drawCanvasCentered(ctx, sourceCanvas, width, height) {
   const srcWidth = sourceCanvas.width || 1;
   const srcHeight = sourceCanvas.height || 1;
-  let drawWidth = srcWidth;
-  let drawHeight = srcHeight;
-  if (rescale) {
-    const scale = Math.min(width / srcWidth, height / srcHeight);
-    drawWidth = Math.max(1, Math.round(srcWidth * scale));
-    drawHeight = Math.max(1, Math.round(srcHeight * scale));
-  }
+  const scale = Math.min(width / srcWidth, height / srcHeight);
+  const drawWidth = Math.max(1, Math.round(srcWidth * scale));
+  const drawHeight = Math.max(1, Math.round(srcHeight * scale));
   const x = Math.round((width - drawWidth) / 2);
   const y = Math.round((height - drawHeight) / 2);
   ctx.imageSmoothingEnabled = true;
@@ -239,27 +197,6 @@ function drawCanvasCentered(ctx, sourceCanvas, width, height, rescale) {
   ctx.drawImage(sourceCanvas, x, y, drawWidth, drawHeight);
 }
 
-function buildVTFWithWorker(payload) {
-  return new Promise((resolve, reject) => {
-    const worker = new Worker(new URL("./spray-worker.js", import.meta.url), {
-      type: "module",
-    });
-    worker.onmessage = (event) => {
-      worker.terminate();
-      if (event.data?.ok) {
-        resolve(new Uint8Array(event.data.bytes));
-      } else {
-        reject(new Error(event.data?.error || "Worker 转换失败"));
-      }
-    };
-    worker.onerror = (error) => {
-      worker.terminate();
-      reject(error instanceof Error ? error : new Error("Worker 转换失败"));
-    };
-    worker.postMessage(payload);
-  });
-}
-
 async function decodeFileFrames(file, { requestClipOptions } = {}) {
   if (isTGAFile(file)) {
     const canvas = decodeTGAToCanvas(await file.arrayBuffer());
@@ -452,16 +389,6 @@ function decodeTGAToCanvas(buffer) {
   return canvas;
 }
 
-function bytesToBase64(bytes) {
-  let binary = "";
-  const chunkSize = 0x8000;
-  for (let i = 0; i < bytes.length; i += chunkSize) {
-    const chunk = bytes.subarray(i, i + chunkSize);
-    binary += String.fromCharCode(...chunk);
-  }
-  return btoa(binary);
-}
-
 function isGifFile(file) {
   return (file.type || "").toLowerCase() === "image/gif" || /\.gif$/i.test(file.name || "");
 }
@@ -478,28 +405,6 @@ function baseName(name) {
     .trim();
 }
 
-function getOriginalLargestResolution(width, height, frameCount) {
-  let maxres = Math.max(width || 1, height || 1);
-  const frames = Math.max(1, frameCount || 1);
-  if (frames > 16384 && maxres > 4) maxres = 4;
-  else if (frames > 4096 && maxres > 8) maxres = 8;
-  else if (frames > 1024 && maxres > 16) maxres = 16;
-  else if (frames > 256 && maxres > 32) maxres = 32;
-  else if (frames > 64 && maxres > 64) maxres = 64;
-  else if (frames > 16 && maxres > 128) maxres = 128;
-  else if (frames > 4 && maxres > 256) maxres = 256;
-  else if (frames > 1 && maxres > 512) maxres = 512;
-
-  for (let value = 4; value <= 1024; value *= 2) {
-    if (value >= maxres || value === 1024) return value;
-  }
-  return 1024;
-}
-
-function clampDimension(value) {
-  return clamp(Math.round(Number(value) || 1), 4, 1024);
-}
-
 function clamp(value, min, max) {
   return Math.max(min, Math.min(max, value));
 }
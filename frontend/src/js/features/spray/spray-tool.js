@@ -1,22 +1,19 @@
 import { showError, showNotification } from "../../core/toast.js";
 import {
   DEFAULT_SPRAY_OPTIONS,
-  FORMAT_LABELS,
-  TEXTURE_FORMATS,
-  buildSprayOutputs,
+  MAX_FADE_LEVEL,
+  MAX_SIZE_PRESETS,
+  SPRAY_FORMATS,
+  buildSprayEncodeCall,
+  canReuseAutoVTF,
+  decodeFarImage,
   decodeFilesAsBatch,
   decodeFilesAsProject,
-  decodeMipmapOverride,
   drawFrameToCanvas,
-  estimateVTFSize,
-  getMipmapDimensions,
-  resolveOutputSize,
+  resolvePreviewSize,
   sanitizeOutputName,
-  shouldShortenOriginalVTF,
-  shouldUseOriginalMipmaps,
-} from "./vtf.js";
+} from "./spray-source.js";
 
-const RESOLUTION_PRESETS = [1024, 768, 720, 512, 256, 128, 64, 32, 16, 8, 4];
 const ACCEPTED_FILES = "image/*,.tga";
 const SPRAY_IMPORT_EXTENSIONS = new Set([
   ".png",
@@ -76,9 +73,15 @@ export async function importSprayPaths(paths = [], { refreshFilesKeepFilter } =
   openSprayTool({ refreshFilesKeepFilter });
   setBusy(true, "正在读取拖入素材...");
   try {
-    const payloads = await callApp("LoadSprayImportFiles", sprayPaths);
-    const files = sprayPayloadsToFiles(payloads || []);
-    await importPrimaryFiles(files);
+    const payloads = (await callApp("LoadSprayImportFiles", sprayPaths)) || [];
+    const files = sprayPayloadsToFiles(payloads);
+    // 单张静态图片沿用后端导入时按默认选项编码的 VTF，动画的帧范围由导入时的选择决定，保存时再编码
+    const single = payloads.length === 1 ? payloads[0] : null;
+    if (single?.encodeError) {
+      showNotification(`自动编码失败，保存时将按当前设置重新编码: ${single.encodeError}`, "info");
+    }
+    const autoVTF = single?.vtf?.frames === 1 ? single.vtf : null;
+    await importPrimaryFiles(files, { autoVTF });
     return true;
   } catch (error) {
     showError("导入喷漆素材失败: " + formatError(error));
@@ -201,67 +204,42 @@ function createSettingsPanel() {
   sizeNote.hidden = true;
   form.append(
     textControl("喷漆名称", "spray-project-name", "", "留空默认使用素材文件名"),
-    dimensionControl("宽度", "spray-width-value"),
-    dimensionControl("高度", "spray-height-value"),
-    checkboxControl("缩放以适应", "spray-rescale", true),
-    checkboxControl("生成 Mipmaps", "spray-mipmaps", false),
-    createMipmapControls(),
+    dimensionControl("最大分辨率", "spray-max-size"),
+    selectControl("纹理格式", "spray-format", SPRAY_FORMATS),
+    checkboxControl("生成 Mipmaps", "spray-mipmaps", true),
     checkboxControl("单张图片制作动画", "spray-single-frame", false),
     numberControl("单张动画帧数", "spray-single-frame-count", 8, 2, 64),
-    formatControl(),
-    selectControl("采样方法", "spray-sampling", [
-      ["0", "默认"],
-      ["1", "点采样"],
-      ["2", "三线性"],
-      ["16", "各向异性"],
-    ]),
-    checkboxControl("使用抖动", "spray-dither", true),
-    selectControl("DXT 压缩质量", "spray-dxt-quality", [
-      ["0", "快速"],
-      ["1", "普通"],
-      ["2", "慢速"],
-      ["3", "非常慢"],
-    ]),
+    createFadeControls(),
     sizeNote
   );
   panel.appendChild(form);
   return panel;
 }
 
-function createMipmapControls() {
+function createFadeControls() {
   const wrap = el("div", "spray-mipmap-controls");
+  const levels = Array.from({ length: MAX_FADE_LEVEL }, (_, index) => [
+    String(index + 1),
+    `第 ${index + 1} 级 Mipmap 起`,
+  ]);
   const row = el("div", "spray-control spray-control-inline");
-  const label = el("label", "", "替换层级");
-  label.setAttribute("for", "spray-mipmap-level");
-  row.append(label, customSelectInput("spray-mipmap-level", []));
+  const label = el("label", "", "远处图片");
+  label.setAttribute("for", "spray-fade-level");
+  row.append(label, customSelectInput("spray-fade-level", levels));
 
   const actions = el("div", "spray-mipmap-actions");
-  const inputEl = input("file", "spray-mipmap-input");
+  const inputEl = input("file", "spray-far-input");
   inputEl.accept = ACCEPTED_FILES;
-  inputEl.multiple = true;
   inputEl.classList.add("hidden");
   actions.append(
-    button("spray-mipmap-import-btn", "btn btn-secondary", "替换 Mipmap"),
-    button("spray-mipmap-clear-btn", "btn btn-secondary", "清除替换"),
+    button("spray-far-import-btn", "btn btn-secondary", "选择远处图片"),
+    button("spray-far-clear-btn", "btn btn-secondary", "清除"),
     inputEl
   );
   wrap.append(row, actions);
   return wrap;
 }
 
-function formatControl() {
-  const pairs = [
-    [TEXTURE_FORMATS.RGBA8888, "RGBA8888"],
-    [TEXTURE_FORMATS.RGB888, "RGB888"],
-    [TEXTURE_FORMATS.RGB565, "RGB565"],
-    [TEXTURE_FORMATS.BGRA5551, "BGRA5551"],
-    [TEXTURE_FORMATS.BGRA4444, "BGRA4444"],
-    [TEXTURE_FORMATS.DXT1, "DXT1（推荐）"],
-    [TEXTURE_FORMATS.DXT5, "DXT5（透明度更细）"],
-  ];
-  return selectControl("纹理格式", "spray-format", pairs);
-}
-
 function bindStaticEvents() {
   refs.closeBtn.addEventListener("click", closeSprayTool);
   refs.modal.addEventListener("click", (event) => {
@@ -285,12 +263,12 @@ function bindStaticEvents() {
   refs.playBtn.addEventListener("click", togglePlayback);
   refs.saveVTFBtn.addEventListener("click", saveSprayVTF);
   refs.saveVMTBtn.addEventListener("click", saveSprayVMT);
-  refs.mipmapImportBtn.addEventListener("click", () => refs.mipmapInput.click());
-  refs.mipmapInput.addEventListener("change", async () => {
-    await importMipmapOverride(refs.mipmapInput.files);
-    refs.mipmapInput.value = "";
+  refs.farImportBtn.addEventListener("click", () => refs.farInput.click());
+  refs.farInput.addEventListener("change", async () => {
+    await importFarImage(refs.farInput.files);
+    refs.farInput.value = "";
   });
-  refs.mipmapClearBtn.addEventListener("click", clearMipmapOverride);
+  refs.farClearBtn.addEventListener("click", clearFarImage);
   document.addEventListener("click", (event) => {
     if (!event.target.closest(".spray-select-dropdown, .spray-combo-field, .spray-select-menu")) {
       closeSprayDropdowns();
@@ -312,18 +290,14 @@ function bindOptionControls() {
       updateFooter();
     }
   });
-  bindDimensionControl("width", refs.widthValue);
-  bindDimensionControl("height", refs.heightValue);
+  bindMaxSizeControl(refs.maxSize);
 
   const optionBindings = [
-    ["rescale", refs.rescale, "checked"],
     ["mipmaps", refs.mipmaps, "checked"],
     ["singleFrameAnimation", refs.singleFrame, "checked"],
     ["singleFrameCount", refs.singleFrameCount, "number"],
-    ["format", refs.format, "number"],
-    ["sampling", refs.sampling, "number"],
-    ["dither", refs.dither, "checked"],
-    ["dxtQuality", refs.dxtQuality, "number"],
+    ["format", refs.format, "text"],
+    ["fadeLevel", refs.fadeLevel, "number"],
   ];
 
   optionBindings.forEach(([key, control, kind]) => {
@@ -331,8 +305,7 @@ function bindOptionControls() {
       if (kind === "checked") state.options[key] = control.checked;
       else if (kind === "number") state.options[key] = Number(control.value);
       else state.options[key] = control.value;
-      enforceMipmapSizeLimit(key === "mipmaps");
-      renderMipmapLevels();
+      renderFadeControls();
       updatePreview();
     });
     control.addEventListener("input", () => {
@@ -342,80 +315,50 @@ function bindOptionControls() {
       }
     });
   });
-
-  refs.mipmapLevel.addEventListener("change", () => {
-    renderMipmapControlsState();
-  });
 }
 
-function bindDimensionControl(axis, control) {
+function bindMaxSizeControl(control) {
   control.addEventListener("input", () => {
-    if (!applyDimensionValue(axis, control.value, false)) return;
-    enforceMipmapSizeLimit(false);
-    renderMipmapLevels();
+    if (!applyMaxSizeValue(control.value)) return;
     updatePreview();
   });
   control.addEventListener("change", () => {
-    if (applyDimensionValue(axis, control.value, true)) {
-      enforceMipmapSizeLimit(false);
-      renderMipmapLevels();
+    if (applyMaxSizeValue(control.value)) {
       updatePreview();
     }
-    control.value = dimensionValueFromOptions(axis);
+    control.value = maxSizeValueFromOptions();
   });
 }
 
-function applyDimensionValue(axis, rawValue, normalize) {
-  const modeKey = `${axis}Mode`;
-  const customKey = axis === "width" ? "customWidth" : "customHeight";
+function applyMaxSizeValue(rawValue) {
   const value = String(rawValue || "").trim();
   if (!value || value === "自动" || value.toLowerCase() === "auto") {
-    state.options[modeKey] = "auto";
+    state.options.maxSize = 0;
     return true;
   }
 
   const numeric = Number(value.replace(/px$/i, ""));
   if (!Number.isFinite(numeric)) return false;
-
-  const clamped = Math.max(4, Math.min(1024, Math.round(numeric)));
-  state.options[modeKey] = String(clamped);
-  state.options[customKey] = clamped;
-  if (normalize) {
-    const control = axis === "width" ? refs.widthValue : refs.heightValue;
-    control.value = String(clamped);
-  }
+  state.options.maxSize = Math.max(16, Math.min(1024, Math.round(numeric)));
   return true;
 }
 
-function dimensionValueFromOptions(axis) {
-  const modeKey = `${axis}Mode`;
-  const customKey = axis === "width" ? "customWidth" : "customHeight";
-  const mode = state.options[modeKey];
-  if (!mode || mode === "auto") return "自动";
-  if (mode === "custom") return String(state.options[customKey] || 512);
-  return String(mode);
+function maxSizeValueFromOptions() {
+  const maxSize = Number(state.options.maxSize) || 0;
+  return maxSize ? String(maxSize) : "自动";
 }
 
-function enforceMipmapSizeLimit(showMessage) {
-  if (!state.options.mipmaps || canUseMipmaps()) return;
-  state.options.mipmaps = false;
-  refs.mipmaps.checked = false;
-  if (showMessage) {
-    showNotification("当前尺寸、格式或采样设置不符合原版 Mipmaps 规则", "info");
-  }
-}
-
-async function importPrimaryFiles(files) {
+async function importPrimaryFiles(files, { autoVTF = null } = {}) {
   if (!files || !files.length) return;
   setBusy(true, "正在读取素材...");
   try {
     const project = await decodeFilesAsProject(files, {
       requestClipOptions: showClipOptionsModal,
     });
+    project.autoVTF = autoVTF;
     state.projects.push(project);
     state.activeProjectId = project.id;
     state.frameIndex = 0;
-    enforceMipmapSizeLimit(true);
     renderAll();
     showNotification("素材已导入", "success");
   } catch (error) {
@@ -435,7 +378,6 @@ async function importBatchFiles(files) {
     state.projects.push(...projects);
     state.activeProjectId = projects[0]?.id || state.activeProjectId;
     state.frameIndex = 0;
-    enforceMipmapSizeLimit(true);
     renderAll();
     showNotification(`已导入 ${projects.length} 个喷漆项目`, "success");
   } catch (error) {
@@ -445,33 +387,29 @@ async function importBatchFiles(files) {
   }
 }
 
-async function importMipmapOverride(files) {
+async function importFarImage(files) {
   const project = activeProject();
   if (!project || !files || !files.length) return;
-  const level = Number(refs.mipmapLevel.value || 0);
-  if (level <= 0) return;
-  setBusy(true, "正在替换 mipmap...");
+  setBusy(true, "正在读取远处图片...");
   try {
-    project.mipOverrides[level] = await decodeMipmapOverride(files, {
+    project.far = await decodeFarImage(files, {
       requestClipOptions: showClipOptionsModal,
     });
-    renderMipmapControlsState();
+    renderFadeControls();
     updatePreview();
-    showNotification("Mipmap 已替换", "success");
+    showNotification("远处图片已设置，远距离时将显示该图片", "success");
   } catch (error) {
-    showError("替换 mipmap 失败: " + formatError(error));
+    showError("读取远处图片失败: " + formatError(error));
   } finally {
     setBusy(false);
   }
 }
 
-function clearMipmapOverride() {
+function clearFarImage() {
   const project = activeProject();
   if (!project) return;
-  const level = Number(refs.mipmapLevel.value || 0);
-  if (level <= 0) return;
-  delete project.mipOverrides[level];
-  renderMipmapControlsState();
+  project.far = null;
+  renderFadeControls();
   updatePreview();
 }
 
@@ -523,7 +461,20 @@ async function buildOutputForActiveProject() {
   }
   setBusy(true, "正在转换 VTF...");
   try {
-    return await buildSprayOutputs(project, state.options);
+    let info = project.autoVTF;
+    if (!canReuseAutoVTF(project, state.options)) {
+      const { method, request } = buildSprayEncodeCall(project, state.options);
+      info = await callApp(method, request);
+    }
+    project.lastOutput = info;
+    updatePreview();
+    if (info.sourceFrames > info.frames) {
+      showNotification(`为满足 512 KB 限制，动画由 ${info.sourceFrames} 帧抽取为 ${info.frames} 帧`, "info");
+    }
+    return {
+      name: sanitizeOutputName(getProjectDisplayName(project)),
+      vtfBase64: info.vtfBase64,
+    };
   } catch (error) {
     showError("转换失败: " + formatError(error));
     return null;
@@ -536,7 +487,7 @@ function renderAll() {
   collectRefs();
   renderProjectList();
   renderSettings();
-  renderMipmapLevels();
+  renderFadeControls();
   renderFrameStrip();
   updatePreview();
 }
@@ -574,48 +525,24 @@ function renderProjectList() {
 function renderSettings() {
   const project = activeProject();
   refs.projectName.value = project?.name || "";
-  refs.widthValue.value = dimensionValueFromOptions("width");
-  refs.heightValue.value = dimensionValueFromOptions("height");
-  refs.rescale.checked = !!state.options.rescale;
+  refs.maxSize.value = maxSizeValueFromOptions();
   refs.mipmaps.checked = !!state.options.mipmaps;
   refs.singleFrame.checked = !!state.options.singleFrameAnimation;
   refs.singleFrameCount.value = state.options.singleFrameCount;
   setCustomSelectValue(refs.format, String(state.options.format), false);
-  setCustomSelectValue(refs.sampling, String(state.options.sampling), false);
-  refs.dither.checked = !!state.options.dither;
-  setCustomSelectValue(refs.dxtQuality, String(state.options.dxtQuality), false);
-}
-
-function renderMipmapLevels() {
-  const project = activeProject();
-  if (!project || !state.options.mipmaps || !canUseMipmaps()) {
-    renderCustomSelectOptions(refs.mipmapLevel, []);
-    setCustomSelectDisabled(refs.mipmapLevel, true);
-    refs.mipmapImportBtn.disabled = true;
-    refs.mipmapClearBtn.disabled = true;
-    return;
-  }
-
-  const size = resolveOutputSize(project, state.options);
-  const levels = getMipmapDimensions(size.width, size.height);
-  const options = levels.slice(1).map((level, index) => [
-    String(index + 1),
-    `${index + 1}: ${level.width}×${level.height}`,
-  ]);
-  renderCustomSelectOptions(refs.mipmapLevel, options);
-  setCustomSelectDisabled(refs.mipmapLevel, levels.length <= 1);
-  refs.mipmapImportBtn.disabled = levels.length <= 1;
-  refs.mipmapClearBtn.disabled = levels.length <= 1;
-  renderMipmapControlsState();
+  setCustomSelectValue(refs.fadeLevel, String(state.options.fadeLevel), false);
 }
 
-function renderMipmapControlsState() {
+// renderFadeControls 远处图片存放在 mip 中，设置后始终生成 Mipmaps
+function renderFadeControls() {
   const project = activeProject();
-  const level = Number(refs.mipmapLevel.value || 0);
-  const hasOverride = !!project?.mipOverrides?.[level];
-  refs.mipmapClearBtn.disabled =
-    !project || !state.options.mipmaps || level <= 0 || !hasOverride;
-  refs.mipmapImportBtn.textContent = hasOverride ? "重新替换" : "替换 Mipmap";
+  const hasFar = !!project?.far;
+  setCustomSelectDisabled(refs.fadeLevel, !project);
+  refs.farImportBtn.disabled = !project;
+  refs.farClearBtn.disabled = !hasFar;
+  refs.farImportBtn.textContent = hasFar ? "重新选择" : "选择远处图片";
+  refs.mipmaps.disabled = hasFar;
+  refs.mipmaps.checked = hasFar || !!state.options.mipmaps;
 }
 
 function renderFrameStrip() {
@@ -629,7 +556,7 @@ function renderFrameStrip() {
     const canvas = document.createElement("canvas");
     canvas.width = 44;
     canvas.height = 44;
-    drawFrameToCanvas(frame, canvas, { rescale: true });
+    drawFrameToCanvas(frame, canvas);
     item.appendChild(canvas);
     item.addEventListener("click", () => {
       state.frameIndex = index;
@@ -657,41 +584,36 @@ function updatePreview() {
     return;
   }
 
-  const size = resolveOutputSize(project, state.options);
-  canvas.width = size.width;
-  canvas.height = size.height;
+  const size = resolvePreviewSize(project, state.options);
+  canvas.width = size;
+  canvas.height = size;
   state.frameIndex = Math.min(state.frameIndex, project.frames.length - 1);
-  drawFrameToCanvas(project.frames[state.frameIndex], canvas, state.options);
-  refs.previewMeta.textContent = `${size.width}×${size.height}`;
+  drawFrameToCanvas(project.frames[state.frameIndex], canvas);
+  refs.previewMeta.textContent = `${size}×${size}`;
   refs.frameLabel.textContent = `${state.frameIndex + 1} / ${project.frames.length}`;
   refs.saveVTFBtn.disabled = state.busy;
   refs.saveVMTBtn.disabled = state.busy;
-  updateSizeNote(project, size);
+  updateSizeNote(project);
   updateFooter();
 }
 
-function updateSizeNote(project, size) {
-  const frames = getEffectiveFrameCount(project);
-  const format = Number(state.options.format);
-  const shortened = shouldShortenOriginalVTF(size.width, size.height, frames, format);
-  const useMipmaps = state.options.mipmaps && canUseMipmaps();
-  const estimate = estimateVTFSize(
-    size.width,
-    size.height,
-    frames,
-    format,
-    useMipmaps,
-    shortened
-  );
-  const kb = Math.round(estimate / 1024);
+// updateSizeNote 分辨率、格式和丢帧由后端编码时决定，转换后显示实际结果
+function updateSizeNote(project) {
+  refs.sizeNote.hidden = false;
+  const info = project.lastOutput;
+  if (!info) {
+    refs.sizeNote.textContent = "保存时自动调整分辨率和帧数，控制在 512 KB 以内";
+    refs.sizeNote.classList.remove("is-warning");
+    return;
+  }
   const suffix = [
-    FORMAT_LABELS.get(format) || "VTF",
-    useMipmaps ? "Mipmaps" : "",
-    shortened ? "原版缩宽" : "",
+    `${info.width}×${info.height}`,
+    info.format,
+    info.mipCount > 1 ? "Mipmaps" : "",
+    info.frames > 1 ? `${info.frames} 帧` : "",
   ].filter(Boolean).join(" · ");
-  refs.sizeNote.hidden = false;
-  refs.sizeNote.textContent = `预计 ${kb} KB · ${suffix}`;
-  refs.sizeNote.classList.toggle("is-warning", kb >= 512);
+  refs.sizeNote.textContent = `上次输出 ${Math.round(info.size / 1024)} KB · ${suffix}`;
+  refs.sizeNote.classList.toggle("is-warning", info.sourceFrames > info.frames);
 }
 
 function updateFooter() {
@@ -760,47 +682,6 @@ function activeProject() {
   return state.projects.find((project) => project.id === state.activeProjectId) || null;
 }
 
-function canUseMipmaps() {
-  const project = activeProject();
-  const size = project
-    ? resolveOutputSize(project, state.options)
-    : {
-      width: getConfiguredMipmapDimension("width"),
-      height: getConfiguredMipmapDimension("height"),
-    };
-  const frames = project ? getEffectiveFrameCount(project) : 1;
-  const format = Number(state.options.format);
-  const shortened = shouldShortenOriginalVTF(size.width, size.height, frames, format);
-  return shouldUseOriginalMipmaps(
-    size.width,
-    size.height,
-    frames,
-    format,
-    Number(state.options.sampling || 0),
-    shortened
-  );
-}
-
-function getConfiguredMipmapDimension(axis) {
-  const mode = state.options[`${axis}Mode`];
-  if (!mode || mode === "auto") return 512;
-  if (mode === "custom") {
-    return Number(state.options[axis === "width" ? "customWidth" : "customHeight"]) || 512;
-  }
-  return Number(mode) || 512;
-}
-
-function getEffectiveFrameCount(project) {
-  if (
-    state.options.singleFrameAnimation &&
-    project.frames.length === 1 &&
-    Number(state.options.singleFrameCount) > 1
-  ) {
-    return Number(state.options.singleFrameCount);
-  }
-  return project.frames.length;
-}
-
 function getProjectDisplayName(project) {
   if (!project) return "spray";
   return project.name || getProjectSourceBaseName(project) || "spray";
@@ -869,20 +750,15 @@ function collectRefs() {
     frameLabel: document.getElementById("spray-frame-label"),
     frameStrip: document.getElementById("spray-frame-strip"),
     projectName: document.getElementById("spray-project-name"),
-    widthValue: document.getElementById("spray-width-value"),
-    heightValue: document.getElementById("spray-height-value"),
-    rescale: document.getElementById("spray-rescale"),
+    maxSize: document.getElementById("spray-max-size"),
     mipmaps: document.getElementById("spray-mipmaps"),
-    mipmapLevel: document.getElementById("spray-mipmap-level"),
-    mipmapImportBtn: document.getElementById("spray-mipmap-import-btn"),
-    mipmapClearBtn: document.getElementById("spray-mipmap-clear-btn"),
-    mipmapInput: document.getElementById("spray-mipmap-input"),
+    fadeLevel: document.getElementById("spray-fade-level"),
+    farImportBtn: document.getElementById("spray-far-import-btn"),
+    farClearBtn: document.getElementById("spray-far-clear-btn"),
+    farInput: document.getElementById("spray-far-input"),
     singleFrame: document.getElementById("spray-single-frame"),
     singleFrameCount: document.getElementById("spray-single-frame-count"),
     format: document.getElementById("spray-format"),
-    sampling: document.getElementById("spray-sampling"),
-    dither: document.getElementById("spray-dither"),
-    dxtQuality: document.getElementById("spray-dxt-quality"),
     sizeNote: document.getElementById("spray-size-note"),
     footerStatus: document.getElementById("spray-footer-status"),
     saveVTFBtn: document.getElementById("spray-save-vtf-btn"),
@@ -923,7 +799,7 @@ function dimensionControl(labelText, id) {
   const menu = el("div", "select-menu spray-select-menu spray-combo-menu hidden");
   menu.id = `${id}-menu`;
 
-  ["自动", ...RESOLUTION_PRESETS].forEach((value) => {
+  ["自动", ...MAX_SIZE_PRESETS].forEach((value) => {
     const option = createSelectOption(String(value), String(value));
     option.addEventListener("click", (event) => {
       event.stopPropagation();
@@ -1,8 +1,8 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {app} from '../models';
-import {network} from '../models';
 import {parser} from '../models';
+import {network} from '../models';
 import {minidump} from '../models';
 
 export function AutoDiscoverAddons():Promise<string>;
@@ -15,8 +15,12 @@ export function ChangePanelDifficulty(arg1:string,arg2:string):Promise<string>;
 
 export function ChangePanelMap(arg1:string,arg2:string):Promise<string>;
 
+export function CheckAllEndpointsHealth():Promise<Array<app.EndpointHealth>>;
+
 export function CheckConflicts():Promise<app.ConflictResult>;
 
+export function CheckEndpointHealth(arg1:string):Promise<Array<app.EndpointHealth>>;
+
 export function CheckModUpdates():Promise<app.UpdateCheckResult>;
 
 export function CheckUpdate():Promise<app.UpdateInfo>;
@@ -25,16 +29,32 @@ export function ClearCompletedPanelMapUploads():Promise<void>;
 
 export function ClearCompletedTasks():Promise<void>;
 
+export function ClearIPHealthHistory():Promise<void>;
+
 export function ClearPanelMaps(arg1:string):Promise<string>;
 
 export function ConnectToServer(arg1:string):Promise<void>;
 
+export function DeleteSmartCollection(arg1:string):Promise<void>;
+
 export function DeleteVPKFile(arg1:string):Promise<void>;
 
 export function DeleteVPKFiles(arg1:Array<string>):Promise<void>;
 
 export function DoUpdate(arg1:string):Promise<string>;
 
+export function EncodeAnimatedSpray(arg1:app.SprayAnimationRequest):Promise<app.SprayVTFInfo>;
+
+export function EncodeFadingSpray(arg1:app.SprayFadeRequest):Promise<app.SprayVTFInfo>;
+
+export function EncodeSprayImage(arg1:app.SprayEncodeRequest):Promise<app.SprayVTFInfo>;
+
+export function ExplainVPKTags(arg1:string):Promise<parser.Classification>;
+
+export function ExportModelGLB(arg1:string,arg2:string):Promise<string>;
+
+export function ExportModelStatsReport(arg1:app.ModelStatsScanResult,arg2:string):Promise<string>;
+
 export function ExportServersToFile(arg1:string):Promise<string>;
 
 export function ExportSprayFiles(arg1:app.SprayExportRequest):Promise<app.SprayExportResult>;
@@ -71,14 +91,24 @@ export function GetAppConfig():Promise<app.ConfigFile>;
 
 export function GetAppVersion():Promise<string>;
 
+export function GetClassificationRules():Promise<app.ClassificationRulesInfo>;
+
 export function GetConfigMigrationVersion():Promise<number>;
 
 export function GetCurrentBestIP():Promise<string>;
 
 export function GetCurrentBestIPOption():Promise<network.IPOption>;
 
+export function GetDefaultEndpointConfig():Promise<app.EndpointConfig>;
+
+export function GetDownloadDiskSpace():Promise<app.DownloadDiskSpace>;
+
 export function GetDownloadTasks():Promise<Array<app.DownloadTask>>;
 
+export function GetEndpointConfig():Promise<app.EndpointConfig>;
+
+export function GetIPHealthHistory():Promise<Array<network.IPHealthRecord>>;
+
 export function GetMapName(arg1:string):Promise<string>;
 
 export function GetMirrors():Promise<Array<string>>;
@@ -87,8 +117,12 @@ export function GetMirrorsInitial():Promise<Array<app.MirrorWithLatency>>;
 
 export function GetMirrorsWithLatency():Promise<Array<app.MirrorWithLatency>>;
 
+export function GetMissingAssetScanState():Promise<app.MissingAssetScanState>;
+
 export function GetModRotation():Promise<app.RotationConfig>;
 
+export function GetModelBudgets():Promise<app.ModelBudgets>;
+
 export function GetModelStatsScanState():Promise<app.ModelStatsScanState>;
 
 export function GetPanelMapUploadTasks():Promise<Array<app.PanelMapUploadTask>>;
@@ -97,18 +131,28 @@ export function GetPrimaryTags():Promise<Array<string>>;
 
 export function GetProblemModScanSession():Promise<app.ProblemModScanSession>;
 
+export function GetProxySettings():Promise<app.ProxySettings>;
+
 export function GetRootDirectory():Promise<string>;
 
 export function GetSecondaryTags(arg1:string):Promise<Array<string>>;
 
 export function GetServerStorage():Promise<app.ServerStorage>;
 
+export function GetSmartCollectionFiles(arg1:string):Promise<Array<parser.VPKFile>>;
+
+export function GetSmartCollections():Promise<Array<app.SmartCollectionInfo>>;
+
 export function GetVPKFiles():Promise<Array<parser.VPKFile>>;
 
 export function GetVPKLoadOrder(arg1:string):Promise<number>;
 
+export function GetVPKMapInfo(arg1:string):Promise<Array<parser.BSPMapInfo>>;
+
 export function GetVPKPreviewImage(arg1:string):Promise<string>;
 
+export function GetVPKSoundDetails(arg1:string):Promise<parser.SoundDetails>;
+
 export function GetWorkshopBrowserTarget():Promise<string>;
 
 export function GetWorkshopDetails(arg1:string):Promise<Array<app.WorkshopFileDetails>>;
@@ -153,6 +197,14 @@ export function LaunchL4D2():Promise<void>;
 
 export function LaunchL4D2ForProblemScan():Promise<void>;
 
+export function ListSprays():Promise<app.SprayInventory>;
+
+export function ListVPKAudioEntries(arg1:string):Promise<Array<app.VPKAudioEntry>>;
+
+export function ListVPKModels(arg1:string):Promise<Array<app.ModelPreviewItem>>;
+
+export function ListVPKTextures(arg1:string):Promise<Array<parser.VTFTextureInfo>>;
+
 export function LoadSprayImportFiles(arg1:Array<string>):Promise<Array<app.SprayImportFilePayload>>;
 
 export function LogError(arg1:string,arg2:string,arg3:string):Promise<void>;
@@ -173,8 +225,14 @@ export function ParseMDMPFile(arg1:string):Promise<minidump.Report>;
 
 export function ParseWorkshopID(arg1:string):Promise<string>;
 
+export function QueryVPKFiles(arg1:app.VPKQuery):Promise<app.VPKQueryResult>;
+
+export function RemoveSpray(arg1:app.SprayInventoryItem):Promise<void>;
+
 export function RenameVPKFile(arg1:string,arg2:string):Promise<string>;
 
+export function ResetModelBudgets():Promise<app.ModelBudgets>;
+
 export function RestartApplication():Promise<void>;
 
 export function RestartPanelServer(arg1:string):Promise<string>;
@@ -189,8 +247,12 @@ export function RotateMods():Promise<void>;
 
 export function SaveAppConfig(arg1:app.ConfigFile):Promise<void>;
 
+export function SaveClassificationRules(arg1:Array<parser.ClassificationRule>):Promise<void>;
+
 export function SaveServerStorage(arg1:app.ServerStorage):Promise<void>;
 
+export function SaveSmartCollection(arg1:string,arg2:string):Promise<void>;
+
 export function SaveSprayVMT(arg1:app.SpraySaveVMTRequest):Promise<string>;
 
 export function SaveSprayVTF(arg1:app.SpraySaveVTFRequest):Promise<string>;
@@ -201,6 +263,8 @@ export function ScanVPKFiles():Promise<void>;
 
 export function SearchVPKFiles(arg1:string,arg2:string,arg3:Array<string>):Promise<Array<parser.VPKFile>>;
 
+export function SearchVPKInnerFiles(arg1:string,arg2:string,arg3:number):Promise<app.VPKInnerFileSearchResult>;
+
 export function SelectDirectory():Promise<string>;
 
 export function SelectFiles():Promise<Array<string>>;
@@ -217,8 +281,16 @@ export function SelectVPKUnpackOutputDirectory():Promise<string>;
 
 export function SendPanelRconCommand(arg1:string,arg2:string):Promise<string>;
 
+export function SetActiveSpray(arg1:string):Promise<void>;
+
+export function SetEndpointConfig(arg1:app.EndpointConfig):Promise<void>;
+
 export function SetModRotation(arg1:app.RotationConfig):Promise<void>;
 
+export function SetModelBudgets(arg1:app.ModelBudgets):Promise<void>;
+
+export function SetProxySettings(arg1:app.ProxySettings):Promise<void>;
+
 export function SetRootDirectory(arg1:string):Promise<void>;
 
 export function SetVPKLoadOrder(arg1:string,arg2:number):Promise<void>;
@@ -245,6 +317,8 @@ export function SetWorkshopUpdateCheckEnabled(arg1:boolean):Promise<void>;
 
 export function StartDownloadTask(arg1:app.WorkshopFileDetails,arg2:boolean):Promise<string>;
 
+export function StartMissingAssetScan(arg1:app.MissingAssetScanOptions):Promise<app.MissingAssetScanState>;
+
 export function StartModelStatsScan():Promise<app.ModelStatsScanState>;
 
 export function StartPanelMapUpload(arg1:string,arg2:Array<string>):Promise<Array<string>>;
@@ -255,6 +329,8 @@ export function SubmitProblemModScanResult(arg1:string):Promise<app.ProblemModSc
 
 export function TestMirrorsLatency():Promise<void>;
 
+export function TestProxyConnection():Promise<app.EndpointHealth>;
+
 export function ToggleVPKFile(arg1:string):Promise<void>;
 
 export function ToggleVPKVisibility(arg1:string):Promise<string>;
@@ -263,4 +339,6 @@ export function TranslateWorkshopDescription(arg1:string):Promise<app.WorkshopTr
 
 export function UnpackVPKFile(arg1:string,arg2:string):Promise<app.VPKUnpackResult>;
 
+export function ValidateCampaigns():Promise<Array<app.CampaignValidation>>;
+
 export function ValidateDirectory(arg1:string):Promise<void>;
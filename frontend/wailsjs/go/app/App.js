@@ -22,10 +22,18 @@ export function ChangePanelMap(arg1, arg2) {
   return window['go']['app']['App']['ChangePanelMap'](arg1, arg2);
 }
 
+export function CheckAllEndpointsHealth() {
+  return window['go']['app']['App']['CheckAllEndpointsHealth']();
+}
+
 export function CheckConflicts() {
   return window['go']['app']['App']['CheckConflicts']();
 }
 
+export function CheckEndpointHealth(arg1) {
+  return window['go']['app']['App']['CheckEndpointHealth'](arg1);
+}
+
 export function CheckModUpdates() {
   return window['go']['app']['App']['CheckModUpdates']();
 }
@@ -42,6 +50,10 @@ export function ClearCompletedTasks() {
   return window['go']['app']['App']['ClearCompletedTasks']();
 }
 
+export function ClearIPHealthHistory() {
+  return window['go']['app']['App']['ClearIPHealthHistory']();
+}
+
 export function ClearPanelMaps(arg1) {
   return window['go']['app']['App']['ClearPanelMaps'](arg1);
 }
@@ -50,6 +62,10 @@ export function ConnectToServer(arg1) {
   return window['go']['app']['App']['ConnectToServer'](arg1);
 }
 
+export function DeleteSmartCollection(arg1) {
+  return window['go']['app']['App']['DeleteSmartCollection'](arg1);
+}
+
 export function DeleteVPKFile(arg1) {
   return window['go']['app']['App']['DeleteVPKFile'](arg1);
 }
@@ -62,6 +78,30 @@ export function DoUpdate(arg1) {
   return window['go']['app']['App']['DoUpdate'](arg1);
 }
 
+export function EncodeAnimatedSpray(arg1) {
+  return window['go']['app']['App']['EncodeAnimatedSpray'](arg1);
+}
+
+export function EncodeFadingSpray(arg1) {
+  return window['go']['app']['App']['EncodeFadingSpray'](arg1);
+}
+
+export function EncodeSprayImage(arg1) {
+  return window['go']['app']['App']['EncodeSprayImage'](arg1);
+}
+
+export function ExplainVPKTags(arg1) {
+  return window['go']['app']['App']['ExplainVPKTags'](arg1);
+}
+
+export function ExportModelGLB(arg1, arg2) {
+  return window['go']['app']['App']['ExportModelGLB'](arg1, arg2);
+}
+
+export function ExportModelStatsReport(arg1, arg2) {
+  return window['go']['app']['App']['ExportModelStatsReport'](arg1, arg2);
+}
+
 export function ExportServersToFile(arg1) {
   return window['go']['app']['App']['ExportServersToFile'](arg1);
 }
@@ -134,6 +174,10 @@ export function GetAppVersion() {
   return window['go']['app']['App']['GetAppVersion']();
 }
 
+export function GetClassificationRules() {
+  return window['go']['app']['App']['GetClassificationRules']();
+}
+
 export function GetConfigMigrationVersion() {
   return window['go']['app']['App']['GetConfigMigrationVersion']();
 }
@@ -146,10 +190,26 @@ export function GetCurrentBestIPOption() {
   return window['go']['app']['App']['GetCurrentBestIPOption']();
 }
 
+export function GetDefaultEndpointConfig() {
+  return window['go']['app']['App']['GetDefaultEndpointConfig']();
+}
+
+export function GetDownloadDiskSpace() {
+  return window['go']['app']['App']['GetDownloadDiskSpace']();
+}
+
 export function GetDownloadTasks() {
   return window['go']['app']['App']['GetDownloadTasks']();
 }
 
+export function GetEndpointConfig() {
+  return window['go']['app']['App']['GetEndpointConfig']();
+}
+
+export function GetIPHealthHistory() {
+  return window['go']['app']['App']['GetIPHealthHistory']();
+}
+
 export function GetMapName(arg1) {
   return window['go']['app']['App']['GetMapName'](arg1);
 }
@@ -166,10 +226,18 @@ export function GetMirrorsWithLatency() {
   return window['go']['app']['App']['GetMirrorsWithLatency']();
 }
 
+export function GetMissingAssetScanState() {
+  return window['go']['app']['App']['GetMissingAssetScanState']();
+}
+
 export function GetModRotation() {
   return window['go']['app']['App']['GetModRotation']();
 }
 
+export function GetModelBudgets() {
+  return window['go']['app']['App']['GetModelBudgets']();
+}
+
 export function GetModelStatsScanState() {
   return window['go']['app']['App']['GetModelStatsScanState']();
 }
@@ -186,6 +254,10 @@ export function GetProblemModScanSession() {
   return window['go']['app']['App']['GetProblemModScanSession']();
 }
 
+export function GetProxySettings() {
+  return window['go']['app']['App']['GetProxySettings']();
+}
+
 export function GetRootDirectory() {
   return window['go']['app']['App']['GetRootDirectory']();
 }
@@ -198,6 +270,14 @@ export function GetServerStorage() {
   return window['go']['app']['App']['GetServerStorage']();
 }
 
+export function GetSmartCollectionFiles(arg1) {
+  return window['go']['app']['App']['GetSmartCollectionFiles'](arg1);
+}
+
+export function GetSmartCollections() {
+  return window['go']['app']['App']['GetSmartCollections']();
+}
+
 export function GetVPKFiles() {
   return window['go']['app']['App']['GetVPKFiles']();
 }
@@ -206,10 +286,18 @@ export function GetVPKLoadOrder(arg1) {
   return window['go']['app']['App']['GetVPKLoadOrder'](arg1);
 }
 
+export function GetVPKMapInfo(arg1) {
+  return window['go']['app']['App']['GetVPKMapInfo'](arg1);
+}
+
 export function GetVPKPreviewImage(arg1) {
   return window['go']['app']['App']['GetVPKPreviewImage'](arg1);
 }
 
+export function GetVPKSoundDetails(arg1) {
+  return window['go']['app']['App']['GetVPKSoundDetails'](arg1);
+}
+
 export function GetWorkshopBrowserTarget() {
   return window['go']['app']['App']['GetWorkshopBrowserTarget']();
 }
@@ -298,6 +386,22 @@ export function LaunchL4D2ForProblemScan() {
   return window['go']['app']['App']['LaunchL4D2ForProblemScan']();
 }
 
+export function ListSprays() {
+  return window['go']['app']['App']['ListSprays']();
+}
+
+export function ListVPKAudioEntries(arg1) {
+  return window['go']['app']['App']['ListVPKAudioEntries'](arg1);
+}
+
+export function ListVPKModels(arg1) {
+  return window['go']['app']['App']['ListVPKModels'](arg1);
+}
+
+export function ListVPKTextures(arg1) {
+  return window['go']['app']['App']['ListVPKTextures'](arg1);
+}
+
 export function LoadSprayImportFiles(arg1) {
   return window['go']['app']['App']['LoadSprayImportFiles'](arg1);
 }
@@ -338,10 +442,22 @@ export function ParseWorkshopID(arg1) {
   return window['go']['app']['App']['ParseWorkshopID'](arg1);
 }
 
+export function QueryVPKFiles(arg1) {
+  return window['go']['app']['App']['QueryVPKFiles'](arg1);
+}
+
+export function RemoveSpray(arg1) {
+  return window['go']['app']['App']['RemoveSpray'](arg1);
+}
+
 export function RenameVPKFile(arg1, arg2) {
   return window['go']['app']['App']['RenameVPKFile'](arg1, arg2);
 }
 
+export function ResetModelBudgets() {
+  return window['go']['app']['App']['ResetModelBudgets']();
+}
+
 export function RestartApplication() {
   return window['go']['app']['App']['RestartApplication']();
 }
@@ -370,10 +486,18 @@ export function SaveAppConfig(arg1) {
   return window['go']['app']['App']['SaveAppConfig'](arg1);
 }
 
+export function SaveClassificationRules(arg1) {
+  return window['go']['app']['App']['SaveClassificationRules'](arg1);
+}
+
 export function SaveServerStorage(arg1) {
   return window['go']['app']['App']['SaveServerStorage'](arg1);
 }
 
+export function SaveSmartCollection(arg1, arg2) {
+  return window['go']['app']['App']['SaveSmartCollection'](arg1, arg2);
+}
+
 export function SaveSprayVMT(arg1) {
   return window['go']['app']['App']['SaveSprayVMT'](arg1);
 }
@@ -394,6 +518,10 @@ export function SearchVPKFiles(arg1, arg2, arg3) {
   return window['go']['app']['App']['SearchVPKFiles'](arg1, arg2, arg3);
 }
 
+export function SearchVPKInnerFiles(arg1, arg2, arg3) {
+  return window['go']['app']['App']['SearchVPKInnerFiles'](arg1, arg2, arg3);
+}
+
 export function SelectDirectory() {
   return window['go']['app']['App']['SelectDirectory']();
 }
@@ -426,10 +554,26 @@ export function SendPanelRconCommand(arg1, arg2) {
   return window['go']['app']['App']['SendPanelRconCommand'](arg1, arg2);
 }
 
+export function SetActiveSpray(arg1) {
+  return window['go']['app']['App']['SetActiveSpray'](arg1);
+}
+
+export function SetEndpointConfig(arg1) {
+  return window['go']['app']['App']['SetEndpointConfig'](arg1);
+}
+
 export function SetModRotation(arg1) {
   return window['go']['app']['App']['SetModRotation'](arg1);
 }
 
+export function SetModelBudgets(arg1) {
+  return window['go']['app']['App']['SetModelBudgets'](arg1);
+}
+
+export function SetProxySettings(arg1) {
+  return window['go']['app']['App']['SetProxySettings'](arg1);
+}
+
 export function SetRootDirectory(arg1) {
   return window['go']['app']['App']['SetRootDirectory'](arg1);
 }
@@ -482,6 +626,10 @@ export function StartDownloadTask(arg1, arg2) {
   return window['go']['app']['App']['StartDownloadTask'](arg1, arg2);
 }
 
+export function StartMissingAssetScan(arg1) {
+  return window['go']['app']['App']['StartMissingAssetScan'](arg1);
+}
+
 export function StartModelStatsScan() {
   return window['go']['app']['App']['StartModelStatsScan']();
 }
@@ -502,6 +650,10 @@ export function TestMirrorsLatency() {
   return window['go']['app']['App']['TestMirrorsLatency']();
 }
 
+export function TestProxyConnection() {
+  return window['go']['app']['App']['TestProxyConnection']();
+}
+
 export function ToggleVPKFile(arg1) {
   return window['go']['app']['App']['ToggleVPKFile'](arg1);
 }
@@ -518,6 +670,10 @@ export function UnpackVPKFile(arg1, arg2) {
   return window['go']['app']['App']['UnpackVPKFile'](arg1, arg2);
 }
 
+export function ValidateCampaigns() {
+  return window['go']['app']['App']['ValidateCampaigns']();
+}
+
 export function ValidateDirectory(arg1) {
   return window['go']['app']['App']['ValidateDirectory'](arg1);
 }
@@ -1,5 +1,105 @@
 export namespace app {
 	
+	export class ConflictVPKFile {
+	    name: string;
+	    path: string;
+	    title: string;
+	    location: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConflictVPKFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.title = source["title"];
+	        this.location = source["location"];
+	    }
+	}
+	export class CampaignValidation {
+	    addon: ConflictVPKFile;
+	    campaign: string;
+	    warnings: parser.MissionWarning[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CampaignValidation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.addon = this.convertValues(source["addon"], ConflictVPKFile);
+	        this.campaign = source["campaign"];
+	        this.warnings = this.convertValues(source["warnings"], parser.MissionWarning);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ClassificationRulesInfo {
+	    path: string;
+	    defaults: parser.ClassificationRule[];
+	    userRules: parser.ClassificationRule[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ClassificationRulesInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.defaults = this.convertValues(source["defaults"], parser.ClassificationRule);
+	        this.userRules = this.convertValues(source["userRules"], parser.ClassificationRule);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SmartCollection {
+	    name: string;
+	    query: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SmartCollection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.query = source["query"];
+	    }
+	}
 	export class SavedDirectory {
 	    path: string;
 	    lastUsed: string;
@@ -14,6 +114,116 @@ export namespace app {
 	        this.lastUsed = source["lastUsed"];
 	    }
 	}
+	export class ModelBudget {
+	    maxVertices: number;
+	    maxTriangles: number;
+	    maxStripGroups: number;
+	    maxBones: number;
+	    maxTextureMemory: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelBudget(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.maxVertices = source["maxVertices"];
+	        this.maxTriangles = source["maxTriangles"];
+	        this.maxStripGroups = source["maxStripGroups"];
+	        this.maxBones = source["maxBones"];
+	        this.maxTextureMemory = source["maxTextureMemory"];
+	    }
+	}
+	export class ModelBudgets {
+	    survivor: ModelBudget;
+	    infected: ModelBudget;
+	    viewmodel: ModelBudget;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelBudgets(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.survivor = this.convertValues(source["survivor"], ModelBudget);
+	        this.infected = this.convertValues(source["infected"], ModelBudget);
+	        this.viewmodel = this.convertValues(source["viewmodel"], ModelBudget);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ProxySettings {
+	    mode: string;
+	    host?: string;
+	    port?: number;
+	    username?: string;
+	    password?: string;
+	    passwordEncrypted?: string;
+	    passwordSet?: boolean;
+	    clearPassword?: boolean;
+	    bypass?: string[];
+	    pinnedIPPolicy?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProxySettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.username = source["username"];
+	        this.password = source["password"];
+	        this.passwordEncrypted = source["passwordEncrypted"];
+	        this.passwordSet = source["passwordSet"];
+	        this.clearPassword = source["clearPassword"];
+	        this.bypass = source["bypass"];
+	        this.pinnedIPPolicy = source["pinnedIPPolicy"];
+	    }
+	}
+	export class EndpointConfig {
+	    workshopWorker?: string;
+	    workshopParse?: string;
+	    mapName?: string;
+	    ipList?: string;
+	    yandexTranslate?: string;
+	    microsoftTranslate?: string;
+	    githubAPI?: string;
+	    updateMirrors?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new EndpointConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.workshopWorker = source["workshopWorker"];
+	        this.workshopParse = source["workshopParse"];
+	        this.mapName = source["mapName"];
+	        this.ipList = source["ipList"];
+	        this.yandexTranslate = source["yandexTranslate"];
+	        this.microsoftTranslate = source["microsoftTranslate"];
+	        this.githubAPI = source["githubAPI"];
+	        this.updateMirrors = source["updateMirrors"];
+	    }
+	}
 	export class RotationConfig {
 	    enableCharacters: boolean;
 	    enableWeapons: boolean;
@@ -39,6 +249,9 @@ export namespace app {
 	    workshopTranslateCustomBaseURL?: string;
 	    workshopTranslateCustomAPIKey?: string;
 	    workshopTranslateCustomModelId?: string;
+	    endpoints: EndpointConfig;
+	    proxy?: ProxySettings;
+	    modelBudgets?: ModelBudgets;
 	    defaultDirectory: string;
 	    savedDirectories: SavedDirectory[];
 	    lastActiveDirectory: string;
@@ -50,6 +263,7 @@ export namespace app {
 	    ignoredVersion: string;
 	    lastUpdateCheckTime: string;
 	    migrationVersion: number;
+	    smartCollections?: SmartCollection[];
 	
 	    static createFrom(source: any = {}) {
 	        return new ConfigFile(source);
@@ -67,6 +281,9 @@ export namespace app {
 	        this.workshopTranslateCustomBaseURL = source["workshopTranslateCustomBaseURL"];
 	        this.workshopTranslateCustomAPIKey = source["workshopTranslateCustomAPIKey"];
 	        this.workshopTranslateCustomModelId = source["workshopTranslateCustomModelId"];
+	        this.endpoints = this.convertValues(source["endpoints"], EndpointConfig);
+	        this.proxy = this.convertValues(source["proxy"], ProxySettings);
+	        this.modelBudgets = this.convertValues(source["modelBudgets"], ModelBudgets);
 	        this.defaultDirectory = source["defaultDirectory"];
 	        this.savedDirectories = this.convertValues(source["savedDirectories"], SavedDirectory);
 	        this.lastActiveDirectory = source["lastActiveDirectory"];
@@ -78,6 +295,7 @@ export namespace app {
 	        this.ignoredVersion = source["ignoredVersion"];
 	        this.lastUpdateCheckTime = source["lastUpdateCheckTime"];
 	        this.migrationVersion = source["migrationVersion"];
+	        this.smartCollections = this.convertValues(source["smartCollections"], SmartCollection);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -98,30 +316,13 @@ export namespace app {
 		    return a;
 		}
 	}
-	export class ConflictVPKFile {
-	    name: string;
-	    path: string;
-	    title: string;
-	    location: string;
-	
-	    static createFrom(source: any = {}) {
-	        return new ConflictVPKFile(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.path = source["path"];
-	        this.title = source["title"];
-	        this.location = source["location"];
-	    }
-	}
 	export class ConflictGroup {
 	    vpk_files: ConflictVPKFile[];
 	    files: string[];
 	    file_count: number;
 	    files_truncated: boolean;
 	    severity: string;
+	    pakfile_files?: string[];
 	
 	    static createFrom(source: any = {}) {
 	        return new ConflictGroup(source);
@@ -134,6 +335,7 @@ export namespace app {
 	        this.file_count = source["file_count"];
 	        this.files_truncated = source["files_truncated"];
 	        this.severity = source["severity"];
+	        this.pakfile_files = source["pakfile_files"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -187,6 +389,46 @@ export namespace app {
 		}
 	}
 	
+	export class DownloadDiagnostics {
+	    workers: number;
+	    max_workers: number;
+	    block_size: number;
+	    throughput: string;
+	    per_worker: string;
+	    events: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DownloadDiagnostics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.workers = source["workers"];
+	        this.max_workers = source["max_workers"];
+	        this.block_size = source["block_size"];
+	        this.throughput = source["throughput"];
+	        this.per_worker = source["per_worker"];
+	        this.events = source["events"];
+	    }
+	}
+	export class DownloadDiskSpace {
+	    free: number;
+	    reserved: number;
+	    queued: number;
+	    available: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DownloadDiskSpace(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.free = source["free"];
+	        this.reserved = source["reserved"];
+	        this.queued = source["queued"];
+	        this.available = source["available"];
+	    }
+	}
 	export class DownloadTask {
 	    id: string;
 	    workshop_id: string;
@@ -201,6 +443,8 @@ export namespace app {
 	    total_size: number;
 	    downloaded_size: number;
 	    speed: string;
+	    current_ip?: string;
+	    diagnostics?: DownloadDiagnostics;
 	    error: string;
 	    description: string;
 	    created_at: string;
@@ -224,10 +468,30 @@ export namespace app {
 	        this.total_size = source["total_size"];
 	        this.downloaded_size = source["downloaded_size"];
 	        this.speed = source["speed"];
+	        this.current_ip = source["current_ip"];
+	        this.diagnostics = this.convertValues(source["diagnostics"], DownloadDiagnostics);
 	        this.error = source["error"];
 	        this.description = source["description"];
 	        this.created_at = source["created_at"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class DropImportItemResult {
 	    path: string;
@@ -289,6 +553,31 @@ export namespace app {
 		    return a;
 		}
 	}
+	
+	export class EndpointHealth {
+	    name: string;
+	    url: string;
+	    is_default: boolean;
+	    ok: boolean;
+	    status_code: number;
+	    latency: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EndpointHealth(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.url = source["url"];
+	        this.is_default = source["is_default"];
+	        this.ok = source["ok"];
+	        this.status_code = source["status_code"];
+	        this.latency = source["latency"];
+	        this.error = source["error"];
+	    }
+	}
 	export class LocalStorageMigrationPayload {
 	    config: string;
 	    theme: string;
@@ -325,6 +614,18 @@ export namespace app {
 	        this.latency = source["latency"];
 	    }
 	}
+	export class MissingAssetScanOptions {
+	    includeBaseGame: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new MissingAssetScanOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.includeBaseGame = source["includeBaseGame"];
+	    }
+	}
 	export class ProgressInfo {
 	    current: number;
 	    total: number;
@@ -341,7 +642,7 @@ export namespace app {
 	        this.message = source["message"];
 	    }
 	}
-	export class ModelStatsScanState {
+	export class MissingAssetScanState {
 	    status: string;
 	    running: boolean;
 	    scanId?: string;
@@ -349,7 +650,7 @@ export namespace app {
 	    progress: ProgressInfo;
 	
 	    static createFrom(source: any = {}) {
-	        return new ModelStatsScanState(source);
+	        return new MissingAssetScanState(source);
 	    }
 	
 	    constructor(source: any = {}) {
@@ -379,52 +680,37 @@ export namespace app {
 		    return a;
 		}
 	}
-	export class MoveResult {
-	    successCount: number;
-	    failCount: number;
-	    errors: string[];
-	
-	    static createFrom(source: any = {}) {
-	        return new MoveResult(source);
-	    }
 	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.successCount = source["successCount"];
-	        this.failCount = source["failCount"];
-	        this.errors = source["errors"];
-	    }
-	}
-	export class PanelChapter {
-	    code: string;
-	    title: string;
-	    modes: string[];
+	export class ModelBudgetViolation {
+	    metric: string;
+	    value: number;
+	    limit: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new PanelChapter(source);
+	        return new ModelBudgetViolation(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.code = source["code"];
-	        this.title = source["title"];
-	        this.modes = source["modes"];
+	        this.metric = source["metric"];
+	        this.value = source["value"];
+	        this.limit = source["limit"];
 	    }
 	}
-	export class PanelCampaign {
-	    title: string;
-	    chapters: PanelChapter[];
-	    vpkName: string;
+	export class ModelBudgetCheck {
+	    path: string;
+	    category?: string;
+	    violations: ModelBudgetViolation[];
 	
 	    static createFrom(source: any = {}) {
-	        return new PanelCampaign(source);
+	        return new ModelBudgetCheck(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.title = source["title"];
-	        this.chapters = this.convertValues(source["chapters"], PanelChapter);
-	        this.vpkName = source["vpkName"];
+	        this.path = source["path"];
+	        this.category = source["category"];
+	        this.violations = this.convertValues(source["violations"], ModelBudgetViolation);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -446,12 +732,232 @@ export namespace app {
 		}
 	}
 	
-	export class PanelMapHotReloadResult {
-	    status: string;
-	    message: string;
+	
+	export class ModelPreviewItem {
+	    path: string;
+	    previewUrl: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new PanelMapHotReloadResult(source);
+	        return new ModelPreviewItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.previewUrl = source["previewUrl"];
+	    }
+	}
+	export class ModelStatsModResult {
+	    name: string;
+	    path: string;
+	    title: string;
+	    location: string;
+	    workshopId?: string;
+	    modelCount: number;
+	    totalVertices: number;
+	    totalTriangles: number;
+	    models: parser.ModelStat[];
+	    budgetChecks: ModelBudgetCheck[];
+	    overBudgetModels: number;
+	    overBudget: boolean;
+	    message?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelStatsModResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.title = source["title"];
+	        this.location = source["location"];
+	        this.workshopId = source["workshopId"];
+	        this.modelCount = source["modelCount"];
+	        this.totalVertices = source["totalVertices"];
+	        this.totalTriangles = source["totalTriangles"];
+	        this.models = this.convertValues(source["models"], parser.ModelStat);
+	        this.budgetChecks = this.convertValues(source["budgetChecks"], ModelBudgetCheck);
+	        this.overBudgetModels = source["overBudgetModels"];
+	        this.overBudget = source["overBudget"];
+	        this.message = source["message"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ModelStatsScanResult {
+	    scanId: string;
+	    generatedAt: string;
+	    totalMods: number;
+	    totalModels: number;
+	    totalVertices: number;
+	    totalTriangles: number;
+	    overBudgetMods: number;
+	    budgets: ModelBudgets;
+	    items: ModelStatsModResult[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelStatsScanResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.scanId = source["scanId"];
+	        this.generatedAt = source["generatedAt"];
+	        this.totalMods = source["totalMods"];
+	        this.totalModels = source["totalModels"];
+	        this.totalVertices = source["totalVertices"];
+	        this.totalTriangles = source["totalTriangles"];
+	        this.overBudgetMods = source["overBudgetMods"];
+	        this.budgets = this.convertValues(source["budgets"], ModelBudgets);
+	        this.items = this.convertValues(source["items"], ModelStatsModResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ModelStatsScanState {
+	    status: string;
+	    running: boolean;
+	    scanId?: string;
+	    rootDir?: string;
+	    progress: ProgressInfo;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelStatsScanState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.status = source["status"];
+	        this.running = source["running"];
+	        this.scanId = source["scanId"];
+	        this.rootDir = source["rootDir"];
+	        this.progress = this.convertValues(source["progress"], ProgressInfo);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MoveResult {
+	    successCount: number;
+	    failCount: number;
+	    errors: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MoveResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.successCount = source["successCount"];
+	        this.failCount = source["failCount"];
+	        this.errors = source["errors"];
+	    }
+	}
+	export class PanelChapter {
+	    code: string;
+	    title: string;
+	    modes: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PanelChapter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.title = source["title"];
+	        this.modes = source["modes"];
+	    }
+	}
+	export class PanelCampaign {
+	    title: string;
+	    chapters: PanelChapter[];
+	    vpkName: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PanelCampaign(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.title = source["title"];
+	        this.chapters = this.convertValues(source["chapters"], PanelChapter);
+	        this.vpkName = source["vpkName"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class PanelMapHotReloadResult {
+	    status: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PanelMapHotReloadResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
@@ -684,6 +1190,7 @@ export namespace app {
 		}
 	}
 	
+	
 	export class RecentServer {
 	    name: string;
 	    address: string;
@@ -784,32 +1291,85 @@ export namespace app {
 		    return a;
 		}
 	}
-	export class SprayFilePayload {
+	
+	export class SmartCollectionInfo {
 	    name: string;
-	    vtfBase64: string;
-	    vmtText: string;
+	    query: string;
+	    count: number;
+	    error?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new SprayFilePayload(source);
+	        return new SmartCollectionInfo(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.name = source["name"];
+	        this.query = source["query"];
+	        this.count = source["count"];
+	        this.error = source["error"];
+	    }
+	}
+	export class SprayEncodeOptions {
+	    format: string;
+	    maxSize: number;
+	    mipmaps: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SprayEncodeOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.format = source["format"];
+	        this.maxSize = source["maxSize"];
+	        this.mipmaps = source["mipmaps"];
+	    }
+	}
+	export class SprayVTFInfo {
+	    vtfBase64: string;
+	    width: number;
+	    height: number;
+	    format: string;
+	    mipCount: number;
+	    size: number;
+	    frames: number;
+	    sourceFrames: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SprayVTFInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.vtfBase64 = source["vtfBase64"];
-	        this.vmtText = source["vmtText"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.format = source["format"];
+	        this.mipCount = source["mipCount"];
+	        this.size = source["size"];
+	        this.frames = source["frames"];
+	        this.sourceFrames = source["sourceFrames"];
 	    }
 	}
-	export class SprayExportRequest {
-	    files: SprayFilePayload[];
+	export class SprayImportFilePayload {
+	    name: string;
+	    type: string;
+	    base64: string;
+	    vtf?: SprayVTFInfo;
+	    encodeError?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new SprayExportRequest(source);
+	        return new SprayImportFilePayload(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.files = this.convertValues(source["files"], SprayFilePayload);
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.base64 = source["base64"];
+	        this.vtf = this.convertValues(source["vtf"], SprayVTFInfo);
+	        this.encodeError = source["encodeError"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -830,34 +1390,20 @@ export namespace app {
 		    return a;
 		}
 	}
-	export class SprayOutputFile {
+	export class SprayAnimationRequest {
 	    name: string;
-	    vtfPath: string;
-	    vmtPath: string;
+	    files: SprayImportFilePayload[];
+	    options: SprayEncodeOptions;
 	
 	    static createFrom(source: any = {}) {
-	        return new SprayOutputFile(source);
+	        return new SprayAnimationRequest(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.name = source["name"];
-	        this.vtfPath = source["vtfPath"];
-	        this.vmtPath = source["vmtPath"];
-	    }
-	}
-	export class SprayExportResult {
-	    outputDir: string;
-	    files: SprayOutputFile[];
-	
-	    static createFrom(source: any = {}) {
-	        return new SprayExportResult(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.outputDir = source["outputDir"];
-	        this.files = this.convertValues(source["files"], SprayOutputFile);
+	        this.files = this.convertValues(source["files"], SprayImportFilePayload);
+	        this.options = this.convertValues(source["options"], SprayEncodeOptions);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -879,34 +1425,20 @@ export namespace app {
 		}
 	}
 	
-	export class SprayImportFilePayload {
+	export class SprayEncodeRequest {
 	    name: string;
-	    type: string;
 	    base64: string;
+	    options: SprayEncodeOptions;
 	
 	    static createFrom(source: any = {}) {
-	        return new SprayImportFilePayload(source);
+	        return new SprayEncodeRequest(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.name = source["name"];
-	        this.type = source["type"];
 	        this.base64 = source["base64"];
-	    }
-	}
-	export class SprayInstallRequest {
-	    packageName: string;
-	    files: SprayFilePayload[];
-	
-	    static createFrom(source: any = {}) {
-	        return new SprayInstallRequest(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.packageName = source["packageName"];
-	        this.files = this.convertValues(source["files"], SprayFilePayload);
+	        this.options = this.convertValues(source["options"], SprayEncodeOptions);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -927,24 +1459,32 @@ export namespace app {
 		    return a;
 		}
 	}
-	export class SprayInstallResult {
-	    packageName: string;
-	    outputPath: string;
-	    files: SprayOutputFile[];
-	    totalFiles: number;
-	    packedFiles: number;
+	export class SprayFilePayload {
+	    name: string;
+	    vtfBase64: string;
+	    vmtText: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new SprayInstallResult(source);
+	        return new SprayFilePayload(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.packageName = source["packageName"];
-	        this.outputPath = source["outputPath"];
-	        this.files = this.convertValues(source["files"], SprayOutputFile);
-	        this.totalFiles = source["totalFiles"];
-	        this.packedFiles = source["packedFiles"];
+	        this.name = source["name"];
+	        this.vtfBase64 = source["vtfBase64"];
+	        this.vmtText = source["vmtText"];
+	    }
+	}
+	export class SprayExportRequest {
+	    files: SprayFilePayload[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SprayExportRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.files = this.convertValues(source["files"], SprayFilePayload);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -965,167 +1505,34 @@ export namespace app {
 		    return a;
 		}
 	}
-	
-	export class SpraySaveVMTRequest {
-	    name: string;
-	
-	    static createFrom(source: any = {}) {
-	        return new SpraySaveVMTRequest(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	    }
-	}
-	export class SpraySaveVTFRequest {
+	export class SprayOutputFile {
 	    name: string;
-	    vtfBase64: string;
+	    vtfPath: string;
+	    vmtPath: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new SpraySaveVTFRequest(source);
+	        return new SprayOutputFile(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.name = source["name"];
-	        this.vtfBase64 = source["vtfBase64"];
-	    }
-	}
-	export class UpdateCheckResult {
-	    total_updates: number;
-	    new_detected: number;
-	
-	    static createFrom(source: any = {}) {
-	        return new UpdateCheckResult(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.total_updates = source["total_updates"];
-	        this.new_detected = source["new_detected"];
-	    }
-	}
-	export class UpdateInfo {
-	    has_update: boolean;
-	    latest_ver: string;
-	    current_ver: string;
-	    release_note: string;
-	    download_url: string;
-	    error?: string;
-	
-	    static createFrom(source: any = {}) {
-	        return new UpdateInfo(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.has_update = source["has_update"];
-	        this.latest_ver = source["latest_ver"];
-	        this.current_ver = source["current_ver"];
-	        this.release_note = source["release_note"];
-	        this.download_url = source["download_url"];
-	        this.error = source["error"];
-	    }
-	}
-	export class VPKPackResult {
-	    sourceDir: string;
-	    outputPath: string;
-	    totalFiles: number;
-	    packedFiles: number;
-	    outputIsAddons: boolean;
-	
-	    static createFrom(source: any = {}) {
-	        return new VPKPackResult(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.sourceDir = source["sourceDir"];
-	        this.outputPath = source["outputPath"];
-	        this.totalFiles = source["totalFiles"];
-	        this.packedFiles = source["packedFiles"];
-	        this.outputIsAddons = source["outputIsAddons"];
+	        this.vtfPath = source["vtfPath"];
+	        this.vmtPath = source["vmtPath"];
 	    }
 	}
-	export class VPKUnpackResult {
-	    sourcePath: string;
+	export class SprayExportResult {
 	    outputDir: string;
-	    totalFiles: number;
-	    extractedFiles: number;
+	    files: SprayOutputFile[];
 	
 	    static createFrom(source: any = {}) {
-	        return new VPKUnpackResult(source);
+	        return new SprayExportResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.sourcePath = source["sourcePath"];
 	        this.outputDir = source["outputDir"];
-	        this.totalFiles = source["totalFiles"];
-	        this.extractedFiles = source["extractedFiles"];
-	    }
-	}
-	export class WorkshopChild {
-	    publishedfileid: string;
-	    sortorder: number;
-	    file_type: number;
-	
-	    static createFrom(source: any = {}) {
-	        return new WorkshopChild(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.publishedfileid = source["publishedfileid"];
-	        this.sortorder = source["sortorder"];
-	        this.file_type = source["file_type"];
-	    }
-	}
-	export class  {
-	    preview_url: string;
-	    preview_type: number;
-	
-	    static createFrom(source: any = {}) {
-	        return new (source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.preview_url = source["preview_url"];
-	        this.preview_type = source["preview_type"];
-	    }
-	}
-	export class WorkshopFileDetails {
-	    result: number;
-	    publishedfileid: string;
-	    creator: string;
-	    filename: string;
-	    file_size: string;
-	    file_url: string;
-	    preview_url: string;
-	    previews: [];
-	    title: string;
-	    file_description: string;
-	    children: WorkshopChild[];
-	
-	    static createFrom(source: any = {}) {
-	        return new WorkshopFileDetails(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.result = source["result"];
-	        this.publishedfileid = source["publishedfileid"];
-	        this.creator = source["creator"];
-	        this.filename = source["filename"];
-	        this.file_size = source["file_size"];
-	        this.file_url = source["file_url"];
-	        this.preview_url = source["preview_url"];
-	        this.previews = this.convertValues(source["previews"], );
-	        this.title = source["title"];
-	        this.file_description = source["file_description"];
-	        this.children = this.convertValues(source["children"], WorkshopChild);
+	        this.files = this.convertValues(source["files"], SprayOutputFile);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1146,22 +1553,24 @@ export namespace app {
 		    return a;
 		}
 	}
-	export class WorkshopDetailsGroup {
-	    root_id: string;
-	    main: WorkshopFileDetails;
-	    items: WorkshopFileDetails[];
-	    downloadable_items: WorkshopFileDetails[];
+	export class SprayFadeRequest {
+	    name: string;
+	    near: SprayImportFilePayload;
+	    far: SprayImportFilePayload;
+	    fadeLevel: number;
+	    options: SprayEncodeOptions;
 	
 	    static createFrom(source: any = {}) {
-	        return new WorkshopDetailsGroup(source);
+	        return new SprayFadeRequest(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.root_id = source["root_id"];
-	        this.main = this.convertValues(source["main"], WorkshopFileDetails);
-	        this.items = this.convertValues(source["items"], WorkshopFileDetails);
-	        this.downloadable_items = this.convertValues(source["downloadable_items"], WorkshopFileDetails);
+	        this.name = source["name"];
+	        this.near = this.convertValues(source["near"], SprayImportFilePayload);
+	        this.far = this.convertValues(source["far"], SprayImportFilePayload);
+	        this.fadeLevel = source["fadeLevel"];
+	        this.options = this.convertValues(source["options"], SprayEncodeOptions);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1182,16 +1591,20 @@ export namespace app {
 		    return a;
 		}
 	}
-	export class WorkshopDetailsResult {
-	    groups: WorkshopDetailsGroup[];
+	
+	
+	export class SprayInstallRequest {
+	    packageName: string;
+	    files: SprayFilePayload[];
 	
 	    static createFrom(source: any = {}) {
-	        return new WorkshopDetailsResult(source);
+	        return new SprayInstallRequest(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.groups = this.convertValues(source["groups"], WorkshopDetailsGroup);
+	        this.packageName = source["packageName"];
+	        this.files = this.convertValues(source["files"], SprayFilePayload);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1212,33 +1625,24 @@ export namespace app {
 		    return a;
 		}
 	}
-	
-	export class WorkshopPreviewItem {
-	    publishedfileid: string;
-	    title: string;
-	    preview_url: string;
-	    creator: string;
-	    file_type: number;
-	    views: number;
-	    subscriptions: number;
-	    favorited: number;
-	    tags: [];
+	export class SprayInstallResult {
+	    packageName: string;
+	    outputPath: string;
+	    files: SprayOutputFile[];
+	    totalFiles: number;
+	    packedFiles: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new WorkshopPreviewItem(source);
+	        return new SprayInstallResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.publishedfileid = source["publishedfileid"];
-	        this.title = source["title"];
-	        this.preview_url = source["preview_url"];
-	        this.creator = source["creator"];
-	        this.file_type = source["file_type"];
-	        this.views = source["views"];
-	        this.subscriptions = source["subscriptions"];
-	        this.favorited = source["favorited"];
-	        this.tags = this.convertValues(source["tags"], );
+	        this.packageName = source["packageName"];
+	        this.outputPath = source["outputPath"];
+	        this.files = this.convertValues(source["files"], SprayOutputFile);
+	        this.totalFiles = source["totalFiles"];
+	        this.packedFiles = source["packedFiles"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1259,70 +1663,60 @@ export namespace app {
 		    return a;
 		}
 	}
-	export class  {
-	    tag: string;
+	export class SprayInventoryItem {
+	    name: string;
+	    entry: string;
+	    source: string;
+	    vtfPath: string;
+	    vmtPath: string;
+	    vpkPath: string;
+	    sprayOnly: boolean;
+	    width: number;
+	    height: number;
+	    frames: number;
+	    format: string;
+	    size: number;
+	    previewUrl: string;
+	    active: boolean;
+	    error?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new (source);
+	        return new SprayInventoryItem(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.tag = source["tag"];
-	    }
-	}
-	export class WorkshopPreviewImage {
-	    preview_url: string;
-	    preview_type: number;
-	
-	    static createFrom(source: any = {}) {
-	        return new WorkshopPreviewImage(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.preview_url = source["preview_url"];
-	        this.preview_type = source["preview_type"];
+	        this.name = source["name"];
+	        this.entry = source["entry"];
+	        this.source = source["source"];
+	        this.vtfPath = source["vtfPath"];
+	        this.vmtPath = source["vmtPath"];
+	        this.vpkPath = source["vpkPath"];
+	        this.sprayOnly = source["sprayOnly"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.frames = source["frames"];
+	        this.format = source["format"];
+	        this.size = source["size"];
+	        this.previewUrl = source["previewUrl"];
+	        this.active = source["active"];
+	        this.error = source["error"];
 	    }
 	}
-	export class WorkshopItemDetail {
-	    publishedfileid: string;
-	    title: string;
-	    description: string;
-	    file_url: string;
-	    preview_url: string;
-	    previews: WorkshopPreviewImage[];
-	    file_type: number;
-	    file_size: any;
-	    time_created: any;
-	    time_updated: any;
-	    subscriptions: any;
-	    favorited: any;
-	    views: any;
-	    tags: [];
-	    child_items: WorkshopPreviewItem[];
+	export class SprayInventory {
+	    gameDir: string;
+	    activeSpray: string;
+	    sprays: SprayInventoryItem[];
 	
 	    static createFrom(source: any = {}) {
-	        return new WorkshopItemDetail(source);
+	        return new SprayInventory(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.publishedfileid = source["publishedfileid"];
-	        this.title = source["title"];
-	        this.description = source["description"];
-	        this.file_url = source["file_url"];
-	        this.preview_url = source["preview_url"];
-	        this.previews = this.convertValues(source["previews"], WorkshopPreviewImage);
-	        this.file_type = source["file_type"];
-	        this.file_size = source["file_size"];
-	        this.time_created = source["time_created"];
-	        this.time_updated = source["time_updated"];
-	        this.subscriptions = source["subscriptions"];
-	        this.favorited = source["favorited"];
-	        this.views = source["views"];
-	        this.tags = this.convertValues(source["tags"], );
-	        this.child_items = this.convertValues(source["child_items"], WorkshopPreviewItem);
+	        this.gameDir = source["gameDir"];
+	        this.activeSpray = source["activeSpray"];
+	        this.sprays = this.convertValues(source["sprays"], SprayInventoryItem);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1343,110 +1737,117 @@ export namespace app {
 		    return a;
 		}
 	}
-	export class WorkshopListResult {
-	    items: WorkshopPreviewItem[];
-	    total: number;
+	
+	
+	export class SpraySaveVMTRequest {
+	    name: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new WorkshopListResult(source);
+	        return new SpraySaveVMTRequest(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.items = this.convertValues(source["items"], WorkshopPreviewItem);
-	        this.total = source["total"];
+	        this.name = source["name"];
 	    }
+	}
+	export class SpraySaveVTFRequest {
+	    name: string;
+	    vtfBase64: string;
 	
-		convertValues(a: any, classs: any, asMap: boolean = false): any {
-		    if (!a) {
-		        return a;
-		    }
-		    if (a.slice && a.map) {
-		        return (a as any[]).map(elem => this.convertValues(elem, classs));
-		    } else if ("object" === typeof a) {
-		        if (asMap) {
-		            for (const key of Object.keys(a)) {
-		                a[key] = new classs(a[key]);
-		            }
-		            return a;
-		        }
-		        return new classs(a);
-		    }
-		    return a;
-		}
+	    static createFrom(source: any = {}) {
+	        return new SpraySaveVTFRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.vtfBase64 = source["vtfBase64"];
+	    }
 	}
 	
+	export class UpdateCheckResult {
+	    total_updates: number;
+	    new_detected: number;
 	
-	export class WorkshopQueryOptions {
-	    page: number;
-	    search_text: string;
-	    sort: string;
-	    tags: string[];
-	    filetype: string;
+	    static createFrom(source: any = {}) {
+	        return new UpdateCheckResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.total_updates = source["total_updates"];
+	        this.new_detected = source["new_detected"];
+	    }
+	}
+	export class UpdateInfo {
+	    has_update: boolean;
+	    latest_ver: string;
+	    current_ver: string;
+	    release_note: string;
+	    download_url: string;
+	    error?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new WorkshopQueryOptions(source);
+	        return new UpdateInfo(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.page = source["page"];
-	        this.search_text = source["search_text"];
-	        this.sort = source["sort"];
-	        this.tags = source["tags"];
-	        this.filetype = source["filetype"];
+	        this.has_update = source["has_update"];
+	        this.latest_ver = source["latest_ver"];
+	        this.current_ver = source["current_ver"];
+	        this.release_note = source["release_note"];
+	        this.download_url = source["download_url"];
+	        this.error = source["error"];
 	    }
 	}
-	export class WorkshopTranslationResult {
-	    provider: string;
-	    text: string;
+	export class VPKAudioEntry {
+	    path: string;
+	    size: number;
+	    url: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new WorkshopTranslationResult(source);
+	        return new VPKAudioEntry(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.provider = source["provider"];
-	        this.text = source["text"];
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.url = source["url"];
 	    }
 	}
-	export class WorkshopWatchLaterItem {
-	    publishedfileid: string;
-	    title: string;
-	    preview_url: string;
-	    views: number;
-	    subscriptions: number;
-	    favorited: number;
-	    file_type: number;
-	    addedAt: string;
+	export class VPKFacetCount {
+	    value: string;
+	    count: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new WorkshopWatchLaterItem(source);
+	        return new VPKFacetCount(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.publishedfileid = source["publishedfileid"];
-	        this.title = source["title"];
-	        this.preview_url = source["preview_url"];
-	        this.views = source["views"];
-	        this.subscriptions = source["subscriptions"];
-	        this.favorited = source["favorited"];
-	        this.file_type = source["file_type"];
-	        this.addedAt = source["addedAt"];
+	        this.value = source["value"];
+	        this.count = source["count"];
 	    }
 	}
-	export class WorkshopWatchLaterStorage {
-	    items: WorkshopWatchLaterItem[];
+	export class VPKInnerFileHit {
+	    file: string;
+	    size: number;
+	    crc: string;
+	    addon: ConflictVPKFile;
 	
 	    static createFrom(source: any = {}) {
-	        return new WorkshopWatchLaterStorage(source);
+	        return new VPKInnerFileHit(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.items = this.convertValues(source["items"], WorkshopWatchLaterItem);
+	        this.file = source["file"];
+	        this.size = source["size"];
+	        this.crc = source["crc"];
+	        this.addon = this.convertValues(source["addon"], ConflictVPKFile);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1467,81 +1868,20 @@ export namespace app {
 		    return a;
 		}
 	}
-
-}
-
-export namespace minidump {
-	
-	export class CodeViewInfo {
-	    signature: string;
-	    guid?: string;
-	    age?: number;
-	    pdbPath?: string;
-	    timestamp?: string;
-	
-	    static createFrom(source: any = {}) {
-	        return new CodeViewInfo(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.signature = source["signature"];
-	        this.guid = source["guid"];
-	        this.age = source["age"];
-	        this.pdbPath = source["pdbPath"];
-	        this.timestamp = source["timestamp"];
-	    }
-	}
-	export class CommentInfo {
-	    stream: string;
-	    text: string;
-	
-	    static createFrom(source: any = {}) {
-	        return new CommentInfo(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.stream = source["stream"];
-	        this.text = source["text"];
-	    }
-	}
-	export class HexPreview {
-	    bytes: number;
+	export class VPKInnerFileSearchResult {
+	    hits: VPKInnerFileHit[];
+	    total: number;
 	    truncated: boolean;
-	    text: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new HexPreview(source);
+	        return new VPKInnerFileSearchResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.bytes = source["bytes"];
+	        this.hits = this.convertValues(source["hits"], VPKInnerFileHit);
+	        this.total = source["total"];
 	        this.truncated = source["truncated"];
-	        this.text = source["text"];
-	    }
-	}
-	export class ContextInfo {
-	    architecture: string;
-	    size: number;
-	    rva: string;
-	    contextFlags: string;
-	    registers: Record<string, string>;
-	    preview: HexPreview;
-	
-	    static createFrom(source: any = {}) {
-	        return new ContextInfo(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.architecture = source["architecture"];
-	        this.size = source["size"];
-	        this.rva = source["rva"];
-	        this.contextFlags = source["contextFlags"];
-	        this.registers = source["registers"];
-	        this.preview = this.convertValues(source["preview"], HexPreview);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1562,48 +1902,1125 @@ export namespace minidump {
 		    return a;
 		}
 	}
-	export class Location {
-	    size: number;
-	    rva: string;
+	export class VPKPackResult {
+	    sourceDir: string;
+	    outputPath: string;
+	    totalFiles: number;
+	    packedFiles: number;
+	    outputIsAddons: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new Location(source);
+	        return new VPKPackResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.size = source["size"];
-	        this.rva = source["rva"];
-	    }
+	        this.sourceDir = source["sourceDir"];
+	        this.outputPath = source["outputPath"];
+	        this.totalFiles = source["totalFiles"];
+	        this.packedFiles = source["packedFiles"];
+	        this.outputIsAddons = source["outputIsAddons"];
+	    }
+	}
+	export class VPKSortKey {
+	    field: string;
+	    desc: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new VPKSortKey(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.desc = source["desc"];
+	    }
+	}
+	export class VPKQueryFilter {
+	    text: string;
+	    primaryTag: string;
+	    secondaryTags: string[];
+	    locations: string[];
+	    enabled?: boolean;
+	    minSize: number;
+	    maxSize: number;
+	    author: string;
+	    hasUpdate?: boolean;
+	    hasWorkshopId?: boolean;
+	    modifiedAfter: string;
+	    modifiedBefore: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new VPKQueryFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.primaryTag = source["primaryTag"];
+	        this.secondaryTags = source["secondaryTags"];
+	        this.locations = source["locations"];
+	        this.enabled = source["enabled"];
+	        this.minSize = source["minSize"];
+	        this.maxSize = source["maxSize"];
+	        this.author = source["author"];
+	        this.hasUpdate = source["hasUpdate"];
+	        this.hasWorkshopId = source["hasWorkshopId"];
+	        this.modifiedAfter = source["modifiedAfter"];
+	        this.modifiedBefore = source["modifiedBefore"];
+	    }
+	}
+	export class VPKQuery {
+	    filter: VPKQueryFilter;
+	    sort: VPKSortKey[];
+	    offset: number;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new VPKQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filter = this.convertValues(source["filter"], VPKQueryFilter);
+	        this.sort = this.convertValues(source["sort"], VPKSortKey);
+	        this.offset = source["offset"];
+	        this.limit = source["limit"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class VPKQueryFacets {
+	    primaryTags: VPKFacetCount[];
+	    secondaryTags: VPKFacetCount[];
+	    locations: VPKFacetCount[];
+	
+	    static createFrom(source: any = {}) {
+	        return new VPKQueryFacets(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.primaryTags = this.convertValues(source["primaryTags"], VPKFacetCount);
+	        this.secondaryTags = this.convertValues(source["secondaryTags"], VPKFacetCount);
+	        this.locations = this.convertValues(source["locations"], VPKFacetCount);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class VPKQueryResult {
+	    files: parser.VPKFile[];
+	    total: number;
+	    offset: number;
+	    limit: number;
+	    facets: VPKQueryFacets;
+	
+	    static createFrom(source: any = {}) {
+	        return new VPKQueryResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.files = this.convertValues(source["files"], parser.VPKFile);
+	        this.total = source["total"];
+	        this.offset = source["offset"];
+	        this.limit = source["limit"];
+	        this.facets = this.convertValues(source["facets"], VPKQueryFacets);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class VPKUnpackResult {
+	    sourcePath: string;
+	    outputDir: string;
+	    totalFiles: number;
+	    extractedFiles: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new VPKUnpackResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sourcePath = source["sourcePath"];
+	        this.outputDir = source["outputDir"];
+	        this.totalFiles = source["totalFiles"];
+	        this.extractedFiles = source["extractedFiles"];
+	    }
+	}
+	export class WorkshopChild {
+	    publishedfileid: string;
+	    sortorder: number;
+	    file_type: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkshopChild(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.publishedfileid = source["publishedfileid"];
+	        this.sortorder = source["sortorder"];
+	        this.file_type = source["file_type"];
+	    }
+	}
+	export class  {
+	    preview_url: string;
+	    preview_type: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new (source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.preview_url = source["preview_url"];
+	        this.preview_type = source["preview_type"];
+	    }
+	}
+	export class WorkshopFileDetails {
+	    result: number;
+	    publishedfileid: string;
+	    creator: string;
+	    filename: string;
+	    file_size: string;
+	    file_url: string;
+	    preview_url: string;
+	    previews: [];
+	    title: string;
+	    file_description: string;
+	    children: WorkshopChild[];
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkshopFileDetails(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.result = source["result"];
+	        this.publishedfileid = source["publishedfileid"];
+	        this.creator = source["creator"];
+	        this.filename = source["filename"];
+	        this.file_size = source["file_size"];
+	        this.file_url = source["file_url"];
+	        this.preview_url = source["preview_url"];
+	        this.previews = this.convertValues(source["previews"], );
+	        this.title = source["title"];
+	        this.file_description = source["file_description"];
+	        this.children = this.convertValues(source["children"], WorkshopChild);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WorkshopDetailsGroup {
+	    root_id: string;
+	    main: WorkshopFileDetails;
+	    items: WorkshopFileDetails[];
+	    downloadable_items: WorkshopFileDetails[];
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkshopDetailsGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.root_id = source["root_id"];
+	        this.main = this.convertValues(source["main"], WorkshopFileDetails);
+	        this.items = this.convertValues(source["items"], WorkshopFileDetails);
+	        this.downloadable_items = this.convertValues(source["downloadable_items"], WorkshopFileDetails);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WorkshopDetailsResult {
+	    groups: WorkshopDetailsGroup[];
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkshopDetailsResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.groups = this.convertValues(source["groups"], WorkshopDetailsGroup);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class WorkshopPreviewItem {
+	    publishedfileid: string;
+	    title: string;
+	    preview_url: string;
+	    creator: string;
+	    file_type: number;
+	    views: number;
+	    subscriptions: number;
+	    favorited: number;
+	    tags: [];
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkshopPreviewItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.publishedfileid = source["publishedfileid"];
+	        this.title = source["title"];
+	        this.preview_url = source["preview_url"];
+	        this.creator = source["creator"];
+	        this.file_type = source["file_type"];
+	        this.views = source["views"];
+	        this.subscriptions = source["subscriptions"];
+	        this.favorited = source["favorited"];
+	        this.tags = this.convertValues(source["tags"], );
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class  {
+	    tag: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new (source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tag = source["tag"];
+	    }
+	}
+	export class WorkshopPreviewImage {
+	    preview_url: string;
+	    preview_type: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkshopPreviewImage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.preview_url = source["preview_url"];
+	        this.preview_type = source["preview_type"];
+	    }
+	}
+	export class WorkshopItemDetail {
+	    publishedfileid: string;
+	    title: string;
+	    description: string;
+	    file_url: string;
+	    preview_url: string;
+	    previews: WorkshopPreviewImage[];
+	    file_type: number;
+	    file_size: any;
+	    time_created: any;
+	    time_updated: any;
+	    subscriptions: any;
+	    favorited: any;
+	    views: any;
+	    tags: [];
+	    child_items: WorkshopPreviewItem[];
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkshopItemDetail(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.publishedfileid = source["publishedfileid"];
+	        this.title = source["title"];
+	        this.description = source["description"];
+	        this.file_url = source["file_url"];
+	        this.preview_url = source["preview_url"];
+	        this.previews = this.convertValues(source["previews"], WorkshopPreviewImage);
+	        this.file_type = source["file_type"];
+	        this.file_size = source["file_size"];
+	        this.time_created = source["time_created"];
+	        this.time_updated = source["time_updated"];
+	        this.subscriptions = source["subscriptions"];
+	        this.favorited = source["favorited"];
+	        this.views = source["views"];
+	        this.tags = this.convertValues(source["tags"], );
+	        this.child_items = this.convertValues(source["child_items"], WorkshopPreviewItem);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WorkshopListResult {
+	    items: WorkshopPreviewItem[];
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkshopListResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.items = this.convertValues(source["items"], WorkshopPreviewItem);
+	        this.total = source["total"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class WorkshopQueryOptions {
+	    page: number;
+	    search_text: string;
+	    sort: string;
+	    tags: string[];
+	    filetype: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkshopQueryOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.page = source["page"];
+	        this.search_text = source["search_text"];
+	        this.sort = source["sort"];
+	        this.tags = source["tags"];
+	        this.filetype = source["filetype"];
+	    }
+	}
+	export class WorkshopTranslationResult {
+	    provider: string;
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkshopTranslationResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.provider = source["provider"];
+	        this.text = source["text"];
+	    }
+	}
+	export class WorkshopWatchLaterItem {
+	    publishedfileid: string;
+	    title: string;
+	    preview_url: string;
+	    views: number;
+	    subscriptions: number;
+	    favorited: number;
+	    file_type: number;
+	    addedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkshopWatchLaterItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.publishedfileid = source["publishedfileid"];
+	        this.title = source["title"];
+	        this.preview_url = source["preview_url"];
+	        this.views = source["views"];
+	        this.subscriptions = source["subscriptions"];
+	        this.favorited = source["favorited"];
+	        this.file_type = source["file_type"];
+	        this.addedAt = source["addedAt"];
+	    }
+	}
+	export class WorkshopWatchLaterStorage {
+	    items: WorkshopWatchLaterItem[];
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkshopWatchLaterStorage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.items = this.convertValues(source["items"], WorkshopWatchLaterItem);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace minidump {
+	
+	export class CodeViewInfo {
+	    signature: string;
+	    guid?: string;
+	    age?: number;
+	    pdbPath?: string;
+	    timestamp?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CodeViewInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.signature = source["signature"];
+	        this.guid = source["guid"];
+	        this.age = source["age"];
+	        this.pdbPath = source["pdbPath"];
+	        this.timestamp = source["timestamp"];
+	    }
+	}
+	export class CommentInfo {
+	    stream: string;
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommentInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stream = source["stream"];
+	        this.text = source["text"];
+	    }
+	}
+	export class HexPreview {
+	    bytes: number;
+	    truncated: boolean;
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HexPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.bytes = source["bytes"];
+	        this.truncated = source["truncated"];
+	        this.text = source["text"];
+	    }
+	}
+	export class ContextInfo {
+	    architecture: string;
+	    size: number;
+	    rva: string;
+	    contextFlags: string;
+	    registers: Record<string, string>;
+	    preview: HexPreview;
+	
+	    static createFrom(source: any = {}) {
+	        return new ContextInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.architecture = source["architecture"];
+	        this.size = source["size"];
+	        this.rva = source["rva"];
+	        this.contextFlags = source["contextFlags"];
+	        this.registers = source["registers"];
+	        this.preview = this.convertValues(source["preview"], HexPreview);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Location {
+	    size: number;
+	    rva: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Location(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.size = source["size"];
+	        this.rva = source["rva"];
+	    }
+	}
+	export class ExceptionInfo {
+	    threadId: number;
+	    code: string;
+	    codeName: string;
+	    flags: string;
+	    record: string;
+	    address: string;
+	    numberParameters: number;
+	    parameters: string[];
+	    contextDescriptor: Location;
+	    context: ContextInfo;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExceptionInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.threadId = source["threadId"];
+	        this.code = source["code"];
+	        this.codeName = source["codeName"];
+	        this.flags = source["flags"];
+	        this.record = source["record"];
+	        this.address = source["address"];
+	        this.numberParameters = source["numberParameters"];
+	        this.parameters = source["parameters"];
+	        this.contextDescriptor = this.convertValues(source["contextDescriptor"], Location);
+	        this.context = this.convertValues(source["context"], ContextInfo);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FileInfo {
+	    path: string;
+	    name: string;
+	    size: number;
+	    lastModified: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.name = source["name"];
+	        this.size = source["size"];
+	        this.lastModified = source["lastModified"];
+	    }
+	}
+	export class HeaderInfo {
+	    signature: string;
+	    signatureAscii: string;
+	    version: string;
+	    formatVersion: number;
+	    implementationVersion: number;
+	    numberOfStreams: number;
+	    streamDirectoryRva: string;
+	    checksum: string;
+	    timeDateStampUnix: number;
+	    timeDateStampUtc: string;
+	    flags: string;
+	    flagNames: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new HeaderInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.signature = source["signature"];
+	        this.signatureAscii = source["signatureAscii"];
+	        this.version = source["version"];
+	        this.formatVersion = source["formatVersion"];
+	        this.implementationVersion = source["implementationVersion"];
+	        this.numberOfStreams = source["numberOfStreams"];
+	        this.streamDirectoryRva = source["streamDirectoryRva"];
+	        this.checksum = source["checksum"];
+	        this.timeDateStampUnix = source["timeDateStampUnix"];
+	        this.timeDateStampUtc = source["timeDateStampUtc"];
+	        this.flags = source["flags"];
+	        this.flagNames = source["flagNames"];
+	    }
+	}
+	
+	
+	export class MemoryBlock {
+	    startAddress: string;
+	    endAddress: string;
+	    size: number;
+	    rva: string;
+	    preview: HexPreview;
+	
+	    static createFrom(source: any = {}) {
+	        return new MemoryBlock(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.startAddress = source["startAddress"];
+	        this.endAddress = source["endAddress"];
+	        this.size = source["size"];
+	        this.rva = source["rva"];
+	        this.preview = this.convertValues(source["preview"], HexPreview);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MemoryInfoEntry {
+	    index: number;
+	    baseAddress: string;
+	    allocationBase: string;
+	    allocationProtect: string;
+	    allocationProtectName: string;
+	    regionSize: number;
+	    state: string;
+	    stateName: string;
+	    protect: string;
+	    protectName: string;
+	    type: string;
+	    typeName: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MemoryInfoEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.baseAddress = source["baseAddress"];
+	        this.allocationBase = source["allocationBase"];
+	        this.allocationProtect = source["allocationProtect"];
+	        this.allocationProtectName = source["allocationProtectName"];
+	        this.regionSize = source["regionSize"];
+	        this.state = source["state"];
+	        this.stateName = source["stateName"];
+	        this.protect = source["protect"];
+	        this.protectName = source["protectName"];
+	        this.type = source["type"];
+	        this.typeName = source["typeName"];
+	    }
+	}
+	export class MemoryRange {
+	    index: number;
+	    source: string;
+	    startAddress: string;
+	    endAddress: string;
+	    size: number;
+	    rva: string;
+	    preview: HexPreview;
+	
+	    static createFrom(source: any = {}) {
+	        return new MemoryRange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.source = source["source"];
+	        this.startAddress = source["startAddress"];
+	        this.endAddress = source["endAddress"];
+	        this.size = source["size"];
+	        this.rva = source["rva"];
+	        this.preview = this.convertValues(source["preview"], HexPreview);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class NamedValue {
+	    name: string;
+	    value: string;
+	    hex?: string;
+	    display?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new NamedValue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.value = source["value"];
+	        this.hex = source["hex"];
+	        this.display = source["display"];
+	    }
+	}
+	export class MiscInfo {
+	    sizeOfInfo: number;
+	    flags1: string;
+	    flagNames: string[];
+	    processId: number;
+	    processCreateTimeUnix: number;
+	    processCreateTimeUtc: string;
+	    processUserTimeSeconds: number;
+	    processKernelTimeSeconds: number;
+	    processorMaxMhz: number;
+	    processorCurrentMhz: number;
+	    processorMhzLimit: number;
+	    processorMaxIdleState: number;
+	    processorCurrentIdleState: number;
+	    rawFields: NamedValue[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MiscInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sizeOfInfo = source["sizeOfInfo"];
+	        this.flags1 = source["flags1"];
+	        this.flagNames = source["flagNames"];
+	        this.processId = source["processId"];
+	        this.processCreateTimeUnix = source["processCreateTimeUnix"];
+	        this.processCreateTimeUtc = source["processCreateTimeUtc"];
+	        this.processUserTimeSeconds = source["processUserTimeSeconds"];
+	        this.processKernelTimeSeconds = source["processKernelTimeSeconds"];
+	        this.processorMaxMhz = source["processorMaxMhz"];
+	        this.processorCurrentMhz = source["processorCurrentMhz"];
+	        this.processorMhzLimit = source["processorMhzLimit"];
+	        this.processorMaxIdleState = source["processorMaxIdleState"];
+	        this.processorCurrentIdleState = source["processorCurrentIdleState"];
+	        this.rawFields = this.convertValues(source["rawFields"], NamedValue);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ModuleHit {
+	    index: number;
+	    path: string;
+	    fileName: string;
+	    baseAddress: string;
+	    sizeOfImage: number;
+	    offset: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModuleHit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.path = source["path"];
+	        this.fileName = source["fileName"];
+	        this.baseAddress = source["baseAddress"];
+	        this.sizeOfImage = source["sizeOfImage"];
+	        this.offset = source["offset"];
+	    }
+	}
+	export class VersionInfo {
+	    signature: string;
+	    structVersion: string;
+	    fileVersion: string;
+	    productVersion: string;
+	    fileFlagsMask: string;
+	    fileFlags: string;
+	    fileOs: string;
+	    fileType: string;
+	    fileTypeName: string;
+	    fileSubtype: string;
+	    fileDate: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new VersionInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.signature = source["signature"];
+	        this.structVersion = source["structVersion"];
+	        this.fileVersion = source["fileVersion"];
+	        this.productVersion = source["productVersion"];
+	        this.fileFlagsMask = source["fileFlagsMask"];
+	        this.fileFlags = source["fileFlags"];
+	        this.fileOs = source["fileOs"];
+	        this.fileType = source["fileType"];
+	        this.fileTypeName = source["fileTypeName"];
+	        this.fileSubtype = source["fileSubtype"];
+	        this.fileDate = source["fileDate"];
+	    }
+	}
+	export class ModuleInfo {
+	    index: number;
+	    baseAddress: string;
+	    endAddress: string;
+	    sizeOfImage: number;
+	    checksum: string;
+	    timeDateStampUnix: number;
+	    timeDateStampUtc: string;
+	    path: string;
+	    fileName: string;
+	    version: VersionInfo;
+	    codeView?: CodeViewInfo;
+	    cvRecord: Location;
+	    miscRecord: Location;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModuleInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.baseAddress = source["baseAddress"];
+	        this.endAddress = source["endAddress"];
+	        this.sizeOfImage = source["sizeOfImage"];
+	        this.checksum = source["checksum"];
+	        this.timeDateStampUnix = source["timeDateStampUnix"];
+	        this.timeDateStampUtc = source["timeDateStampUtc"];
+	        this.path = source["path"];
+	        this.fileName = source["fileName"];
+	        this.version = this.convertValues(source["version"], VersionInfo);
+	        this.codeView = this.convertValues(source["codeView"], CodeViewInfo);
+	        this.cvRecord = this.convertValues(source["cvRecord"], Location);
+	        this.miscRecord = this.convertValues(source["miscRecord"], Location);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class ExceptionInfo {
-	    threadId: number;
-	    code: string;
-	    codeName: string;
-	    flags: string;
-	    record: string;
-	    address: string;
-	    numberParameters: number;
-	    parameters: string[];
-	    contextDescriptor: Location;
-	    context: ContextInfo;
+	
+	export class RawFieldStream {
+	    size: number;
+	    fields: NamedValue[];
+	    preview: HexPreview;
 	
 	    static createFrom(source: any = {}) {
-	        return new ExceptionInfo(source);
+	        return new RawFieldStream(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.threadId = source["threadId"];
-	        this.code = source["code"];
-	        this.codeName = source["codeName"];
-	        this.flags = source["flags"];
-	        this.record = source["record"];
-	        this.address = source["address"];
-	        this.numberParameters = source["numberParameters"];
-	        this.parameters = source["parameters"];
-	        this.contextDescriptor = this.convertValues(source["contextDescriptor"], Location);
-	        this.context = this.convertValues(source["context"], ContextInfo);
+	        this.size = source["size"];
+	        this.fields = this.convertValues(source["fields"], NamedValue);
+	        this.preview = this.convertValues(source["preview"], HexPreview);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1624,78 +3041,224 @@ export namespace minidump {
 		    return a;
 		}
 	}
-	export class FileInfo {
-	    path: string;
+	export class ThreadName {
+	    threadId: number;
 	    name: string;
-	    size: number;
-	    lastModified: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new FileInfo(source);
+	        return new ThreadName(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.path = source["path"];
+	        this.threadId = source["threadId"];
 	        this.name = source["name"];
-	        this.size = source["size"];
-	        this.lastModified = source["lastModified"];
 	    }
 	}
-	export class HeaderInfo {
-	    signature: string;
-	    signatureAscii: string;
-	    version: string;
-	    formatVersion: number;
-	    implementationVersion: number;
-	    numberOfStreams: number;
-	    streamDirectoryRva: string;
-	    checksum: string;
-	    timeDateStampUnix: number;
-	    timeDateStampUtc: string;
-	    flags: string;
-	    flagNames: string[];
+	export class ThreadState {
+	    dumpFlags: string;
+	    dumpFlagNames: string[];
+	    dumpError: string;
+	    exitStatus: string;
+	    createTimeUtc: string;
+	    exitTimeUtc: string;
+	    kernelTime100ns: string;
+	    userTime100ns: string;
+	    startAddress: string;
+	    affinity: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new HeaderInfo(source);
+	        return new ThreadState(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.signature = source["signature"];
-	        this.signatureAscii = source["signatureAscii"];
-	        this.version = source["version"];
-	        this.formatVersion = source["formatVersion"];
-	        this.implementationVersion = source["implementationVersion"];
-	        this.numberOfStreams = source["numberOfStreams"];
-	        this.streamDirectoryRva = source["streamDirectoryRva"];
-	        this.checksum = source["checksum"];
-	        this.timeDateStampUnix = source["timeDateStampUnix"];
-	        this.timeDateStampUtc = source["timeDateStampUtc"];
-	        this.flags = source["flags"];
-	        this.flagNames = source["flagNames"];
+	        this.dumpFlags = source["dumpFlags"];
+	        this.dumpFlagNames = source["dumpFlagNames"];
+	        this.dumpError = source["dumpError"];
+	        this.exitStatus = source["exitStatus"];
+	        this.createTimeUtc = source["createTimeUtc"];
+	        this.exitTimeUtc = source["exitTimeUtc"];
+	        this.kernelTime100ns = source["kernelTime100ns"];
+	        this.userTime100ns = source["userTime100ns"];
+	        this.startAddress = source["startAddress"];
+	        this.affinity = source["affinity"];
 	    }
 	}
+	export class ThreadInfo {
+	    threadId: number;
+	    name: string;
+	    suspendCount: number;
+	    priorityClass: number;
+	    priority: number;
+	    teb: string;
+	    stack: MemoryBlock;
+	    context: ContextInfo;
+	    threadState?: ThreadState;
 	
+	    static createFrom(source: any = {}) {
+	        return new ThreadInfo(source);
+	    }
 	
-	export class MemoryBlock {
-	    startAddress: string;
-	    endAddress: string;
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.threadId = source["threadId"];
+	        this.name = source["name"];
+	        this.suspendCount = source["suspendCount"];
+	        this.priorityClass = source["priorityClass"];
+	        this.priority = source["priority"];
+	        this.teb = source["teb"];
+	        this.stack = this.convertValues(source["stack"], MemoryBlock);
+	        this.context = this.convertValues(source["context"], ContextInfo);
+	        this.threadState = this.convertValues(source["threadState"], ThreadState);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SystemInfo {
+	    processorArchitecture: string;
+	    architectureName: string;
+	    processorLevel: number;
+	    processorRevision: string;
+	    numberOfProcessors: number;
+	    productType: number;
+	    productTypeName: string;
+	    majorVersion: number;
+	    minorVersion: number;
+	    buildNumber: number;
+	    platformId: number;
+	    platformName: string;
+	    csdVersion: string;
+	    suiteMask: string;
+	    cpuVendor: string;
+	    cpuVersion: string;
+	    cpuFeatures: string;
+	    amdExtendedFeatures: string;
+	    processorFeatures: NamedValue[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SystemInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.processorArchitecture = source["processorArchitecture"];
+	        this.architectureName = source["architectureName"];
+	        this.processorLevel = source["processorLevel"];
+	        this.processorRevision = source["processorRevision"];
+	        this.numberOfProcessors = source["numberOfProcessors"];
+	        this.productType = source["productType"];
+	        this.productTypeName = source["productTypeName"];
+	        this.majorVersion = source["majorVersion"];
+	        this.minorVersion = source["minorVersion"];
+	        this.buildNumber = source["buildNumber"];
+	        this.platformId = source["platformId"];
+	        this.platformName = source["platformName"];
+	        this.csdVersion = source["csdVersion"];
+	        this.suiteMask = source["suiteMask"];
+	        this.cpuVendor = source["cpuVendor"];
+	        this.cpuVersion = source["cpuVersion"];
+	        this.cpuFeatures = source["cpuFeatures"];
+	        this.amdExtendedFeatures = source["amdExtendedFeatures"];
+	        this.processorFeatures = this.convertValues(source["processorFeatures"], NamedValue);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StreamInfo {
+	    index: number;
+	    type: number;
+	    name: string;
 	    size: number;
 	    rva: string;
-	    preview: HexPreview;
+	    end: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new MemoryBlock(source);
+	        return new StreamInfo(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.startAddress = source["startAddress"];
-	        this.endAddress = source["endAddress"];
+	        this.index = source["index"];
+	        this.type = source["type"];
+	        this.name = source["name"];
 	        this.size = source["size"];
 	        this.rva = source["rva"];
-	        this.preview = this.convertValues(source["preview"], HexPreview);
+	        this.end = source["end"];
+	    }
+	}
+	export class Report {
+	    file: FileInfo;
+	    header: HeaderInfo;
+	    streams: StreamInfo[];
+	    system?: SystemInfo;
+	    misc?: MiscInfo;
+	    exception?: ExceptionInfo;
+	    exceptionModule?: ModuleHit;
+	    threads: ThreadInfo[];
+	    threadNames: ThreadName[];
+	    modules: ModuleInfo[];
+	    memoryRanges: MemoryRange[];
+	    memoryInfo: MemoryInfoEntry[];
+	    systemMemory?: RawFieldStream;
+	    processVmCounters?: RawFieldStream;
+	    comments: CommentInfo[];
+	    parseWarnings: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Report(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.file = this.convertValues(source["file"], FileInfo);
+	        this.header = this.convertValues(source["header"], HeaderInfo);
+	        this.streams = this.convertValues(source["streams"], StreamInfo);
+	        this.system = this.convertValues(source["system"], SystemInfo);
+	        this.misc = this.convertValues(source["misc"], MiscInfo);
+	        this.exception = this.convertValues(source["exception"], ExceptionInfo);
+	        this.exceptionModule = this.convertValues(source["exceptionModule"], ModuleHit);
+	        this.threads = this.convertValues(source["threads"], ThreadInfo);
+	        this.threadNames = this.convertValues(source["threadNames"], ThreadName);
+	        this.modules = this.convertValues(source["modules"], ModuleInfo);
+	        this.memoryRanges = this.convertValues(source["memoryRanges"], MemoryRange);
+	        this.memoryInfo = this.convertValues(source["memoryInfo"], MemoryInfoEntry);
+	        this.systemMemory = this.convertValues(source["systemMemory"], RawFieldStream);
+	        this.processVmCounters = this.convertValues(source["processVmCounters"], RawFieldStream);
+	        this.comments = this.convertValues(source["comments"], CommentInfo);
+	        this.parseWarnings = source["parseWarnings"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1716,62 +3279,40 @@ export namespace minidump {
 		    return a;
 		}
 	}
-	export class MemoryInfoEntry {
-	    index: number;
-	    baseAddress: string;
-	    allocationBase: string;
-	    allocationProtect: string;
-	    allocationProtectName: string;
-	    regionSize: number;
-	    state: string;
-	    stateName: string;
-	    protect: string;
-	    protectName: string;
-	    type: string;
-	    typeName: string;
 	
-	    static createFrom(source: any = {}) {
-	        return new MemoryInfoEntry(source);
-	    }
 	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.index = source["index"];
-	        this.baseAddress = source["baseAddress"];
-	        this.allocationBase = source["allocationBase"];
-	        this.allocationProtect = source["allocationProtect"];
-	        this.allocationProtectName = source["allocationProtectName"];
-	        this.regionSize = source["regionSize"];
-	        this.state = source["state"];
-	        this.stateName = source["stateName"];
-	        this.protect = source["protect"];
-	        this.protectName = source["protectName"];
-	        this.type = source["type"];
-	        this.typeName = source["typeName"];
-	    }
-	}
-	export class MemoryRange {
-	    index: number;
-	    source: string;
-	    startAddress: string;
-	    endAddress: string;
-	    size: number;
-	    rva: string;
-	    preview: HexPreview;
+	
+	
+	
+
+}
+
+export namespace network {
+	
+	export class IPHealthRecord {
+	    ip: string;
+	    successes: number;
+	    failures: number;
+	    consecutive_failures: number;
+	    avg_speed: number;
+	    // Go type: time
+	    last_success?: any;
+	    // Go type: time
+	    last_failure?: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new MemoryRange(source);
+	        return new IPHealthRecord(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.index = source["index"];
-	        this.source = source["source"];
-	        this.startAddress = source["startAddress"];
-	        this.endAddress = source["endAddress"];
-	        this.size = source["size"];
-	        this.rva = source["rva"];
-	        this.preview = this.convertValues(source["preview"], HexPreview);
+	        this.ip = source["ip"];
+	        this.successes = source["successes"];
+	        this.failures = source["failures"];
+	        this.consecutive_failures = source["consecutive_failures"];
+	        this.avg_speed = source["avg_speed"];
+	        this.last_success = this.convertValues(source["last_success"], null);
+	        this.last_failure = this.convertValues(source["last_failure"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1792,168 +3333,131 @@ export namespace minidump {
 		    return a;
 		}
 	}
-	export class NamedValue {
-	    name: string;
-	    value: string;
-	    hex?: string;
-	    display?: string;
+	export class IPOption {
+	    ip: string;
+	    category: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new NamedValue(source);
+	        return new IPOption(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.value = source["value"];
-	        this.hex = source["hex"];
-	        this.display = source["display"];
+	        this.ip = source["ip"];
+	        this.category = source["category"];
 	    }
 	}
-	export class MiscInfo {
-	    sizeOfInfo: number;
-	    flags1: string;
-	    flagNames: string[];
-	    processId: number;
-	    processCreateTimeUnix: number;
-	    processCreateTimeUtc: string;
-	    processUserTimeSeconds: number;
-	    processKernelTimeSeconds: number;
-	    processorMaxMhz: number;
-	    processorCurrentMhz: number;
-	    processorMhzLimit: number;
-	    processorMaxIdleState: number;
-	    processorCurrentIdleState: number;
-	    rawFields: NamedValue[];
+
+}
+
+export namespace parser {
+	
+	export class BSPChangeLevel {
+	    map: string;
+	    landmark: string;
+	    targetName: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new MiscInfo(source);
+	        return new BSPChangeLevel(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.sizeOfInfo = source["sizeOfInfo"];
-	        this.flags1 = source["flags1"];
-	        this.flagNames = source["flagNames"];
-	        this.processId = source["processId"];
-	        this.processCreateTimeUnix = source["processCreateTimeUnix"];
-	        this.processCreateTimeUtc = source["processCreateTimeUtc"];
-	        this.processUserTimeSeconds = source["processUserTimeSeconds"];
-	        this.processKernelTimeSeconds = source["processKernelTimeSeconds"];
-	        this.processorMaxMhz = source["processorMaxMhz"];
-	        this.processorCurrentMhz = source["processorCurrentMhz"];
-	        this.processorMhzLimit = source["processorMhzLimit"];
-	        this.processorMaxIdleState = source["processorMaxIdleState"];
-	        this.processorCurrentIdleState = source["processorCurrentIdleState"];
-	        this.rawFields = this.convertValues(source["rawFields"], NamedValue);
+	        this.map = source["map"];
+	        this.landmark = source["landmark"];
+	        this.targetName = source["targetName"];
 	    }
-	
-		convertValues(a: any, classs: any, asMap: boolean = false): any {
-		    if (!a) {
-		        return a;
-		    }
-		    if (a.slice && a.map) {
-		        return (a as any[]).map(elem => this.convertValues(elem, classs));
-		    } else if ("object" === typeof a) {
-		        if (asMap) {
-		            for (const key of Object.keys(a)) {
-		                a[key] = new classs(a[key]);
-		            }
-		            return a;
-		        }
-		        return new classs(a);
-		    }
-		    return a;
-		}
 	}
-	export class ModuleHit {
+	export class BSPLump {
 	    index: number;
-	    path: string;
-	    fileName: string;
-	    baseAddress: string;
-	    sizeOfImage: number;
-	    offset: string;
+	    name: string;
+	    version: number;
+	    offset: number;
+	    length: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new ModuleHit(source);
+	        return new BSPLump(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.index = source["index"];
-	        this.path = source["path"];
-	        this.fileName = source["fileName"];
-	        this.baseAddress = source["baseAddress"];
-	        this.sizeOfImage = source["sizeOfImage"];
+	        this.name = source["name"];
+	        this.version = source["version"];
 	        this.offset = source["offset"];
+	        this.length = source["length"];
 	    }
 	}
-	export class VersionInfo {
-	    signature: string;
-	    structVersion: string;
-	    fileVersion: string;
-	    productVersion: string;
-	    fileFlagsMask: string;
-	    fileFlags: string;
-	    fileOs: string;
-	    fileType: string;
-	    fileTypeName: string;
-	    fileSubtype: string;
-	    fileDate: string;
+	export class BSPPakFile {
+	    name: string;
+	    size: number;
+	    compressedSize: number;
+	    crc: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new VersionInfo(source);
+	        return new BSPPakFile(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.signature = source["signature"];
-	        this.structVersion = source["structVersion"];
-	        this.fileVersion = source["fileVersion"];
-	        this.productVersion = source["productVersion"];
-	        this.fileFlagsMask = source["fileFlagsMask"];
-	        this.fileFlags = source["fileFlags"];
-	        this.fileOs = source["fileOs"];
-	        this.fileType = source["fileType"];
-	        this.fileTypeName = source["fileTypeName"];
-	        this.fileSubtype = source["fileSubtype"];
-	        this.fileDate = source["fileDate"];
+	        this.name = source["name"];
+	        this.size = source["size"];
+	        this.compressedSize = source["compressedSize"];
+	        this.crc = source["crc"];
 	    }
 	}
-	export class ModuleInfo {
-	    index: number;
-	    baseAddress: string;
-	    endAddress: string;
-	    sizeOfImage: number;
-	    checksum: string;
-	    timeDateStampUnix: number;
-	    timeDateStampUtc: string;
+	export class BSPPlayerStart {
+	    targetName: string;
+	    origin: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BSPPlayerStart(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.targetName = source["targetName"];
+	        this.origin = source["origin"];
+	    }
+	}
+	export class BSPMapInfo {
 	    path: string;
-	    fileName: string;
-	    version: VersionInfo;
-	    codeView?: CodeViewInfo;
-	    cvRecord: Location;
-	    miscRecord: Location;
+	    name: string;
+	    version: number;
+	    revision: number;
+	    size: number;
+	    lumps?: BSPLump[];
+	    entityCount: number;
+	    playerStarts: BSPPlayerStart[];
+	    changeLevels: BSPChangeLevel[];
+	    propModels?: string[];
+	    propModelCount: number;
+	    pakFiles?: BSPPakFile[];
+	    pakFileCount: number;
+	    pakFileSize: number;
+	    message?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new ModuleInfo(source);
+	        return new BSPMapInfo(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.index = source["index"];
-	        this.baseAddress = source["baseAddress"];
-	        this.endAddress = source["endAddress"];
-	        this.sizeOfImage = source["sizeOfImage"];
-	        this.checksum = source["checksum"];
-	        this.timeDateStampUnix = source["timeDateStampUnix"];
-	        this.timeDateStampUtc = source["timeDateStampUtc"];
 	        this.path = source["path"];
-	        this.fileName = source["fileName"];
-	        this.version = this.convertValues(source["version"], VersionInfo);
-	        this.codeView = this.convertValues(source["codeView"], CodeViewInfo);
-	        this.cvRecord = this.convertValues(source["cvRecord"], Location);
-	        this.miscRecord = this.convertValues(source["miscRecord"], Location);
+	        this.name = source["name"];
+	        this.version = source["version"];
+	        this.revision = source["revision"];
+	        this.size = source["size"];
+	        this.lumps = this.convertValues(source["lumps"], BSPLump);
+	        this.entityCount = source["entityCount"];
+	        this.playerStarts = this.convertValues(source["playerStarts"], BSPPlayerStart);
+	        this.changeLevels = this.convertValues(source["changeLevels"], BSPChangeLevel);
+	        this.propModels = source["propModels"];
+	        this.propModelCount = source["propModelCount"];
+	        this.pakFiles = this.convertValues(source["pakFiles"], BSPPakFile);
+	        this.pakFileCount = source["pakFileCount"];
+	        this.pakFileSize = source["pakFileSize"];
+	        this.message = source["message"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1975,20 +3479,63 @@ export namespace minidump {
 		}
 	}
 	
-	export class RawFieldStream {
-	    size: number;
-	    fields: NamedValue[];
-	    preview: HexPreview;
+	
+	export class ChapterInfo {
+	    title: string;
+	    modes: string[];
 	
 	    static createFrom(source: any = {}) {
-	        return new RawFieldStream(source);
+	        return new ChapterInfo(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.size = source["size"];
-	        this.fields = this.convertValues(source["fields"], NamedValue);
-	        this.preview = this.convertValues(source["preview"], HexPreview);
+	        this.title = source["title"];
+	        this.modes = source["modes"];
+	    }
+	}
+	export class TagMatch {
+	    tag: string;
+	    primary: boolean;
+	    source: string;
+	    ruleId?: string;
+	    priority: number;
+	    file?: string;
+	    pattern?: string;
+	    text?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TagMatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tag = source["tag"];
+	        this.primary = source["primary"];
+	        this.source = source["source"];
+	        this.ruleId = source["ruleId"];
+	        this.priority = source["priority"];
+	        this.file = source["file"];
+	        this.pattern = source["pattern"];
+	        this.text = source["text"];
+	    }
+	}
+	export class Classification {
+	    primaryTag: string;
+	    secondaryTags: string[];
+	    matches: TagMatch[];
+	    filenameOverride: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Classification(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.primaryTag = source["primaryTag"];
+	        this.secondaryTags = source["secondaryTags"];
+	        this.matches = this.convertValues(source["matches"], TagMatch);
+	        this.filenameOverride = source["filenameOverride"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -2009,76 +3556,180 @@ export namespace minidump {
 		    return a;
 		}
 	}
-	export class ThreadName {
-	    threadId: number;
+	export class ClassificationRule {
+	    id: string;
+	    primaryTag: string;
+	    secondaryTag?: string;
+	    priority: number;
+	    pathGlobs?: string[];
+	    excludeGlobs?: string[];
+	    metadataRegex?: string;
+	    exclusive?: boolean;
+	    allFiles?: boolean;
+	    disabled?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ClassificationRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.primaryTag = source["primaryTag"];
+	        this.secondaryTag = source["secondaryTag"];
+	        this.priority = source["priority"];
+	        this.pathGlobs = source["pathGlobs"];
+	        this.excludeGlobs = source["excludeGlobs"];
+	        this.metadataRegex = source["metadataRegex"];
+	        this.exclusive = source["exclusive"];
+	        this.allFiles = source["allFiles"];
+	        this.disabled = source["disabled"];
+	    }
+	}
+	export class MissionWarning {
+	    code: string;
+	    file?: string;
+	    chapter?: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MissionWarning(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.file = source["file"];
+	        this.chapter = source["chapter"];
+	        this.message = source["message"];
+	    }
+	}
+	export class ModelBodyGroup {
 	    name: string;
+	    models: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new ThreadName(source);
+	        return new ModelBodyGroup(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.threadId = source["threadId"];
 	        this.name = source["name"];
+	        this.models = source["models"];
 	    }
 	}
-	export class ThreadState {
-	    dumpFlags: string;
-	    dumpFlagNames: string[];
-	    dumpError: string;
-	    exitStatus: string;
-	    createTimeUtc: string;
-	    exitTimeUtc: string;
-	    kernelTime100ns: string;
-	    userTime100ns: string;
-	    startAddress: string;
-	    affinity: string;
+	export class ModelLODStat {
+	    lod: number;
+	    vertices: number;
+	    triangles: number;
+	    stripGroupCount: number;
+	    switchPoint: number;
+	    triangleStripEstimated: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new ThreadState(source);
+	        return new ModelLODStat(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.dumpFlags = source["dumpFlags"];
-	        this.dumpFlagNames = source["dumpFlagNames"];
-	        this.dumpError = source["dumpError"];
-	        this.exitStatus = source["exitStatus"];
-	        this.createTimeUtc = source["createTimeUtc"];
-	        this.exitTimeUtc = source["exitTimeUtc"];
-	        this.kernelTime100ns = source["kernelTime100ns"];
-	        this.userTime100ns = source["userTime100ns"];
-	        this.startAddress = source["startAddress"];
-	        this.affinity = source["affinity"];
+	        this.lod = source["lod"];
+	        this.vertices = source["vertices"];
+	        this.triangles = source["triangles"];
+	        this.stripGroupCount = source["stripGroupCount"];
+	        this.switchPoint = source["switchPoint"];
+	        this.triangleStripEstimated = source["triangleStripEstimated"];
 	    }
 	}
-	export class ThreadInfo {
-	    threadId: number;
-	    name: string;
-	    suspendCount: number;
-	    priorityClass: number;
-	    priority: number;
-	    teb: string;
-	    stack: MemoryBlock;
-	    context: ContextInfo;
-	    threadState?: ThreadState;
+	export class ModelStripGroupStat {
+	    bodyPart: number;
+	    model: number;
+	    mesh: number;
+	    stripGroup: number;
+	    vertices: number;
+	    indices: number;
+	    strips: number;
+	    triangles: number;
+	    triangleStripEstimated: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new ThreadInfo(source);
+	        return new ModelStripGroupStat(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.threadId = source["threadId"];
+	        this.bodyPart = source["bodyPart"];
+	        this.model = source["model"];
+	        this.mesh = source["mesh"];
+	        this.stripGroup = source["stripGroup"];
+	        this.vertices = source["vertices"];
+	        this.indices = source["indices"];
+	        this.strips = source["strips"];
+	        this.triangles = source["triangles"];
+	        this.triangleStripEstimated = source["triangleStripEstimated"];
+	    }
+	}
+	export class ModelStat {
+	    path: string;
+	    vvdPath?: string;
+	    vtxPath?: string;
+	    name?: string;
+	    mdlVersion?: number;
+	    bones: number;
+	    attachments: number;
+	    hitboxes: number;
+	    hitboxSets: number;
+	    bodyGroups: ModelBodyGroup[];
+	    skinFamilies: number;
+	    materialPaths: string[];
+	    materials: string[];
+	    textures: number;
+	    textureMemory: number;
+	    lod: number;
+	    vertices: number;
+	    triangles: number;
+	    stripGroupVertices: number;
+	    stripGroupIndices: number;
+	    maxStripGroupVertices: number;
+	    maxStripGroupIndices: number;
+	    stripGroupCount: number;
+	    triangleStripEstimated: boolean;
+	    stripGroups: ModelStripGroupStat[];
+	    lods: ModelLODStat[];
+	    message?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.vvdPath = source["vvdPath"];
+	        this.vtxPath = source["vtxPath"];
 	        this.name = source["name"];
-	        this.suspendCount = source["suspendCount"];
-	        this.priorityClass = source["priorityClass"];
-	        this.priority = source["priority"];
-	        this.teb = source["teb"];
-	        this.stack = this.convertValues(source["stack"], MemoryBlock);
-	        this.context = this.convertValues(source["context"], ContextInfo);
-	        this.threadState = this.convertValues(source["threadState"], ThreadState);
+	        this.mdlVersion = source["mdlVersion"];
+	        this.bones = source["bones"];
+	        this.attachments = source["attachments"];
+	        this.hitboxes = source["hitboxes"];
+	        this.hitboxSets = source["hitboxSets"];
+	        this.bodyGroups = this.convertValues(source["bodyGroups"], ModelBodyGroup);
+	        this.skinFamilies = source["skinFamilies"];
+	        this.materialPaths = source["materialPaths"];
+	        this.materials = source["materials"];
+	        this.textures = source["textures"];
+	        this.textureMemory = source["textureMemory"];
+	        this.lod = source["lod"];
+	        this.vertices = source["vertices"];
+	        this.triangles = source["triangles"];
+	        this.stripGroupVertices = source["stripGroupVertices"];
+	        this.stripGroupIndices = source["stripGroupIndices"];
+	        this.maxStripGroupVertices = source["maxStripGroupVertices"];
+	        this.maxStripGroupIndices = source["maxStripGroupIndices"];
+	        this.stripGroupCount = source["stripGroupCount"];
+	        this.triangleStripEstimated = source["triangleStripEstimated"];
+	        this.stripGroups = this.convertValues(source["stripGroups"], ModelStripGroupStat);
+	        this.lods = this.convertValues(source["lods"], ModelLODStat);
+	        this.message = source["message"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -2099,52 +3750,63 @@ export namespace minidump {
 		    return a;
 		}
 	}
-	export class SystemInfo {
-	    processorArchitecture: string;
-	    architectureName: string;
-	    processorLevel: number;
-	    processorRevision: string;
-	    numberOfProcessors: number;
-	    productType: number;
-	    productTypeName: string;
-	    majorVersion: number;
-	    minorVersion: number;
-	    buildNumber: number;
-	    platformId: number;
-	    platformName: string;
-	    csdVersion: string;
-	    suiteMask: string;
-	    cpuVendor: string;
-	    cpuVersion: string;
-	    cpuFeatures: string;
-	    amdExtendedFeatures: string;
-	    processorFeatures: NamedValue[];
+	
+	export class SoundScriptOverride {
+	    file: string;
+	    entries: string[];
+	    error?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new SystemInfo(source);
+	        return new SoundScriptOverride(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.processorArchitecture = source["processorArchitecture"];
-	        this.architectureName = source["architectureName"];
-	        this.processorLevel = source["processorLevel"];
-	        this.processorRevision = source["processorRevision"];
-	        this.numberOfProcessors = source["numberOfProcessors"];
-	        this.productType = source["productType"];
-	        this.productTypeName = source["productTypeName"];
-	        this.majorVersion = source["majorVersion"];
-	        this.minorVersion = source["minorVersion"];
-	        this.buildNumber = source["buildNumber"];
-	        this.platformId = source["platformId"];
-	        this.platformName = source["platformName"];
-	        this.csdVersion = source["csdVersion"];
-	        this.suiteMask = source["suiteMask"];
-	        this.cpuVendor = source["cpuVendor"];
-	        this.cpuVersion = source["cpuVersion"];
-	        this.cpuFeatures = source["cpuFeatures"];
-	        this.amdExtendedFeatures = source["amdExtendedFeatures"];
-	        this.processorFeatures = this.convertValues(source["processorFeatures"], NamedValue);
+	        this.file = source["file"];
+	        this.entries = source["entries"];
+	        this.error = source["error"];
+	    }
+	}
+	export class SoundFileInfo {
+	    path: string;
+	    format: string;
+	    codec?: string;
+	    duration: number;
+	    sampleRate: number;
+	    channels: number;
+	    bitsPerSample?: number;
+	    bitrate?: number;
+	    warnings?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SoundFileInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.format = source["format"];
+	        this.codec = source["codec"];
+	        this.duration = source["duration"];
+	        this.sampleRate = source["sampleRate"];
+	        this.channels = source["channels"];
+	        this.bitsPerSample = source["bitsPerSample"];
+	        this.bitrate = source["bitrate"];
+	        this.warnings = source["warnings"];
+	    }
+	}
+	export class SoundDetails {
+	    files: SoundFileInfo[];
+	    soundScripts: SoundScriptOverride[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SoundDetails(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.files = this.convertValues(source["files"], SoundFileInfo);
+	        this.soundScripts = this.convertValues(source["soundScripts"], SoundScriptOverride);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -2165,68 +3827,40 @@ export namespace minidump {
 		    return a;
 		}
 	}
-	export class StreamInfo {
-	    index: number;
-	    type: number;
-	    name: string;
-	    size: number;
-	    rva: string;
-	    end: string;
+	export class SoundDirectoryCount {
+	    dir: string;
+	    count: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new StreamInfo(source);
+	        return new SoundDirectoryCount(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.index = source["index"];
-	        this.type = source["type"];
-	        this.name = source["name"];
-	        this.size = source["size"];
-	        this.rva = source["rva"];
-	        this.end = source["end"];
+	        this.dir = source["dir"];
+	        this.count = source["count"];
 	    }
 	}
-	export class Report {
-	    file: FileInfo;
-	    header: HeaderInfo;
-	    streams: StreamInfo[];
-	    system?: SystemInfo;
-	    misc?: MiscInfo;
-	    exception?: ExceptionInfo;
-	    exceptionModule?: ModuleHit;
-	    threads: ThreadInfo[];
-	    threadNames: ThreadName[];
-	    modules: ModuleInfo[];
-	    memoryRanges: MemoryRange[];
-	    memoryInfo: MemoryInfoEntry[];
-	    systemMemory?: RawFieldStream;
-	    processVmCounters?: RawFieldStream;
-	    comments: CommentInfo[];
-	    parseWarnings: string[];
+	
+	
+	export class SoundSummary {
+	    fileCount: number;
+	    totalDuration: number;
+	    warningCount: number;
+	    scriptCount: number;
+	    directories: SoundDirectoryCount[];
 	
 	    static createFrom(source: any = {}) {
-	        return new Report(source);
+	        return new SoundSummary(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.file = this.convertValues(source["file"], FileInfo);
-	        this.header = this.convertValues(source["header"], HeaderInfo);
-	        this.streams = this.convertValues(source["streams"], StreamInfo);
-	        this.system = this.convertValues(source["system"], SystemInfo);
-	        this.misc = this.convertValues(source["misc"], MiscInfo);
-	        this.exception = this.convertValues(source["exception"], ExceptionInfo);
-	        this.exceptionModule = this.convertValues(source["exceptionModule"], ModuleHit);
-	        this.threads = this.convertValues(source["threads"], ThreadInfo);
-	        this.threadNames = this.convertValues(source["threadNames"], ThreadName);
-	        this.modules = this.convertValues(source["modules"], ModuleInfo);
-	        this.memoryRanges = this.convertValues(source["memoryRanges"], MemoryRange);
-	        this.memoryInfo = this.convertValues(source["memoryInfo"], MemoryInfoEntry);
-	        this.systemMemory = this.convertValues(source["systemMemory"], RawFieldStream);
-	        this.processVmCounters = this.convertValues(source["processVmCounters"], RawFieldStream);
-	        this.comments = this.convertValues(source["comments"], CommentInfo);
-	        this.parseWarnings = source["parseWarnings"];
+	        this.fileCount = source["fileCount"];
+	        this.totalDuration = source["totalDuration"];
+	        this.warningCount = source["warningCount"];
+	        this.scriptCount = source["scriptCount"];
+	        this.directories = this.convertValues(source["directories"], SoundDirectoryCount);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -2248,48 +3882,6 @@ export namespace minidump {
 		}
 	}
 	
-	
-	
-	
-	
-
-}
-
-export namespace network {
-	
-	export class IPOption {
-	    ip: string;
-	    category: string;
-	
-	    static createFrom(source: any = {}) {
-	        return new IPOption(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.ip = source["ip"];
-	        this.category = source["category"];
-	    }
-	}
-
-}
-
-export namespace parser {
-	
-	export class ChapterInfo {
-	    title: string;
-	    modes: string[];
-	
-	    static createFrom(source: any = {}) {
-	        return new ChapterInfo(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.title = source["title"];
-	        this.modes = source["modes"];
-	    }
-	}
 	export class VPKFile {
 	    name: string;
 	    path: string;
@@ -2310,6 +3902,9 @@ export namespace parser {
 	    addonURL0: string;
 	    workshopId: string;
 	    hasUpdate: boolean;
+	    sound?: SoundSummary;
+	    maps?: BSPMapInfo[];
+	    missionWarnings?: MissionWarning[];
 	
 	    static createFrom(source: any = {}) {
 	        return new VPKFile(source);
@@ -2336,6 +3931,9 @@ export namespace parser {
 	        this.addonURL0 = source["addonURL0"];
 	        this.workshopId = source["workshopId"];
 	        this.hasUpdate = source["hasUpdate"];
+	        this.sound = this.convertValues(source["sound"], SoundSummary);
+	        this.maps = this.convertValues(source["maps"], BSPMapInfo);
+	        this.missionWarnings = this.convertValues(source["missionWarnings"], MissionWarning);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -2356,6 +3954,46 @@ export namespace parser {
 		    return a;
 		}
 	}
+	export class VTFTextureInfo {
+	    path: string;
+	    version?: string;
+	    width: number;
+	    height: number;
+	    depth: number;
+	    frames: number;
+	    faces: number;
+	    mipCount: number;
+	    format?: string;
+	    flags: number;
+	    fileSize: number;
+	    memorySize: number;
+	    decodable: boolean;
+	    previewUrl: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new VTFTextureInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.version = source["version"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.depth = source["depth"];
+	        this.frames = source["frames"];
+	        this.faces = source["faces"];
+	        this.mipCount = source["mipCount"];
+	        this.format = source["format"];
+	        this.flags = source["flags"];
+	        this.fileSize = source["fileSize"];
+	        this.memorySize = source["memorySize"];
+	        this.decodable = source["decodable"];
+	        this.previewUrl = source["previewUrl"];
+	        this.error = source["error"];
+	    }
+	}
 
 }
 
@@ -3,8 +3,6 @@ package app
 import (
 	"context"
 	"log"
-	"net"
-	"net/http"
 	"os"
 	"path/filepath"
 	rt "runtime"
@@ -24,6 +22,9 @@ type VPKFile = parser.VPKFile
 // IPOption 类型别名,用于Wails绑定
 type IPOption = network.IPOption
 
+// IPHealthRecord 类型别名,用于Wails绑定
+type IPHealthRecord = network.IPHealthRecord
+
 // ServerInfo 服务器信息
 type ServerInfo struct {
 	Name       string `json:"name"`
@@ -60,21 +61,30 @@ type VPKFileCache struct {
 
 // App struct
 type App struct {
-	ctx                    context.Context
-	vpkCache               sync.Map // map[string]*VPKFileCache, key是文件路径
-	mu                     sync.RWMutex
-	rootDir                string
-	goroutinePool          *ants.Pool
-	conflictCheckMu        sync.Mutex
-	modelStatsScanMu       sync.Mutex
-	modelStatsScanRunning  bool
-	modelStatsScanID       string
-	modelStatsScanRoot     string
-	modelStatsScanProgress ProgressInfo
-	forceClose             bool
-	restyClient            *resty.Client
-	proxyServer            *network.ImageProxyServer
-	singletonMgr           *SingletonManager // 单例管理器
+	ctx                      context.Context
+	vpkCache                 sync.Map // map[string]*VPKFileCache, key是文件路径
+	mu                       sync.RWMutex
+	rootDir                  string
+	goroutinePool            *ants.Pool
+	conflictCheckMu          sync.Mutex
+	modelStatsScanMu         sync.Mutex
+	modelStatsScanRunning    bool
+	modelStatsScanID         string
+	modelStatsScanRoot       string
+	modelStatsScanProgress   ProgressInfo
+	missingAssetScanMu       sync.Mutex
+	missingAssetScanRunning  bool
+	missingAssetScanID       string
+	missingAssetScanRoot     string
+	missingAssetScanProgress ProgressInfo
+	forceClose               bool
+	restyClient              *resty.Client
+	proxyServer              *network.ImageProxyServer
+	previewServer            *previewImageServer
+	pathIndex                *vpkPathIndex     // VPK 内部文件路径索引
+	singletonMgr             *SingletonManager // 单例管理器
+	addonWatcher             *addonWatcher     // 目录监听，根目录变化时重建
+	watchIgnores             watchIgnoreSet    // 应用自身正在写入、监听时忽略的路径
 
 	// 配置项
 	modRotationConfig              RotationConfig
@@ -87,9 +97,13 @@ type App struct {
 	workshopTranslateCustomBaseURL string
 	workshopTranslateCustomAPIKey  string
 	workshopTranslateCustomModelId string
+	endpoints                      EndpointConfig
+	proxySettings                  ProxySettings
+	modelBudgets                   *ModelBudgets // nil 表示使用默认预算
 	migrationVersion               int
 	defaultDirectory               string
 	savedDirectories               []SavedDirectory
+	smartCollections               []SmartCollection
 	lastActiveDirectory            string
 	displayMode                    string
 	filterLayoutMode               string
@@ -117,6 +131,9 @@ type ConfigFile struct {
 	WorkshopTranslateCustomBaseURL string           `json:"workshopTranslateCustomBaseURL,omitempty"`
 	WorkshopTranslateCustomAPIKey  string           `json:"workshopTranslateCustomAPIKey,omitempty"`
 	WorkshopTranslateCustomModelId string           `json:"workshopTranslateCustomModelId,omitempty"`
+	Endpoints                      EndpointConfig   `json:"endpoints"`
+	Proxy                          *ProxySettings   `json:"proxy,omitempty"`
+	ModelBudgets                   *ModelBudgets    `json:"modelBudgets,omitempty"`
 	DefaultDirectory               string           `json:"defaultDirectory"`
 	SavedDirectories               []SavedDirectory `json:"savedDirectories"`
 	LastActiveDirectory            string           `json:"lastActiveDirectory"`
@@ -129,6 +146,8 @@ type ConfigFile struct {
 	LastUpdateCheckTime            string           `json:"lastUpdateCheckTime"`
 	// migrationVersion=2 表示前端 localStorage 配置已迁移到配置目录。
 	MigrationVersion int `json:"migrationVersion"`
+	// 智能合集，通过 SaveSmartCollection/DeleteSmartCollection 修改
+	SmartCollections []SmartCollection `json:"smartCollections,omitempty"`
 }
 
 // RotationConfig Mod轮换配置
@@ -200,19 +219,10 @@ func NewApp() *App {
 
 	pool, _ := ants.NewPool(cores) // 创建协程池
 
-	// 初始化 Resty 客户端（强制 IPv4，避免伪 IPv6 导致连接失败）
-	ipv4Dialer := &net.Dialer{
-		Timeout:   30 * time.Second,
-		KeepAlive: 30 * time.Second,
-	}
-	ipv4Transport := &http.Transport{
-		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
-			return ipv4Dialer.DialContext(ctx, "tcp4", addr)
-		},
-	}
+	// 初始化 Resty 客户端（双栈，由 Happy Eyeballs 在 IPv6 不可用时回退 IPv4；遵循全局代理配置）
 	client := resty.New()
 	client.SetTimeout(2 * time.Second)
-	client.SetTransport(ipv4Transport)
+	client.SetTransport(network.NewTransport(network.TransportOptions{}))
 
 	// 启动本地图片代理
 	proxy := network.NewImageProxyServer(network.GlobalIPSelector)
@@ -227,6 +237,11 @@ func NewApp() *App {
 	workshopWatchLaterPath := filepath.Join(appConfigDir, "workshop_watch_later.json")
 	problemScanPath := filepath.Join(appConfigDir, "problem_mod_scan.json")
 
+	// 加载CDN IP历史健康记录，优选时跳过已知不可用的IP
+	if err := network.GlobalIPHealth.Load(filepath.Join(appConfigDir, "ip_health.json")); err != nil {
+		log.Printf("加载IP健康记录失败: %v", err)
+	}
+
 	app := &App{
 		goroutinePool:             pool,
 		restyClient:               client,
@@ -240,6 +255,7 @@ func NewApp() *App {
 		workshopMetaEnabled:       true,     // 默认开启工坊meta信息存储
 		workshopBrowserTarget:     "mirror", // 默认使用镜像站
 		workshopTranslateProvider: workshopTranslateProviderMicrosoft,
+		proxySettings:             defaultProxySettings(),
 		displayMode:               "list",
 		filterLayoutMode:          "compact",
 		boxSelectionEnabled:       true,
@@ -247,8 +263,23 @@ func NewApp() *App {
 		savedDirectories:          []SavedDirectory{},
 	}
 
+	// 启动本地预览图服务，列表中的预览图按需加载
+	app.previewServer = newPreviewImageServer(app, filepath.Join(appConfigDir, "preview_cache"))
+	if err := app.previewServer.Start(); err != nil {
+		log.Printf("启动预览图服务失败: %v", err)
+	}
+
+	// 加载 VPK 内部文件索引，扫描时增量更新
+	app.pathIndex = newVPKPathIndex()
+	if err := app.pathIndex.load(filepath.Join(appConfigDir, "vpk_path_index.json.gz")); err != nil {
+		log.Printf("加载文件索引失败: %v", err)
+	}
+
 	// 加载配置
 	app.loadConfig()
 
+	// 加载用户分类规则
+	app.loadClassificationRules()
+
 	return app
 }
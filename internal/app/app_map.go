@@ -10,20 +10,19 @@ func (a *App) GetMapName(mapCode string) string {
 		return ""
 	}
 
-	resp, err := a.restyClient.R().Get("https://l4d2-maps.laoyutang.cn/" + mapCode)
-
-	if err != nil {
-		return ""
-	}
-
-	if resp.StatusCode() != 200 {
-		return ""
+	for _, baseURL := range a.endpointCandidates(endpointMapName) {
+		resp, err := a.restyClient.R().Get(baseURL + "/" + mapCode)
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode()
+		}
+		if shouldFallbackEndpoint(statusCode, err) {
+			continue
+		}
+		if statusCode != 200 {
+			return ""
+		}
+		return strings.TrimSpace(resp.String())
 	}
-
-	name := resp.String()
-	if strings.TrimSpace(name) == "" {
-		return ""
-	}
-
-	return strings.TrimSpace(name)
+	return ""
 }
@@ -0,0 +1,133 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"l4d2-manager-next/pkg/valve/vpk"
+	"vpk-manager/internal/parser"
+)
+
+// audioMIMETypes 可在应用内试听的音频格式
+var audioMIMETypes = map[string]string{
+	".wav": "audio/wav",
+	".mp3": "audio/mpeg",
+	".ogg": "audio/ogg",
+}
+
+// audioExts 可试听的扩展名，用于校验试听请求
+var audioExts = slices.Sorted(maps.Keys(audioMIMETypes))
+
+// VPKAudioEntry VPK中的音频文件
+type VPKAudioEntry struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	URL  string `json:"url"` // 本地试听地址，服务未启动时为空
+}
+
+// ListVPKAudioEntries 列出VPK中的音频文件及试听地址
+func (a *App) ListVPKAudioEntries(vpkPath string) ([]VPKAudioEntry, error) {
+	cached, ok := a.vpkCache.Load(vpkPath)
+	if !ok {
+		return nil, fmt.Errorf("文件未找到: %s", vpkPath)
+	}
+	cache := cached.(*VPKFileCache)
+
+	opener := vpk.Single(vpkPath)
+	defer opener.Close()
+	archive, err := opener.ReadArchive()
+	if err != nil {
+		return nil, fmt.Errorf("无法读取 VPK: %v", err)
+	}
+
+	entries := make([]VPKAudioEntry, 0)
+	for i := range archive.Files {
+		file := &archive.Files[i]
+		name := file.Name()
+		if _, ok := audioMIMETypes[strings.ToLower(path.Ext(name))]; !ok {
+			continue
+		}
+		if _, err := cleanVPKEntryPath(name); err != nil {
+			log.Printf("跳过非法音频路径: %s, 错误: %v", name, err)
+			continue
+		}
+		entries = append(entries, VPKAudioEntry{
+			Path: name,
+			Size: int64(file.Size()),
+			URL:  a.previewServer.AudioURL(vpkPath, name, cache),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries, nil
+}
+
+// GetVPKSoundDetails 读取VPK中每个声音文件的格式信息和覆盖的声音脚本，列表中只保留汇总
+func (a *App) GetVPKSoundDetails(vpkPath string) (*parser.SoundDetails, error) {
+	if _, ok := a.vpkCache.Load(vpkPath); !ok {
+		return nil, fmt.Errorf("文件未找到: %s", vpkPath)
+	}
+	details, err := parser.ReadVPKSoundDetails(vpkPath)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取 VPK: %v", err)
+	}
+	return details, nil
+}
+
+// AudioURL 返回VPK内音频的试听地址，服务未启动时返回空字符串
+func (s *previewImageServer) AudioURL(vpkPath string, entry string, cache *VPKFileCache) string {
+	if s == nil || s.port == 0 {
+		return ""
+	}
+	return s.endpointURL("audio", url.Values{"path": {vpkPath}, "entry": {entry}, "v": {previewCacheKey(vpkPath, cache)}})
+}
+
+// handleAudio 提供VPK内的音频，支持 Range 请求以便拖动进度条
+func (s *previewImageServer) handleAudio(w http.ResponseWriter, r *http.Request) {
+	entry, ok := s.resolvePreviewEntry(w, r, audioExts...)
+	if !ok {
+		return
+	}
+
+	opener := vpk.Single(entry.vpkPath)
+	defer opener.Close()
+	content, err := openVPKEntry(opener, entry.name)
+	if err != nil {
+		if err == errVPKEntryNotFound {
+			http.NotFound(w, r)
+			return
+		}
+		log.Printf("读取音频失败: %s:%s, 错误: %v", entry.vpkPath, entry.name, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", audioMIMETypes[strings.ToLower(path.Ext(entry.name))])
+	w.Header().Set("Cache-Control", "max-age=31536000, immutable")
+	http.ServeContent(w, r, path.Base(entry.name), entry.cache.ModTime, content)
+}
+
+var errVPKEntryNotFound = fmt.Errorf("VPK 中未找到文件")
+
+// openVPKEntry 打开VPK内指定文件供按偏移读取，路径不区分大小写，Range 请求只需读取请求的区间
+func openVPKEntry(opener *vpk.Opener, entry string) (*io.SectionReader, error) {
+	archive, err := opener.ReadArchive()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range archive.Files {
+		file := &archive.Files[i]
+		if strings.EqualFold(strings.ReplaceAll(file.Name(), "\\", "/"), entry) {
+			return parser.OpenVPKFileAt(opener, file)
+		}
+	}
+	return nil, errVPKEntryNotFound
+}
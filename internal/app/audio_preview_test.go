@@ -0,0 +1,103 @@
+package app
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func TestAudioPreviewListsEntriesAndServesRanges(t *testing.T) {
+	app, _ := newPreviewTestApp(t)
+	vpkPath := filepath.Join(app.rootDir, "sounds.vpk")
+	wav := []byte("RIFF0000WAVEfmt 0123456789abcdef")
+	writeTestVPK(t, vpkPath, map[string][]byte{
+		"sound/weapons/ak47/Fire.wav": wav,
+		"sound/music/theme.mp3":       []byte("ID3mp3data"),
+		"materials/a.vtf":             []byte("vtf"),
+	})
+	app.processVPKFileWithCache(vpkPath)
+
+	entries, err := app.ListVPKAudioEntries(vpkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[1].Path != "sound/weapons/ak47/Fire.wav" || entries[1].Size != int64(len(wav)) || entries[1].URL == "" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, entries[1].URL, nil)
+	req.Header.Set("Range", "bytes=4-11")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent || resp.Header.Get("Content-Type") != "audio/wav" || string(body) != "0000WAVE" {
+		t.Fatalf("unexpected range response: %d %q %q", resp.StatusCode, resp.Header.Get("Content-Type"), body)
+	}
+
+	resp, err = http.Get(entries[0].URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "audio/mpeg" || resp.Header.Get("Accept-Ranges") != "bytes" {
+		t.Fatalf("unexpected mp3 response: %d %v", resp.StatusCode, resp.Header)
+	}
+
+	for entry, want := range map[string]int{
+		"../../etc/passwd.wav":    http.StatusBadRequest,
+		"materials/a.vtf":         http.StatusBadRequest,
+		"sound/missing.wav":       http.StatusNotFound,
+		"SOUND/MUSIC/THEME.MP3":   http.StatusOK,
+		"sound\\music\\theme.mp3": http.StatusOK,
+	} {
+		status, _ := fetchPreview(t, app.previewServer.AudioURL(vpkPath, entry, &VPKFileCache{}))
+		if status != want {
+			t.Errorf("entry %q: expected %d, got %d", entry, want, status)
+		}
+	}
+	if status, _ := fetchPreview(t, app.previewServer.AudioURL(filepath.Join(app.rootDir, "other.vpk"), "sound/music/theme.mp3", &VPKFileCache{})); status != http.StatusNotFound {
+		t.Errorf("expected 404 for unscanned vpk, got %d", status)
+	}
+
+	if _, err := app.ListVPKAudioEntries(filepath.Join(app.rootDir, "unknown.vpk")); err == nil {
+		t.Fatal("expected error for unscanned vpk")
+	}
+
+	details, err := app.GetVPKSoundDetails(vpkPath)
+	if err != nil || len(details.Files) != 2 || details.Files[0].Path != "sound/music/theme.mp3" {
+		t.Fatalf("unexpected sound details: %+v, %v", details, err)
+	}
+	if _, err := app.GetVPKSoundDetails(filepath.Join(app.rootDir, "unknown.vpk")); err == nil {
+		t.Fatal("expected error for unscanned vpk")
+	}
+}
+
+func TestAudioPreviewServesPreloadedEntries(t *testing.T) {
+	app, _ := newPreviewTestApp(t)
+	vpkPath := filepath.Join(app.rootDir, "preload.vpk")
+	wav := []byte("RIFFxxxxWAVEfmt 0123456789abcdef")
+	writeTestVPKWithPreload(t, vpkPath, map[string][]byte{"sound/a.wav": wav}, 4)
+	app.processVPKFileWithCache(vpkPath)
+
+	url := app.previewServer.AudioURL(vpkPath, "sound/a.wav", &VPKFileCache{})
+	if status, body := fetchPreview(t, url); status != http.StatusOK || string(body) != string(wav) {
+		t.Fatalf("unexpected preloaded entry: %d %q", status, body)
+	}
+
+	// 跨越预载数据和数据区的区间
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("Range", "bytes=2-9")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent || string(body) != "FFxxxxWA" {
+		t.Fatalf("unexpected range response: %d %q", resp.StatusCode, body)
+	}
+}
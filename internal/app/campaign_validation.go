@@ -0,0 +1,88 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"vpk-manager/internal/parser"
+)
+
+// CampaignValidation 单个地图模组的战役校验问题
+type CampaignValidation struct {
+	Addon    ConflictVPKFile         `json:"addon"`
+	Campaign string                  `json:"campaign"`
+	Warnings []parser.MissionWarning `json:"warnings"`
+}
+
+// ValidateCampaigns 返回有问题的地图模组：扫描时记录的 mission 文件和 BSP/NAV 问题，
+// 以及已启用地图之间重复的章节代码（游戏只会加载其中一个）。问题多的排在前面
+func (a *App) ValidateCampaigns() []CampaignValidation {
+	files := make([]VPKFile, 0)
+	a.vpkCache.Range(func(key, value interface{}) bool {
+		file := value.(*VPKFileCache).File
+		if file.PrimaryTag == "地图" || len(file.Chapters) > 0 || len(file.MissionWarnings) > 0 {
+			files = append(files, file)
+		}
+		return true
+	})
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+
+	// 小写章节代码 -> 提供该章节的已启用模组下标
+	chapterOwners := make(map[string][]int)
+	for i, file := range files {
+		if !file.Enabled {
+			continue
+		}
+		for code := range file.Chapters {
+			lower := strings.ToLower(code)
+			chapterOwners[lower] = append(chapterOwners[lower], i)
+		}
+	}
+
+	result := make([]CampaignValidation, 0)
+	for i, file := range files {
+		warnings := append([]parser.MissionWarning{}, file.MissionWarnings...)
+		if file.Enabled {
+			codes := make([]string, 0, len(file.Chapters))
+			for code := range file.Chapters {
+				codes = append(codes, code)
+			}
+			sort.Strings(codes)
+			for _, code := range codes {
+				others := make([]string, 0)
+				for _, owner := range chapterOwners[strings.ToLower(code)] {
+					if owner != i {
+						others = append(others, campaignAddonTitle(files[owner]))
+					}
+				}
+				if len(others) == 0 {
+					continue
+				}
+				warnings = append(warnings, parser.MissionWarning{
+					Code:    parser.MissionWarningDuplicateChapter,
+					Chapter: code,
+					Message: fmt.Sprintf("章节 %s 与已启用的 %s 重复，游戏只会加载其中一个", code, strings.Join(others, "、")),
+				})
+			}
+		}
+		if len(warnings) == 0 {
+			continue
+		}
+		result = append(result, CampaignValidation{
+			Addon:    newConflictVPKFile(file.Name, file.Path, file.Title, file.Location),
+			Campaign: file.Campaign,
+			Warnings: warnings,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return len(result[i].Warnings) > len(result[j].Warnings) })
+	return result
+}
+
+func campaignAddonTitle(file VPKFile) string {
+	if file.Title != "" {
+		return file.Title
+	}
+	return file.Name
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"testing"
+
+	"vpk-manager/internal/parser"
+)
+
+func TestValidateCampaigns(t *testing.T) {
+	app := &App{}
+	store := func(file VPKFile) {
+		app.vpkCache.Store(file.Path, &VPKFileCache{File: file})
+	}
+	store(VPKFile{Name: "a.vpk", Path: "/addons/a.vpk", Title: "Campaign A", PrimaryTag: "地图", Enabled: true, Location: "root",
+		Chapters: map[string]parser.ChapterInfo{"c1m1_test": {}, "c1m2_test": {}}})
+	store(VPKFile{Name: "b.vpk", Path: "/addons/b.vpk", PrimaryTag: "地图", Enabled: true, Location: "root",
+		Chapters:        map[string]parser.ChapterInfo{"C1M1_Test": {}},
+		MissionWarnings: []parser.MissionWarning{{Code: parser.MissionWarningMissingNAV, Chapter: "C1M1_Test", Message: "缺少 nav"}}})
+	store(VPKFile{Name: "c.vpk", Path: "/addons/disabled/c.vpk", PrimaryTag: "地图", Enabled: false, Location: "disabled",
+		Chapters: map[string]parser.ChapterInfo{"c1m2_test": {}}})
+	store(VPKFile{Name: "skin.vpk", Path: "/addons/skin.vpk", PrimaryTag: "人物", Enabled: true})
+
+	result := app.ValidateCampaigns()
+	if len(result) != 2 {
+		t.Fatalf("expected two addons with warnings, got %+v", result)
+	}
+	if result[0].Addon.Name != "b.vpk" || len(result[0].Warnings) != 2 || result[0].Warnings[1].Code != parser.MissionWarningDuplicateChapter {
+		t.Fatalf("unexpected warnings for b.vpk %+v", result[0])
+	}
+	if result[1].Addon.Name != "a.vpk" || len(result[1].Warnings) != 1 || result[1].Warnings[0].Chapter != "c1m1_test" || result[1].Warnings[0].Message == "" {
+		t.Fatalf("disabled addon must not count as duplicate, got %+v", result[1])
+	}
+}
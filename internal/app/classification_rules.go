@@ -0,0 +1,134 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"vpk-manager/internal/parser"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// classificationRulesFile 用户分类规则文件，只保存用户新增或覆盖的规则，内置规则随程序更新
+type classificationRulesFile struct {
+	Rules []parser.ClassificationRule `json:"rules"`
+}
+
+// ClassificationRulesInfo 内置规则和用户规则
+type ClassificationRulesInfo struct {
+	Path      string                      `json:"path"`
+	Defaults  []parser.ClassificationRule `json:"defaults"`
+	UserRules []parser.ClassificationRule `json:"userRules"`
+}
+
+func (a *App) classificationRulesPath() string {
+	a.ensureConfigPaths()
+	return filepath.Join(a.configDir, "classification_rules.json")
+}
+
+// readUserClassificationRules 读取用户规则，文件不存在时返回空列表
+func (a *App) readUserClassificationRules() ([]parser.ClassificationRule, error) {
+	var file classificationRulesFile
+	if err := readJSONFile(a.classificationRulesPath(), &file); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []parser.ClassificationRule{}, nil
+		}
+		return nil, err
+	}
+	if file.Rules == nil {
+		file.Rules = []parser.ClassificationRule{}
+	}
+	return file.Rules, nil
+}
+
+// loadClassificationRules 启动时加载用户规则，规则无效时使用内置规则
+func (a *App) loadClassificationRules() {
+	userRules, err := a.readUserClassificationRules()
+	if err != nil {
+		log.Printf("读取分类规则失败: %v", err)
+		return
+	}
+	if len(userRules) == 0 {
+		return
+	}
+	classifier, err := parser.NewClassifier(parser.MergeClassificationRules(parser.DefaultClassificationRules(), userRules))
+	if err != nil {
+		log.Printf("分类规则无效，使用内置规则: %v", err)
+		return
+	}
+	parser.SetClassifier(classifier)
+}
+
+// GetClassificationRules 获取内置分类规则和用户规则
+func (a *App) GetClassificationRules() (*ClassificationRulesInfo, error) {
+	userRules, err := a.readUserClassificationRules()
+	if err != nil {
+		return nil, fmt.Errorf("读取分类规则失败: %w", err)
+	}
+	return &ClassificationRulesInfo{
+		Path:      a.classificationRulesPath(),
+		Defaults:  parser.DefaultClassificationRules(),
+		UserRules: userRules,
+	}, nil
+}
+
+// SaveClassificationRules 校验并保存用户规则。与内置规则 ID 相同的规则会覆盖内置规则，
+// 保存后按新规则重新分类已缓存的VPK
+func (a *App) SaveClassificationRules(rules []parser.ClassificationRule) error {
+	if rules == nil {
+		rules = []parser.ClassificationRule{}
+	}
+	classifier, err := parser.NewClassifier(parser.MergeClassificationRules(parser.DefaultClassificationRules(), rules))
+	if err != nil {
+		return err
+	}
+	if err := writeJSONFile(a.configDir, a.classificationRulesPath(), classificationRulesFile{Rules: rules}); err != nil {
+		return fmt.Errorf("保存分类规则失败: %w", err)
+	}
+	parser.SetClassifier(classifier)
+
+	a.reclassifyCachedVPKs()
+	a.refreshSmartCollections()
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "refresh_files", nil)
+	}
+	return nil
+}
+
+// reclassifyCachedVPKs 用文件索引中的内部文件列表重新分类缓存的VPK，
+// 无法读取文件列表或涉及地图标签的VPK重新解析
+func (a *App) reclassifyCachedVPKs() {
+	var reparse []string
+	a.vpkCache.Range(func(key, value interface{}) bool {
+		path := key.(string)
+		files, err := a.vpkInnerFileNames(path)
+		if err != nil {
+			reparse = append(reparse, path)
+			return true
+		}
+		cache := *value.(*VPKFileCache)
+		cache.File.SecondaryTags = append([]string{}, cache.File.SecondaryTags...)
+		if !parser.ReclassifyVPKFile(&cache.File, files) {
+			reparse = append(reparse, path)
+			return true
+		}
+		a.vpkCache.Store(path, &cache)
+		return true
+	})
+
+	for _, path := range reparse {
+		a.vpkCache.Delete(path)
+		a.processVPKFileWithCache(path)
+	}
+}
+
+// ExplainVPKTags 说明VPK的每个标签由哪条规则、哪个文件得出
+func (a *App) ExplainVPKTags(filePath string) (*parser.Classification, error) {
+	if _, ok := a.vpkCache.Load(filePath); !ok {
+		return nil, fmt.Errorf("文件未找到: %s", filePath)
+	}
+	return parser.ExplainVPKTags(filePath)
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+
+	"vpk-manager/internal/parser"
+)
+
+func TestSaveClassificationRulesReclassifiesCachedFiles(t *testing.T) {
+	app := newConfigTestApp(t)
+	app.rootDir = t.TempDir()
+	t.Cleanup(func() { parser.SetClassifier(nil) })
+
+	vpkPath := filepath.Join(app.rootDir, "props.vpk")
+	writeTestVPK(t, vpkPath, map[string][]byte{"models/props/crate01.mdl": []byte("x")})
+	app.processVPKFileWithCache(vpkPath)
+	if cached, _ := app.vpkCache.Load(vpkPath); cached.(*VPKFileCache).File.PrimaryTag != "其他" {
+		t.Fatalf("expected default rules to leave props untagged")
+	}
+
+	if err := app.SaveClassificationRules([]parser.ClassificationRule{{ID: "bad", PrimaryTag: "道具"}}); err == nil {
+		t.Fatal("expected invalid rule to be rejected")
+	}
+	err := app.SaveClassificationRules([]parser.ClassificationRule{
+		{ID: "type-props", PrimaryTag: "道具", Priority: 30, PathGlobs: []string{"models/props/**.mdl"}},
+		{ID: "props-crate", PrimaryTag: "道具", SecondaryTag: "箱子", PathGlobs: []string{"**/crate*"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, ok := app.vpkCache.Load(vpkPath)
+	if !ok {
+		t.Fatal("expected cached file to be kept after saving rules")
+	}
+	if file := cached.(*VPKFileCache).File; file.PrimaryTag != "道具" || len(file.SecondaryTags) != 1 || file.SecondaryTags[0] != "箱子" {
+		t.Fatalf("expected cached file to be reclassified, got %s %v", file.PrimaryTag, file.SecondaryTags)
+	}
+
+	explanation, err := app.ExplainVPKTags(vpkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if explanation.PrimaryTag != "道具" || len(explanation.Matches) != 2 || explanation.Matches[1].RuleID != "props-crate" ||
+		explanation.Matches[1].File != "models/props/crate01.mdl" {
+		t.Fatalf("unexpected explanation: %+v", explanation)
+	}
+
+	parser.SetClassifier(nil)
+	app.loadClassificationRules()
+	info, err := app.GetClassificationRules()
+	if err != nil || len(info.UserRules) != 2 || len(info.Defaults) == 0 {
+		t.Fatalf("unexpected rules info: %+v, %v", info, err)
+	}
+	if tags := app.GetPrimaryTags(); tags[len(tags)-2] != "道具" || tags[len(tags)-1] != "其他" {
+		t.Fatalf("expected user primary tag before 其他, got %v", tags)
+	}
+	if _, err := app.ExplainVPKTags(filepath.Join(app.rootDir, "missing.vpk")); err == nil {
+		t.Fatal("expected error for unknown file")
+	}
+}
@@ -98,8 +98,28 @@ func (a *App) loadConfig() {
 	a.workshopTranslateCustomBaseURL = config.WorkshopTranslateCustomBaseURL
 	a.workshopTranslateCustomAPIKey = config.WorkshopTranslateCustomAPIKey
 	a.workshopTranslateCustomModelId = config.WorkshopTranslateCustomModelId
+	if endpoints, err := normalizeEndpointConfig(config.Endpoints); err == nil {
+		a.endpoints = endpoints
+	} else {
+		log.Printf("远程服务地址配置无效，已使用默认地址: %v", err)
+	}
+	network.SetIPListURL(a.endpoints.IPList)
+	if config.Proxy != nil {
+		proxySettings := cloneProxySettings(*config.Proxy)
+		proxySettings.Password = ""
+		if err := applyProxySettings(proxySettings); err != nil {
+			log.Printf("代理设置无效，已忽略: %v", err)
+		} else {
+			a.proxySettings = proxySettings
+		}
+	}
+	if config.ModelBudgets != nil {
+		budgets := normalizeModelBudgets(*config.ModelBudgets)
+		a.modelBudgets = &budgets
+	}
 	a.defaultDirectory = config.DefaultDirectory
 	a.savedDirectories = cloneSavedDirectories(config.SavedDirectories)
+	a.smartCollections = cloneSmartCollections(config.SmartCollections)
 	a.lastActiveDirectory = config.LastActiveDirectory
 	if config.DisplayMode != "" {
 		a.displayMode = config.DisplayMode
@@ -143,6 +163,16 @@ func (a *App) snapshotConfig() ConfigFile {
 	}
 	boxSelectionEnabled := a.boxSelectionEnabled
 	ctrlClickSelectionEnabled := a.ctrlClickSelectionEnabled
+	var proxySettings *ProxySettings
+	if a.proxySettings.Mode != "" {
+		stored := cloneProxySettings(a.proxySettings)
+		proxySettings = &stored
+	}
+	var modelBudgets *ModelBudgets
+	if a.modelBudgets != nil {
+		stored := *a.modelBudgets
+		modelBudgets = &stored
+	}
 
 	return ConfigFile{
 		ModRotationConfig:              a.modRotationConfig,
@@ -155,6 +185,9 @@ func (a *App) snapshotConfig() ConfigFile {
 		WorkshopTranslateCustomBaseURL: a.workshopTranslateCustomBaseURL,
 		WorkshopTranslateCustomAPIKey:  a.workshopTranslateCustomAPIKey,
 		WorkshopTranslateCustomModelId: a.workshopTranslateCustomModelId,
+		Endpoints:                      cloneEndpointConfig(a.endpoints),
+		Proxy:                          proxySettings,
+		ModelBudgets:                   modelBudgets,
 		DefaultDirectory:               a.defaultDirectory,
 		SavedDirectories:               cloneSavedDirectories(a.savedDirectories),
 		LastActiveDirectory:            a.lastActiveDirectory,
@@ -166,6 +199,7 @@ func (a *App) snapshotConfig() ConfigFile {
 		IgnoredVersion:                 a.ignoredVersion,
 		LastUpdateCheckTime:            a.lastUpdateCheckTime,
 		MigrationVersion:               a.migrationVersion,
+		SmartCollections:               cloneSmartCollections(a.smartCollections),
 	}
 }
 
@@ -472,8 +506,19 @@ func (a *App) GetWorkshopIPOptions() []IPOption {
 	return network.GetSteamCDNIPOptions()
 }
 
+// GetIPHealthHistory 获取各CDN IP的历史成功率与速度
+func (a *App) GetIPHealthHistory() []IPHealthRecord {
+	return network.GlobalIPHealth.Snapshot()
+}
+
+// ClearIPHealthHistory 清空CDN IP历史记录，之前被跳过的IP将重新参与优选
+func (a *App) ClearIPHealthHistory() error {
+	return network.GlobalIPHealth.Clear()
+}
+
 // SetWorkshopFixedIP 设置工坊固定IP（留空则使用自动优选）
 func (a *App) SetWorkshopFixedIP(ip string) {
+	ip = network.NormalizeIP(ip)
 	a.mu.Lock()
 	a.workshopFixedIP = ip
 	a.mu.Unlock()
@@ -27,6 +27,8 @@ type ConflictGroup struct {
 	FileCount      int               `json:"file_count"`
 	FilesTruncated bool              `json:"files_truncated"`
 	Severity       string            `json:"severity"` // "critical", "warning", "info"
+	// Files 中因地图内嵌 pakfile 才产生的冲突，直接包含该文件的VPK不足两个
+	PakfileFiles []string `json:"pakfile_files,omitempty"`
 }
 
 type ConflictResult struct {
@@ -39,10 +41,29 @@ const (
 	conflictGroupFileListLimit = 2000
 )
 
+// conflictPakfileKey 某个VPK中只存在于地图内嵌 pakfile 里的文件
+type conflictPakfileKey struct {
+	vpk  string
+	file string
+}
+
 type conflictGroupAccumulator struct {
-	files     []string
-	fileCount int
-	severity  string
+	files        []string
+	pakfileFiles []string
+	fileCount    int
+	severity     string
+}
+
+// isPakfileConflict 冲突中有VPK的文件来自地图内嵌 pakfile，且直接包含该文件的VPK不足两个时返回 true；
+// 两个以上VPK直接包含时按普通冲突处理
+func isPakfileConflict(file string, owners []string, pakfileEntries map[conflictPakfileKey]bool) bool {
+	loose := 0
+	for _, owner := range owners {
+		if !pakfileEntries[conflictPakfileKey{vpk: owner, file: file}] {
+			loose++
+		}
+	}
+	return loose < len(owners) && loose < 2
 }
 
 // getConflictSeverity 判断文件冲突严重程度
@@ -206,6 +227,8 @@ func (a *App) CheckConflicts() (*ConflictResult, error) {
 	// 文件路径 -> VPK列表（使用完整路径）
 	fileFirstOwner := make(map[string]string)
 	conflictOwners := make(map[string][]string)
+	// 来自地图内嵌 pakfile 的文件，载入该地图时会覆盖其他模组的同名文件；按所属VPK记录
+	pakfileEntries := make(map[conflictPakfileKey]bool)
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 	workerCount := min(conflictWorkerLimit, rt.GOMAXPROCS(0))
@@ -228,7 +251,7 @@ func (a *App) CheckConflicts() (*ConflictResult, error) {
 			workerSlots <- struct{}{}
 			defer func() { <-workerSlots }()
 
-			files, err := getVPKFileListSafely(p)
+			files, err := a.vpkInnerFileNames(p)
 
 			countMu.Lock()
 			processedCount++
@@ -248,31 +271,47 @@ func (a *App) CheckConflicts() (*ConflictResult, error) {
 				log.Printf("冲突检测跳过VPK: %s, 错误: %v", p, err)
 				return
 			}
+			pakFiles, err := a.vpkBSPPakfileNames(p, files)
+			if err != nil {
+				log.Printf("冲突检测读取地图 pakfile 失败: %s, 错误: %v", p, err)
+			}
 
 			mu.Lock()
-			for _, f := range files {
+			addOwner := func(f string) string {
 				lowerF := normalizeConflictFilePath(f)
 				if isIgnoredConflictFile(lowerF) {
-					continue
+					return ""
 				}
 
 				firstOwner, ok := fileFirstOwner[lowerF]
 				if !ok {
 					fileFirstOwner[lowerF] = p
-					continue
+					return lowerF
 				}
 				if firstOwner == p {
-					continue
+					return lowerF
 				}
 
 				owners := conflictOwners[lowerF]
 				if len(owners) == 0 {
 					conflictOwners[lowerF] = []string{firstOwner, p}
-					continue
+					return lowerF
 				}
 				if !containsString(owners, p) {
 					conflictOwners[lowerF] = append(owners, p)
 				}
+				return lowerF
+			}
+			loose := make(map[string]bool, len(files))
+			for _, f := range files {
+				if lowerF := addOwner(f); lowerF != "" && len(pakFiles) > 0 {
+					loose[lowerF] = true
+				}
+			}
+			for _, f := range pakFiles {
+				if lowerF := addOwner(f); lowerF != "" && !loose[lowerF] {
+					pakfileEntries[conflictPakfileKey{vpk: p, file: lowerF}] = true
+				}
 			}
 			mu.Unlock()
 		})
@@ -283,6 +322,9 @@ func (a *App) CheckConflicts() (*ConflictResult, error) {
 	}
 
 	wg.Wait()
+	if err := a.pathIndex.save(); err != nil {
+		log.Printf("保存文件索引失败: %v", err)
+	}
 
 	// 分析冲突
 	runtime.EventsEmit(a.ctx, "conflict_check_progress", ProgressInfo{
@@ -309,6 +351,9 @@ func (a *App) CheckConflicts() (*ConflictResult, error) {
 		acc.fileCount++
 		if len(acc.files) < conflictGroupFileListLimit {
 			acc.files = append(acc.files, f)
+			if isPakfileConflict(f, vpks, pakfileEntries) {
+				acc.pakfileFiles = append(acc.pakfileFiles, f)
+			}
 		}
 		if s := getConflictSeverity(f); getConflictSeverityRank(s) > getConflictSeverityRank(acc.severity) {
 			acc.severity = s
@@ -320,6 +365,7 @@ func (a *App) CheckConflicts() (*ConflictResult, error) {
 		files := acc.files
 		vpkFullPaths := strings.Split(key, "|")
 		sort.Strings(files) // 文件列表也排序
+		sort.Strings(acc.pakfileFiles)
 
 		// 从缓存获取完整VPK信息
 		vpkInfos := make([]ConflictVPKFile, 0, len(vpkFullPaths))
@@ -338,6 +384,7 @@ func (a *App) CheckConflicts() (*ConflictResult, error) {
 			Files:          files,
 			FileCount:      acc.fileCount,
 			FilesTruncated: acc.fileCount > len(files),
+			PakfileFiles:   acc.pakfileFiles,
 			Severity:       acc.severity,
 		})
 	}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package app
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// diskFreeBytes 返回路径所在磁盘对当前用户可用的剩余空间
+func diskFreeBytes(path string) (uint64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return 0, fmt.Errorf("获取磁盘剩余空间失败: %w", err)
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
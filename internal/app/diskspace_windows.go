@@ -0,0 +1,22 @@
+//go:build windows
+
+package app
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+// diskFreeBytes 返回路径所在磁盘对当前用户可用的剩余空间
+func diskFreeBytes(path string) (uint64, error) {
+	pathPtr, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var freeBytesAvailable, totalBytes, totalFreeBytes uint64
+	if err := windows.GetDiskFreeSpaceEx(pathPtr, &freeBytesAvailable, &totalBytes, &totalFreeBytes); err != nil {
+		return 0, fmt.Errorf("获取磁盘剩余空间失败: %w", err)
+	}
+	return freeBytesAvailable, nil
+}
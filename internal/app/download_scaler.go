@@ -0,0 +1,101 @@
+package app
+
+import (
+	"fmt"
+	"time"
+)
+
+// Worker scaling parameters for chunked downloads
+const (
+	// scaleInterval is how often the scaler samples total throughput
+	scaleInterval = 2 * time.Second
+	// scaleGrowthThreshold is the minimum relative throughput gain that justifies an extra worker
+	scaleGrowthThreshold = 0.1
+	// scaleCooldownTicks is how many samples to wait after removing a worker before probing again
+	scaleCooldownTicks = 3
+	// maxDiagnosticEvents caps the number of events kept on a task
+	maxDiagnosticEvents = 50
+)
+
+// DownloadDiagnostics 分块下载引擎的运行状态与调度记录
+type DownloadDiagnostics struct {
+	Workers    int      `json:"workers"`     // 当前线程数
+	MaxWorkers int      `json:"max_workers"` // 线程数上限
+	BlockSize  int64    `json:"block_size"`  // 最近分配的分块大小（字节）
+	Throughput string   `json:"throughput"`  // 总吞吐量
+	PerWorker  string   `json:"per_worker"`  // 平均每线程吞吐量
+	Events     []string `json:"events"`      // 调度记录，如增减线程、切换IP
+}
+
+// updateTaskDiagnostics 修改任务的诊断信息。每次替换为新副本，避免与正在序列化的旧值竞争。
+func updateTaskDiagnostics(task *DownloadTask, update func(d *DownloadDiagnostics)) {
+	taskManager.mu.Lock()
+	defer taskManager.mu.Unlock()
+
+	next := DownloadDiagnostics{}
+	if task.Diagnostics != nil {
+		next = *task.Diagnostics
+		next.Events = append([]string(nil), task.Diagnostics.Events...)
+	}
+	update(&next)
+	task.Diagnostics = &next
+}
+
+// addTaskDiagnosticEvent 追加一条带时间的调度记录，超出上限时丢弃最早的记录
+func addTaskDiagnosticEvent(task *DownloadTask, format string, args ...interface{}) {
+	event := time.Now().Format("15:04:05") + " " + fmt.Sprintf(format, args...)
+	updateTaskDiagnostics(task, func(d *DownloadDiagnostics) {
+		d.Events = append(d.Events, event)
+		if len(d.Events) > maxDiagnosticEvents {
+			d.Events = d.Events[len(d.Events)-maxDiagnosticEvents:]
+		}
+	})
+}
+
+// workerScaler decides the worker count by hill climbing on total throughput:
+// it adds a worker while throughput keeps growing and removes the last added
+// worker when that addition did not pay off.
+type workerScaler struct {
+	minWorkers     int
+	maxWorkers     int
+	lastThroughput float64
+	lastAdded      bool
+	cooldown       int
+}
+
+func newWorkerScaler(maxWorkers int) *workerScaler {
+	return &workerScaler{minWorkers: 1, maxWorkers: max(maxWorkers, 1)}
+}
+
+// initialWorkers returns how many workers to start with, leaving room to probe upwards
+func (s *workerScaler) initialWorkers() int {
+	return max(s.maxWorkers/2, s.minWorkers)
+}
+
+// decide returns the new worker count for the sampled throughput (bytes/s) and a
+// diagnostic message describing the decision, or "" when the count is unchanged.
+// canGrow is false when there is no unassigned work left to give a new worker.
+func (s *workerScaler) decide(current int, throughput float64, canGrow bool) (int, string) {
+	last := s.lastThroughput
+	s.lastThroughput = throughput
+
+	if s.cooldown > 0 {
+		s.cooldown--
+		return current, ""
+	}
+
+	if s.lastAdded {
+		s.lastAdded = false
+		if throughput < last*(1+scaleGrowthThreshold) && current > s.minWorkers {
+			s.cooldown = scaleCooldownTicks
+			return current - 1, fmt.Sprintf("增加线程后吞吐量未提升 (%s → %s)，减少到 %d 个线程",
+				formatSpeed(last), formatSpeed(throughput), current-1)
+		}
+	}
+
+	if canGrow && current < s.maxWorkers && throughput > 0 {
+		s.lastAdded = true
+		return current + 1, fmt.Sprintf("吞吐量 %s，尝试增加到 %d 个线程", formatSpeed(throughput), current+1)
+	}
+	return current, ""
+}
@@ -0,0 +1,261 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// diskSpaceSafetyMargin 预留的安全余量，避免把磁盘写满影响系统和游戏
+	diskSpaceSafetyMargin int64 = 200 * 1024 * 1024
+	// archiveExtractionRatio 压缩包解压所需空间的估算倍数（VPK 压缩率通常不高）
+	archiveExtractionRatio = 2
+	// diskSpaceRecheckInterval 空间不足时重新检查的间隔
+	diskSpaceRecheckInterval = 5 * time.Second
+)
+
+// diskFreeSpace 获取剩余空间，测试中可替换
+var diskFreeSpace = diskFreeBytes
+
+// DownloadDiskSpace 下载目录的磁盘空间概况
+type DownloadDiskSpace struct {
+	Free      int64 `json:"free"`      // 磁盘剩余空间
+	Reserved  int64 `json:"reserved"`  // 进行中的任务仍需的空间
+	Queued    int64 `json:"queued"`    // 尚未开始写入的任务预计需要的空间
+	Available int64 `json:"available"` // 扣除预留、排队与安全余量后的可用空间
+}
+
+// spaceReservation 单个进行中任务的空间预留
+type spaceReservation struct {
+	task *DownloadTask
+	// preallocated 分块下载会预先分配完整文件，此后文件本身不再占用新的空间
+	preallocated bool
+}
+
+// diskSpaceReservations 记录进行中任务预留的空间
+type diskSpaceReservations struct {
+	tasks map[string]*spaceReservation
+	// rechecks 因空间不足而等待的任务，用户重试时通过它立即重新检查
+	rechecks map[string]chan struct{}
+	mu       sync.Mutex
+}
+
+var spaceReservations = &diskSpaceReservations{
+	tasks: make(map[string]*spaceReservation),
+}
+
+// needsArchiveExtraction 判断任务下载完成后是否会解压（与 handleArchiveExtraction 的条件一致）
+func needsArchiveExtraction(task *DownloadTask) bool {
+	ext := strings.ToLower(filepath.Ext(task.Filename))
+	return strings.HasPrefix(task.WorkshopID, "direct-") && (ext == ".zip" || ext == ".rar" || ext == ".7z")
+}
+
+// estimateTaskDiskSpace 估算任务总共需要的磁盘空间：文件本身加上解压所需空间
+func estimateTaskDiskSpace(task *DownloadTask) int64 {
+	required := task.TotalSize
+	if needsArchiveExtraction(task) {
+		required += task.TotalSize * archiveExtractionRatio
+	}
+	return required
+}
+
+// remainingTaskDiskSpace 返回任务还需占用的空间，已写入磁盘的部分已经体现在剩余空间中
+func remainingTaskDiskSpace(task *DownloadTask, preallocated bool) int64 {
+	written := task.DownloadedSize
+	if preallocated {
+		written = task.TotalSize
+	}
+	return max(estimateTaskDiskSpace(task)-written, 0)
+}
+
+// reservedLocked 返回除 excludeID 外所有进行中任务仍需的空间，调用方需持有 r.mu 与 taskManager.mu 读锁
+func (r *diskSpaceReservations) reservedLocked(excludeID string) int64 {
+	var reserved int64
+	for id, reservation := range r.tasks {
+		if id != excludeID {
+			reserved += remainingTaskDiskSpace(reservation.task, reservation.preallocated)
+		}
+	}
+	return reserved
+}
+
+// isQueuedDownloadStatus 判断任务是否仍在等待或即将写入磁盘
+func isQueuedDownloadStatus(status string) bool {
+	switch status {
+	case "pending", "selecting_ip", "downloading", "insufficient_space":
+		return true
+	}
+	return false
+}
+
+// queuedAheadLocked 返回排在 task 之前、尚未预留空间的任务预计需要的空间，
+// 只统计更早加入队列的任务，避免排队任务互相等待。调用方需持有 r.mu 与 taskManager.mu 读锁
+func (r *diskSpaceReservations) queuedAheadLocked(task *DownloadTask) int64 {
+	var queued int64
+	for id, other := range taskManager.tasks {
+		if id == task.ID || !isQueuedDownloadStatus(other.Status) {
+			continue
+		}
+		if _, reserved := r.tasks[id]; reserved {
+			continue
+		}
+		if other.CreatedAt < task.CreatedAt || (other.CreatedAt == task.CreatedAt && id < task.ID) {
+			queued += remainingTaskDiskSpace(other, false)
+		}
+	}
+	return queued
+}
+
+// tryReserve 检查剩余空间是否足够并为任务预留，空间不足时返回错误。
+// 进行中任务仍需的空间和排在前面的任务都会计入
+func (r *diskSpaceReservations) tryReserve(dir string, task *DownloadTask) error {
+	free, err := diskFreeSpace(dir)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	taskManager.mu.RLock()
+	required := estimateTaskDiskSpace(task)
+	reserved := r.reservedLocked(task.ID)
+	queued := r.queuedAheadLocked(task)
+	taskManager.mu.RUnlock()
+
+	available := int64(free) - reserved - queued - diskSpaceSafetyMargin
+	if required > available {
+		return fmt.Errorf("磁盘空间不足: 需要 %s，可用 %s（其他任务已预留 %s，排队中 %s）",
+			formatBytes(required), formatBytes(max(available, 0)), formatBytes(reserved), formatBytes(queued))
+	}
+	r.tasks[task.ID] = &spaceReservation{task: task}
+	return nil
+}
+
+// setPreallocated 记录任务的下载文件是否已预分配完整大小。
+// 分块下载回退到单线程时预分配的文件已删除，需要重新按已下载大小计算
+func (r *diskSpaceReservations) setPreallocated(taskID string, preallocated bool) {
+	r.mu.Lock()
+	if reservation, ok := r.tasks[taskID]; ok {
+		reservation.preallocated = preallocated
+	}
+	r.mu.Unlock()
+}
+
+func (r *diskSpaceReservations) release(taskID string) {
+	r.mu.Lock()
+	delete(r.tasks, taskID)
+	r.mu.Unlock()
+}
+
+// recheckSignal 登记等待中的任务，返回请求立即重新检查时收到信号的通道
+func (r *diskSpaceReservations) recheckSignal(taskID string) <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.rechecks == nil {
+		r.rechecks = make(map[string]chan struct{})
+	}
+	signal, ok := r.rechecks[taskID]
+	if !ok {
+		signal = make(chan struct{}, 1)
+		r.rechecks[taskID] = signal
+	}
+	return signal
+}
+
+// stopRecheck 任务不再等待空间时取消登记
+func (r *diskSpaceReservations) stopRecheck(taskID string) {
+	r.mu.Lock()
+	delete(r.rechecks, taskID)
+	r.mu.Unlock()
+}
+
+// requestRecheck 让等待中的任务立即重新检查空间，任务未在等待时返回 false
+func (r *diskSpaceReservations) requestRecheck(taskID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	signal, ok := r.rechecks[taskID]
+	if !ok {
+		return false
+	}
+	select {
+	case signal <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// waitForDiskSpace 为任务预留空间。空间不足时将任务置为 insufficient_space 并定期重试，
+// 直到空间足够（返回 true）或任务被取消（返回 false）。用户重试时会立即重新检查。
+func (a *App) waitForDiskSpace(ctx context.Context, task *DownloadTask, updateStatus func(string, string)) bool {
+	recheck := spaceReservations.recheckSignal(task.ID)
+	defer spaceReservations.stopRecheck(task.ID)
+
+	held := false
+	for {
+		err := spaceReservations.tryReserve(a.rootDir, task)
+		if err == nil {
+			if held {
+				fmt.Printf("[Download] Disk space available again for %s, resuming\n", task.Filename)
+				updateStatus("downloading", "")
+			}
+			return true
+		}
+
+		if !held {
+			fmt.Printf("[Download] Holding %s: %v\n", task.Filename, err)
+			updateStatus("insufficient_space", err.Error())
+			held = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-recheck:
+		case <-time.After(diskSpaceRecheckInterval):
+		}
+	}
+}
+
+// GetDownloadDiskSpace 获取下载目录的磁盘空间概况，供前端在加入下载前提示
+func (a *App) GetDownloadDiskSpace() (DownloadDiskSpace, error) {
+	if a.rootDir == "" {
+		return DownloadDiskSpace{}, fmt.Errorf("未设置根目录")
+	}
+	free, err := diskFreeSpace(a.rootDir)
+	if err != nil {
+		return DownloadDiskSpace{}, err
+	}
+
+	spaceReservations.mu.Lock()
+	defer spaceReservations.mu.Unlock()
+	taskManager.mu.RLock()
+	defer taskManager.mu.RUnlock()
+
+	status := DownloadDiskSpace{
+		Free:     int64(free),
+		Reserved: spaceReservations.reservedLocked(""),
+	}
+	for id, task := range taskManager.tasks {
+		if _, reserved := spaceReservations.tasks[id]; reserved {
+			continue
+		}
+		if isQueuedDownloadStatus(task.Status) {
+			status.Queued += remainingTaskDiskSpace(task, false)
+		}
+	}
+	status.Available = max(status.Free-status.Reserved-status.Queued-diskSpaceSafetyMargin, 0)
+	return status, nil
+}
+
+func formatBytes(bytes int64) string {
+	if bytes < 1024*1024 {
+		return fmt.Sprintf("%.1f KB", float64(bytes)/1024)
+	} else if bytes < 1024*1024*1024 {
+		return fmt.Sprintf("%.1f MB", float64(bytes)/(1024*1024))
+	}
+	return fmt.Sprintf("%.2f GB", float64(bytes)/(1024*1024*1024))
+}
@@ -0,0 +1,149 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func stubDiskFreeSpace(t *testing.T, free uint64) {
+	t.Helper()
+	original := diskFreeSpace
+	diskFreeSpace = func(string) (uint64, error) { return free, nil }
+	t.Cleanup(func() { diskFreeSpace = original })
+}
+
+func TestDiskSpaceReservationCountsRunningTasks(t *testing.T) {
+	const gb = 1024 * 1024 * 1024
+	stubDiskFreeSpace(t, 2*gb+uint64(diskSpaceSafetyMargin))
+	reservations := &diskSpaceReservations{tasks: make(map[string]*spaceReservation)}
+
+	first := &DownloadTask{ID: "first", WorkshopID: "123", Filename: "123.vpk", TotalSize: gb}
+	second := &DownloadTask{ID: "second", WorkshopID: "456", Filename: "456.vpk", TotalSize: gb + gb/2}
+
+	if err := reservations.tryReserve("root", first); err != nil {
+		t.Fatalf("expected first task to fit: %v", err)
+	}
+	err := reservations.tryReserve("root", second)
+	if err == nil || !strings.Contains(err.Error(), "磁盘空间不足") {
+		t.Fatalf("expected second task to be refused, got %v", err)
+	}
+
+	reservations.setPreallocated("first", true)
+	if err := reservations.tryReserve("root", second); err != nil {
+		t.Fatalf("expected preallocated file to stop counting against the reservation: %v", err)
+	}
+
+	reservations.release("first")
+	reservations.release("second")
+	if len(reservations.tasks) != 0 {
+		t.Fatalf("expected reservations to be released, got %d", len(reservations.tasks))
+	}
+}
+
+func TestEstimateTaskDiskSpaceIncludesExtraction(t *testing.T) {
+	vpk := &DownloadTask{WorkshopID: "123", Filename: "123.vpk", TotalSize: 100}
+	archive := &DownloadTask{WorkshopID: "direct-1", Filename: "maps.7z", TotalSize: 100}
+
+	if got := estimateTaskDiskSpace(vpk); got != 100 {
+		t.Fatalf("expected plain download to need its own size, got %d", got)
+	}
+	if got := estimateTaskDiskSpace(archive); got != 100*(1+archiveExtractionRatio) {
+		t.Fatalf("expected archive to include extraction estimate, got %d", got)
+	}
+}
+
+func TestWaitForDiskSpaceHoldsUntilCancelled(t *testing.T) {
+	stubDiskFreeSpace(t, 0)
+	app := &App{rootDir: t.TempDir()}
+	task := &DownloadTask{ID: "held", WorkshopID: "123", Filename: "123.vpk", TotalSize: 1024}
+
+	var statuses []string
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	if app.waitForDiskSpace(ctx, task, func(status string, _ string) { statuses = append(statuses, status) }) {
+		t.Fatalf("expected wait to end without a reservation")
+	}
+	if len(statuses) != 1 || statuses[0] != "insufficient_space" {
+		t.Fatalf("expected task to be held as insufficient_space, got %v", statuses)
+	}
+	spaceReservations.release(task.ID)
+}
+
+func TestDiskSpaceReservationCountsQueuedTasksAhead(t *testing.T) {
+	const gb = 1024 * 1024 * 1024
+	stubDiskFreeSpace(t, 2*gb+uint64(diskSpaceSafetyMargin))
+	reservations := &diskSpaceReservations{tasks: make(map[string]*spaceReservation)}
+
+	earlier := &DownloadTask{ID: "earlier", WorkshopID: "1", Filename: "1.vpk", TotalSize: gb + gb/2, Status: "pending", CreatedAt: "2026-01-01 10:00:00"}
+	later := &DownloadTask{ID: "later", WorkshopID: "2", Filename: "2.vpk", TotalSize: gb, Status: "pending", CreatedAt: "2026-01-01 10:00:05"}
+	taskManager.mu.Lock()
+	taskManager.tasks[earlier.ID] = earlier
+	taskManager.tasks[later.ID] = later
+	taskManager.mu.Unlock()
+	t.Cleanup(func() {
+		taskManager.mu.Lock()
+		delete(taskManager.tasks, earlier.ID)
+		delete(taskManager.tasks, later.ID)
+		taskManager.mu.Unlock()
+	})
+
+	if err := reservations.tryReserve("root", later); err == nil || !strings.Contains(err.Error(), "排队中") {
+		t.Fatalf("expected later task to wait for the earlier queued task, got %v", err)
+	}
+	// 排在后面的任务不会挡住前面的任务
+	if err := reservations.tryReserve("root", earlier); err != nil {
+		t.Fatalf("expected earlier task to ignore tasks queued behind it: %v", err)
+	}
+
+	reservations.setPreallocated("earlier", true)
+	if err := reservations.tryReserve("root", later); err != nil {
+		t.Fatalf("expected later task to fit once the earlier file is preallocated: %v", err)
+	}
+	reservations.release("later")
+	reservations.setPreallocated("earlier", false)
+	if err := reservations.tryReserve("root", later); err == nil {
+		t.Fatal("expected cleared preallocation to count against the reservation again")
+	}
+}
+
+func TestWaitForDiskSpaceRechecksOnRequest(t *testing.T) {
+	var free atomic.Uint64
+	original := diskFreeSpace
+	diskFreeSpace = func(string) (uint64, error) { return free.Load(), nil }
+	t.Cleanup(func() { diskFreeSpace = original })
+
+	app := &App{rootDir: t.TempDir()}
+	task := &DownloadTask{ID: "recheck", WorkshopID: "123", Filename: "123.vpk", TotalSize: 1024}
+	held := make(chan struct{})
+	done := make(chan bool)
+	go func() {
+		done <- app.waitForDiskSpace(context.Background(), task, func(status string, _ string) {
+			if status == "insufficient_space" {
+				close(held)
+			}
+		})
+	}()
+
+	<-held
+	free.Store(uint64(diskSpaceSafetyMargin) * 2)
+	if !spaceReservations.requestRecheck(task.ID) {
+		t.Fatalf("expected held task to accept a recheck")
+	}
+
+	select {
+	case ok := <-done:
+		if !ok {
+			t.Fatalf("expected reservation after recheck")
+		}
+	case <-time.After(diskSpaceRecheckInterval / 2):
+		t.Fatalf("expected recheck before the periodic interval")
+	}
+	spaceReservations.release(task.ID)
+	if spaceReservations.requestRecheck(task.ID) {
+		t.Fatalf("expected recheck to be refused once the task stopped waiting")
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -13,6 +12,8 @@ import (
 	"sync/atomic"
 	"time"
 
+	"vpk-manager/internal/network"
+
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
@@ -27,7 +28,22 @@ const (
 // errRangeNotSupported indicates the server does not support Range requests
 var errRangeNotSupported = errors.New("range not supported")
 
-// Block represents a fixed-size block for parallel download (default 5MB)
+// errBlockStalled indicates a block's throughput dropped below the stall threshold
+var errBlockStalled = errors.New("block download stalled")
+
+// Stall detection: a block is considered stalled when fewer than blockStallMinBytes
+// arrive within blockStallWindow. Variables so tests can shorten them.
+var (
+	blockStallWindow         = 10 * time.Second
+	blockStallMinBytes int64 = 64 * 1024
+)
+
+// blockFailoverThreshold is the number of consecutive block errors on the same IP
+// before switching to the next-best IP. Stalls switch immediately.
+const blockFailoverThreshold = 2
+
+// Block represents a byte range for parallel download. Blocks are carved on demand,
+// so their size adapts to how much of the file is left (see BlockManager.NextBlock).
 type Block struct {
 	Index     int
 	StartByte int64
@@ -38,13 +54,20 @@ type Block struct {
 func (b *Block) Status() int     { return int(b.status.Load()) }
 func (b *Block) SetStatus(s int) { b.status.Store(int32(s)) }
 
-// BlockManager manages the work queue and global progress for parallel downloads
+// minBlockSize is the smallest block carved near the end of the file
+const minBlockSize int64 = 256 * 1024
+
+// BlockManager hands out blocks to workers and tracks global progress for parallel downloads.
+// The number of active workers can change during the download (see workerScaler).
 type BlockManager struct {
-	blocks          []*Block
+	blocks          []*Block // blocks carved so far
 	totalSize       int64
-	blockSize       int64
-	workerCount     int
-	queue           chan int // pending block indices
+	blockSize       int64 // base block size
+	nextOffset      int64 // first byte not yet assigned to a block
+	lastBlockSize   int64
+	blocksMu        sync.Mutex
+	activeWorkers   atomic.Int32
+	targetWorkers   atomic.Int32
 	completedBlocks atomic.Int32
 	failedBlocks    atomic.Int32
 	completedBytes  atomic.Int64
@@ -57,64 +80,100 @@ type BlockManager struct {
 	lastReportBytes atomic.Int64
 }
 
-// NewBlockManager creates a BlockManager that splits totalSize into fixed-size blocks
+// NewBlockManager creates a BlockManager for totalSize bytes with the given base block size
+// and initial target worker count
 func NewBlockManager(totalSize int64, workerCount int, blockSize int64) *BlockManager {
 	if blockSize <= 0 {
 		blockSize = 5 * 1024 * 1024 // 5MB default
 	}
 
-	numBlocks := int((totalSize + blockSize - 1) / blockSize)
-	blocks := make([]*Block, numBlocks)
-	for i := 0; i < numBlocks; i++ {
-		start := int64(i) * blockSize
-		end := start + blockSize - 1
-		if end >= totalSize {
-			end = totalSize - 1
-		}
-		blocks[i] = &Block{
-			Index:     i,
-			StartByte: start,
-			EndByte:   end,
-		}
-	}
-
 	ctx, cancel := context.WithCancel(context.Background())
 	bm := &BlockManager{
-		blocks:      blocks,
-		totalSize:   totalSize,
-		blockSize:   blockSize,
-		workerCount: workerCount,
-		queue:       make(chan int, numBlocks),
+		totalSize: totalSize,
+		blockSize: blockSize,
 	}
 	bm.ctx = ctx
 	bm.cancel = cancel
+	bm.targetWorkers.Store(int32(max(workerCount, 1)))
 	bm.lastReportTime.Store(time.Now())
 
-	// Initialize queue with all block indices
-	for i := 0; i < numBlocks; i++ {
-		bm.queue <- i
-	}
-	close(bm.queue)
-
 	return bm
 }
 
-// NextBlock retrieves the next pending block from the queue
+// nextBlockSizeLocked picks the size of the next block. Away from the end of the file
+// it is the base size; in the tail it shrinks so every active worker gets a share and
+// no single slow block holds up completion.
+func (bm *BlockManager) nextBlockSizeLocked() int64 {
+	remaining := bm.totalSize - bm.nextOffset
+	workers := int64(max(bm.activeWorkers.Load(), 1))
+
+	size := bm.blockSize
+	if remaining < bm.blockSize*workers {
+		size = min(max(remaining/(2*workers), minBlockSize), bm.blockSize)
+	}
+	// Don't leave a sliver smaller than minBlockSize behind
+	if remaining-size < minBlockSize {
+		size = remaining
+	}
+	return size
+}
+
+// NextBlock carves the next block from the unassigned part of the file
 func (bm *BlockManager) NextBlock() (*Block, bool) {
+	if bm.ctx.Err() != nil {
+		return nil, false
+	}
+
+	bm.blocksMu.Lock()
+	defer bm.blocksMu.Unlock()
+	if bm.nextOffset >= bm.totalSize {
+		return nil, false
+	}
+
+	size := bm.nextBlockSizeLocked()
+	block := &Block{
+		Index:     len(bm.blocks),
+		StartByte: bm.nextOffset,
+		EndByte:   bm.nextOffset + size - 1,
+	}
+	block.SetStatus(blockStatusDownloading)
+	bm.blocks = append(bm.blocks, block)
+	bm.nextOffset += size
+	bm.lastBlockSize = size
+	return block, true
+}
+
+// RemainingBytes returns how many bytes have not yet been assigned to a block
+func (bm *BlockManager) RemainingBytes() int64 {
+	bm.blocksMu.Lock()
+	defer bm.blocksMu.Unlock()
+	return bm.totalSize - bm.nextOffset
+}
+
+// LastBlockSize returns the size of the most recently carved block
+func (bm *BlockManager) LastBlockSize() int64 {
+	bm.blocksMu.Lock()
+	defer bm.blocksMu.Unlock()
+	return bm.lastBlockSize
+}
+
+// BlockCount returns the number of blocks carved so far
+func (bm *BlockManager) BlockCount() int {
+	bm.blocksMu.Lock()
+	defer bm.blocksMu.Unlock()
+	return len(bm.blocks)
+}
+
+// retireWorker reports whether the calling worker should exit because the scaler
+// lowered the target worker count. A retiring worker is removed from activeWorkers.
+func (bm *BlockManager) retireWorker() bool {
 	for {
-		select {
-		case <-bm.ctx.Done():
-			return nil, false
-		case idx, ok := <-bm.queue:
-			if !ok {
-				return nil, false
-			}
-			block := bm.blocks[idx]
-			// Use CAS to ensure only one worker gets this block
-			if block.status.CompareAndSwap(blockStatusPending, blockStatusDownloading) {
-				return block, true
-			}
-			// If not pending, skip (shouldn't happen with closed queue)
+		active := bm.activeWorkers.Load()
+		if active <= bm.targetWorkers.Load() {
+			return false
+		}
+		if bm.activeWorkers.CompareAndSwap(active, active-1) {
+			return true
 		}
 	}
 }
@@ -136,11 +195,15 @@ func (bm *BlockManager) MarkFailed(block *Block, err error) {
 	bm.errMu.Unlock()
 }
 
-// IsDone checks if all blocks are either completed or failed
+// IsDone checks if the whole file has been assigned and every block is either completed or failed
 func (bm *BlockManager) IsDone() bool {
+	bm.blocksMu.Lock()
+	assigned := bm.nextOffset >= bm.totalSize
+	carved := len(bm.blocks)
+	bm.blocksMu.Unlock()
 	completed := bm.completedBlocks.Load()
 	failed := bm.failedBlocks.Load()
-	return completed+failed >= int32(len(bm.blocks))
+	return assigned && completed+failed >= int32(carved)
 }
 
 // HasFatalError returns the first fatal error if any
@@ -204,58 +267,145 @@ func createPreallocatedFile(path string, size int64) (*os.File, error) {
 	return file, nil
 }
 
-// buildDownloadTransport creates an HTTP transport with optional optimized IP
+// buildDownloadTransport creates an HTTP transport with optional optimized IP.
+// The transport follows the global proxy settings; see network.TransportOptions
+// for how a pinned IP interacts with the proxy.
 func buildDownloadTransport(bestIP string, downloadUrl string) *http.Transport {
-	transport := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
-		ForceAttemptHTTP2:     true,
-		MaxIdleConns:          100,
-		IdleConnTimeout:       90 * time.Second,
+	options := network.TransportOptions{
+		DialTimeout:           30 * time.Second,
 		TLSHandshakeTimeout:   15 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
 		ResponseHeaderTimeout: 60 * time.Second,
 	}
-
 	if bestIP != "" {
-		dialer := &net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}
-		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
-			host, port, _ := net.SplitHostPort(addr)
-			u, parseErr := url.Parse(downloadUrl)
-			if parseErr == nil && u.Hostname() == host {
-				return dialer.DialContext(ctx, network, net.JoinHostPort(bestIP, port))
-			}
-			return dialer.DialContext(ctx, network, addr)
+		if u, err := url.Parse(downloadUrl); err == nil {
+			options.PinnedIP = bestIP
+			options.PinnedHost = u.Hostname()
 		}
 	}
+	return network.NewTransport(options)
+}
+
+// ipFailover tracks the CDN IP shared by all workers of a chunked download and
+// switches to the next-best IP when the current one stalls or keeps failing.
+// A nil *ipFailover means no pinned IP is used.
+type ipFailover struct {
+	mu        sync.Mutex
+	current   string
+	fallbacks []string
+	failures  int // consecutive failures on current
+	onSwitch  func(from, to string)
+}
+
+func newIPFailover(bestIP string, fallbacks []string, onSwitch func(from, to string)) *ipFailover {
+	if bestIP == "" {
+		return nil
+	}
+	return &ipFailover{current: bestIP, fallbacks: fallbacks, onSwitch: onSwitch}
+}
+
+// Current returns the IP workers should use now
+func (f *ipFailover) Current() string {
+	if f == nil {
+		return ""
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.current
+}
+
+// CanSwitch reports whether an unused fallback IP remains to switch to
+func (f *ipFailover) CanSwitch() bool {
+	if f == nil {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.fallbacks) > 0
+}
 
-	return transport
+// ReportSuccess records a completed block on ip with its throughput in MB/s
+func (f *ipFailover) ReportSuccess(ip string, speed float64) {
+	if f == nil {
+		return
+	}
+	network.GlobalIPHealth.RecordSuccess(ip, speed)
+	f.mu.Lock()
+	if ip == f.current {
+		f.failures = 0
+	}
+	f.mu.Unlock()
 }
 
-// downloadWorker processes blocks from the queue, reusing a single http.Client per worker
+// ReportFailure records a failed block on ip. It returns true when the worker
+// should retry on a different IP, either because this failure triggered a switch
+// or because another worker already switched away from ip.
+func (f *ipFailover) ReportFailure(ip string, stalled bool) bool {
+	if f == nil {
+		return false
+	}
+	network.GlobalIPHealth.RecordFailure(ip)
+
+	f.mu.Lock()
+	if ip != f.current {
+		f.mu.Unlock()
+		return true
+	}
+	f.failures++
+	if (!stalled && f.failures < blockFailoverThreshold) || len(f.fallbacks) == 0 {
+		f.mu.Unlock()
+		return false
+	}
+	from := f.current
+	f.current = f.fallbacks[0]
+	f.fallbacks = f.fallbacks[1:]
+	f.failures = 0
+	to := f.current
+	onSwitch := f.onSwitch
+	f.mu.Unlock()
+
+	if onSwitch != nil {
+		onSwitch(from, to)
+	}
+	return true
+}
+
+func newDownloadClient(ip string, downloadUrl string) *http.Client {
+	return &http.Client{
+		Transport: buildDownloadTransport(ip, downloadUrl),
+		Timeout:   0,
+	}
+}
+
+// downloadWorker processes blocks until the file is fully assigned, reusing a single http.Client
+// per worker. The client is rebuilt whenever the shared failover switches to a different IP.
+// The caller must add the worker to bm.activeWorkers before starting it; the worker removes
+// itself on exit, or earlier when the scaler retires it between blocks.
 func (a *App) downloadWorker(
 	_ int,
 	bm *BlockManager,
 	file *os.File,
 	downloadUrl string,
-	bestIP string,
+	failover *ipFailover,
 ) {
 	const maxRetries = 3
 
-	// One client per worker so connections can be reused across blocks via HTTP keep-alive
-	transport := buildDownloadTransport(bestIP, downloadUrl)
-	client := &http.Client{
-		Transport: transport,
-		Timeout:   0,
-	}
+	// Transports are shared by every download pinned to the same IP (see network.NewTransport),
+	// so the worker never closes idle connections; the pool expires them on its own
+	ip := failover.Current()
+	client := newDownloadClient(ip, downloadUrl)
+	retired := false
+	defer func() {
+		if !retired {
+			bm.activeWorkers.Add(-1)
+		}
+	}()
 
 	for {
+		if bm.retireWorker() {
+			retired = true
+			return
+		}
+
 		block, ok := bm.NextBlock()
 		if !ok {
 			return
@@ -271,9 +421,19 @@ func (a *App) downloadWorker(
 				}
 			}
 
-			err := a.downloadBlock(bm.ctx, block, file, client, downloadUrl, bm)
+			if current := failover.Current(); current != ip {
+				ip = current
+				client = newDownloadClient(ip, downloadUrl)
+			}
+
+			start := time.Now()
+			// Stall detection only matters when there is another IP to switch to;
+			// otherwise a slow but working IP is better than failing the download
+			err := a.downloadBlock(bm.ctx, block, file, client, downloadUrl, bm, failover.CanSwitch())
 			if err == nil {
 				bm.MarkCompleted(block)
+				elapsed := max(time.Since(start).Seconds(), 0.001)
+				failover.ReportSuccess(ip, float64(block.EndByte-block.StartByte+1)/1024/1024/elapsed)
 				break
 			}
 
@@ -288,6 +448,12 @@ func (a *App) downloadWorker(
 			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 				return
 			}
+
+			// Retries on a freshly switched IP don't count against the block's retry budget.
+			// The fallback list is finite, so this cannot loop forever.
+			if failover.ReportFailure(ip, errors.Is(err, errBlockStalled)) {
+				attempt--
+			}
 		}
 
 		if block.Status() != blockStatusCompleted {
@@ -298,7 +464,31 @@ func (a *App) downloadWorker(
 	}
 }
 
-// downloadBlock downloads a single block and writes it directly to the file at the correct offset
+// watchBlockThroughput cancels the block with errBlockStalled when fewer than
+// blockStallMinBytes arrive within any blockStallWindow.
+func watchBlockThroughput(ctx context.Context, cancel context.CancelCauseFunc, received *atomic.Int64) {
+	ticker := time.NewTicker(blockStallWindow)
+	defer ticker.Stop()
+
+	var last int64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := received.Load()
+			if current-last < blockStallMinBytes {
+				cancel(errBlockStalled)
+				return
+			}
+			last = current
+		}
+	}
+}
+
+// downloadBlock downloads a single block and writes it directly to the file at the correct offset.
+// On failure the bytes counted for this attempt are rolled back so retries don't inflate progress.
+// With detectStall set, a block that slows to a crawl fails with errBlockStalled.
 func (a *App) downloadBlock(
 	ctx context.Context,
 	block *Block,
@@ -306,8 +496,26 @@ func (a *App) downloadBlock(
 	client *http.Client,
 	downloadUrl string,
 	bm *BlockManager,
-) error {
-	req, err := http.NewRequestWithContext(ctx, "GET", downloadUrl, nil)
+	detectStall bool,
+) (err error) {
+	blockCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var received atomic.Int64
+	if detectStall {
+		go watchBlockThroughput(blockCtx, cancel, &received)
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		bm.completedBytes.Add(-received.Load())
+		if ctx.Err() == nil && errors.Is(context.Cause(blockCtx), errBlockStalled) {
+			err = errBlockStalled
+		}
+	}()
+
+	req, err := http.NewRequestWithContext(blockCtx, "GET", downloadUrl, nil)
 	if err != nil {
 		return err
 	}
@@ -341,6 +549,7 @@ func (a *App) downloadBlock(
 				return fmt.Errorf("write at offset %d failed: %w", offset, writeErr)
 			}
 			offset += int64(written)
+			received.Add(int64(written))
 			bm.completedBytes.Add(int64(written))
 		}
 		if readErr != nil {
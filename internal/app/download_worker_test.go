@@ -0,0 +1,163 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIPFailoverSwitchesOnStallAndRepeatedErrors(t *testing.T) {
+	var switches []string
+	failover := newIPFailover("23.59.72.59", []string{"23.59.72.42", "23.55.51.221"}, func(from, to string) {
+		switches = append(switches, from+"->"+to)
+	})
+
+	if !failover.CanSwitch() {
+		t.Fatalf("expected fallbacks to allow switching")
+	}
+	if failover.ReportFailure("23.59.72.59", false) {
+		t.Fatalf("expected a single error not to trigger failover")
+	}
+	if !failover.ReportFailure("23.59.72.59", false) || failover.Current() != "23.59.72.42" {
+		t.Fatalf("expected repeated errors to switch IP, current=%s", failover.Current())
+	}
+	if !failover.ReportFailure("23.59.72.59", false) {
+		t.Fatalf("expected stale IP failure to ask for retry on the new IP")
+	}
+	if !failover.ReportFailure("23.59.72.42", true) || failover.Current() != "23.55.51.221" {
+		t.Fatalf("expected stall to switch immediately, current=%s", failover.Current())
+	}
+	if failover.CanSwitch() || failover.ReportFailure("23.55.51.221", true) {
+		t.Fatalf("expected no switch once fallbacks are exhausted")
+	}
+	if len(switches) != 2 {
+		t.Fatalf("expected two switches, got %v", switches)
+	}
+
+	// Without fallbacks, stall detection stays off
+	if newIPFailover("23.59.72.59", nil, nil).CanSwitch() {
+		t.Fatalf("expected a failover without fallbacks not to switch")
+	}
+
+	var none *ipFailover
+	if none.Current() != "" || none.CanSwitch() || none.ReportFailure("", true) {
+		t.Fatalf("expected nil failover to be inert")
+	}
+}
+
+func TestDownloadBlockDetectsStallAndRollsBackProgress(t *testing.T) {
+	originalWindow, originalMin := blockStallWindow, blockStallMinBytes
+	blockStallWindow, blockStallMinBytes = 100*time.Millisecond, 1024
+	t.Cleanup(func() { blockStallWindow, blockStallMinBytes = originalWindow, originalMin })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Range", "bytes 0-4095/4096")
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(make([]byte, 2048))
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "block"))
+	if err != nil {
+		t.Fatalf("create file: %v", err)
+	}
+	defer file.Close()
+
+	app := &App{}
+	bm := NewBlockManager(4096, 1, 4096)
+	block, _ := bm.NextBlock()
+	err = app.downloadBlock(context.Background(), block, file, server.Client(), server.URL, bm, true)
+	if !errors.Is(err, errBlockStalled) {
+		t.Fatalf("expected stall error, got %v", err)
+	}
+	if got := bm.completedBytes.Load(); got != 0 {
+		t.Fatalf("expected progress to be rolled back, got %d bytes", got)
+	}
+}
+
+func TestBlockManagerShrinksBlocksNearTheEnd(t *testing.T) {
+	const base = 4 * 1024 * 1024
+	bm := NewBlockManager(10*base, 2, base)
+	bm.activeWorkers.Store(2)
+
+	var sizes []int64
+	var covered int64
+	for {
+		block, ok := bm.NextBlock()
+		if !ok {
+			break
+		}
+		if block.StartByte != covered {
+			t.Fatalf("block %d starts at %d, expected %d", block.Index, block.StartByte, covered)
+		}
+		size := block.EndByte - block.StartByte + 1
+		sizes = append(sizes, size)
+		covered += size
+	}
+
+	if covered != 10*base {
+		t.Fatalf("expected blocks to cover the whole file, got %d", covered)
+	}
+	if sizes[0] != base {
+		t.Fatalf("expected full-size blocks away from the tail, got %d", sizes[0])
+	}
+	last := sizes[len(sizes)-1]
+	if last >= base || last < minBlockSize {
+		t.Fatalf("expected a shrunken tail block within bounds, got sizes %v", sizes)
+	}
+}
+
+func TestBlockManagerRetiresWorkersAboveTarget(t *testing.T) {
+	bm := NewBlockManager(1024, 3, 0)
+	bm.activeWorkers.Store(3)
+	bm.targetWorkers.Store(2)
+
+	if !bm.retireWorker() {
+		t.Fatalf("expected one worker to retire")
+	}
+	if bm.retireWorker() {
+		t.Fatalf("expected remaining workers to keep running")
+	}
+	if got := bm.activeWorkers.Load(); got != 2 {
+		t.Fatalf("expected 2 active workers, got %d", got)
+	}
+}
+
+func TestWorkerScalerAddsWhileThroughputGrows(t *testing.T) {
+	scaler := newWorkerScaler(8)
+	workers := scaler.initialWorkers()
+	if workers != 4 {
+		t.Fatalf("expected to start at half the cap, got %d", workers)
+	}
+
+	workers, reason := scaler.decide(workers, 4<<20, true)
+	if workers != 5 || reason == "" {
+		t.Fatalf("expected first sample to probe upwards, got %d %q", workers, reason)
+	}
+	workers, _ = scaler.decide(workers, 5<<20, true)
+	if workers != 6 {
+		t.Fatalf("expected growth to add another worker, got %d", workers)
+	}
+	workers, reason = scaler.decide(workers, 5<<20, true)
+	if workers != 5 || reason == "" {
+		t.Fatalf("expected flat throughput to drop the added worker, got %d %q", workers, reason)
+	}
+	for range scaleCooldownTicks {
+		if next, _ := scaler.decide(workers, 5<<20, true); next != workers {
+			t.Fatalf("expected no change during cooldown, got %d", next)
+		}
+	}
+	if next, _ := scaler.decide(workers, 5<<20, false); next != workers {
+		t.Fatalf("expected no growth without remaining work, got %d", next)
+	}
+}
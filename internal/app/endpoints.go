@@ -0,0 +1,329 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"vpk-manager/internal/network"
+)
+
+// 远程服务类型，对应 EndpointConfig 中的字段
+const (
+	endpointWorkshopWorker     = "workshopWorker"
+	endpointWorkshopParse      = "workshopParse"
+	endpointMapName            = "mapName"
+	endpointIPList             = "ipList"
+	endpointYandexTranslate    = "yandexTranslate"
+	endpointMicrosoftTranslate = "microsoftTranslate"
+	endpointGithubAPI          = "githubAPI"
+	endpointUpdateMirror       = "updateMirror"
+)
+
+var (
+	workshopParseURL = "https://l4d2-workshop-parse.laoyutang.cn"
+	mapNameURL       = "https://l4d2-maps.laoyutang.cn"
+	githubAPIURL     = "https://api.github.com"
+)
+
+// EndpointConfig 远程服务地址配置，留空表示使用内置默认地址。
+// 自定义地址请求失败时会自动回退到默认地址。
+type EndpointConfig struct {
+	WorkshopWorker     string   `json:"workshopWorker,omitempty"`
+	WorkshopParse      string   `json:"workshopParse,omitempty"`
+	MapName            string   `json:"mapName,omitempty"`
+	IPList             string   `json:"ipList,omitempty"`
+	YandexTranslate    string   `json:"yandexTranslate,omitempty"`
+	MicrosoftTranslate string   `json:"microsoftTranslate,omitempty"`
+	GithubAPI          string   `json:"githubAPI,omitempty"`
+	UpdateMirrors      []string `json:"updateMirrors,omitempty"`
+}
+
+// EndpointHealth 单个远程服务地址的健康检查结果
+type EndpointHealth struct {
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	IsDefault  bool   `json:"is_default"`
+	OK         bool   `json:"ok"`
+	StatusCode int    `json:"status_code"`
+	Latency    int64  `json:"latency"` // 毫秒，-1 表示超时或错误
+	Error      string `json:"error,omitempty"`
+}
+
+var endpointHealthClient = network.NewHTTPClient(5 * time.Second)
+
+// endpointNames 返回所有可配置的远程服务类型
+func endpointNames() []string {
+	return []string{
+		endpointWorkshopWorker,
+		endpointWorkshopParse,
+		endpointMapName,
+		endpointIPList,
+		endpointYandexTranslate,
+		endpointMicrosoftTranslate,
+		endpointGithubAPI,
+		endpointUpdateMirror,
+	}
+}
+
+// defaultEndpoint 返回内置默认地址，更新镜像返回空（由 MirrorList 提供）
+func defaultEndpoint(name string) string {
+	switch name {
+	case endpointWorkshopWorker:
+		return WorkshopWorkerURL
+	case endpointWorkshopParse:
+		return workshopParseURL
+	case endpointMapName:
+		return mapNameURL
+	case endpointIPList:
+		return network.DefaultIPListURL
+	case endpointYandexTranslate:
+		return yandexTranslateBaseURL
+	case endpointMicrosoftTranslate:
+		return microsoftTranslateBaseURL
+	case endpointGithubAPI:
+		return githubAPIURL
+	default:
+		return ""
+	}
+}
+
+func (c EndpointConfig) get(name string) string {
+	switch name {
+	case endpointWorkshopWorker:
+		return c.WorkshopWorker
+	case endpointWorkshopParse:
+		return c.WorkshopParse
+	case endpointMapName:
+		return c.MapName
+	case endpointIPList:
+		return c.IPList
+	case endpointYandexTranslate:
+		return c.YandexTranslate
+	case endpointMicrosoftTranslate:
+		return c.MicrosoftTranslate
+	case endpointGithubAPI:
+		return c.GithubAPI
+	default:
+		return ""
+	}
+}
+
+// normalizeEndpointURL 校验并规范化地址，空字符串表示使用默认值
+func normalizeEndpointURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("无效的地址 %q: %w", raw, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return "", fmt.Errorf("地址 %q 必须以 http:// 或 https:// 开头", raw)
+	}
+	if parsed.Host == "" {
+		return "", fmt.Errorf("地址 %q 缺少主机名", raw)
+	}
+	return strings.TrimRight(raw, "/"), nil
+}
+
+func normalizeEndpointConfig(config EndpointConfig) (EndpointConfig, error) {
+	var next EndpointConfig
+	fields := []struct {
+		src *string
+		dst *string
+	}{
+		{&config.WorkshopWorker, &next.WorkshopWorker},
+		{&config.WorkshopParse, &next.WorkshopParse},
+		{&config.MapName, &next.MapName},
+		{&config.IPList, &next.IPList},
+		{&config.YandexTranslate, &next.YandexTranslate},
+		{&config.MicrosoftTranslate, &next.MicrosoftTranslate},
+		{&config.GithubAPI, &next.GithubAPI},
+	}
+	for _, field := range fields {
+		normalized, err := normalizeEndpointURL(*field.src)
+		if err != nil {
+			return EndpointConfig{}, err
+		}
+		*field.dst = normalized
+	}
+
+	seen := make(map[string]bool, len(config.UpdateMirrors))
+	for _, mirror := range config.UpdateMirrors {
+		normalized, err := normalizeEndpointURL(mirror)
+		if err != nil {
+			return EndpointConfig{}, err
+		}
+		if normalized == "" {
+			continue
+		}
+		// 镜像地址以拼接方式使用，统一保留结尾斜杠
+		normalized += "/"
+		if seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		next.UpdateMirrors = append(next.UpdateMirrors, normalized)
+	}
+	return next, nil
+}
+
+func cloneEndpointConfig(config EndpointConfig) EndpointConfig {
+	if config.UpdateMirrors != nil {
+		config.UpdateMirrors = append([]string(nil), config.UpdateMirrors...)
+	}
+	return config
+}
+
+// GetEndpointConfig 获取远程服务地址配置
+func (a *App) GetEndpointConfig() EndpointConfig {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return cloneEndpointConfig(a.endpoints)
+}
+
+// GetDefaultEndpointConfig 获取内置默认地址，供前端展示占位
+func (a *App) GetDefaultEndpointConfig() EndpointConfig {
+	return EndpointConfig{
+		WorkshopWorker:     defaultEndpoint(endpointWorkshopWorker),
+		WorkshopParse:      defaultEndpoint(endpointWorkshopParse),
+		MapName:            defaultEndpoint(endpointMapName),
+		IPList:             defaultEndpoint(endpointIPList),
+		YandexTranslate:    defaultEndpoint(endpointYandexTranslate),
+		MicrosoftTranslate: defaultEndpoint(endpointMicrosoftTranslate),
+		GithubAPI:          defaultEndpoint(endpointGithubAPI),
+		UpdateMirrors:      append([]string(nil), MirrorList...),
+	}
+}
+
+// SetEndpointConfig 设置远程服务地址，留空的项恢复默认地址
+func (a *App) SetEndpointConfig(config EndpointConfig) error {
+	normalized, err := normalizeEndpointConfig(config)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.endpoints = normalized
+	a.mu.Unlock()
+
+	network.SetIPListURL(normalized.IPList)
+	a.saveConfig()
+	return nil
+}
+
+// endpointCandidates 返回按优先级排列的地址：自定义地址在前，默认地址兜底
+func (a *App) endpointCandidates(name string) []string {
+	a.mu.RLock()
+	custom := a.endpoints.get(name)
+	a.mu.RUnlock()
+
+	fallback := strings.TrimRight(defaultEndpoint(name), "/")
+	if custom == "" || custom == fallback {
+		return []string{fallback}
+	}
+	return []string{custom, fallback}
+}
+
+// updateMirrors 返回更新镜像列表：自定义镜像在前，内置镜像兜底
+func (a *App) updateMirrors() []string {
+	a.mu.RLock()
+	custom := append([]string(nil), a.endpoints.UpdateMirrors...)
+	a.mu.RUnlock()
+
+	mirrors := make([]string, 0, len(custom)+len(MirrorList))
+	seen := make(map[string]bool, len(custom)+len(MirrorList))
+	for _, mirror := range append(custom, MirrorList...) {
+		if seen[mirror] {
+			continue
+		}
+		seen[mirror] = true
+		mirrors = append(mirrors, mirror)
+	}
+	return mirrors
+}
+
+// shouldFallbackEndpoint 判断请求结果是否需要切换到下一个地址
+func shouldFallbackEndpoint(statusCode int, err error) bool {
+	return err != nil || statusCode >= http.StatusInternalServerError
+}
+
+// CheckEndpointHealth 检查指定远程服务的自定义地址和默认地址
+func (a *App) CheckEndpointHealth(name string) ([]EndpointHealth, error) {
+	if name == endpointUpdateMirror {
+		mirrors := a.updateMirrors()
+		results := make([]EndpointHealth, len(mirrors))
+		defaults := make(map[string]bool, len(MirrorList))
+		for _, mirror := range MirrorList {
+			defaults[mirror] = true
+		}
+		var wg sync.WaitGroup
+		for i, mirror := range mirrors {
+			wg.Add(1)
+			go func(i int, mirror string) {
+				defer wg.Done()
+				results[i] = probeEndpoint(name, mirror, defaults[mirror])
+			}(i, mirror)
+		}
+		wg.Wait()
+		return results, nil
+	}
+
+	if defaultEndpoint(name) == "" {
+		return nil, fmt.Errorf("未知的服务类型: %s", name)
+	}
+
+	candidates := a.endpointCandidates(name)
+	results := make([]EndpointHealth, len(candidates))
+	var wg sync.WaitGroup
+	for i, candidate := range candidates {
+		wg.Add(1)
+		go func(i int, candidate string) {
+			defer wg.Done()
+			results[i] = probeEndpoint(name, candidate, i == len(candidates)-1)
+		}(i, candidate)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// CheckAllEndpointsHealth 检查所有远程服务地址
+func (a *App) CheckAllEndpointsHealth() []EndpointHealth {
+	var results []EndpointHealth
+	for _, name := range endpointNames() {
+		health, err := a.CheckEndpointHealth(name)
+		if err != nil {
+			continue
+		}
+		results = append(results, health...)
+	}
+	return results
+}
+
+// probeEndpoint 发起一次请求检测地址是否可达。
+// 服务根路径可能返回 404/405，只要不是 5xx 即视为可用。
+func probeEndpoint(name string, target string, isDefault bool) EndpointHealth {
+	health := EndpointHealth{Name: name, URL: target, IsDefault: isDefault, Latency: -1}
+
+	start := time.Now()
+	resp, err := endpointHealthClient.Get(target)
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	defer resp.Body.Close()
+
+	health.StatusCode = resp.StatusCode
+	if resp.StatusCode >= http.StatusInternalServerError {
+		health.Error = fmt.Sprintf("服务返回状态码: %d", resp.StatusCode)
+		return health
+	}
+	health.OK = true
+	health.Latency = time.Since(start).Milliseconds()
+	return health
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+)
+
+func TestSetEndpointConfigValidatesAndPersists(t *testing.T) {
+	app := newConfigTestApp(t)
+
+	if err := app.SetEndpointConfig(EndpointConfig{WorkshopWorker: "ftp://example.com"}); err == nil {
+		t.Fatalf("expected non-http endpoint to be rejected")
+	}
+
+	err := app.SetEndpointConfig(EndpointConfig{
+		WorkshopWorker: " http://127.0.0.1:8787/ ",
+		UpdateMirrors:  []string{"https://mirror.example.com", "https://mirror.example.com/"},
+	})
+	if err != nil {
+		t.Fatalf("set endpoints: %v", err)
+	}
+
+	reloaded := newConfigTestApp(t)
+	reloaded.configDir = app.configDir
+	reloaded.configPath = app.configPath
+	reloaded.loadConfig()
+
+	config := reloaded.GetEndpointConfig()
+	if config.WorkshopWorker != "http://127.0.0.1:8787" {
+		t.Fatalf("expected normalized worker endpoint, got %q", config.WorkshopWorker)
+	}
+	if len(config.UpdateMirrors) != 1 || config.UpdateMirrors[0] != "https://mirror.example.com/" {
+		t.Fatalf("expected deduplicated mirror list, got %#v", config.UpdateMirrors)
+	}
+
+	mirrors := reloaded.updateMirrors()
+	if len(mirrors) != len(MirrorList)+1 || mirrors[0] != "https://mirror.example.com/" {
+		t.Fatalf("expected custom mirror before defaults, got %#v", mirrors)
+	}
+}
+
+func TestGetMapNameFallsBackToDefaultEndpoint(t *testing.T) {
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer broken.Close()
+
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/c1m1_hotel" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		w.Write([]byte(" 死亡中心 \n"))
+	}))
+	defer fallback.Close()
+
+	originalURL := mapNameURL
+	mapNameURL = fallback.URL
+	defer func() { mapNameURL = originalURL }()
+
+	app := newConfigTestApp(t)
+	app.restyClient = resty.New()
+	if err := app.SetEndpointConfig(EndpointConfig{MapName: broken.URL}); err != nil {
+		t.Fatalf("set endpoints: %v", err)
+	}
+
+	if got := app.GetMapName("c1m1_hotel"); got != "死亡中心" {
+		t.Fatalf("expected fallback map name, got %q", got)
+	}
+}
+
+func TestCheckEndpointHealthReportsCustomAndDefault(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer healthy.Close()
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer broken.Close()
+
+	originalURL := workshopParseURL
+	workshopParseURL = healthy.URL
+	defer func() { workshopParseURL = originalURL }()
+
+	app := newConfigTestApp(t)
+	if err := app.SetEndpointConfig(EndpointConfig{WorkshopParse: broken.URL}); err != nil {
+		t.Fatalf("set endpoints: %v", err)
+	}
+
+	results, err := app.CheckEndpointHealth(endpointWorkshopParse)
+	if err != nil {
+		t.Fatalf("check health: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected custom and default results, got %#v", results)
+	}
+	if results[0].OK || results[0].IsDefault || results[0].StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected custom endpoint to be unhealthy, got %#v", results[0])
+	}
+	if !results[1].OK || !results[1].IsDefault {
+		t.Fatalf("expected default endpoint to be healthy, got %#v", results[1])
+	}
+
+	if _, err := app.CheckEndpointHealth("unknown"); err == nil {
+		t.Fatalf("expected unknown endpoint to be rejected")
+	}
+}
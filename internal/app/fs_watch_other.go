@@ -0,0 +1,74 @@
+//go:build !windows
+
+package app
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// watchPollInterval 非 Windows 平台轮询目录的间隔，测试中可调小
+var watchPollInterval = 2 * time.Second
+
+type watchEntry struct {
+	size    int64
+	modTime time.Time
+}
+
+// startDirWatch 在非 Windows 平台上定期对比目录快照，每个变化的路径回调 onChange。
+// stop 关闭后停止轮询。
+func startDirWatch(dir string, recursive bool, onChange func(string), stop <-chan struct{}) error {
+	if _, err := os.Stat(dir); err != nil {
+		return err
+	}
+
+	go func() {
+		previous := snapshotDir(dir, recursive)
+		ticker := time.NewTicker(watchPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+
+			current := snapshotDir(dir, recursive)
+			for path, entry := range current {
+				if old, ok := previous[path]; !ok || old != entry {
+					onChange(path)
+				}
+			}
+			for path := range previous {
+				if _, ok := current[path]; !ok {
+					onChange(path)
+				}
+			}
+			previous = current
+		}
+	}()
+	return nil
+}
+
+func snapshotDir(dir string, recursive bool) map[string]watchEntry {
+	snapshot := make(map[string]watchEntry)
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == dir {
+			return nil
+		}
+		if d.IsDir() {
+			snapshot[path] = watchEntry{}
+			if !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			snapshot[path] = watchEntry{size: info.Size(), modTime: info.ModTime()}
+		}
+		return nil
+	})
+	return snapshot
+}
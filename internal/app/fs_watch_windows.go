@@ -0,0 +1,106 @@
+//go:build windows
+
+package app
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// watchBufferSize ReadDirectoryChangesW 的缓冲区大小，溢出时会整体重新对账
+const watchBufferSize = 64 * 1024
+
+const watchNotifyFilter = windows.FILE_NOTIFY_CHANGE_FILE_NAME |
+	windows.FILE_NOTIFY_CHANGE_DIR_NAME |
+	windows.FILE_NOTIFY_CHANGE_SIZE |
+	windows.FILE_NOTIFY_CHANGE_LAST_WRITE
+
+// startDirWatch 使用 ReadDirectoryChangesW 监听目录，每个变化的完整路径回调 onChange。
+// 缓冲区溢出时回调目录本身。stop 关闭后停止监听并释放句柄。
+func startDirWatch(dir string, recursive bool, onChange func(string), stop <-chan struct{}) error {
+	dirPtr, err := windows.UTF16PtrFromString(dir)
+	if err != nil {
+		return err
+	}
+	handle, err := windows.CreateFile(dirPtr,
+		windows.FILE_LIST_DIRECTORY,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE|windows.FILE_SHARE_DELETE,
+		nil,
+		windows.OPEN_EXISTING,
+		windows.FILE_FLAG_BACKUP_SEMANTICS|windows.FILE_FLAG_OVERLAPPED,
+		0)
+	if err != nil {
+		return fmt.Errorf("打开目录失败: %w", err)
+	}
+
+	changeEvent, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		windows.CloseHandle(handle)
+		return fmt.Errorf("创建事件失败: %w", err)
+	}
+	stopEvent, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		windows.CloseHandle(changeEvent)
+		windows.CloseHandle(handle)
+		return fmt.Errorf("创建事件失败: %w", err)
+	}
+
+	go func() {
+		<-stop
+		windows.SetEvent(stopEvent)
+	}()
+
+	go func() {
+		defer windows.CloseHandle(stopEvent)
+		defer windows.CloseHandle(changeEvent)
+		defer windows.CloseHandle(handle)
+
+		buf := make([]byte, watchBufferSize)
+		overlapped := &windows.Overlapped{HEvent: changeEvent}
+		for {
+			windows.ResetEvent(changeEvent)
+			err := windows.ReadDirectoryChanges(handle, &buf[0], uint32(len(buf)), recursive,
+				watchNotifyFilter, nil, overlapped, 0)
+			if err != nil && err != windows.ERROR_IO_PENDING {
+				log.Printf("监听目录失败: %s, 错误: %v", dir, err)
+				return
+			}
+
+			index, err := windows.WaitForMultipleObjects([]windows.Handle{changeEvent, stopEvent}, false, windows.INFINITE)
+			if err != nil || index != windows.WAIT_OBJECT_0 {
+				// 停止监听：取消挂起的读取并等待其结束，之后才能释放缓冲区
+				windows.CancelIoEx(handle, overlapped)
+				var n uint32
+				windows.GetOverlappedResult(handle, overlapped, &n, true)
+				return
+			}
+
+			var n uint32
+			if err := windows.GetOverlappedResult(handle, overlapped, &n, false); err != nil {
+				log.Printf("读取目录变化失败: %s, 错误: %v", dir, err)
+				return
+			}
+			if n == 0 {
+				// 缓冲区溢出，变化已丢失，让监听器重新对账整个目录
+				onChange(dir)
+				continue
+			}
+
+			var offset uint32
+			for {
+				info := (*windows.FileNotifyInformation)(unsafe.Pointer(&buf[offset]))
+				name := windows.UTF16ToString(unsafe.Slice(&info.FileName, info.FileNameLength/2))
+				onChange(filepath.Join(dir, name))
+				if info.NextEntryOffset == 0 {
+					break
+				}
+				offset += info.NextEntryOffset
+			}
+		}
+	}()
+	return nil
+}
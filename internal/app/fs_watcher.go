@@ -0,0 +1,367 @@
+package app
+
+import (
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// watchDebounce 文件变化的防抖时间，复制大文件时会连续产生大量事件
+const watchDebounce = 800 * time.Millisecond
+
+// VPKFilesChange 文件监听产生的增量变化
+type VPKFilesChange struct {
+	Added   []VPKFile `json:"added"`
+	Updated []VPKFile `json:"updated"`
+	Removed []string  `json:"removed"`
+}
+
+func (c VPKFilesChange) empty() bool {
+	return len(c.Added) == 0 && len(c.Updated) == 0 && len(c.Removed) == 0
+}
+
+// addonWatcher 监听根目录、workshop 和 disabled 目录，增量更新 vpkCache
+type addonWatcher struct {
+	app      *App
+	root     string
+	emit     func(VPKFilesChange)
+	stop     chan struct{}
+	pending  map[string]struct{}
+	timer    *time.Timer
+	watching map[string]bool
+	mu       sync.Mutex
+}
+
+// watchIgnoreSet 应用自身正在写入的路径，写入完成前不处理其变化
+type watchIgnoreSet struct {
+	paths map[string]int
+	mu    sync.Mutex
+}
+
+func (s *watchIgnoreSet) add(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.paths == nil {
+		s.paths = make(map[string]int)
+	}
+	s.paths[filepath.Clean(path)]++
+}
+
+func (s *watchIgnoreSet) remove(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path = filepath.Clean(path)
+	if s.paths[path] <= 1 {
+		delete(s.paths, path)
+		return
+	}
+	s.paths[path]--
+}
+
+func (s *watchIgnoreSet) contains(path string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.paths[filepath.Clean(path)] > 0
+}
+
+// ignoreWatchPath 在应用自身写入文件期间忽略该路径的变化（如打包中的 VPK）。
+// 返回的函数结束忽略，并补发一次变化让监听器处理写好的文件。
+func (a *App) ignoreWatchPath(path string) func() {
+	a.watchIgnores.add(path)
+	return func() {
+		a.watchIgnores.remove(path)
+		a.mu.RLock()
+		watcher := a.addonWatcher
+		a.mu.RUnlock()
+		if watcher != nil {
+			watcher.notify(path)
+		}
+	}
+}
+
+// startAddonWatcher 为当前根目录启动文件监听，已有的监听会先停止
+func (a *App) startAddonWatcher() {
+	a.mu.Lock()
+	root := a.rootDir
+	previous := a.addonWatcher
+	a.addonWatcher = nil
+	a.mu.Unlock()
+
+	if previous != nil {
+		previous.close()
+	}
+	if root == "" {
+		return
+	}
+
+	watcher := newAddonWatcher(a, root, func(change VPKFilesChange) {
+		if a.ctx != nil {
+			runtime.EventsEmit(a.ctx, "vpk_files_changed", change)
+		}
+	})
+	watcher.ensureWatches()
+
+	a.mu.Lock()
+	a.addonWatcher = watcher
+	a.mu.Unlock()
+	log.Printf("已启动目录监听: %s", root)
+}
+
+// stopAddonWatcher 停止文件监听
+func (a *App) stopAddonWatcher() {
+	a.mu.Lock()
+	watcher := a.addonWatcher
+	a.addonWatcher = nil
+	a.mu.Unlock()
+	if watcher != nil {
+		watcher.close()
+	}
+}
+
+func newAddonWatcher(app *App, root string, emit func(VPKFilesChange)) *addonWatcher {
+	return &addonWatcher{
+		app:      app,
+		root:     filepath.Clean(root),
+		emit:     emit,
+		stop:     make(chan struct{}),
+		pending:  make(map[string]struct{}),
+		watching: make(map[string]bool),
+	}
+}
+
+// ensureWatches 监听根目录（不含子目录）以及已存在的 workshop、disabled 目录（含子目录）。
+// workshop/disabled 在之后被创建时会在处理变化时补上监听。
+func (w *addonWatcher) ensureWatches() {
+	dirs := []struct {
+		path      string
+		recursive bool
+	}{
+		{w.root, false},
+		{filepath.Join(w.root, "workshop"), true},
+		{filepath.Join(w.root, "disabled"), true},
+	}
+
+	for _, dir := range dirs {
+		w.mu.Lock()
+		watched := w.watching[dir.path]
+		w.mu.Unlock()
+		if watched {
+			continue
+		}
+		if info, err := os.Stat(dir.path); err != nil || !info.IsDir() {
+			continue
+		}
+		if err := startDirWatch(dir.path, dir.recursive, w.notify, w.stop); err != nil {
+			log.Printf("监听目录失败: %s, 错误: %v", dir.path, err)
+			continue
+		}
+		w.mu.Lock()
+		w.watching[dir.path] = true
+		w.mu.Unlock()
+	}
+}
+
+func (w *addonWatcher) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	select {
+	case <-w.stop:
+		return
+	default:
+	}
+	close(w.stop)
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+}
+
+// notify 记录一个变化的路径，防抖后统一处理
+func (w *addonWatcher) notify(path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	select {
+	case <-w.stop:
+		return
+	default:
+	}
+
+	w.pending[filepath.Clean(path)] = struct{}{}
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.timer = time.AfterFunc(watchDebounce, w.flush)
+}
+
+// isTempPath 判断是否为应用自身的临时文件（下载临时目录、导入时的隐藏临时文件）
+func (w *addonWatcher) isTempPath(path string) bool {
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil {
+		return true
+	}
+	first := strings.Split(rel, string(filepath.Separator))[0]
+	if first == "temp" {
+		return true
+	}
+	name := filepath.Base(path)
+	return strings.HasPrefix(name, ".") || strings.Contains(name, ".tmp")
+}
+
+// inScope 判断 VPK 路径是否属于扫描范围：根目录下的文件，或 workshop/disabled 下任意层级的文件
+func (w *addonWatcher) inScope(path string) bool {
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	parts := strings.Split(rel, string(filepath.Separator))
+	if len(parts) == 1 {
+		return true
+	}
+	return parts[0] == "workshop" || parts[0] == "disabled"
+}
+
+// vpkPathForChange 将变化的路径映射到对应的 VPK 文件：预览图和 meta 的变化会影响同名 VPK
+func vpkPathForChange(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".vpk":
+		return path
+	case ".jpg", ".jpeg", ".png", ".gif", ".meta":
+		return strings.TrimSuffix(path, filepath.Ext(path)) + ".vpk"
+	default:
+		return ""
+	}
+}
+
+// flush 处理防抖期间累积的变化，并发出一次增量事件
+func (w *addonWatcher) flush() {
+	w.mu.Lock()
+	paths := make([]string, 0, len(w.pending))
+	for path := range w.pending {
+		paths = append(paths, path)
+	}
+	w.pending = make(map[string]struct{})
+	w.timer = nil
+	w.mu.Unlock()
+
+	// workshop/disabled 目录可能刚被创建
+	w.ensureWatches()
+
+	var change VPKFilesChange
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		if w.isTempPath(path) {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			w.syncDirectory(path, &change, seen)
+			continue
+		}
+		if vpkPath := vpkPathForChange(path); vpkPath != "" {
+			w.syncFile(vpkPath, &change, seen)
+		} else {
+			// 目录被删除或移走时，清理其中的缓存条目
+			w.removeUnder(path, &change, seen)
+		}
+	}
+
+	if !change.empty() {
+		if w.emit != nil {
+			w.emit(change)
+		}
+		if err := w.app.pathIndex.save(); err != nil {
+			log.Printf("保存文件索引失败: %v", err)
+		}
+		w.app.refreshSmartCollections()
+	}
+}
+
+// syncFile 根据文件当前状态更新缓存：文件不存在则移除，否则增量解析
+func (w *addonWatcher) syncFile(path string, change *VPKFilesChange, seen map[string]bool) {
+	if seen[path] || !w.inScope(path) || w.isTempPath(path) || w.app.watchIgnores.contains(path) {
+		return
+	}
+	seen[path] = true
+
+	app := w.app
+	before, had := app.vpkCache.Load(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		if had {
+			app.vpkCache.Delete(path)
+			app.pathIndex.remove(path)
+			change.Removed = append(change.Removed, path)
+			log.Printf("监听: 文件已删除 %s", path)
+		}
+		return
+	}
+	// 文件仍在写入（如资源管理器复制大文件），稍后再处理
+	if time.Since(info.ModTime()) < watchDebounce {
+		delete(seen, path)
+		w.notify(path)
+		return
+	}
+
+	app.processVPKFileWithCache(path)
+	after, ok := app.vpkCache.Load(path)
+	if !ok {
+		return
+	}
+	file := app.previewFile(path, after.(*VPKFileCache))
+	switch {
+	case !had:
+		change.Added = append(change.Added, file)
+	case before != after:
+		// 缓存命中时 processVPKFileWithCache 保留原对象，只有重新解析才会替换
+		change.Updated = append(change.Updated, file)
+	}
+}
+
+// syncDirectory 处理整个目录的变化（目录被移入，或监听缓冲区溢出需要重新对账）
+func (w *addonWatcher) syncDirectory(dir string, change *VPKFilesChange, seen map[string]bool) {
+	var vpkPaths []string
+	if dir == w.root {
+		if err := w.app.scanRootDirectory(dir, &vpkPaths); err != nil {
+			return
+		}
+	} else {
+		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() && path != dir && w.isTempPath(path) {
+				return filepath.SkipDir
+			}
+			if !d.IsDir() && strings.HasSuffix(strings.ToLower(path), ".vpk") {
+				vpkPaths = append(vpkPaths, path)
+			}
+			return nil
+		})
+	}
+
+	for _, path := range vpkPaths {
+		w.syncFile(path, change, seen)
+	}
+	w.removeUnder(dir, change, seen)
+}
+
+// removeUnder 检查缓存中位于 dir 下的条目，移除已不存在的文件
+func (w *addonWatcher) removeUnder(dir string, change *VPKFilesChange, seen map[string]bool) {
+	prefix := dir + string(filepath.Separator)
+	var stale []string
+	w.app.vpkCache.Range(func(key, value interface{}) bool {
+		path := key.(string)
+		if strings.HasPrefix(path, prefix) && !seen[path] {
+			stale = append(stale, path)
+		}
+		return true
+	})
+	for _, path := range stale {
+		w.syncFile(path, change, seen)
+	}
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newWatcherTestApp(t *testing.T) (*App, *addonWatcher, *[]VPKFilesChange) {
+	t.Helper()
+	root := t.TempDir()
+	for _, dir := range []string{"workshop", "disabled", "temp"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	app := &App{rootDir: root}
+	changes := &[]VPKFilesChange{}
+	watcher := newAddonWatcher(app, root, func(change VPKFilesChange) {
+		*changes = append(*changes, change)
+	})
+	t.Cleanup(watcher.close)
+	return app, watcher, changes
+}
+
+// writeSettledTestVPK 写入 VPK 并把修改时间设为过去，避免被当作仍在写入的文件
+func writeSettledTestVPK(t *testing.T, path string) {
+	t.Helper()
+	writeTestVPK(t, path, map[string][]byte{"scripts/addon.txt": []byte("x")})
+	settleTestFile(t, path)
+}
+
+func settleTestFile(t *testing.T, path string) {
+	t.Helper()
+	past := time.Now().Add(-time.Minute)
+	if err := os.Chtimes(path, past, past); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// flushPaths 直接提交变化并同步处理，不经过防抖计时器
+func flushPaths(w *addonWatcher, paths ...string) {
+	w.mu.Lock()
+	for _, path := range paths {
+		w.pending[path] = struct{}{}
+	}
+	w.mu.Unlock()
+	w.flush()
+}
+
+func TestAddonWatcherAppliesIncrementalChanges(t *testing.T) {
+	app, watcher, changes := newWatcherTestApp(t)
+	rootVPK := filepath.Join(app.rootDir, "a.vpk")
+	workshopVPK := filepath.Join(app.rootDir, "workshop", "123", "b.vpk")
+	if err := os.MkdirAll(filepath.Dir(workshopVPK), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeSettledTestVPK(t, rootVPK)
+	writeSettledTestVPK(t, workshopVPK)
+
+	flushPaths(watcher, rootVPK, filepath.Dir(workshopVPK))
+	if len(*changes) != 1 || len((*changes)[0].Added) != 2 {
+		t.Fatalf("expected one event adding 2 files, got %+v", *changes)
+	}
+
+	// 未变化的文件不产生事件
+	flushPaths(watcher, rootVPK)
+	if len(*changes) != 1 {
+		t.Fatalf("unchanged file should not emit, got %+v", *changes)
+	}
+
+	// 预览图变化映射到同名 VPK
+	image := filepath.Join(app.rootDir, "a.jpg")
+	if err := os.WriteFile(image, []byte("img"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	settleTestFile(t, image)
+	flushPaths(watcher, image)
+	if len(*changes) != 2 || len((*changes)[1].Updated) != 1 || (*changes)[1].Updated[0].Path != rootVPK {
+		t.Fatalf("expected update for %s, got %+v", rootVPK, *changes)
+	}
+
+	// 删除整个子目录时移除其中的缓存条目
+	if err := os.RemoveAll(filepath.Dir(workshopVPK)); err != nil {
+		t.Fatal(err)
+	}
+	flushPaths(watcher, filepath.Dir(workshopVPK))
+	if len(*changes) != 3 || len((*changes)[2].Removed) != 1 || (*changes)[2].Removed[0] != workshopVPK {
+		t.Fatalf("expected removal of %s, got %+v", workshopVPK, *changes)
+	}
+	if _, ok := app.vpkCache.Load(workshopVPK); ok {
+		t.Fatal("removed file should be dropped from cache")
+	}
+}
+
+func TestAddonWatcherSkipsTempIgnoredAndOutOfScopeFiles(t *testing.T) {
+	app, watcher, changes := newWatcherTestApp(t)
+
+	tempVPK := filepath.Join(app.rootDir, "temp", "download.vpk")
+	hiddenVPK := filepath.Join(app.rootDir, ".import.vpk")
+	nestedVPK := filepath.Join(app.rootDir, "other", "c.vpk")
+	packingVPK := filepath.Join(app.rootDir, "packing.vpk")
+	writingVPK := filepath.Join(app.rootDir, "writing.vpk")
+	if err := os.MkdirAll(filepath.Dir(nestedVPK), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{tempVPK, hiddenVPK, nestedVPK, packingVPK} {
+		writeSettledTestVPK(t, path)
+	}
+	writeTestVPK(t, writingVPK, map[string][]byte{"scripts/addon.txt": []byte("x")})
+
+	release := app.ignoreWatchPath(packingVPK)
+	flushPaths(watcher, tempVPK, hiddenVPK, nestedVPK, packingVPK, writingVPK)
+	if len(*changes) != 0 {
+		t.Fatalf("expected no changes, got %+v", *changes)
+	}
+	release()
+
+	// 释放忽略后补处理写好的文件
+	flushPaths(watcher, packingVPK)
+	if len(*changes) != 1 || len((*changes)[0].Added) != 1 || (*changes)[0].Added[0].Path != packingVPK {
+		t.Fatalf("expected packed file to be added, got %+v", *changes)
+	}
+}
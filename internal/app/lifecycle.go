@@ -136,6 +136,7 @@ func (a *App) beforeClose() (prevent bool) {
 		if a.singletonMgr != nil {
 			a.singletonMgr.Close()
 		}
+		a.stopAddonWatcher()
 		return false
 	}
 
@@ -148,6 +149,7 @@ func (a *App) beforeClose() (prevent bool) {
 	if a.singletonMgr != nil {
 		a.singletonMgr.Close()
 	}
+	a.stopAddonWatcher()
 	return false
 }
 
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"vpk-manager/internal/parser"
+)
+
+// GetVPKMapInfo 返回VPK中每张地图的 BSP 版本、数据块、实体信息、使用的模型和内嵌 pakfile 文件列表
+func (a *App) GetVPKMapInfo(vpkPath string) ([]parser.BSPMapInfo, error) {
+	if _, ok := a.vpkCache.Load(vpkPath); !ok {
+		return nil, fmt.Errorf("文件未找到: %s", vpkPath)
+	}
+	maps, err := parser.AnalyzeVPKMaps(vpkPath)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取 VPK: %v", err)
+	}
+	return maps, nil
+}
+
+// vpkBSPPakfileNames 返回VPK中地图内嵌 pakfile 打包的文件路径。
+// innerNames 为VPK内部文件列表，不含 maps/*.bsp 时不打开VPK
+func (a *App) vpkBSPPakfileNames(vpkPath string, innerNames []string) ([]string, error) {
+	hasMap := false
+	for _, name := range innerNames {
+		if isConflictBSPPath(normalizeConflictFilePath(name)) {
+			hasMap = true
+			break
+		}
+	}
+	if !hasMap {
+		return nil, nil
+	}
+
+	maps, err := parser.AnalyzeVPKMaps(vpkPath)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0)
+	for _, m := range maps {
+		for _, file := range m.PakFiles {
+			files = append(files, file.Name)
+		}
+	}
+	return files, nil
+}
+
+func isConflictBSPPath(name string) bool {
+	return strings.HasPrefix(name, "maps/") && strings.HasSuffix(name, ".bsp")
+}
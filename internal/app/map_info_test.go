@@ -0,0 +1,74 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"vpk-manager/internal/parser/parsertest"
+)
+
+// testBSPWithPakfile 生成只有内嵌 pakfile 数据块的 BSP，文件内容为文件名
+func testBSPWithPakfile(t *testing.T, files ...string) []byte {
+	t.Helper()
+	pak := make(map[string][]byte, len(files))
+	for _, name := range files {
+		pak[name] = []byte(name)
+	}
+	return parsertest.BuildBSP(t, false, "", nil, pak)
+}
+
+func TestVPKBSPPakfileNames(t *testing.T) {
+	dir := t.TempDir()
+	mapVPK := filepath.Join(dir, "map.vpk")
+	writeTestVPK(t, mapVPK, map[string][]byte{
+		"maps/c1m1_test.bsp": testBSPWithPakfile(t, "Materials/Models/Weapons/rifle.vtf", "scripts/vscripts/director_base.nut"),
+	})
+	app := &App{}
+
+	files, err := app.vpkBSPPakfileNames(mapVPK, []string{"maps/c1m1_test.bsp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0] != "materials/models/weapons/rifle.vtf" || files[1] != "scripts/vscripts/director_base.nut" {
+		t.Fatalf("unexpected pakfile entries %v", files)
+	}
+	if files, err := app.vpkBSPPakfileNames(filepath.Join(dir, "missing.vpk"), []string{"models/a.mdl"}); err != nil || files != nil {
+		t.Fatalf("vpk without maps must not be opened, got %v %v", files, err)
+	}
+
+	// 缓存按修改时间失效
+	writeTestVPK(t, mapVPK, map[string][]byte{"maps/c1m1_test.bsp": testBSPWithPakfile(t, "sound/music/theme.wav")})
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(mapVPK, later, later); err != nil {
+		t.Fatal(err)
+	}
+	files, err = app.vpkBSPPakfileNames(mapVPK, []string{"maps/c1m1_test.bsp"})
+	if err != nil || len(files) != 1 || files[0] != "sound/music/theme.wav" {
+		t.Fatalf("expected refreshed pakfile entries, got %v %v", files, err)
+	}
+}
+
+func TestGetVPKMapInfoRequiresScannedVPK(t *testing.T) {
+	app := &App{}
+	if _, err := app.GetVPKMapInfo(filepath.Join(t.TempDir(), "map.vpk")); err == nil {
+		t.Fatal("expected error for unknown vpk")
+	}
+}
+
+func TestIsPakfileConflict(t *testing.T) {
+	const file = "materials/models/weapons/rifle.vtf"
+	entries := map[conflictPakfileKey]bool{{vpk: "map.vpk", file: file}: true}
+
+	if !isPakfileConflict(file, []string{"map.vpk", "skin.vpk"}, entries) {
+		t.Fatal("expected conflict with the map's pakfile to be flagged")
+	}
+	// 另外两个VPK直接包含该文件，是真正的冲突
+	if isPakfileConflict(file, []string{"map.vpk", "skin.vpk", "other.vpk"}, entries) {
+		t.Fatal("loose-file conflict must not be hidden by another vpk's pakfile")
+	}
+	if isPakfileConflict(file, []string{"skin.vpk", "other.vpk"}, entries) {
+		t.Fatal("pakfile flag must only apply to the vpk that packs the file")
+	}
+}
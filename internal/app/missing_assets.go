@@ -0,0 +1,349 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"vpk-manager/internal/parser"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	missingAssetScanStatusIdle    = "idle"
+	missingAssetScanStatusRunning = "running"
+)
+
+// baseGameVPKDirs 游戏安装目录下包含 pak01_dir.vpk 的目录，按游戏的加载顺序排列
+var baseGameVPKDirs = []string{"update", "left4dead2_dlc3", "left4dead2_dlc2", "left4dead2_dlc1", "left4dead2"}
+
+type MissingAssetScanOptions struct {
+	IncludeBaseGame bool `json:"includeBaseGame"` // 同时在游戏本体 VPK 中查找引用的资源
+}
+
+type MissingAssetScanState struct {
+	Status   string       `json:"status"`
+	Running  bool         `json:"running"`
+	ScanID   string       `json:"scanId,omitempty"`
+	RootDir  string       `json:"rootDir,omitempty"`
+	Progress ProgressInfo `json:"progress"`
+}
+
+type MissingAssetScanProgress struct {
+	ScanID  string `json:"scanId"`
+	Current int    `json:"current"`
+	Total   int    `json:"total"`
+	Message string `json:"message"`
+}
+
+type MissingAssetScanComplete struct {
+	ScanID string                  `json:"scanId"`
+	Result *MissingAssetScanResult `json:"result,omitempty"`
+	Error  string                  `json:"error,omitempty"`
+}
+
+type MissingAssetScanResult struct {
+	ScanID           string                  `json:"scanId"`
+	GeneratedAt      string                  `json:"generatedAt"`
+	TotalMods        int                     `json:"totalMods"`
+	ModsWithMissing  int                     `json:"modsWithMissing"`
+	MissingMaterials int                     `json:"missingMaterials"`
+	MissingTextures  int                     `json:"missingTextures"`
+	BaseGameVPKs     []string                `json:"baseGameVpks"` // 参与查找的游戏本体 VPK
+	Items            []MissingAssetModResult `json:"items"`
+}
+
+type MissingAssetModResult struct {
+	Name             string                  `json:"name"`
+	Path             string                  `json:"path"`
+	Title            string                  `json:"title"`
+	Location         string                  `json:"location"`
+	WorkshopID       string                  `json:"workshopId,omitempty"`
+	References       int                     `json:"references"`
+	MissingMaterials int                     `json:"missingMaterials"`
+	MissingTextures  int                     `json:"missingTextures"`
+	Missing          []parser.AssetReference `json:"missing"`
+	Message          string                  `json:"message,omitempty"`
+}
+
+func (a *App) GetMissingAssetScanState() MissingAssetScanState {
+	a.missingAssetScanMu.Lock()
+	defer a.missingAssetScanMu.Unlock()
+	return a.missingAssetScanStateLocked()
+}
+
+// StartMissingAssetScan 在后台检查启用和工坊 Mod 中模型引用的材质、材质引用的贴图是否存在，
+// 引用的资源可以来自 Mod 自身、其他启用的 Mod，以及可选的游戏本体 VPK
+func (a *App) StartMissingAssetScan(options MissingAssetScanOptions) (MissingAssetScanState, error) {
+	a.missingAssetScanMu.Lock()
+	if a.missingAssetScanRunning {
+		state := a.missingAssetScanStateLocked()
+		a.missingAssetScanMu.Unlock()
+		return state, nil
+	}
+	a.missingAssetScanMu.Unlock()
+
+	a.mu.RLock()
+	rootDir := a.rootDir
+	a.mu.RUnlock()
+	if strings.TrimSpace(rootDir) == "" {
+		return MissingAssetScanState{}, fmt.Errorf("请先选择 addons 目录")
+	}
+	if a.goroutinePool == nil {
+		return MissingAssetScanState{}, fmt.Errorf("扫描任务池未初始化")
+	}
+
+	targets, err := collectModelStatsScanTargets(rootDir)
+	if err != nil {
+		return MissingAssetScanState{}, err
+	}
+	for i := range targets {
+		a.hydrateModelStatsTarget(&targets[i], rootDir)
+	}
+	var baseVPKs []string
+	if options.IncludeBaseGame {
+		baseVPKs = findBaseGameVPKs(filepath.Dir(filepath.Clean(rootDir)))
+	}
+
+	scanID := fmt.Sprintf("missing-assets-%d", time.Now().UnixNano())
+	a.missingAssetScanMu.Lock()
+	a.missingAssetScanRunning = true
+	a.missingAssetScanID = scanID
+	a.missingAssetScanRoot = rootDir
+	a.missingAssetScanProgress = ProgressInfo{
+		Current: 0,
+		Total:   len(targets),
+		Message: "准备检查缺失资源...",
+	}
+	state := a.missingAssetScanStateLocked()
+	a.missingAssetScanMu.Unlock()
+
+	if err := a.goroutinePool.Submit(func() {
+		a.runMissingAssetScan(scanID, targets, baseVPKs)
+	}); err != nil {
+		a.finishMissingAssetScan(scanID)
+		return MissingAssetScanState{}, err
+	}
+
+	a.emitMissingAssetScanProgress(scanID, 0, len(targets), "开始检查缺失资源...")
+	return state, nil
+}
+
+func (a *App) runMissingAssetScan(scanID string, targets []modelStatsScanTarget, baseVPKs []string) {
+	defer func() {
+		if r := recover(); r != nil {
+			a.emitMissingAssetScanComplete(scanID, nil, fmt.Sprintf("缺失资源检测异常: %v", r))
+			a.finishMissingAssetScan(scanID)
+		}
+	}()
+
+	result := a.collectMissingAssets(scanID, targets, baseVPKs)
+	if result == nil {
+		return
+	}
+	a.emitMissingAssetScanProgress(scanID, len(targets), len(targets), "缺失资源检测完成")
+	a.emitMissingAssetScanComplete(scanID, result, "")
+	a.finishMissingAssetScan(scanID)
+}
+
+// collectMissingAssets 建立文件索引后并发检查每个 Mod，提交任务失败时发送错误并返回 nil
+func (a *App) collectMissingAssets(scanID string, targets []modelStatsScanTarget, baseVPKs []string) *MissingAssetScanResult {
+	result := &MissingAssetScanResult{
+		ScanID:       scanID,
+		GeneratedAt:  time.Now().Format(time.RFC3339),
+		TotalMods:    len(targets),
+		BaseGameVPKs: []string{},
+		Items:        make([]MissingAssetModResult, 0, len(targets)),
+	}
+	if len(targets) == 0 {
+		a.emitMissingAssetScanProgress(scanID, 0, 0, "没有可扫描的启用或工坊 Mod")
+		return result
+	}
+
+	a.emitMissingAssetScanProgress(scanID, 0, len(targets), "正在建立文件索引...")
+	available := make(map[string]struct{})
+	for _, target := range targets {
+		names, err := a.vpkInnerFileNames(target.Path)
+		if err != nil {
+			continue
+		}
+		for _, name := range names {
+			available[normalizeConflictFilePath(name)] = struct{}{}
+		}
+	}
+	for _, vpkPath := range baseVPKs {
+		entries, err := readVPKEntriesSafely(vpkPath)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			available[normalizeConflictFilePath(entry.Name)] = struct{}{}
+		}
+		result.BaseGameVPKs = append(result.BaseGameVPKs, vpkPath)
+	}
+
+	var resultMu sync.Mutex
+	var wg sync.WaitGroup
+	workerCount := a.modelStatsScanWorkerCount(len(targets))
+	jobs := make(chan modelStatsScanTarget, workerCount)
+	processed := 0
+
+	recordItem := func(item MissingAssetModResult, targetName string) {
+		resultMu.Lock()
+		if len(item.Missing) > 0 {
+			result.ModsWithMissing++
+		}
+		result.MissingMaterials += item.MissingMaterials
+		result.MissingTextures += item.MissingTextures
+		result.Items = append(result.Items, item)
+		processed++
+		current := processed
+		resultMu.Unlock()
+		a.emitMissingAssetScanProgress(scanID, current, len(targets), fmt.Sprintf("正在检查: %s", targetName))
+	}
+
+	for i := 0; i < workerCount; i++ {
+		wg.Add(1)
+		submitErr := a.goroutinePool.Submit(func() {
+			defer wg.Done()
+			for target := range jobs {
+				recordItem(scanMissingAssetTarget(target, available), target.Name)
+			}
+		})
+		if submitErr != nil {
+			wg.Done()
+			close(jobs)
+			wg.Wait()
+			a.emitMissingAssetScanComplete(scanID, nil, fmt.Sprintf("提交缺失资源检查任务失败: %v", submitErr))
+			a.finishMissingAssetScan(scanID)
+			return nil
+		}
+	}
+
+	for _, target := range targets {
+		jobs <- target
+	}
+	close(jobs)
+	wg.Wait()
+	sort.SliceStable(result.Items, func(i, j int) bool {
+		if len(result.Items[i].Missing) != len(result.Items[j].Missing) {
+			return len(result.Items[i].Missing) > len(result.Items[j].Missing)
+		}
+		return strings.ToLower(result.Items[i].Name) < strings.ToLower(result.Items[j].Name)
+	})
+	return result
+}
+
+// scanMissingAssetTarget 列出 Mod 中在 available 里找不到任何候选路径的引用
+func scanMissingAssetTarget(target modelStatsScanTarget, available map[string]struct{}) MissingAssetModResult {
+	item := MissingAssetModResult{
+		Name:       target.Name,
+		Path:       target.Path,
+		Title:      target.Title,
+		Location:   target.Location,
+		WorkshopID: target.WorkshopID,
+		Missing:    []parser.AssetReference{},
+	}
+	if item.Title == "" {
+		item.Title = item.Name
+	}
+
+	refs, err := parser.CollectVPKAssetReferences(target.Path)
+	if err != nil {
+		item.Message = err.Error()
+		return item
+	}
+	item.References = len(refs)
+	for _, ref := range refs {
+		found := false
+		for _, candidate := range ref.Candidates {
+			if _, ok := available[candidate]; ok {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		item.Missing = append(item.Missing, ref)
+		if ref.Kind == "texture" {
+			item.MissingTextures++
+		} else {
+			item.MissingMaterials++
+		}
+	}
+	return item
+}
+
+// findBaseGameVPKs 返回与 gameDir（left4dead2 目录）同级的本体 pak01_dir.vpk
+func findBaseGameVPKs(gameDir string) []string {
+	installDir := filepath.Dir(gameDir)
+	vpks := make([]string, 0, len(baseGameVPKDirs))
+	for _, dir := range baseGameVPKDirs {
+		vpkPath := filepath.Join(installDir, dir, "pak01_dir.vpk")
+		if info, err := os.Stat(vpkPath); err == nil && !info.IsDir() {
+			vpks = append(vpks, vpkPath)
+		}
+	}
+	return vpks
+}
+
+func (a *App) emitMissingAssetScanProgress(scanID string, current, total int, message string) {
+	progress := ProgressInfo{Current: current, Total: total, Message: message}
+	a.missingAssetScanMu.Lock()
+	if a.missingAssetScanRunning && a.missingAssetScanID == scanID {
+		a.missingAssetScanProgress = progress
+	}
+	a.missingAssetScanMu.Unlock()
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "missing_asset_scan_progress", MissingAssetScanProgress{
+			ScanID:  scanID,
+			Current: current,
+			Total:   total,
+			Message: message,
+		})
+	}
+}
+
+func (a *App) emitMissingAssetScanComplete(scanID string, result *MissingAssetScanResult, message string) {
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "missing_asset_scan_complete", MissingAssetScanComplete{
+			ScanID: scanID,
+			Result: result,
+			Error:  message,
+		})
+	}
+}
+
+func (a *App) finishMissingAssetScan(scanID string) {
+	a.missingAssetScanMu.Lock()
+	defer a.missingAssetScanMu.Unlock()
+	if a.missingAssetScanID != scanID {
+		return
+	}
+	a.missingAssetScanRunning = false
+	a.missingAssetScanID = ""
+	a.missingAssetScanRoot = ""
+	a.missingAssetScanProgress = ProgressInfo{}
+}
+
+func (a *App) missingAssetScanStateLocked() MissingAssetScanState {
+	if !a.missingAssetScanRunning {
+		return MissingAssetScanState{
+			Status:  missingAssetScanStatusIdle,
+			Running: false,
+		}
+	}
+	return MissingAssetScanState{
+		Status:   missingAssetScanStatusRunning,
+		Running:  true,
+		ScanID:   a.missingAssetScanID,
+		RootDir:  a.missingAssetScanRoot,
+		Progress: a.missingAssetScanProgress,
+	}
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/panjf2000/ants/v2"
+)
+
+func TestCollectMissingAssetsResolvesAcrossAddonsAndBaseGame(t *testing.T) {
+	pool, err := ants.NewPool(4)
+	if err != nil {
+		t.Fatalf("create pool: %v", err)
+	}
+	defer pool.Release()
+
+	installDir := t.TempDir()
+	rootDir := filepath.Join(installDir, "left4dead2", "addons")
+	if err := os.MkdirAll(filepath.Join(installDir, "update"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(rootDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	writeTestVPK(t, filepath.Join(rootDir, "broken.vpk"), map[string][]byte{
+		"materials/models/broken/skin.vmt": []byte("\"VertexLitGeneric\"\n{\n\t\"$basetexture\" \"models/broken/skin\"\n\t\"$bumpmap\" \"models/shared/normal\"\n\t\"$envmapmask\" \"models/base/mask\"\n}\n"),
+	})
+	writeTestVPK(t, filepath.Join(rootDir, "shared.vpk"), map[string][]byte{
+		"materials/models/shared/normal.vtf": testVTF(1, 1, [4]byte{}),
+	})
+	basePath := filepath.Join(installDir, "update", "pak01_dir.vpk")
+	writeTestVPK(t, basePath, map[string][]byte{
+		"materials/models/base/mask.vtf": testVTF(1, 1, [4]byte{}),
+	})
+
+	targets, err := collectModelStatsScanTargets(rootDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := &App{goroutinePool: pool, rootDir: rootDir}
+	for i := range targets {
+		app.hydrateModelStatsTarget(&targets[i], rootDir)
+	}
+
+	baseVPKs := findBaseGameVPKs(filepath.Dir(rootDir))
+	if len(baseVPKs) != 1 || baseVPKs[0] != basePath {
+		t.Fatalf("unexpected base game vpks: %v", baseVPKs)
+	}
+
+	result := app.collectMissingAssets("test", targets, baseVPKs)
+	if result == nil || result.TotalMods != 2 || result.ModsWithMissing != 1 || result.MissingTextures != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	broken := result.Items[0]
+	if broken.Name != "broken.vpk" || broken.References != 3 || len(broken.Missing) != 1 ||
+		broken.Missing[0].Param != "$basetexture" || broken.Missing[0].Candidates[0] != "materials/models/broken/skin.vtf" {
+		t.Fatalf("unexpected broken addon result: %+v", broken)
+	}
+
+	// 不使用本体索引时，本体中的贴图也算缺失
+	result = app.collectMissingAssets("test", targets, nil)
+	if result.MissingTextures != 2 || len(result.BaseGameVPKs) != 0 {
+		t.Fatalf("expected base game texture to be missing without index: %+v", result)
+	}
+}
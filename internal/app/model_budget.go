@@ -0,0 +1,334 @@
+package app
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"vpk-manager/internal/parser"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// 模型预算分类，其他路径的模型不做预算检查
+const (
+	modelCategorySurvivor  = "survivor"
+	modelCategoryInfected  = "infected"
+	modelCategoryViewmodel = "viewmodel"
+)
+
+// 预算检查项，同时用作报告中的超标字段名
+const (
+	modelBudgetVertices      = "vertices"
+	modelBudgetTriangles     = "triangles"
+	modelBudgetStripGroups   = "stripGroups"
+	modelBudgetBones         = "bones"
+	modelBudgetTextureMemory = "textureMemory"
+)
+
+// ModelBudget 单类模型的性能预算，0 表示不限制
+type ModelBudget struct {
+	MaxVertices      int   `json:"maxVertices"`
+	MaxTriangles     int   `json:"maxTriangles"`
+	MaxStripGroups   int   `json:"maxStripGroups"`
+	MaxBones         int   `json:"maxBones"`
+	MaxTextureMemory int64 `json:"maxTextureMemory"` // 字节
+}
+
+// ModelBudgets 按模型分类配置的性能预算
+type ModelBudgets struct {
+	Survivor  ModelBudget `json:"survivor"`
+	Infected  ModelBudget `json:"infected"`
+	Viewmodel ModelBudget `json:"viewmodel"`
+}
+
+// ModelBudgetViolation 模型超出预算的一项
+type ModelBudgetViolation struct {
+	Metric string `json:"metric"`
+	Value  int64  `json:"value"`
+	Limit  int64  `json:"limit"`
+}
+
+// ModelBudgetCheck 单个模型的预算检查结果，Path 对应 ModelStatsModResult.Models 中的模型
+type ModelBudgetCheck struct {
+	Path       string                 `json:"path"`
+	Category   string                 `json:"category,omitempty"`
+	Violations []ModelBudgetViolation `json:"violations"`
+}
+
+// ModelStatsReportRow 导出报告中的一行，对应一个模型
+type ModelStatsReportRow struct {
+	ModName       string `json:"modName"`
+	ModTitle      string `json:"modTitle"`
+	Location      string `json:"location"`
+	WorkshopID    string `json:"workshopId,omitempty"`
+	Model         string `json:"model"`
+	Category      string `json:"category,omitempty"`
+	Vertices      int    `json:"vertices"`
+	Triangles     int    `json:"triangles"`
+	StripGroups   int    `json:"stripGroups"`
+	Bones         int    `json:"bones"`
+	Materials     int    `json:"materials"`
+	Textures      int    `json:"textures"`
+	TextureMemory int64  `json:"textureMemory"`
+	LODs          int    `json:"lods"`
+	OverBudget    bool   `json:"overBudget"`
+	Exceeded      string `json:"exceeded,omitempty"` // 超标项，以 ; 分隔
+	Message       string `json:"message,omitempty"`
+}
+
+// ModelStatsReport JSON 报告内容
+type ModelStatsReport struct {
+	ScanID      string                `json:"scanId"`
+	GeneratedAt string                `json:"generatedAt"`
+	ExportedAt  string                `json:"exportedAt"`
+	Budgets     ModelBudgets          `json:"budgets"`
+	Models      []ModelStatsReportRow `json:"models"`
+}
+
+var modelStatsReportHeader = []string{
+	"mod_name", "mod_title", "location", "workshop_id", "model", "category",
+	"vertices", "triangles", "strip_groups", "bones", "materials", "textures", "texture_memory", "lods",
+	"over_budget", "exceeded", "message",
+}
+
+func defaultModelBudgets() ModelBudgets {
+	return ModelBudgets{
+		Survivor:  ModelBudget{MaxVertices: 30000, MaxTriangles: 40000, MaxStripGroups: 64, MaxBones: 128, MaxTextureMemory: 64 << 20},
+		Infected:  ModelBudget{MaxVertices: 20000, MaxTriangles: 25000, MaxStripGroups: 32, MaxBones: 128, MaxTextureMemory: 32 << 20},
+		Viewmodel: ModelBudget{MaxVertices: 25000, MaxTriangles: 30000, MaxStripGroups: 48, MaxBones: 128, MaxTextureMemory: 48 << 20},
+	}
+}
+
+func normalizeModelBudget(budget ModelBudget) ModelBudget {
+	budget.MaxVertices = max(budget.MaxVertices, 0)
+	budget.MaxTriangles = max(budget.MaxTriangles, 0)
+	budget.MaxStripGroups = max(budget.MaxStripGroups, 0)
+	budget.MaxBones = max(budget.MaxBones, 0)
+	budget.MaxTextureMemory = max(budget.MaxTextureMemory, 0)
+	return budget
+}
+
+func normalizeModelBudgets(budgets ModelBudgets) ModelBudgets {
+	return ModelBudgets{
+		Survivor:  normalizeModelBudget(budgets.Survivor),
+		Infected:  normalizeModelBudget(budgets.Infected),
+		Viewmodel: normalizeModelBudget(budgets.Viewmodel),
+	}
+}
+
+// GetModelBudgets 获取模型性能预算，未配置时返回默认值
+func (a *App) GetModelBudgets() ModelBudgets {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.modelBudgets == nil {
+		return defaultModelBudgets()
+	}
+	return *a.modelBudgets
+}
+
+// SetModelBudgets 保存模型性能预算，负数按不限制处理，下次扫描生效
+func (a *App) SetModelBudgets(budgets ModelBudgets) error {
+	normalized := normalizeModelBudgets(budgets)
+	a.mu.Lock()
+	a.modelBudgets = &normalized
+	a.mu.Unlock()
+	a.saveConfig()
+	return nil
+}
+
+// ResetModelBudgets 恢复默认模型性能预算
+func (a *App) ResetModelBudgets() ModelBudgets {
+	a.mu.Lock()
+	a.modelBudgets = nil
+	a.mu.Unlock()
+	a.saveConfig()
+	return defaultModelBudgets()
+}
+
+// modelBudgetCategory 按游戏内路径判断模型分类，无法归类时返回空
+func modelBudgetCategory(modelPath string) string {
+	modelPath = strings.ToLower(strings.ReplaceAll(modelPath, "\\", "/"))
+	switch {
+	case strings.HasPrefix(modelPath, "models/survivors/"):
+		return modelCategorySurvivor
+	case strings.HasPrefix(modelPath, "models/infected/"):
+		return modelCategoryInfected
+	case strings.HasPrefix(modelPath, "models/v_models/"),
+		strings.HasPrefix(modelPath, "models/weapons/arms/"),
+		strings.HasPrefix(modelPath, "models/") && strings.HasPrefix(path.Base(modelPath), "v_"):
+		return modelCategoryViewmodel
+	}
+	return ""
+}
+
+func (budgets ModelBudgets) forCategory(category string) (ModelBudget, bool) {
+	switch category {
+	case modelCategorySurvivor:
+		return budgets.Survivor, true
+	case modelCategoryInfected:
+		return budgets.Infected, true
+	case modelCategoryViewmodel:
+		return budgets.Viewmodel, true
+	}
+	return ModelBudget{}, false
+}
+
+// checkModelBudget 返回模型超出所属分类预算的项
+func checkModelBudget(stat parser.ModelStat, budgets ModelBudgets) ModelBudgetCheck {
+	check := ModelBudgetCheck{
+		Path:       stat.Path,
+		Category:   modelBudgetCategory(stat.Path),
+		Violations: []ModelBudgetViolation{},
+	}
+	budget, ok := budgets.forCategory(check.Category)
+	if !ok {
+		return check
+	}
+
+	limits := []struct {
+		metric string
+		value  int64
+		limit  int64
+	}{
+		{modelBudgetVertices, int64(stat.Vertices), int64(budget.MaxVertices)},
+		{modelBudgetTriangles, int64(stat.Triangles), int64(budget.MaxTriangles)},
+		{modelBudgetStripGroups, int64(stat.StripGroupCount), int64(budget.MaxStripGroups)},
+		{modelBudgetBones, int64(stat.Bones), int64(budget.MaxBones)},
+		{modelBudgetTextureMemory, stat.TextureMemory, budget.MaxTextureMemory},
+	}
+	for _, item := range limits {
+		if item.limit > 0 && item.value > item.limit {
+			check.Violations = append(check.Violations, ModelBudgetViolation{
+				Metric: item.metric,
+				Value:  item.value,
+				Limit:  item.limit,
+			})
+		}
+	}
+	return check
+}
+
+// applyModelBudgets 检查 Mod 中每个模型的预算并汇总超标数量
+func applyModelBudgets(item *ModelStatsModResult, budgets ModelBudgets) {
+	item.BudgetChecks = make([]ModelBudgetCheck, 0, len(item.Models))
+	item.OverBudgetModels = 0
+	for _, model := range item.Models {
+		check := checkModelBudget(model, budgets)
+		if len(check.Violations) > 0 {
+			item.OverBudgetModels++
+		}
+		item.BudgetChecks = append(item.BudgetChecks, check)
+	}
+	item.OverBudget = item.OverBudgetModels > 0
+}
+
+// modelStatsReportRows 将扫描结果展开为每个模型一行
+func modelStatsReportRows(result *ModelStatsScanResult) []ModelStatsReportRow {
+	rows := make([]ModelStatsReportRow, 0, result.TotalModels)
+	for _, item := range result.Items {
+		checks := make(map[string]ModelBudgetCheck, len(item.BudgetChecks))
+		for _, check := range item.BudgetChecks {
+			checks[check.Path] = check
+		}
+		for _, model := range item.Models {
+			check, ok := checks[model.Path]
+			if !ok {
+				check = checkModelBudget(model, result.Budgets)
+			}
+			exceeded := make([]string, 0, len(check.Violations))
+			for _, violation := range check.Violations {
+				exceeded = append(exceeded, violation.Metric)
+			}
+			rows = append(rows, ModelStatsReportRow{
+				ModName:       item.Name,
+				ModTitle:      item.Title,
+				Location:      item.Location,
+				WorkshopID:    item.WorkshopID,
+				Model:         model.Path,
+				Category:      check.Category,
+				Vertices:      model.Vertices,
+				Triangles:     model.Triangles,
+				StripGroups:   model.StripGroupCount,
+				Bones:         model.Bones,
+				Materials:     len(model.Materials),
+				Textures:      model.Textures,
+				TextureMemory: model.TextureMemory,
+				LODs:          len(model.LODs),
+				OverBudget:    len(exceeded) > 0,
+				Exceeded:      strings.Join(exceeded, ";"),
+				Message:       model.Message,
+			})
+		}
+	}
+	return rows
+}
+
+// encodeModelStatsReport 按格式生成报告内容，CSV 带 UTF-8 BOM 以便表格软件识别中文
+func encodeModelStatsReport(result *ModelStatsScanResult, format string) ([]byte, error) {
+	rows := modelStatsReportRows(result)
+	switch format {
+	case "json":
+		return json.MarshalIndent(ModelStatsReport{
+			ScanID:      result.ScanID,
+			GeneratedAt: result.GeneratedAt,
+			ExportedAt:  time.Now().Format(time.RFC3339),
+			Budgets:     result.Budgets,
+			Models:      rows,
+		}, "", "  ")
+	case "csv":
+		var buf bytes.Buffer
+		buf.WriteString("\ufeff")
+		writer := csv.NewWriter(&buf)
+		if err := writer.Write(modelStatsReportHeader); err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			record := []string{
+				row.ModName, row.ModTitle, row.Location, row.WorkshopID, row.Model, row.Category,
+				strconv.Itoa(row.Vertices), strconv.Itoa(row.Triangles), strconv.Itoa(row.StripGroups),
+				strconv.Itoa(row.Bones), strconv.Itoa(row.Materials), strconv.Itoa(row.Textures),
+				strconv.FormatInt(row.TextureMemory, 10), strconv.Itoa(row.LODs),
+				strconv.FormatBool(row.OverBudget), row.Exceeded, row.Message,
+			}
+			if err := writer.Write(record); err != nil {
+				return nil, err
+			}
+		}
+		writer.Flush()
+		return buf.Bytes(), writer.Error()
+	}
+	return nil, fmt.Errorf("不支持的报告格式: %s", format)
+}
+
+// ExportModelStatsReport 将模型面数检测结果导出为 CSV 或 JSON，每个模型一行，返回保存路径，用户取消时返回空
+func (a *App) ExportModelStatsReport(result ModelStatsScanResult, format string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	data, err := encodeModelStatsReport(&result, format)
+	if err != nil {
+		return "", err
+	}
+
+	filter := runtime.FileFilter{DisplayName: "CSV Files (*.csv)", Pattern: "*.csv"}
+	if format == "json" {
+		filter = runtime.FileFilter{DisplayName: "JSON Files (*.json)", Pattern: "*.json"}
+	}
+	selection, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "导出模型面数报告",
+		DefaultFilename: fmt.Sprintf("model_stats_%s.%s", time.Now().Format("20060102_150405"), format),
+		Filters:         []runtime.FileFilter{filter},
+	})
+	if err != nil {
+		return "", err
+	}
+	if selection == "" {
+		return "", nil // 用户取消
+	}
+	return selection, os.WriteFile(selection, data, 0644)
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"vpk-manager/internal/parser"
+)
+
+func TestModelBudgetCategory(t *testing.T) {
+	cases := map[string]string{
+		"models/survivors/survivor_coach.mdl":       modelCategorySurvivor,
+		"Models\\Infected\\common_male01.mdl":       modelCategoryInfected,
+		"models/v_models/v_rifle.mdl":               modelCategoryViewmodel,
+		"models/weapons/arms/v_arms_coach_new.mdl":  modelCategoryViewmodel,
+		"models/w_models/weapons/w_rifle_m16a2.mdl": "",
+		"models/props/cs_office/chair.mdl":          "",
+	}
+	for modelPath, want := range cases {
+		if got := modelBudgetCategory(modelPath); got != want {
+			t.Errorf("%s: expected %q, got %q", modelPath, want, got)
+		}
+	}
+}
+
+func TestApplyModelBudgets(t *testing.T) {
+	budgets := defaultModelBudgets()
+	budgets.Survivor.MaxBones = 0 // 0 表示不限制
+	item := ModelStatsModResult{
+		Models: []parser.ModelStat{
+			{Path: "models/survivors/survivor_coach.mdl", Vertices: 40000, Triangles: 10000, Bones: 200, TextureMemory: 80 << 20},
+			{Path: "models/infected/boomer.mdl", Vertices: 1000, Triangles: 1000, Bones: 40},
+			{Path: "models/props/big.mdl", Vertices: 1000000},
+		},
+	}
+	applyModelBudgets(&item, budgets)
+
+	if !item.OverBudget || item.OverBudgetModels != 1 || len(item.BudgetChecks) != 3 {
+		t.Fatalf("unexpected budget result %+v", item)
+	}
+	violations := item.BudgetChecks[0].Violations
+	if len(violations) != 2 || violations[0].Metric != modelBudgetVertices || violations[0].Limit != 30000 || violations[1].Metric != modelBudgetTextureMemory {
+		t.Fatalf("unexpected survivor violations %+v", violations)
+	}
+	if item.BudgetChecks[2].Category != "" || len(item.BudgetChecks[2].Violations) != 0 {
+		t.Fatalf("uncategorized model must not be checked: %+v", item.BudgetChecks[2])
+	}
+}
+
+func TestModelBudgetsPersistInConfig(t *testing.T) {
+	app := newConfigTestApp(t)
+	app.loadConfig()
+	if app.GetModelBudgets() != defaultModelBudgets() {
+		t.Fatalf("expected default budgets")
+	}
+
+	budgets := defaultModelBudgets()
+	budgets.Infected.MaxTriangles = 12345
+	budgets.Viewmodel.MaxBones = -1
+	if err := app.SetModelBudgets(budgets); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := newConfigTestApp(t)
+	reloaded.configDir, reloaded.configPath = app.configDir, app.configPath
+	reloaded.loadConfig()
+	got := reloaded.GetModelBudgets()
+	if got.Infected.MaxTriangles != 12345 || got.Viewmodel.MaxBones != 0 || got.Survivor != budgets.Survivor {
+		t.Fatalf("unexpected reloaded budgets %+v", got)
+	}
+
+	if reset := reloaded.ResetModelBudgets(); reset != defaultModelBudgets() || reloaded.GetModelBudgets() != defaultModelBudgets() {
+		t.Fatalf("expected budgets to reset to defaults")
+	}
+}
+
+func TestEncodeModelStatsReport(t *testing.T) {
+	item := ModelStatsModResult{
+		Name:       "coach.vpk",
+		Title:      "教练替换",
+		Location:   "workshop",
+		WorkshopID: "123",
+		Models: []parser.ModelStat{
+			{Path: "models/survivors/survivor_coach.mdl", Vertices: 40000, Triangles: 50000, StripGroupCount: 3, Bones: 72, Materials: []string{"a", "b"}, Textures: 4, TextureMemory: 1024},
+			{Path: "models/props/box.mdl", Vertices: 8, Triangles: 12, Message: "缺少 .vvd"},
+		},
+	}
+	applyModelBudgets(&item, defaultModelBudgets())
+	result := &ModelStatsScanResult{
+		ScanID:      "model-stats-1",
+		TotalModels: 2,
+		Budgets:     defaultModelBudgets(),
+		Items:       []ModelStatsModResult{item},
+	}
+
+	data, err := encodeModelStatsReport(result, "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "\ufeff") {
+		t.Fatal("expected csv to start with a UTF-8 BOM")
+	}
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(string(data), "\ufeff"))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || len(records[0]) != len(modelStatsReportHeader) {
+		t.Fatalf("expected header and one row per model, got %v", records)
+	}
+	if row := records[1]; row[1] != "教练替换" || row[4] != "models/survivors/survivor_coach.mdl" || row[5] != modelCategorySurvivor ||
+		row[6] != "40000" || row[14] != "true" || row[15] != "vertices;triangles" {
+		t.Fatalf("unexpected survivor row %v", row)
+	}
+	if row := records[2]; row[5] != "" || row[14] != "false" || row[16] != "缺少 .vvd" {
+		t.Fatalf("unexpected prop row %v", row)
+	}
+
+	data, err = encodeModelStatsReport(result, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report ModelStatsReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.ScanID != "model-stats-1" || len(report.Models) != 2 || report.Models[0].Materials != 2 || report.Budgets != defaultModelBudgets() {
+		t.Fatalf("unexpected json report %+v", report)
+	}
+
+	if _, err := encodeModelStatsReport(result, "xlsx"); err == nil {
+		t.Fatal("expected error for unsupported format")
+	}
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"vpk-manager/internal/parser"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ModelPreviewItem VPK内可预览的模型
+type ModelPreviewItem struct {
+	Path       string `json:"path"`
+	PreviewURL string `json:"previewUrl"` // glTF 二进制（.glb）地址，服务未启动时为空
+}
+
+// ListVPKModels 列出VPK中的 .mdl 模型及其 3D 预览地址
+func (a *App) ListVPKModels(vpkPath string) ([]ModelPreviewItem, error) {
+	cached, ok := a.vpkCache.Load(vpkPath)
+	if !ok {
+		return nil, fmt.Errorf("文件未找到: %s", vpkPath)
+	}
+	cache := cached.(*VPKFileCache)
+
+	names, err := a.vpkInnerFileNames(vpkPath)
+	if err != nil {
+		return nil, fmt.Errorf("无法读取 VPK: %v", err)
+	}
+	models := make([]ModelPreviewItem, 0)
+	for _, name := range names {
+		entry := strings.ToLower(strings.ReplaceAll(name, "\\", "/"))
+		if !strings.HasPrefix(entry, "models/") || !strings.HasSuffix(entry, ".mdl") {
+			continue
+		}
+		models = append(models, ModelPreviewItem{
+			Path:       name,
+			PreviewURL: a.previewServer.ModelURL(vpkPath, name, cache),
+		})
+	}
+	sort.Slice(models, func(i, j int) bool { return strings.ToLower(models[i].Path) < strings.ToLower(models[j].Path) })
+	return models, nil
+}
+
+// ExportModelGLB 将VPK内的模型转换为 glTF 二进制文件并保存，返回保存路径，用户取消时返回空
+func (a *App) ExportModelGLB(vpkPath string, modelPath string) (string, error) {
+	if _, ok := a.vpkCache.Load(vpkPath); !ok {
+		return "", fmt.Errorf("文件未找到: %s", vpkPath)
+	}
+
+	data, info, err := parser.ConvertVPKModelToGLB(vpkPath, modelPath)
+	if err != nil {
+		return "", fmt.Errorf("转换模型失败: %v", err)
+	}
+
+	selection, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "导出 3D 模型",
+		DefaultFilename: strings.TrimSuffix(path.Base(info.Path), ".mdl") + ".glb",
+		Filters: []runtime.FileFilter{
+			{
+				DisplayName: "glTF Binary (*.glb)",
+				Pattern:     "*.glb",
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if selection == "" {
+		return "", nil // 用户取消
+	}
+	if err := os.WriteFile(selection, data, 0644); err != nil {
+		return "", err
+	}
+	log.Printf("已导出模型: %s -> %s (%d 个顶点, %d 个三角形, 缺少贴图 %d 个)",
+		info.Path, selection, info.Vertices, info.Triangles, len(info.MissingTextures))
+	return selection, nil
+}
+
+// ModelURL 返回VPK内模型转换为 glTF 二进制后的地址。服务未启动时返回空字符串
+func (s *previewImageServer) ModelURL(vpkPath string, entry string, cache *VPKFileCache) string {
+	if s == nil || s.port == 0 {
+		return ""
+	}
+	return s.endpointURL("model", url.Values{"path": {vpkPath}, "entry": {entry}, "v": {previewCacheKey(vpkPath, cache)}})
+}
+
+// handleModel 将VPK内模型的 LOD0 转换为 glTF 二进制，供前端 3D 预览
+func (s *previewImageServer) handleModel(w http.ResponseWriter, r *http.Request) {
+	// 3D 预览用 fetch 从 Wails 页面跨域读取，地址已带会话令牌
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	entry, ok := s.resolvePreviewEntry(w, r, ".mdl")
+	if !ok {
+		return
+	}
+
+	data, _, err := parser.ConvertVPKModelToGLB(entry.vpkPath, entry.name)
+	if err != nil {
+		log.Printf("转换模型失败: %s:%s, 错误: %v", entry.vpkPath, entry.name, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "max-age=31536000, immutable")
+	w.Header().Set("Content-Type", "model/gltf-binary")
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Write(data)
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func TestListVPKModelsAndModelRoute(t *testing.T) {
+	app, _ := newPreviewTestApp(t)
+	vpkPath := filepath.Join(app.rootDir, "weapons.vpk")
+	writeTestVPK(t, vpkPath, map[string][]byte{
+		"models/v_models/v_rifle.mdl": []byte("not a model"),
+		"models/v_models/v_rifle.vvd": []byte("IDSV"),
+		"models/w_models/w_rifle.mdl": []byte("not a model"),
+		"materials/models/rifle.vmt":  []byte(`"VertexLitGeneric" {}`),
+		"scripts/weapon_rifle.txt":    []byte("WeaponData {}"),
+	})
+	app.processVPKFileWithCache(vpkPath)
+
+	models, err := app.ListVPKModels(vpkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(models) != 2 || models[0].Path != "models/v_models/v_rifle.mdl" || models[0].PreviewURL == "" {
+		t.Fatalf("unexpected models: %+v", models)
+	}
+	if _, err := app.ListVPKModels(filepath.Join(app.rootDir, "missing.vpk")); err == nil {
+		t.Fatal("expected error for unknown vpk")
+	}
+	if _, err := app.ExportModelGLB(filepath.Join(app.rootDir, "missing.vpk"), "models/v_models/v_rifle.mdl"); err == nil {
+		t.Fatal("expected export from unknown vpk to fail")
+	}
+
+	for entry, want := range map[string]int{
+		"../v_rifle.mdl":              http.StatusBadRequest,
+		"materials/models/rifle.vmt":  http.StatusBadRequest,
+		"models/v_models/v_rifle.mdl": http.StatusInternalServerError, // 缺少 .dx90.vtx
+	} {
+		if status, _ := fetchPreview(t, app.previewServer.ModelURL(vpkPath, entry, &VPKFileCache{})); status != want {
+			t.Errorf("entry %q: expected %d, got %d", entry, want, status)
+		}
+	}
+	if status, _ := fetchPreview(t, app.previewServer.ModelURL(filepath.Join(app.rootDir, "other.vpk"), "models/a.mdl", &VPKFileCache{})); status != http.StatusNotFound {
+		t.Errorf("expected unknown vpk to be rejected, got %d", status)
+	}
+}
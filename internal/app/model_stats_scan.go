@@ -49,6 +49,8 @@ type ModelStatsScanResult struct {
 	TotalModels    int                   `json:"totalModels"`
 	TotalVertices  int                   `json:"totalVertices"`
 	TotalTriangles int                   `json:"totalTriangles"`
+	OverBudgetMods int                   `json:"overBudgetMods"`
+	Budgets        ModelBudgets          `json:"budgets"` // 本次扫描使用的预算
 	Items          []ModelStatsModResult `json:"items"`
 }
 
@@ -62,7 +64,11 @@ type ModelStatsModResult struct {
 	TotalVertices  int                `json:"totalVertices"`
 	TotalTriangles int                `json:"totalTriangles"`
 	Models         []parser.ModelStat `json:"models"`
-	Message        string             `json:"message,omitempty"`
+	// BudgetChecks 与 Models 一一对应
+	BudgetChecks     []ModelBudgetCheck `json:"budgetChecks"`
+	OverBudgetModels int                `json:"overBudgetModels"`
+	OverBudget       bool               `json:"overBudget"`
+	Message          string             `json:"message,omitempty"`
 }
 
 type modelStatsScanTarget struct {
@@ -119,8 +125,9 @@ func (a *App) StartModelStatsScan() (ModelStatsScanState, error) {
 	state := a.modelStatsScanStateLocked()
 	a.modelStatsScanMu.Unlock()
 
+	budgets := a.GetModelBudgets()
 	if err := a.goroutinePool.Submit(func() {
-		a.runModelStatsScan(scanID, targets)
+		a.runModelStatsScan(scanID, targets, budgets)
 	}); err != nil {
 		a.finishModelStatsScan(scanID)
 		return ModelStatsScanState{}, err
@@ -130,7 +137,7 @@ func (a *App) StartModelStatsScan() (ModelStatsScanState, error) {
 	return state, nil
 }
 
-func (a *App) runModelStatsScan(scanID string, targets []modelStatsScanTarget) {
+func (a *App) runModelStatsScan(scanID string, targets []modelStatsScanTarget, budgets ModelBudgets) {
 	defer func() {
 		if r := recover(); r != nil {
 			a.emitModelStatsScanComplete(scanID, nil, fmt.Sprintf("模型面数检测异常: %v", r))
@@ -142,6 +149,7 @@ func (a *App) runModelStatsScan(scanID string, targets []modelStatsScanTarget) {
 		ScanID:      scanID,
 		GeneratedAt: time.Now().Format(time.RFC3339),
 		TotalMods:   len(targets),
+		Budgets:     budgets,
 		Items:       make([]ModelStatsModResult, 0, len(targets)),
 	}
 
@@ -163,6 +171,9 @@ func (a *App) runModelStatsScan(scanID string, targets []modelStatsScanTarget) {
 		result.TotalModels += item.ModelCount
 		result.TotalVertices += item.TotalVertices
 		result.TotalTriangles += item.TotalTriangles
+		if item.OverBudget {
+			result.OverBudgetMods++
+		}
 		result.Items = append(result.Items, item)
 		processed++
 		current := processed
@@ -182,7 +193,7 @@ func (a *App) runModelStatsScan(scanID string, targets []modelStatsScanTarget) {
 		submitErr := a.goroutinePool.Submit(func() {
 			defer wg.Done()
 			for target := range jobs {
-				recordItem(a.scanModelStatsTarget(target), target.Name)
+				recordItem(a.scanModelStatsTarget(target, budgets), target.Name)
 			}
 		})
 		if submitErr != nil {
@@ -201,6 +212,9 @@ func (a *App) runModelStatsScan(scanID string, targets []modelStatsScanTarget) {
 	close(jobs)
 	wg.Wait()
 	sort.SliceStable(result.Items, func(i, j int) bool {
+		if result.Items[i].OverBudget != result.Items[j].OverBudget {
+			return result.Items[i].OverBudget
+		}
 		if result.Items[i].TotalTriangles != result.Items[j].TotalTriangles {
 			return result.Items[i].TotalTriangles > result.Items[j].TotalTriangles
 		}
@@ -212,14 +226,15 @@ func (a *App) runModelStatsScan(scanID string, targets []modelStatsScanTarget) {
 	a.finishModelStatsScan(scanID)
 }
 
-func (a *App) scanModelStatsTarget(target modelStatsScanTarget) ModelStatsModResult {
+func (a *App) scanModelStatsTarget(target modelStatsScanTarget, budgets ModelBudgets) ModelStatsModResult {
 	item := ModelStatsModResult{
-		Name:       target.Name,
-		Path:       target.Path,
-		Title:      target.Title,
-		Location:   target.Location,
-		WorkshopID: target.WorkshopID,
-		Models:     []parser.ModelStat{},
+		Name:         target.Name,
+		Path:         target.Path,
+		Title:        target.Title,
+		Location:     target.Location,
+		WorkshopID:   target.WorkshopID,
+		Models:       []parser.ModelStat{},
+		BudgetChecks: []ModelBudgetCheck{},
 	}
 	if item.Title == "" {
 		item.Title = item.Name
@@ -238,6 +253,7 @@ func (a *App) scanModelStatsTarget(target modelStatsScanTarget) ModelStatsModRes
 	if item.Models == nil {
 		item.Models = []parser.ModelStat{}
 	}
+	applyModelBudgets(&item, budgets)
 	return item
 }
 
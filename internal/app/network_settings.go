@@ -0,0 +1,133 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"vpk-manager/internal/network"
+)
+
+// ProxySettings 上游代理设置，密码加密后写入配置文件
+type ProxySettings struct {
+	Mode              string   `json:"mode"` // none, system, http, https, socks5
+	Host              string   `json:"host,omitempty"`
+	Port              int      `json:"port,omitempty"`
+	Username          string   `json:"username,omitempty"`
+	Password          string   `json:"password,omitempty"`
+	PasswordEncrypted string   `json:"passwordEncrypted,omitempty"`
+	PasswordSet       bool     `json:"passwordSet,omitempty"`
+	ClearPassword     bool     `json:"clearPassword,omitempty"`
+	Bypass            []string `json:"bypass,omitempty"`
+	// PinnedIPPolicy 优选IP与代理同时开启时的策略: skip(走代理时不使用优选IP) / direct(优选IP主机绕过代理直连)
+	PinnedIPPolicy string `json:"pinnedIPPolicy,omitempty"`
+}
+
+func defaultProxySettings() ProxySettings {
+	return ProxySettings{Mode: network.ProxyModeSystem, PinnedIPPolicy: network.PinnedIPPolicySkip}
+}
+
+func cloneProxySettings(settings ProxySettings) ProxySettings {
+	if settings.Bypass != nil {
+		settings.Bypass = append([]string(nil), settings.Bypass...)
+	}
+	return settings
+}
+
+// proxyConfigFromSettings 将存储的代理设置转换为运行时配置（解密密码）
+func proxyConfigFromSettings(settings ProxySettings) (network.ProxyConfig, error) {
+	password, err := unprotectSecret(settings.PasswordEncrypted)
+	if err != nil {
+		return network.ProxyConfig{}, fmt.Errorf("解密代理密码失败: %w", err)
+	}
+	return network.ProxyConfig{
+		Mode:           settings.Mode,
+		Host:           settings.Host,
+		Port:           settings.Port,
+		Username:       settings.Username,
+		Password:       password,
+		Bypass:         settings.Bypass,
+		PinnedIPPolicy: settings.PinnedIPPolicy,
+	}, nil
+}
+
+// applyProxySettings 将存储的代理设置应用到全局网络配置
+func applyProxySettings(settings ProxySettings) error {
+	config, err := proxyConfigFromSettings(settings)
+	if err != nil {
+		return err
+	}
+	return network.SetProxyConfig(config)
+}
+
+// GetProxySettings 获取代理设置，密码不会返回给前端
+func (a *App) GetProxySettings() ProxySettings {
+	a.mu.RLock()
+	settings := cloneProxySettings(a.proxySettings)
+	a.mu.RUnlock()
+
+	if settings.Mode == "" {
+		settings = defaultProxySettings()
+	}
+	settings.PasswordSet = settings.PasswordEncrypted != ""
+	settings.Password = ""
+	settings.PasswordEncrypted = ""
+	settings.ClearPassword = false
+	return settings
+}
+
+// SetProxySettings 保存并应用代理设置，对所有对外请求立即生效。
+// 密码留空表示保留原密码，ClearPassword 为 true 时清除密码。
+func (a *App) SetProxySettings(settings ProxySettings) error {
+	normalized, err := network.NormalizeProxyConfig(network.ProxyConfig{
+		Mode:           settings.Mode,
+		Host:           settings.Host,
+		Port:           settings.Port,
+		Username:       settings.Username,
+		Bypass:         settings.Bypass,
+		PinnedIPPolicy: settings.PinnedIPPolicy,
+	})
+	if err != nil {
+		return err
+	}
+
+	a.mu.RLock()
+	encryptedPassword := a.proxySettings.PasswordEncrypted
+	a.mu.RUnlock()
+	if settings.ClearPassword || normalized.Username == "" {
+		encryptedPassword = ""
+	}
+	if password := strings.TrimSpace(settings.Password); password != "" {
+		encryptedPassword, err = protectSecret(password)
+		if err != nil {
+			return err
+		}
+	}
+
+	next := ProxySettings{
+		Mode:              normalized.Mode,
+		Host:              normalized.Host,
+		Port:              normalized.Port,
+		Username:          normalized.Username,
+		PasswordEncrypted: encryptedPassword,
+		Bypass:            normalized.Bypass,
+		PinnedIPPolicy:    normalized.PinnedIPPolicy,
+	}
+	if err := applyProxySettings(next); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.proxySettings = next
+	a.mu.Unlock()
+
+	log.Printf("已更新代理设置: 模式=%s, 地址=%s:%d, 优选IP策略=%s", next.Mode, next.Host, next.Port, next.PinnedIPPolicy)
+	a.saveConfig()
+	return nil
+}
+
+// TestProxyConnection 通过当前代理设置访问工坊服务，检查代理是否可用
+func (a *App) TestProxyConnection() EndpointHealth {
+	candidates := a.endpointCandidates(endpointWorkshopWorker)
+	return probeEndpoint(endpointWorkshopWorker, candidates[0], len(candidates) == 1)
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"testing"
+
+	"vpk-manager/internal/network"
+)
+
+func TestSetProxySettingsValidatesAndPersists(t *testing.T) {
+	t.Cleanup(func() {
+		network.SetProxyConfig(network.ProxyConfig{})
+	})
+	app := newConfigTestApp(t)
+
+	if err := app.SetProxySettings(ProxySettings{Mode: network.ProxyModeSOCKS5, Port: 1080}); err == nil {
+		t.Fatalf("expected proxy without host to be rejected")
+	}
+
+	err := app.SetProxySettings(ProxySettings{
+		Mode:           network.ProxyModeSOCKS5,
+		Host:           " 127.0.0.1 ",
+		Port:           1080,
+		Bypass:         []string{"laoyutang.cn", ""},
+		PinnedIPPolicy: network.PinnedIPPolicyDirect,
+	})
+	if err != nil {
+		t.Fatalf("set proxy settings: %v", err)
+	}
+	if proxy, _ := network.ProxyForHost("steamcommunity.com"); proxy == nil || proxy.String() != "socks5://127.0.0.1:1080" {
+		t.Fatalf("expected proxy to be applied immediately, got %v", proxy)
+	}
+
+	network.SetProxyConfig(network.ProxyConfig{})
+	reloaded := newConfigTestApp(t)
+	reloaded.configDir = app.configDir
+	reloaded.configPath = app.configPath
+	reloaded.loadConfig()
+
+	settings := reloaded.GetProxySettings()
+	if settings.Mode != network.ProxyModeSOCKS5 || settings.Host != "127.0.0.1" || settings.Port != 1080 {
+		t.Fatalf("unexpected reloaded proxy settings: %#v", settings)
+	}
+	if len(settings.Bypass) != 1 || settings.PinnedIPPolicy != network.PinnedIPPolicyDirect || settings.PasswordSet {
+		t.Fatalf("unexpected reloaded proxy settings: %#v", settings)
+	}
+	if proxy, _ := network.ProxyForHost("laoyutang.cn"); proxy != nil {
+		t.Fatalf("expected bypass rule to be applied after reload, got %v", proxy)
+	}
+}
@@ -19,6 +19,8 @@ import (
 	"sync/atomic"
 	"time"
 
+	"vpk-manager/internal/network"
+
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
@@ -683,7 +685,7 @@ func (a *App) panelPostMultipartFile(ctx context.Context, credentials *panelCred
 }
 
 func doPanelUploadRequest(req *http.Request, result interface{}) (string, error) {
-	client := &http.Client{}
+	client := network.NewHTTPClient(0)
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("连接面板失败: %w", err)
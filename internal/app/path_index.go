@@ -0,0 +1,334 @@
+package app
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"vpk-manager/internal/parser"
+)
+
+// defaultInnerFileSearchLimit 未指定数量时最多返回的结果数
+const defaultInnerFileSearchLimit = 500
+
+// indexedVPK 单个VPK的内部文件索引，VPK大小或修改时间变化后失效
+type indexedVPK struct {
+	ModTime time.Time        `json:"modTime"`
+	Size    int64            `json:"size"`
+	Files   []indexedVPKFile `json:"files"`
+}
+
+// indexedVPKFile 内部文件。字段名缩写以减小索引文件体积
+type indexedVPKFile struct {
+	Name string `json:"n"` // 小写、以 / 分隔
+	Size int64  `json:"s"`
+	CRC  uint32 `json:"c"`
+}
+
+// vpkPathIndex 所有已扫描VPK的内部文件路径索引，随扫描缓存增量更新并持久化到磁盘。
+// indexedVPK 存入后不再修改，更新时整体替换
+type vpkPathIndex struct {
+	path   string
+	vpks   map[string]*indexedVPK
+	dirty  bool
+	mu     sync.RWMutex
+	saveMu sync.Mutex // 串行化写盘，写盘期间不持有 mu
+}
+
+func newVPKPathIndex() *vpkPathIndex {
+	return &vpkPathIndex{vpks: make(map[string]*indexedVPK)}
+}
+
+// load 设置持久化路径并读取索引，文件不存在时从空索引开始
+func (x *vpkPathIndex) load(indexPath string) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.path = indexPath
+	x.vpks = make(map[string]*indexedVPK)
+	x.dirty = false
+
+	file, err := os.Open(indexPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("读取文件索引失败: %w", err)
+	}
+	defer reader.Close()
+	if err := json.NewDecoder(reader).Decode(&x.vpks); err != nil {
+		x.vpks = make(map[string]*indexedVPK)
+		return fmt.Errorf("解析文件索引失败: %w", err)
+	}
+	return nil
+}
+
+// save 将有变化的索引写回磁盘。持锁时只复制映射，编码和写盘期间查询不受影响
+func (x *vpkPathIndex) save() error {
+	if x == nil {
+		return nil
+	}
+	x.saveMu.Lock()
+	defer x.saveMu.Unlock()
+
+	x.mu.Lock()
+	if !x.dirty || x.path == "" {
+		x.mu.Unlock()
+		return nil
+	}
+	indexPath := x.path
+	snapshot := maps.Clone(x.vpks)
+	x.dirty = false
+	x.mu.Unlock()
+
+	if err := writePathIndex(indexPath, snapshot); err != nil {
+		x.mu.Lock()
+		x.dirty = true
+		x.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func writePathIndex(indexPath string, vpks map[string]*indexedVPK) error {
+	if err := os.MkdirAll(filepath.Dir(indexPath), 0755); err != nil {
+		return err
+	}
+	tempPath := indexPath + ".tmp"
+	file, err := os.Create(tempPath)
+	if err != nil {
+		return err
+	}
+	writer := gzip.NewWriter(file)
+	encodeErr := json.NewEncoder(writer).Encode(vpks)
+	closeErr := writer.Close()
+	fileErr := file.Close()
+	if err := firstError(encodeErr, closeErr, fileErr); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	if err := os.Rename(tempPath, indexPath); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	return nil
+}
+
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// files 返回VPK的内部文件列表。索引缺失或过期时重新读取VPK目录并更新索引。
+func (x *vpkPathIndex) files(vpkPath string, modTime time.Time, size int64) ([]indexedVPKFile, error) {
+	x.mu.RLock()
+	entry, ok := x.vpks[vpkPath]
+	x.mu.RUnlock()
+	if ok && entry.Size == size && entry.ModTime.Equal(modTime) {
+		return entry.Files, nil
+	}
+
+	entries, err := readVPKEntriesSafely(vpkPath)
+	if err != nil {
+		return nil, err
+	}
+	return x.store(vpkPath, modTime, size, entries), nil
+}
+
+// store 用已读取的内部文件列表更新索引，扫描时解析VPK得到的列表直接复用
+func (x *vpkPathIndex) store(vpkPath string, modTime time.Time, size int64, entries []parser.VPKEntryInfo) []indexedVPKFile {
+	files := make([]indexedVPKFile, 0, len(entries))
+	for _, e := range entries {
+		files = append(files, indexedVPKFile{Name: normalizeConflictFilePath(e.Name), Size: e.Size, CRC: e.CRC})
+	}
+
+	x.mu.Lock()
+	x.vpks[vpkPath] = &indexedVPK{ModTime: modTime, Size: size, Files: files}
+	x.dirty = true
+	x.mu.Unlock()
+	return files
+}
+
+// update 确保VPK已被索引，供扫描缓存更新时调用
+func (x *vpkPathIndex) update(vpkPath string, modTime time.Time, size int64) {
+	if x == nil {
+		return
+	}
+	if _, err := x.files(vpkPath, modTime, size); err != nil {
+		log.Printf("更新文件索引失败: %s, 错误: %v", vpkPath, err)
+	}
+}
+
+func (x *vpkPathIndex) remove(vpkPath string) {
+	if x == nil {
+		return
+	}
+	x.mu.Lock()
+	if _, ok := x.vpks[vpkPath]; ok {
+		delete(x.vpks, vpkPath)
+		x.dirty = true
+	}
+	x.mu.Unlock()
+}
+
+// prune 移除不在 keep 中的VPK
+func (x *vpkPathIndex) prune(keep map[string]bool) {
+	if x == nil {
+		return
+	}
+	x.mu.Lock()
+	for vpkPath := range x.vpks {
+		if !keep[vpkPath] {
+			delete(x.vpks, vpkPath)
+			x.dirty = true
+		}
+	}
+	x.mu.Unlock()
+}
+
+func readVPKEntriesSafely(filePath string) (entries []parser.VPKEntryInfo, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("解析VPK文件时发生异常: %v", r)
+		}
+	}()
+
+	return parser.GetVPKEntries(filePath)
+}
+
+// vpkInnerFileNames 获取VPK内部文件路径（小写），优先使用索引
+func (a *App) vpkInnerFileNames(vpkPath string) ([]string, error) {
+	if a.pathIndex == nil {
+		return getVPKFileListSafely(vpkPath)
+	}
+	info, err := os.Stat(vpkPath)
+	if err != nil {
+		return nil, err
+	}
+	files, err := a.pathIndex.files(vpkPath, info.ModTime(), info.Size())
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(files))
+	for i, file := range files {
+		names[i] = file.Name
+	}
+	return names, nil
+}
+
+// VPKInnerFileHit 包含目标文件的模组
+type VPKInnerFileHit struct {
+	File  string          `json:"file"` // VPK 内部路径
+	Size  int64           `json:"size"`
+	CRC   string          `json:"crc"` // 8 位十六进制
+	Addon ConflictVPKFile `json:"addon"`
+}
+
+// VPKInnerFileSearchResult 内部文件搜索结果
+type VPKInnerFileSearchResult struct {
+	Hits      []VPKInnerFileHit `json:"hits"`
+	Total     int               `json:"total"`
+	Truncated bool              `json:"truncated"`
+}
+
+// newInnerFileMatcher 构造匹配函数。mode 为 substring（默认）、prefix 或 glob；
+// 不含 / 的 glob 只匹配文件名，如 *.nut。
+func newInnerFileMatcher(query string, mode string) (func(name string) bool, error) {
+	query = normalizeConflictFilePath(query)
+	if query == "" {
+		return nil, fmt.Errorf("请输入要查找的文件路径")
+	}
+
+	switch mode {
+	case "", "substring":
+		return func(name string) bool { return strings.Contains(name, query) }, nil
+	case "prefix":
+		return func(name string) bool { return strings.HasPrefix(name, query) }, nil
+	case "glob":
+		if _, err := path.Match(query, ""); err != nil {
+			return nil, fmt.Errorf("通配符格式无效: %s", query)
+		}
+		matchBase := !strings.Contains(query, "/")
+		return func(name string) bool {
+			if matchBase {
+				name = path.Base(name)
+			}
+			matched, _ := path.Match(query, name)
+			return matched
+		}, nil
+	default:
+		return nil, fmt.Errorf("不支持的搜索方式: %s", mode)
+	}
+}
+
+// SearchVPKInnerFiles 在所有已扫描VPK的内部文件中查找路径，返回所属模组、大小和CRC
+func (a *App) SearchVPKInnerFiles(query string, mode string, limit int) (*VPKInnerFileSearchResult, error) {
+	match, err := newInnerFileMatcher(query, mode)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultInnerFileSearchLimit
+	}
+	if a.pathIndex == nil {
+		return &VPKInnerFileSearchResult{Hits: []VPKInnerFileHit{}}, nil
+	}
+
+	var hits []VPKInnerFileHit
+	a.vpkCache.Range(func(key, value interface{}) bool {
+		vpkPath := key.(string)
+		cache := value.(*VPKFileCache)
+		a.pathIndex.mu.RLock()
+		entry, ok := a.pathIndex.vpks[vpkPath]
+		a.pathIndex.mu.RUnlock()
+		if !ok {
+			return true
+		}
+		for _, file := range entry.Files {
+			if match(file.Name) {
+				hits = append(hits, VPKInnerFileHit{
+					File:  file.Name,
+					Size:  file.Size,
+					CRC:   fmt.Sprintf("%08x", file.CRC),
+					Addon: newConflictVPKFile(cache.File.Name, vpkPath, cache.File.Title, cache.File.Location),
+				})
+			}
+		}
+		return true
+	})
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].File != hits[j].File {
+			return hits[i].File < hits[j].File
+		}
+		return hits[i].Addon.Path < hits[j].Addon.Path
+	})
+
+	result := &VPKInnerFileSearchResult{Hits: hits, Total: len(hits)}
+	if len(hits) > limit {
+		result.Hits = hits[:limit]
+		result.Truncated = true
+	}
+	if result.Hits == nil {
+		result.Hits = []VPKInnerFileHit{}
+	}
+	return result, nil
+}
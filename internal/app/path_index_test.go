@@ -0,0 +1,134 @@
+package app
+
+import (
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newPathIndexTestApp(t *testing.T) *App {
+	t.Helper()
+	app := &App{rootDir: t.TempDir(), pathIndex: newVPKPathIndex()}
+	if err := app.pathIndex.load(filepath.Join(t.TempDir(), "vpk_path_index.json.gz")); err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
+
+func TestSearchVPKInnerFilesFindsOwningAddons(t *testing.T) {
+	app := newPathIndexTestApp(t)
+	coachHead := []byte("coach head texture")
+	first := filepath.Join(app.rootDir, "coach.vpk")
+	second := filepath.Join(app.rootDir, "scripts.vpk")
+	writeTestVPK(t, first, map[string][]byte{
+		"materials/models/survivors/coach/coach_head.vtf": coachHead,
+		"scripts/vscripts/director_base_addon.nut":        []byte("a"),
+	})
+	writeTestVPK(t, second, map[string][]byte{
+		"scripts/vscripts/mapspawn.nut": []byte("bb"),
+	})
+	app.processVPKFileWithCache(first)
+	app.processVPKFileWithCache(second)
+
+	result, err := app.SearchVPKInnerFiles("COACH_HEAD", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 1 || result.Hits[0].Addon.Path != first || result.Hits[0].Size != int64(len(coachHead)) {
+		t.Fatalf("unexpected substring result: %+v", result)
+	}
+	if want := crc32.ChecksumIEEE(coachHead); result.Hits[0].CRC != fmt.Sprintf("%08x", want) {
+		t.Fatalf("unexpected crc %s", result.Hits[0].CRC)
+	}
+
+	result, err = app.SearchVPKInnerFiles("*.nut", "glob", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 2 || len(result.Hits) != 1 || !result.Truncated {
+		t.Fatalf("unexpected glob result: %+v", result)
+	}
+
+	result, err = app.SearchVPKInnerFiles("scripts/vscripts/m", "prefix", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 1 || result.Hits[0].Addon.Path != second {
+		t.Fatalf("unexpected prefix result: %+v", result)
+	}
+
+	if _, err := app.SearchVPKInnerFiles("[", "glob", 0); err == nil {
+		t.Fatal("expected invalid glob error")
+	}
+	if _, err := app.SearchVPKInnerFiles("x", "regex", 0); err == nil {
+		t.Fatal("expected unsupported mode error")
+	}
+}
+
+func TestVPKPathIndexPersistsAndRefreshesStaleEntries(t *testing.T) {
+	app := newPathIndexTestApp(t)
+	vpkPath := filepath.Join(app.rootDir, "a.vpk")
+	writeTestVPK(t, vpkPath, map[string][]byte{"scripts/a.txt": []byte("a")})
+	info, _ := os.Stat(vpkPath)
+	app.pathIndex.update(vpkPath, info.ModTime(), info.Size())
+	if err := app.pathIndex.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := newVPKPathIndex()
+	if err := reloaded.load(app.pathIndex.path); err != nil {
+		t.Fatal(err)
+	}
+	files, err := reloaded.files(vpkPath, info.ModTime(), info.Size())
+	if err != nil || len(files) != 1 || files[0].Name != "scripts/a.txt" {
+		t.Fatalf("expected persisted index, got %+v, %v", files, err)
+	}
+
+	// VPK 变化后索引重新读取
+	writeTestVPK(t, vpkPath, map[string][]byte{"scripts/a.txt": []byte("a"), "scripts/b.txt": []byte("b")})
+	later := info.ModTime().Add(time.Second)
+	os.Chtimes(vpkPath, later, later)
+	info, _ = os.Stat(vpkPath)
+	names, err := (&App{pathIndex: reloaded}).vpkInnerFileNames(vpkPath)
+	if err != nil || len(names) != 2 {
+		t.Fatalf("expected refreshed index, got %v, %v", names, err)
+	}
+
+	reloaded.prune(map[string]bool{})
+	if len(reloaded.vpks) != 0 {
+		t.Fatal("expected prune to drop missing vpks")
+	}
+}
+
+func TestVPKPathIndexKeepsChangesWhenSaveFails(t *testing.T) {
+	app := newPathIndexTestApp(t)
+	vpkPath := filepath.Join(app.rootDir, "a.vpk")
+	writeTestVPK(t, vpkPath, map[string][]byte{"scripts/a.txt": []byte("a")})
+	app.processVPKFileWithCache(vpkPath)
+
+	// 扫描时直接复用解析得到的文件列表
+	app.pathIndex.mu.RLock()
+	entry := app.pathIndex.vpks[vpkPath]
+	app.pathIndex.mu.RUnlock()
+	if entry == nil || len(entry.Files) != 1 || entry.Files[0].Name != "scripts/a.txt" {
+		t.Fatalf("expected scan to index the vpk, got %+v", entry)
+	}
+
+	indexPath := app.pathIndex.path
+	if err := os.MkdirAll(indexPath+".tmp", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.pathIndex.save(); err == nil {
+		t.Fatal("expected save to fail while the temp path is a directory")
+	}
+	os.Remove(indexPath + ".tmp")
+	if err := app.pathIndex.save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(indexPath); err != nil {
+		t.Fatalf("expected the retried save to write the index: %v", err)
+	}
+}
@@ -0,0 +1,324 @@
+package app
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"vpk-manager/internal/parser"
+)
+
+const (
+	// previewThumbnailSize 缩略图最长边的像素数
+	previewThumbnailSize = 320
+	// previewThumbnailQuality 缩略图 JPEG 质量
+	previewThumbnailQuality = 85
+	// previewCacheRetention 超过该时间未被访问的缩略图缓存会在启动时清理
+	previewCacheRetention = 30 * 24 * time.Hour
+)
+
+// previewImageServer 通过本地 HTTP 按需提供 VPK 预览图、贴图预览、音频试听和 3D 模型，缩略图缓存在磁盘上。
+// 地址中带有每次启动随机生成的令牌，其他本地程序或网页无法猜出地址读取 VPK 内容
+type previewImageServer struct {
+	app      *App
+	server   *http.Server
+	port     int
+	token    string
+	cacheDir string
+	locks    sync.Map // 缓存键 -> *sync.Mutex，避免同一缩略图被并发生成
+}
+
+func newPreviewImageServer(app *App, cacheDir string) *previewImageServer {
+	return &previewImageServer{
+		app:      app,
+		token:    newPreviewToken(),
+		cacheDir: cacheDir,
+	}
+}
+
+func newPreviewToken() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		panic(fmt.Sprintf("生成预览服务令牌失败: %v", err))
+	}
+	return hex.EncodeToString(buf)
+}
+
+// Start 启动预览图服务，并清理长期未使用的缩略图缓存
+func (s *previewImageServer) Start() error {
+	if err := os.MkdirAll(s.cacheDir, 0755); err != nil {
+		return err
+	}
+	go s.pruneCache()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	s.port = listener.Addr().(*net.TCPAddr).Port
+	log.Printf("预览图服务已启动，端口 %d", s.port)
+
+	s.server = &http.Server{Handler: s.handler()}
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("预览图服务错误: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (s *previewImageServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/preview", s.handlePreview)
+	mux.HandleFunc("/audio", s.handleAudio)
+	mux.HandleFunc("/texture", s.handleTexture)
+	mux.HandleFunc("/spray", s.handleSpray)
+	mux.HandleFunc("/model", s.handleModel)
+	return s.requireToken(mux)
+}
+
+// requireToken 拒绝没有携带正确令牌的请求
+func (s *previewImageServer) requireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// endpointURL 返回带令牌的服务地址，query 中的参数会被转义
+func (s *previewImageServer) endpointURL(endpoint string, query url.Values) string {
+	query.Set("token", s.token)
+	return fmt.Sprintf("http://127.0.0.1:%d/%s?%s", s.port, endpoint, query.Encode())
+}
+
+// previewCacheKey 根据文件路径、大小和修改时间生成缓存键，文件或外部图片变化后自动失效
+func previewCacheKey(path string, cache *VPKFileCache) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%d|%d|%d", path, cache.Size, cache.ModTime.UnixNano(), cache.ImageModTime.UnixNano())))
+	return hex.EncodeToString(sum[:])
+}
+
+// URL 返回预览图地址，thumbnail 为 true 时返回缩略图。服务未启动或文件没有预览图时返回空字符串。
+func (s *previewImageServer) URL(path string, cache *VPKFileCache, thumbnail bool) string {
+	if s == nil || s.port == 0 || cache.File.PreviewSource == "" {
+		return ""
+	}
+	size := "full"
+	if thumbnail {
+		size = "thumb"
+	}
+	return s.endpointURL("preview", url.Values{"path": {path}, "v": {previewCacheKey(path, cache)}, "size": {size}})
+}
+
+func (s *previewImageServer) handlePreview(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Query().Get("path")
+	// 只提供已扫描到的 VPK 的预览图，避免读取任意文件
+	cached, ok := s.app.vpkCache.Load(path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	cache := cached.(*VPKFileCache)
+	if cache.File.PreviewSource == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	var data []byte
+	var err error
+	if r.URL.Query().Get("size") == "thumb" {
+		data, err = s.thumbnail(path, cache)
+	} else {
+		data, err = parser.ReadPreviewImage(path, cache.File.PreviewSource)
+	}
+	if err != nil {
+		log.Printf("读取预览图失败: %s, 错误: %v", path, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 地址中带有缓存键，内容变化后地址随之变化，可以长期缓存
+	w.Header().Set("Cache-Control", "max-age=31536000, immutable")
+	w.Header().Set("Content-Type", http.DetectContentType(data))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Write(data)
+}
+
+// previewEntry 预览请求指向的VPK内文件
+type previewEntry struct {
+	vpkPath string
+	cache   *VPKFileCache
+	name    string // 校验后的内部路径，使用 / 分隔
+}
+
+// resolvePreviewEntry 解析请求中的 path 与 entry 参数，扩展名须为 exts 之一（不区分大小写）。
+// 校验失败时已写入错误响应，返回 false
+func (s *previewImageServer) resolvePreviewEntry(w http.ResponseWriter, r *http.Request, exts ...string) (previewEntry, bool) {
+	vpkPath := r.URL.Query().Get("path")
+
+	// 只提供已扫描到的 VPK，内部路径按解包的规则校验
+	cached, ok := s.app.vpkCache.Load(vpkPath)
+	if !ok {
+		http.NotFound(w, r)
+		return previewEntry{}, false
+	}
+	cleanEntry, err := cleanVPKEntryPath(r.URL.Query().Get("entry"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return previewEntry{}, false
+	}
+	name := filepath.ToSlash(cleanEntry)
+	ext := path.Ext(name)
+	if !slices.ContainsFunc(exts, func(allowed string) bool { return strings.EqualFold(allowed, ext) }) {
+		http.Error(w, "不支持的文件类型: "+ext, http.StatusBadRequest)
+		return previewEntry{}, false
+	}
+
+	return previewEntry{vpkPath: vpkPath, cache: cached.(*VPKFileCache), name: name}, true
+}
+
+// thumbnail 返回缩略图，优先使用磁盘缓存
+func (s *previewImageServer) thumbnail(path string, cache *VPKFileCache) ([]byte, error) {
+	key := previewCacheKey(path, cache)
+	lock, _ := s.locks.LoadOrStore(key, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer func() {
+		lock.(*sync.Mutex).Unlock()
+		s.locks.Delete(key)
+	}()
+
+	cachePath := filepath.Join(s.cacheDir, key+".jpg")
+	if data, err := os.ReadFile(cachePath); err == nil {
+		now := time.Now()
+		os.Chtimes(cachePath, now, now)
+		return data, nil
+	}
+
+	original, err := parser.ReadPreviewImage(path, cache.File.PreviewSource)
+	if err != nil {
+		return nil, err
+	}
+	data, err := makeThumbnail(original, previewThumbnailSize)
+	if err != nil {
+		// 无法解码时直接返回原图，不影响显示
+		return original, nil
+	}
+
+	tempPath := cachePath + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err == nil {
+		if err := os.Rename(tempPath, cachePath); err != nil {
+			os.Remove(tempPath)
+		}
+	}
+	return data, nil
+}
+
+// pruneCache 清理长期未访问的缩略图
+func (s *previewImageServer) pruneCache() {
+	entries, err := os.ReadDir(s.cacheDir)
+	if err != nil {
+		return
+	}
+	removed := 0
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() {
+			continue
+		}
+		if time.Since(info.ModTime()) > previewCacheRetention {
+			if os.Remove(filepath.Join(s.cacheDir, entry.Name())) == nil {
+				removed++
+			}
+		}
+	}
+	if removed > 0 {
+		log.Printf("已清理 %d 个过期预览图缓存", removed)
+	}
+}
+
+// makeThumbnail 将图片等比缩小到最长边不超过 maxSize，并编码为 JPEG
+func makeThumbnail(data []byte, maxSize int) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, resizeImage(src, maxSize), &jpeg.Options{Quality: previewThumbnailQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resizeImage 使用区域平均等比缩小图片，透明部分按白色背景合成
+func resizeImage(src image.Image, maxSize int) image.Image {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	dstW, dstH := srcW, srcH
+	if srcW > maxSize || srcH > maxSize {
+		if srcW >= srcH {
+			dstW, dstH = maxSize, max(srcH*maxSize/srcW, 1)
+		} else {
+			dstW, dstH = max(srcW*maxSize/srcH, 1), maxSize
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0 := bounds.Min.Y + y*srcH/dstH
+		y1 := max(bounds.Min.Y+(y+1)*srcH/dstH, y0+1)
+		for x := 0; x < dstW; x++ {
+			x0 := bounds.Min.X + x*srcW/dstW
+			x1 := max(bounds.Min.X+(x+1)*srcW/dstW, x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r += uint64(pr)
+					g += uint64(pg)
+					b += uint64(pb)
+					a += uint64(pa)
+					n++
+				}
+			}
+			// 颜色值是预乘 alpha 的，补上白色背景
+			white := 0xffff*n - a
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8((r + white) / n >> 8),
+				G: uint8((g + white) / n >> 8),
+				B: uint8((b + white) / n >> 8),
+				A: 0xff,
+			})
+		}
+	}
+	return dst
+}
+
+// previewFile 返回用于列表的文件信息，预览图字段为缩略图地址
+func (a *App) previewFile(path string, cache *VPKFileCache) VPKFile {
+	file := cache.File
+	file.PreviewImage = a.previewServer.URL(path, cache, true)
+	return file
+}
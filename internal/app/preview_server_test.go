@@ -0,0 +1,138 @@
+package app
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func newPreviewTestApp(t *testing.T) (*App, *previewImageServer) {
+	t.Helper()
+	app := &App{rootDir: t.TempDir()}
+	server := newPreviewImageServer(app, filepath.Join(t.TempDir(), "preview_cache"))
+	if err := os.MkdirAll(server.cacheDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server.handler())
+	t.Cleanup(httpServer.Close)
+	_, port, _ := net.SplitHostPort(strings.TrimPrefix(httpServer.URL, "http://"))
+	server.port, _ = strconv.Atoi(port)
+	app.previewServer = server
+	return app, server
+}
+
+func testJPEG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func fetchPreview(t *testing.T, rawURL string) (int, []byte) {
+	t.Helper()
+	resp, err := http.Get(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, data
+}
+
+func TestPreviewServerServesThumbnailAndOriginal(t *testing.T) {
+	app, server := newPreviewTestApp(t)
+	original := testJPEG(t, 800, 400)
+	vpkPath := filepath.Join(app.rootDir, "a.vpk")
+	writeTestVPK(t, vpkPath, map[string][]byte{"addonimage.jpg": original})
+	app.processVPKFileWithCache(vpkPath)
+
+	files := app.GetVPKFiles()
+	if len(files) != 1 || !strings.Contains(files[0].PreviewImage, "size=thumb") {
+		t.Fatalf("expected thumbnail url in listing, got %+v", files)
+	}
+
+	status, data := fetchPreview(t, files[0].PreviewImage)
+	if status != http.StatusOK {
+		t.Fatalf("thumbnail status %d", status)
+	}
+	config, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode thumbnail: %v", err)
+	}
+	if config.Width != previewThumbnailSize || config.Height != previewThumbnailSize/2 {
+		t.Fatalf("unexpected thumbnail size %dx%d", config.Width, config.Height)
+	}
+	entries, _ := os.ReadDir(server.cacheDir)
+	if len(entries) != 1 {
+		t.Fatalf("expected thumbnail cached on disk, got %d entries", len(entries))
+	}
+
+	status, data = fetchPreview(t, app.GetVPKPreviewImage(vpkPath))
+	if status != http.StatusOK || !bytes.Equal(data, original) {
+		t.Fatalf("expected original image, status %d, %d bytes", status, len(data))
+	}
+}
+
+func TestPreviewServerRequiresToken(t *testing.T) {
+	app, _ := newPreviewTestApp(t)
+	vpkPath := filepath.Join(app.rootDir, "a.vpk")
+	writeTestVPK(t, vpkPath, map[string][]byte{"addonimage.jpg": testJPEG(t, 8, 8)})
+	app.processVPKFileWithCache(vpkPath)
+
+	valid := app.GetVPKPreviewImage(vpkPath)
+	if status, _ := fetchPreview(t, valid); status != http.StatusOK {
+		t.Fatalf("expected 200 with token, got %d", status)
+	}
+	u, _ := url.Parse(valid)
+	query := u.Query()
+	for _, token := range []string{"", "0123456789abcdef0123456789abcdef"} {
+		query.Set("token", token)
+		u.RawQuery = query.Encode()
+		if status, _ := fetchPreview(t, u.String()); status != http.StatusForbidden {
+			t.Fatalf("expected 403 for token %q, got %d", token, status)
+		}
+	}
+}
+
+func TestPreviewServerRejectsUnknownPathsAndFilesWithoutPreview(t *testing.T) {
+	app, server := newPreviewTestApp(t)
+	vpkPath := filepath.Join(app.rootDir, "no_preview.vpk")
+	writeTestVPK(t, vpkPath, map[string][]byte{"scripts/addon.txt": []byte("x")})
+	app.processVPKFileWithCache(vpkPath)
+
+	if url := app.GetVPKPreviewImage(vpkPath); url != "" {
+		t.Fatalf("expected no preview url, got %q", url)
+	}
+
+	outside := filepath.Join(t.TempDir(), "secret.jpg")
+	if err := os.WriteFile(outside, testJPEG(t, 8, 8), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{vpkPath, outside} {
+		if status, _ := fetchPreview(t, server.endpointURL("preview", url.Values{"path": {path}, "size": {"full"}})); status != http.StatusNotFound {
+			t.Fatalf("expected 404 for %s, got %d", path, status)
+		}
+	}
+}
@@ -15,7 +15,7 @@ var officialTags = map[string]bool{
 	"Bill": true, "Francis": true, "Louis": true, "Zoey": true,
 	"Coach": true, "Ellis": true, "Nick": true, "Rochelle": true,
 
-	// 感染者 (虽然目前轮换逻辑只处理武器和人物，但保留这些标签作为参考)
+	// 感染者（随人物一起轮换）
 	"tank": true, "witch": true, "hunter": true, "smoker": true,
 	"boomer": true, "charger": true, "jockey": true, "spitter": true,
 	"common": true, "uncommon_infected": true,
@@ -92,9 +92,9 @@ func (a *App) rotateModsInternal(config RotationConfig) error {
 	for _, file := range files {
 		if file.Enabled {
 			enabledMods[file.Path] = file
-			if file.PrimaryTag == "武器" || file.PrimaryTag == "人物" {
+			if file.PrimaryTag == "武器" || file.PrimaryTag == "人物" || file.PrimaryTag == "感染者" {
 				// 根据配置过滤
-				if file.PrimaryTag == "人物" && !config.EnableCharacters {
+				if (file.PrimaryTag == "人物" || file.PrimaryTag == "感染者") && !config.EnableCharacters {
 					continue
 				}
 				if file.PrimaryTag == "武器" && !config.EnableWeapons {
@@ -0,0 +1,480 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// 搜索语法：
+//
+//	ellis                    模糊匹配标题、文件名或标签（与原搜索框一致）
+//	tag:武器 -tag:人物        字段条件，前缀 - 表示排除
+//	author:"foo bar"         含空格的值使用引号
+//	size>200MB enabled:false 比较运算符 > >= < <= =，大小单位 B/KB/MB/GB
+//	updated:<30d             修改时间在 30 天内；也可写日期 updated>2026-01-01
+//	a OR (b -c)              相邻条件为“且”，OR 表示“或”，括号用于分组
+//
+// 支持的字段：tag, primary, author, name, title, location, enabled, update, workshop, size, updated；
+// 未知字段（如 c1m1:hotel）或缺少值的字段（如 tag:）按普通文本匹配
+
+// searchFields 支持的字段，其他 field:value 形式的词按普通文本匹配
+var searchFields = map[string]bool{
+	"tag": true, "primary": true, "author": true, "name": true, "title": true, "location": true,
+	"enabled": true, "update": true, "workshop": true, "size": true, "updated": true,
+}
+
+// vpkPredicate 编译后的搜索条件
+type vpkPredicate func(file *VPKFile) bool
+
+type searchTokenKind int
+
+const (
+	searchTokenWord searchTokenKind = iota
+	searchTokenNot
+	searchTokenLParen
+	searchTokenRParen
+	searchTokenOr
+	searchTokenEOF
+)
+
+type searchToken struct {
+	kind   searchTokenKind
+	text   string // 原始文本（保留引号，便于区分字段名与引号内的冒号）
+	negate bool
+	pos    int // 从 1 开始的字符位置，用于错误提示
+}
+
+func searchSyntaxError(pos int, format string, args ...interface{}) error {
+	return fmt.Errorf("搜索语法错误（第 %d 个字符）: %s", pos, fmt.Sprintf(format, args...))
+}
+
+// tokenizeSearchQuery 将查询拆分为词、括号和 OR
+func tokenizeSearchQuery(query string) ([]searchToken, error) {
+	runes := []rune(query)
+	var tokens []searchToken
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(':
+			tokens = append(tokens, searchToken{kind: searchTokenLParen, pos: i + 1})
+			i++
+			continue
+		case r == ')':
+			tokens = append(tokens, searchToken{kind: searchTokenRParen, pos: i + 1})
+			i++
+			continue
+		}
+
+		start := i
+		negate := false
+		if r == '-' {
+			if i+1 >= len(runes) || unicode.IsSpace(runes[i+1]) || runes[i+1] == ')' {
+				return nil, searchSyntaxError(start+1, "'-' 后缺少要排除的条件")
+			}
+			if runes[i+1] == '(' {
+				tokens = append(tokens, searchToken{kind: searchTokenNot, pos: start + 1})
+				i++
+				continue
+			}
+			negate = true
+			i++
+		}
+
+		wordStart := i
+		inQuote := false
+		quotePos := 0
+		for i < len(runes) {
+			c := runes[i]
+			if c == '"' {
+				inQuote = !inQuote
+				quotePos = i + 1
+			} else if !inQuote && (unicode.IsSpace(c) || c == '(' || c == ')') {
+				break
+			}
+			i++
+		}
+		if inQuote {
+			return nil, searchSyntaxError(quotePos, "引号未闭合")
+		}
+
+		text := string(runes[wordStart:i])
+		if text == "OR" && !negate {
+			tokens = append(tokens, searchToken{kind: searchTokenOr, pos: start + 1})
+			continue
+		}
+		tokens = append(tokens, searchToken{kind: searchTokenWord, text: text, negate: negate, pos: start + 1})
+	}
+	tokens = append(tokens, searchToken{kind: searchTokenEOF, pos: len(runes) + 1})
+	return tokens, nil
+}
+
+type searchParser struct {
+	tokens []searchToken
+	index  int
+	now    time.Time
+}
+
+// compileSearchQuery 解析搜索表达式，空查询匹配所有文件
+func compileSearchQuery(query string, now time.Time) (vpkPredicate, error) {
+	tokens, err := tokenizeSearchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return func(*VPKFile) bool { return true }, nil
+	}
+
+	p := &searchParser{tokens: tokens, now: now}
+	predicate, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if token := p.peek(); token.kind != searchTokenEOF {
+		if token.kind == searchTokenRParen {
+			return nil, searchSyntaxError(token.pos, "多余的 ')'")
+		}
+		return nil, searchSyntaxError(token.pos, "无法解析的内容")
+	}
+	return predicate, nil
+}
+
+func (p *searchParser) peek() searchToken {
+	return p.tokens[p.index]
+}
+
+func (p *searchParser) next() searchToken {
+	token := p.tokens[p.index]
+	if token.kind != searchTokenEOF {
+		p.index++
+	}
+	return token
+}
+
+// parseOr: and ('OR' and)*
+func (p *searchParser) parseOr() (vpkPredicate, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == searchTokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(file *VPKFile) bool { return l(file) || right(file) }
+	}
+	return left, nil
+}
+
+// parseAnd: unary+，相邻条件同时满足
+func (p *searchParser) parseAnd() (vpkPredicate, error) {
+	var predicates []vpkPredicate
+	for {
+		token := p.peek()
+		if token.kind == searchTokenEOF || token.kind == searchTokenRParen || token.kind == searchTokenOr {
+			break
+		}
+		predicate, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, predicate)
+	}
+	if len(predicates) == 0 {
+		token := p.peek()
+		switch token.kind {
+		case searchTokenOr:
+			return nil, searchSyntaxError(token.pos, "OR 前缺少条件")
+		case searchTokenRParen:
+			return nil, searchSyntaxError(token.pos, "括号内缺少条件")
+		default:
+			return nil, searchSyntaxError(token.pos, "OR 后缺少条件")
+		}
+	}
+	if len(predicates) == 1 {
+		return predicates[0], nil
+	}
+	return func(file *VPKFile) bool {
+		for _, predicate := range predicates {
+			if !predicate(file) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// parseUnary: '-' '(' expr ')' | '(' expr ')' | term
+func (p *searchParser) parseUnary() (vpkPredicate, error) {
+	token := p.next()
+	switch token.kind {
+	case searchTokenNot:
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(file *VPKFile) bool { return !inner(file) }, nil
+	case searchTokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != searchTokenRParen {
+			return nil, searchSyntaxError(token.pos, "缺少与之匹配的 ')'")
+		}
+		p.next()
+		return inner, nil
+	case searchTokenWord:
+		predicate, err := p.compileTerm(token)
+		if err != nil {
+			return nil, err
+		}
+		if token.negate {
+			return func(file *VPKFile) bool { return !predicate(file) }, nil
+		}
+		return predicate, nil
+	default:
+		return nil, searchSyntaxError(token.pos, "缺少搜索条件")
+	}
+}
+
+// splitSearchTerm 将 field:value、field>value 拆分为字段、运算符和值。
+// 字段名必须是不带引号的字母，否则整个词按普通文本处理。
+func splitSearchTerm(text string) (field, op, value string, ok bool) {
+	end := strings.IndexFunc(text, func(r rune) bool { return !unicode.IsLetter(r) || r > unicode.MaxASCII })
+	if end <= 0 {
+		return "", "", "", false
+	}
+	field = strings.ToLower(text[:end])
+	rest := text[end:]
+	if strings.HasPrefix(rest, ":") {
+		rest = rest[1:]
+		op = ":"
+	}
+	for _, candidate := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(rest, candidate) {
+			op = candidate
+			rest = rest[len(candidate):]
+			break
+		}
+	}
+	if op == "" {
+		return "", "", "", false
+	}
+	return field, op, strings.ReplaceAll(rest, `"`, ""), true
+}
+
+func (p *searchParser) compileTerm(token searchToken) (vpkPredicate, error) {
+	field, op, value, ok := splitSearchTerm(token.text)
+	// 未知字段和尚未输入值的字段按普通文本处理，输入过程中不提示错误
+	if !ok || !searchFields[field] || value == "" {
+		text := strings.ToLower(strings.ReplaceAll(token.text, `"`, ""))
+		return func(file *VPKFile) bool { return matchesVPKText(file, text) }, nil
+	}
+
+	equality := op == ":" || op == "="
+	requireEquality := func() error {
+		if !equality {
+			return searchSyntaxError(token.pos, "字段 %s 不支持运算符 %s", field, op)
+		}
+		return nil
+	}
+	lower := strings.ToLower(value)
+
+	switch field {
+	case "tag":
+		if err := requireEquality(); err != nil {
+			return nil, err
+		}
+		return func(file *VPKFile) bool {
+			if strings.EqualFold(file.PrimaryTag, value) {
+				return true
+			}
+			for _, tag := range file.SecondaryTags {
+				if strings.EqualFold(tag, value) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	case "primary":
+		if err := requireEquality(); err != nil {
+			return nil, err
+		}
+		return func(file *VPKFile) bool { return strings.EqualFold(file.PrimaryTag, value) }, nil
+	case "author", "name", "title":
+		if err := requireEquality(); err != nil {
+			return nil, err
+		}
+		return func(file *VPKFile) bool {
+			target := file.Author
+			if field == "name" {
+				target = file.Name
+			} else if field == "title" {
+				target = file.Title
+			}
+			return strings.Contains(strings.ToLower(target), lower)
+		}, nil
+	case "location":
+		if err := requireEquality(); err != nil {
+			return nil, err
+		}
+		if lower != "root" && lower != "workshop" && lower != "disabled" {
+			return nil, searchSyntaxError(token.pos, "location 只能是 root、workshop 或 disabled")
+		}
+		return func(file *VPKFile) bool { return file.Location == lower }, nil
+	case "enabled", "update":
+		if err := requireEquality(); err != nil {
+			return nil, err
+		}
+		want, err := parseSearchBool(value)
+		if err != nil {
+			return nil, searchSyntaxError(token.pos, "%s 的值应为 true 或 false", field)
+		}
+		if field == "enabled" {
+			return func(file *VPKFile) bool { return file.Enabled == want }, nil
+		}
+		return func(file *VPKFile) bool { return file.HasUpdate == want }, nil
+	case "workshop":
+		if err := requireEquality(); err != nil {
+			return nil, err
+		}
+		if want, err := parseSearchBool(value); err == nil {
+			return func(file *VPKFile) bool { return (file.WorkshopID != "") == want }, nil
+		}
+		return func(file *VPKFile) bool { return file.WorkshopID == value }, nil
+	case "size":
+		if op == ":" {
+			return nil, searchSyntaxError(token.pos, "size 需要比较运算符，如 size>200MB")
+		}
+		size, err := parseSearchSize(value)
+		if err != nil {
+			return nil, searchSyntaxError(token.pos, "%v", err)
+		}
+		return func(file *VPKFile) bool { return compareSearchValue(compareInt64(file.Size, size), op) }, nil
+	default: // updated
+		return p.compileUpdatedTerm(token, op, value)
+	}
+}
+
+// compileUpdatedTerm 处理修改时间条件。相对时间表示“距今多久”：updated<30d 即 30 天内修改过；
+// 日期按当天计算：updated>2026-01-01 即该日期之后修改。
+func (p *searchParser) compileUpdatedTerm(token searchToken, op, value string) (vpkPredicate, error) {
+	if op == ":" {
+		return nil, searchSyntaxError(token.pos, "updated 需要比较运算符，如 updated:<30d")
+	}
+
+	var threshold time.Time
+	if age, err := parseSearchAge(value); err == nil {
+		if op == "=" {
+			return nil, searchSyntaxError(token.pos, "相对时间不支持 =，请使用 < 或 >")
+		}
+		threshold = p.now.Add(-age)
+		// 距今时间越短，修改时间越晚，比较方向相反
+		op = map[string]string{"<": ">", "<=": ">=", ">": "<", ">=": "<="}[op]
+	} else if date, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		threshold = date
+		if op == "=" {
+			dayEnd := date.AddDate(0, 0, 1)
+			return func(file *VPKFile) bool {
+				modified, ok := fileModifiedTime(file)
+				return ok && !modified.Before(date) && modified.Before(dayEnd)
+			}, nil
+		}
+		if op == ">" {
+			// “某日之后”不包含当天
+			threshold = date.AddDate(0, 0, 1)
+			op = ">="
+		}
+	} else {
+		return nil, searchSyntaxError(token.pos, "无法识别的时间: %s，应为 30d、12h、2w 或 2006-01-02", value)
+	}
+
+	return func(file *VPKFile) bool {
+		modified, ok := fileModifiedTime(file)
+		return ok && compareSearchValue(modified.Compare(threshold), op)
+	}, nil
+}
+
+func fileModifiedTime(file *VPKFile) (time.Time, bool) {
+	modified, err := time.Parse(time.RFC3339, file.LastModified)
+	return modified, err == nil
+}
+
+// compareSearchValue 根据比较结果（-1/0/1）判断是否满足运算符
+func compareSearchValue(c int, op string) bool {
+	switch op {
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	default:
+		return c == 0
+	}
+}
+
+func parseSearchBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "1":
+		return true, nil
+	case "false", "no", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid bool: %s", value)
+}
+
+// parseSearchSize 解析 200MB、1.5GB、512KB、100 这样的大小
+func parseSearchSize(value string) (int64, error) {
+	upper := strings.ToUpper(value)
+	units := []struct {
+		suffix string
+		scale  float64
+	}{
+		{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+		{"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1},
+	}
+	scale := 1.0
+	for _, unit := range units {
+		if strings.HasSuffix(upper, unit.suffix) {
+			upper = strings.TrimSuffix(upper, unit.suffix)
+			scale = unit.scale
+			break
+		}
+	}
+	number, err := strconv.ParseFloat(upper, 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("无法识别的大小: %s，应为 200MB、1.5GB 这样的格式", value)
+	}
+	return int64(number * scale), nil
+}
+
+// parseSearchAge 解析 12h、30d、2w、6m、1y 这样的相对时间
+func parseSearchAge(value string) (time.Duration, error) {
+	if len(value) < 2 {
+		return 0, fmt.Errorf("invalid age: %s", value)
+	}
+	unit := strings.ToLower(value[len(value)-1:])
+	number, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid age: %s", value)
+	}
+	day := 24 * time.Hour
+	scales := map[string]time.Duration{"h": time.Hour, "d": day, "w": 7 * day, "m": 30 * day, "y": 365 * day}
+	scale, ok := scales[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid age: %s", value)
+	}
+	return time.Duration(number) * scale, nil
+}
@@ -0,0 +1,134 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func searchTestFiles(now time.Time) []VPKFile {
+	return []VPKFile{
+		{Name: "ak47_gold.vpk", Title: "Golden AK", PrimaryTag: "武器", SecondaryTags: []string{"ak47"}, Author: "foo bar", Size: 300 << 20, Enabled: false, Location: "disabled",
+			LastModified: now.AddDate(0, 0, -3).Format(time.RFC3339)},
+		{Name: "ellis.vpk", Title: "Ellis Skin", PrimaryTag: "人物", SecondaryTags: []string{"ellis"}, Author: "foo", Size: 250 << 20, Enabled: true, Location: "root", WorkshopID: "123",
+			LastModified: now.AddDate(0, 0, -3).Format(time.RFC3339)},
+		{Name: "m4.vpk", Title: "M4", PrimaryTag: "武器", SecondaryTags: []string{"m16"}, Author: "baz", Size: 10 << 20, Enabled: true, Location: "workshop", WorkshopID: "456", HasUpdate: true,
+			LastModified: now.AddDate(0, -3, 0).Format(time.RFC3339)},
+	}
+}
+
+func matchingSearchNames(t *testing.T, query string, files []VPKFile, now time.Time) string {
+	t.Helper()
+	predicate, err := compileSearchQuery(query, now)
+	if err != nil {
+		t.Fatalf("compile %q: %v", query, err)
+	}
+	var names []string
+	for i := range files {
+		if predicate(&files[i]) {
+			names = append(names, files[i].Name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+func TestCompileSearchQueryMatchesFieldsAndOperators(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.Local)
+	files := searchTestFiles(now)
+
+	cases := map[string]string{
+		"":                              "ak47_gold.vpk,ellis.vpk,m4.vpk",
+		"ellis":                         "ellis.vpk",
+		"gldak":                         "ak47_gold.vpk", // 默认模糊匹配
+		`tag:武器 author:"foo bar"`:       "ak47_gold.vpk",
+		"size>200MB enabled:false":      "ak47_gold.vpk",
+		"-tag:人物 updated:<30d":          "ak47_gold.vpk",
+		"updated>30d":                   "m4.vpk",
+		"updated>=2026-05-01":           "ak47_gold.vpk,ellis.vpk",
+		"workshop:true size<=0.5GB":     "ellis.vpk,m4.vpk",
+		"workshop:456 OR location:root": "ellis.vpk,m4.vpk",
+		"tag:ak47 OR (tag:人物 -enabled:false)": "ak47_gold.vpk,ellis.vpk",
+		"-(tag:武器 update:false)":              "ellis.vpk,m4.vpk",
+		`"tag:武器"`:                            "",
+		"color:red":                           "", // 未知字段按普通文本匹配
+		"tag:":                                "", // 尚未输入值
+		"Golden: OR author:":                  "",
+	}
+	for query, want := range cases {
+		if got := matchingSearchNames(t, query, files, now); got != want {
+			t.Errorf("query %q: expected %q, got %q", query, want, got)
+		}
+	}
+}
+
+func TestCompileSearchQueryReportsSyntaxErrors(t *testing.T) {
+	cases := map[string]string{
+		`author:"foo`:      "第 8 个字符",
+		"(tag:武器":          "缺少与之匹配的 ')'",
+		"tag:武器)":          "多余的 ')'",
+		"OR ellis":         "OR 前缺少条件",
+		"ellis OR":         "OR 后缺少条件",
+		"()":               "括号内缺少条件",
+		"ellis -":          "'-' 后缺少要排除的条件",
+		"size:200MB":       "size 需要比较运算符",
+		"size>lots":        "无法识别的大小",
+		"updated<soon":     "无法识别的时间",
+		"enabled:maybe":    "true 或 false",
+		"tag>武器":           "不支持运算符",
+		"location:nowhere": "location 只能是",
+	}
+	for query, want := range cases {
+		_, err := compileSearchQuery(query, time.Now())
+		if err == nil {
+			t.Errorf("query %q: expected error", query)
+			continue
+		}
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("query %q: expected error containing %q, got %v", query, want, err)
+		}
+	}
+}
+
+func TestSmartCollectionsPersistAndEvaluate(t *testing.T) {
+	app := newConfigTestApp(t)
+	for _, file := range searchTestFiles(time.Now()) {
+		file.Path = "/addons/" + file.Name
+		app.vpkCache.Store(file.Path, &VPKFileCache{File: file})
+	}
+
+	if err := app.SaveSmartCollection("武器", "tag:武器"); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SaveSmartCollection("坏表达式", "size:1"); err == nil {
+		t.Fatal("expected invalid query to be rejected")
+	}
+	if err := app.SaveSmartCollection("武器", "tag:武器 enabled:true"); err != nil {
+		t.Fatal(err)
+	}
+
+	collections := app.GetSmartCollections()
+	if len(collections) != 1 || collections[0].Query != "tag:武器 enabled:true" || collections[0].Count != 1 {
+		t.Fatalf("unexpected collections: %+v", collections)
+	}
+	files, err := app.GetSmartCollectionFiles("武器")
+	if err != nil || len(files) != 1 || files[0].Name != "m4.vpk" {
+		t.Fatalf("unexpected collection files: %+v, %v", files, err)
+	}
+
+	reloaded := newConfigTestApp(t)
+	reloaded.configPath = app.configPath
+	reloaded.loadConfig()
+	if len(reloaded.smartCollections) != 1 || reloaded.smartCollections[0].Name != "武器" {
+		t.Fatalf("expected collection to persist, got %+v", reloaded.smartCollections)
+	}
+
+	if err := app.DeleteSmartCollection("武器"); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.DeleteSmartCollection("武器"); err == nil {
+		t.Fatal("expected error deleting missing collection")
+	}
+	if len(app.GetSmartCollections()) != 0 {
+		t.Fatal("expected collection to be deleted")
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"strings"
 	"time"
 
+	"vpk-manager/internal/network"
+
 	"github.com/go-resty/resty/v2"
 )
 
@@ -187,7 +189,8 @@ func (a *App) panelPost(serverID string, endpoint string, formData map[string]st
 		return "", err
 	}
 
-	client := resty.New().SetTimeout(8 * time.Second)
+	client := resty.New().SetTimeout(8 * time.Second).
+		SetTransport(network.NewTransport(network.TransportOptions{}))
 	request := client.R().SetHeader("Authorization", "Bearer "+credentials.password)
 	if formData != nil {
 		request.SetFormData(formData)
@@ -0,0 +1,164 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// SmartCollection 以搜索表达式定义的动态合集，保存在配置中
+type SmartCollection struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+// SmartCollectionInfo 合集及最近一次计算的结果
+type SmartCollectionInfo struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+	Count int    `json:"count"`
+	Error string `json:"error,omitempty"` // 表达式无效时的错误信息
+}
+
+func cloneSmartCollections(collections []SmartCollection) []SmartCollection {
+	if collections == nil {
+		return []SmartCollection{}
+	}
+	return append([]SmartCollection(nil), collections...)
+}
+
+// rangeSearchQuery 对满足搜索表达式的缓存条目调用 fn，直接匹配缓存中的文件信息
+func (a *App) rangeSearchQuery(query string, fn func(path string, cache *VPKFileCache)) error {
+	matches, err := compileSearchQuery(query, time.Now())
+	if err != nil {
+		return err
+	}
+	a.vpkCache.Range(func(key, value interface{}) bool {
+		cache := value.(*VPKFileCache)
+		if matches(&cache.File) {
+			fn(key.(string), cache)
+		}
+		return true
+	})
+	return nil
+}
+
+// matchSearchQuery 返回满足搜索表达式的文件
+func (a *App) matchSearchQuery(query string) ([]VPKFile, error) {
+	result := make([]VPKFile, 0)
+	err := a.rangeSearchQuery(query, func(path string, cache *VPKFileCache) {
+		result = append(result, a.previewFile(path, cache))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// evaluateSmartCollections 重新计算所有合集的文件数量，只计数不构造文件列表
+func (a *App) evaluateSmartCollections() []SmartCollectionInfo {
+	a.mu.RLock()
+	collections := cloneSmartCollections(a.smartCollections)
+	a.mu.RUnlock()
+
+	infos := make([]SmartCollectionInfo, 0, len(collections))
+	for _, collection := range collections {
+		info := SmartCollectionInfo{Name: collection.Name, Query: collection.Query}
+		count := 0
+		if err := a.rangeSearchQuery(collection.Query, func(string, *VPKFileCache) { count++ }); err != nil {
+			info.Error = err.Error()
+		} else {
+			info.Count = count
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// refreshSmartCollections 在扫描或文件变化后重新计算合集并通知前端
+func (a *App) refreshSmartCollections() {
+	infos := a.evaluateSmartCollections()
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "smart_collections_updated", infos)
+	}
+}
+
+// GetSmartCollections 获取所有智能合集及其文件数量
+func (a *App) GetSmartCollections() []SmartCollectionInfo {
+	return a.evaluateSmartCollections()
+}
+
+// GetSmartCollectionFiles 获取智能合集当前包含的文件
+func (a *App) GetSmartCollectionFiles(name string) ([]VPKFile, error) {
+	a.mu.RLock()
+	var query string
+	found := false
+	for _, collection := range a.smartCollections {
+		if collection.Name == name {
+			query, found = collection.Query, true
+			break
+		}
+	}
+	a.mu.RUnlock()
+	if !found {
+		return nil, fmt.Errorf("合集不存在: %s", name)
+	}
+	return a.matchSearchQuery(query)
+}
+
+// SaveSmartCollection 新建或更新智能合集，保存前校验表达式
+func (a *App) SaveSmartCollection(name string, query string) error {
+	name = strings.TrimSpace(name)
+	query = strings.TrimSpace(query)
+	if name == "" {
+		return fmt.Errorf("合集名称不能为空")
+	}
+	if query == "" {
+		return fmt.Errorf("合集的搜索条件不能为空")
+	}
+	if _, err := compileSearchQuery(query, time.Now()); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	updated := false
+	for i := range a.smartCollections {
+		if a.smartCollections[i].Name == name {
+			a.smartCollections[i].Query = query
+			updated = true
+			break
+		}
+	}
+	if !updated {
+		a.smartCollections = append(a.smartCollections, SmartCollection{Name: name, Query: query})
+	}
+	a.mu.Unlock()
+
+	a.saveConfig()
+	a.refreshSmartCollections()
+	return nil
+}
+
+// DeleteSmartCollection 删除智能合集
+func (a *App) DeleteSmartCollection(name string) error {
+	a.mu.Lock()
+	index := -1
+	for i, collection := range a.smartCollections {
+		if collection.Name == name {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		a.mu.Unlock()
+		return fmt.Errorf("合集不存在: %s", name)
+	}
+	a.smartCollections = append(a.smartCollections[:index:index], a.smartCollections[index+1:]...)
+	a.mu.Unlock()
+
+	a.saveConfig()
+	a.refreshSmartCollections()
+	return nil
+}
@@ -17,9 +17,11 @@ type SprayFilePayload struct {
 }
 
 type SprayImportFilePayload struct {
-	Name   string `json:"name"`
-	Type   string `json:"type"`
-	Base64 string `json:"base64"`
+	Name        string        `json:"name"`
+	Type        string        `json:"type"`
+	Base64      string        `json:"base64"`
+	VTF         *SprayVTFInfo `json:"vtf,omitempty"`         // PNG/JPEG/GIF 自动编码的 VTF
+	EncodeError string        `json:"encodeError,omitempty"` // 自动编码失败的原因
 }
 
 type SprayExportRequest struct {
@@ -72,6 +74,9 @@ func (a *App) LoadSprayImportFiles(paths []string) ([]SprayImportFilePayload, er
 		}
 
 		ext := strings.ToLower(filepath.Ext(targetPath))
+		if isSprayVideoExt(ext) {
+			return nil, fmt.Errorf("暂不支持视频素材 %s，请先导出为 GIF 或帧序列图片", filepath.Base(targetPath))
+		}
 		if !isSupportedSprayImportExt(ext) {
 			return nil, fmt.Errorf("不支持的喷漆素材格式: %s", filepath.Base(targetPath))
 		}
@@ -91,11 +96,19 @@ func (a *App) LoadSprayImportFiles(paths []string) ([]SprayImportFilePayload, er
 			return nil, fmt.Errorf("素材为空: %s", filepath.Base(targetPath))
 		}
 
-		files = append(files, SprayImportFilePayload{
+		file := SprayImportFilePayload{
 			Name:   filepath.Base(targetPath),
 			Type:   sprayImportMimeType(ext),
 			Base64: base64.StdEncoding.EncodeToString(data),
-		})
+		}
+		if isNativeSprayEncodeExt(ext) {
+			if vtf, err := encodeSprayVTF(data, defaultSprayEncodeOptions); err != nil {
+				file.EncodeError = err.Error()
+			} else {
+				file.VTF = vtf
+			}
+		}
+		files = append(files, file)
 	}
 
 	if len(files) == 0 {
@@ -384,6 +397,26 @@ func isSupportedSprayImportExt(ext string) bool {
 	}
 }
 
+// isSprayVideoExt 常见视频格式，导入时给出转换提示
+func isSprayVideoExt(ext string) bool {
+	switch strings.ToLower(ext) {
+	case ".mp4", ".webm", ".avi", ".mov", ".mkv":
+		return true
+	default:
+		return false
+	}
+}
+
+// isNativeSprayEncodeExt 可由后端直接编码为 VTF 的素材格式，其余格式仍由前端转换
+func isNativeSprayEncodeExt(ext string) bool {
+	switch strings.ToLower(ext) {
+	case ".png", ".jpg", ".jpeg", ".gif":
+		return true
+	default:
+		return false
+	}
+}
+
 func sprayImportMimeType(ext string) string {
 	switch strings.ToLower(ext) {
 	case ".png":
@@ -0,0 +1,489 @@
+package app
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"strings"
+
+	"vpk-manager/internal/parser"
+)
+
+const (
+	// sprayMaxVTFSize L4D2 允许上传的喷漆文件大小上限
+	sprayMaxVTFSize = 512 * 1024
+	// sprayMaxDimension 喷漆最长边上限
+	sprayMaxDimension = 1024
+	// sprayMinDimension 自动降低分辨率时的下限
+	sprayMinDimension = 16
+	// sprayMinAnimatedDimension 动画喷漆降到该分辨率后改为丢帧
+	sprayMinAnimatedDimension = 128
+	// sprayMaxSourceFrames 动画素材最多读取的帧数，超出时均匀抽取
+	sprayMaxSourceFrames = 256
+	// sprayMaxFadeLevel 渐变喷漆远处图片最晚开始的 mip 级别
+	sprayMaxFadeLevel = 3
+)
+
+// 喷漆贴图标志：边缘不重复、不受贴图质量设置影响
+const sprayVTFFlags = parser.VTFFlagClampS | parser.VTFFlagClampT | parser.VTFFlagNoLOD
+
+type SprayEncodeOptions struct {
+	Format  string `json:"format"`  // "auto"、"DXT1"、"DXT5"、"BGRA8888"，为空时自动选择
+	MaxSize int    `json:"maxSize"` // 最长边上限，0 为 1024
+	Mipmaps bool   `json:"mipmaps"`
+}
+
+type SprayEncodeRequest struct {
+	Name    string             `json:"name"`
+	Base64  string             `json:"base64"`
+	Options SprayEncodeOptions `json:"options"`
+}
+
+type SprayVTFInfo struct {
+	VTFBase64 string `json:"vtfBase64"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Format    string `json:"format"`
+	MipCount  int    `json:"mipCount"`
+	Size      int    `json:"size"`
+
+	Frames       int `json:"frames"`
+	SourceFrames int `json:"sourceFrames"` // 素材帧数，大于 Frames 时表示为满足大小限制丢弃了部分帧
+}
+
+// defaultSprayEncodeOptions 导入素材时使用的自动编码选项
+var defaultSprayEncodeOptions = SprayEncodeOptions{Format: "auto", Mipmaps: true}
+
+// EncodeSprayImage 按选项将 PNG/JPEG/GIF 素材重新编码为喷漆 VTF
+func (a *App) EncodeSprayImage(request SprayEncodeRequest) (*SprayVTFInfo, error) {
+	data, err := base64.StdEncoding.DecodeString(request.Base64)
+	if err != nil {
+		return nil, fmt.Errorf("解码素材 %s 失败: %v", request.Name, err)
+	}
+	return encodeSprayVTF(data, request.Options)
+}
+
+type SprayAnimationRequest struct {
+	Name    string                   `json:"name"`
+	Files   []SprayImportFilePayload `json:"files"` // 单个 GIF 取其全部帧，多个文件按顺序各作为一帧
+	Options SprayEncodeOptions       `json:"options"`
+}
+
+type SprayFadeRequest struct {
+	Name      string                 `json:"name"`
+	Near      SprayImportFilePayload `json:"near"`
+	Far       SprayImportFilePayload `json:"far"`
+	FadeLevel int                    `json:"fadeLevel"` // 远处图片开始显示的 mip 级别，1-3，0 为 1
+	Options   SprayEncodeOptions     `json:"options"`
+}
+
+// EncodeAnimatedSpray 将动画 GIF 或帧序列编码为多帧喷漆 VTF，超出大小限制时降低分辨率或丢帧
+func (a *App) EncodeAnimatedSpray(request SprayAnimationRequest) (*SprayVTFInfo, error) {
+	if len(request.Files) == 0 {
+		return nil, fmt.Errorf("没有可编码的喷漆素材")
+	}
+
+	var frames []image.Image
+	for _, file := range request.Files {
+		data, err := base64.StdEncoding.DecodeString(file.Base64)
+		if err != nil {
+			return nil, fmt.Errorf("解码素材 %s 失败: %v", file.Name, err)
+		}
+		// 多个文件各取第一帧作为帧序列
+		limit := sprayMaxSourceFrames
+		if len(request.Files) > 1 {
+			limit = 1
+		}
+		decoded, err := decodeSprayFrames(data, limit)
+		if err != nil {
+			return nil, fmt.Errorf("读取素材 %s 失败: %v", file.Name, err)
+		}
+		frames = append(frames, decoded...)
+	}
+	if len(frames) > sprayMaxSourceFrames {
+		frames = sampleSprayImages(frames, sprayMaxSourceFrames)
+	}
+	return encodeSpray(spraySource{frames: frames}, request.Options)
+}
+
+// EncodeFadingSpray 生成随距离切换图片的渐变喷漆：近处显示 Near，从 FadeLevel 级 mip 起显示 Far
+func (a *App) EncodeFadingSpray(request SprayFadeRequest) (*SprayVTFInfo, error) {
+	fadeLevel := request.FadeLevel
+	if fadeLevel == 0 {
+		fadeLevel = 1
+	}
+	if fadeLevel < 1 || fadeLevel > sprayMaxFadeLevel {
+		return nil, fmt.Errorf("渐变级别需在 1 到 %d 之间: %d", sprayMaxFadeLevel, request.FadeLevel)
+	}
+
+	images := make([]image.Image, 0, 2)
+	for _, file := range []SprayImportFilePayload{request.Near, request.Far} {
+		data, err := base64.StdEncoding.DecodeString(file.Base64)
+		if err != nil {
+			return nil, fmt.Errorf("解码素材 %s 失败: %v", file.Name, err)
+		}
+		decoded, err := decodeSprayFrames(data, 1)
+		if err != nil {
+			return nil, fmt.Errorf("读取素材 %s 失败: %v", file.Name, err)
+		}
+		images = append(images, decoded[0])
+	}
+	return encodeSpray(spraySource{frames: images[:1], far: images[1], fadeLevel: fadeLevel}, request.Options)
+}
+
+// spraySource 待编码的喷漆：一帧或多帧动画；渐变喷漆另有从 fadeLevel 级 mip 开始显示的远处图片
+type spraySource struct {
+	frames    []image.Image
+	far       image.Image
+	fadeLevel int
+}
+
+// encodeSprayVTF 将图片编码为符合 L4D2 限制的喷漆 VTF，多帧 GIF 编码为动画喷漆
+func encodeSprayVTF(data []byte, options SprayEncodeOptions) (*SprayVTFInfo, error) {
+	frames, err := decodeSprayFrames(data, sprayMaxSourceFrames)
+	if err != nil {
+		return nil, err
+	}
+	return encodeSpray(spraySource{frames: frames}, options)
+}
+
+// encodeSpray 分辨率取不超过原图 4/3 倍的最大 2 的幂，超出大小限制时先去掉 mipmap，再逐级降低分辨率；
+// 动画降到 sprayMinAnimatedDimension 后仍放不下时均匀丢帧
+func encodeSpray(source spraySource, options SprayEncodeOptions) (*SprayVTFInfo, error) {
+	if len(source.frames) == 0 {
+		return nil, fmt.Errorf("没有可编码的图像帧")
+	}
+	format, err := sprayFormatOption(options.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSize := sprayMaxDimension
+	if options.MaxSize > 0 {
+		maxSize = min(options.MaxSize, sprayMaxDimension)
+	}
+	bounds := source.frames[0].Bounds()
+	size := sprayTargetDimension(max(bounds.Dx(), bounds.Dy()), maxSize)
+
+	// 渐变喷漆的远处图片存放在 mip 中，必须保留 mipmap
+	mipChoices := []bool{options.Mipmaps, false}
+	if source.far != nil {
+		mipChoices = []bool{true}
+	}
+
+	// 原图只缩放一次到最大尺寸，之后每次降级都从这份画布缩小，避免反复处理大图
+	canvases := make([]*image.NRGBA, len(source.frames))
+	for i, frame := range source.frames {
+		canvases[i] = fitSprayCanvas(frame, size)
+	}
+	var far *image.NRGBA
+	if source.far != nil {
+		far = fitSprayCanvas(source.far, size>>source.fadeLevel)
+	}
+
+	for ; size >= sprayMinDimension; size /= 2 {
+		if canvases[0].Bounds().Dx() != size {
+			for i, canvas := range canvases {
+				canvases[i] = resampleNRGBA(canvas, size, size)
+			}
+			if far != nil {
+				far = resampleNRGBA(far, size>>source.fadeLevel, size>>source.fadeLevel)
+			}
+		}
+		frames := make([]image.Image, len(canvases))
+		for i, canvas := range canvases {
+			frames[i] = canvas
+		}
+
+		chosen := format
+		if chosen == parser.VTFFormatNone {
+			chosen = autoSprayFormat(canvases, far)
+		}
+		for _, mipmaps := range mipChoices {
+			if parser.VTFFileSize(size, size, len(frames), chosen, mipmaps) <= sprayMaxVTFSize {
+				return buildSprayVTF(frames, far, source.fadeLevel, chosen, mipmaps, len(frames))
+			}
+		}
+		// 丢帧时不带 mipmap，尽量保留更多帧
+		if len(frames) > 1 && size <= sprayMinAnimatedDimension {
+			mipmaps := mipChoices[len(mipChoices)-1]
+			if count := sprayFrameBudget(size, chosen, mipmaps); count >= 2 {
+				return buildSprayVTF(sampleSprayImages(frames, count), far, source.fadeLevel, chosen, mipmaps, len(frames))
+			}
+		}
+	}
+	return nil, fmt.Errorf("无法在 %d KB 限制内编码为 %s 格式", sprayMaxVTFSize/1024, format)
+}
+
+// sprayFrameBudget 返回该尺寸下大小限制内最多能写入的帧数
+func sprayFrameBudget(size int, format parser.VTFFormat, mipmaps bool) int {
+	single := parser.VTFFileSize(size, size, 1, format, mipmaps)
+	perFrame := parser.VTFFileSize(size, size, 2, format, mipmaps) - single
+	return int((sprayMaxVTFSize - (single - perFrame)) / perFrame)
+}
+
+func buildSprayVTF(frames []image.Image, far *image.NRGBA, fadeLevel int, format parser.VTFFormat, mipmaps bool, sourceFrames int) (*SprayVTFInfo, error) {
+	transparent := far != nil && hasTransparency(far)
+	for _, frame := range frames {
+		transparent = transparent || hasTransparency(frame.(*image.NRGBA))
+	}
+
+	flags := sprayVTFFlags
+	switch {
+	case format == parser.VTFFormatDXT1 && transparent:
+		flags |= parser.VTFFlagOneBitAlpha
+	case format == parser.VTFFormatDXT5 || format == parser.VTFFormatBGRA8888:
+		flags |= parser.VTFFlagEightBitAlpha
+	}
+	if mipmaps {
+		flags |= parser.VTFFlagTrilinear
+	}
+
+	encodeOptions := parser.VTFEncodeOptions{Format: format, Mipmaps: mipmaps, Flags: flags}
+	if far != nil {
+		encodeOptions.MipImages = make([]image.Image, fadeLevel+1)
+		encodeOptions.MipImages[fadeLevel] = far
+	}
+	vtfData, err := parser.EncodeVTF(frames, encodeOptions)
+	if err != nil {
+		return nil, err
+	}
+	header, err := parser.ParseVTFHeader(vtfData)
+	if err != nil {
+		return nil, err
+	}
+	return &SprayVTFInfo{
+		VTFBase64:    base64.StdEncoding.EncodeToString(vtfData),
+		Width:        header.Width,
+		Height:       header.Height,
+		Format:       format.String(),
+		MipCount:     header.MipCount,
+		Frames:       header.Frames,
+		SourceFrames: sourceFrames,
+		Size:         len(vtfData),
+	}, nil
+}
+
+// sprayFormatOption 解析格式选项，自动选择时返回 VTFFormatNone
+func sprayFormatOption(value string) (parser.VTFFormat, error) {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "", "AUTO":
+		return parser.VTFFormatNone, nil
+	case "DXT1":
+		return parser.VTFFormatDXT1, nil
+	case "DXT5":
+		return parser.VTFFormatDXT5, nil
+	case "BGRA8888":
+		return parser.VTFFormatBGRA8888, nil
+	default:
+		return parser.VTFFormatNone, fmt.Errorf("不支持的喷漆格式: %s", value)
+	}
+}
+
+// autoSprayFormat 所有帧都能放下无损格式时使用 BGRA8888，否则不透明或只有全透明像素时用 DXT1，半透明用 DXT5
+func autoSprayFormat(canvases []*image.NRGBA, far *image.NRGBA) parser.VTFFormat {
+	size := canvases[0].Rect.Dx()
+	if parser.VTFFileSize(size, size, len(canvases), parser.VTFFormatBGRA8888, far != nil) <= sprayMaxVTFSize {
+		return parser.VTFFormatBGRA8888
+	}
+	images := canvases
+	if far != nil {
+		images = append(images[:len(images):len(images)], far)
+	}
+	for _, canvas := range images {
+		for i := 3; i < len(canvas.Pix); i += 4 {
+			if a := canvas.Pix[i]; a != 0 && a != 0xff {
+				return parser.VTFFormatDXT5
+			}
+		}
+	}
+	return parser.VTFFormatDXT1
+}
+
+func hasTransparency(img *image.NRGBA) bool {
+	for i := 3; i < len(img.Pix); i += 4 {
+		if img.Pix[i] < 0xff {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeSprayFrames 解码素材图片，多帧 GIF 按处置方式合成每一帧，帧数超过 limit 时均匀抽取
+func decodeSprayFrames(data []byte, limit int) ([]image.Image, error) {
+	if bytes.HasPrefix(data, []byte("GIF8")) {
+		animation, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("无法解析 GIF: %v", err)
+		}
+		if len(animation.Image) > 1 {
+			return composeGIFFrames(animation, limit), nil
+		}
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("无法解析图片: %v", err)
+	}
+	return []image.Image{img}, nil
+}
+
+// composeGIFFrames 在同一画布上按处置方式依次叠加 GIF 的局部帧，只复制出均匀抽取的最多 limit 帧
+func composeGIFFrames(animation *gif.GIF, limit int) []image.Image {
+	bounds := image.Rect(0, 0, animation.Config.Width, animation.Config.Height)
+	if bounds.Empty() {
+		bounds = animation.Image[0].Bounds()
+	}
+	count := len(animation.Image)
+	wanted := min(count, limit)
+	canvas := image.NewNRGBA(bounds)
+	var previous *image.NRGBA
+	frames := make([]image.Image, 0, wanted)
+	for i, frame := range animation.Image {
+		disposal := byte(0)
+		if i < len(animation.Disposal) {
+			disposal = animation.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			if previous == nil {
+				previous = image.NewNRGBA(bounds)
+			}
+			copy(previous.Pix, canvas.Pix)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		// 与 sampleSprayImages 相同的抽取方式
+		if len(frames) < wanted && i == len(frames)*count/wanted {
+			composed := image.NewNRGBA(bounds)
+			copy(composed.Pix, canvas.Pix)
+			frames = append(frames, composed)
+		}
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			copy(canvas.Pix, previous.Pix)
+		}
+	}
+	return frames
+}
+
+// sampleSprayImages 帧数超过 limit 时均匀抽取 limit 帧
+func sampleSprayImages(frames []image.Image, limit int) []image.Image {
+	if len(frames) <= limit {
+		return frames
+	}
+	sampled := make([]image.Image, limit)
+	for i := range sampled {
+		sampled[i] = frames[i*len(frames)/limit]
+	}
+	return sampled
+}
+
+// sprayTargetDimension 返回不超过 side*4/3 和 maxSize 的最大 2 的幂
+func sprayTargetDimension(side int, maxSize int) int {
+	limit := min(max(side*4/3, sprayMinDimension), maxSize)
+	size := sprayMinDimension
+	for size*2 <= limit {
+		size *= 2
+	}
+	return size
+}
+
+// fitSprayCanvas 将图片等比缩放后居中放入正方形画布，空白部分透明
+func fitSprayCanvas(src image.Image, size int) *image.NRGBA {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	dw, dh := size, size
+	if w >= h {
+		dh = max(h*size/w, 1)
+	} else {
+		dw = max(w*size/h, 1)
+	}
+	scaled := resampleNRGBA(src, dw, dh)
+
+	canvas := image.NewNRGBA(image.Rect(0, 0, size, size))
+	offsetX, offsetY := (size-dw)/2, (size-dh)/2
+	for y := 0; y < dh; y++ {
+		copy(canvas.Pix[canvas.PixOffset(offsetX, offsetY+y):], scaled.Pix[scaled.PixOffset(0, y):scaled.PixOffset(dw, y)])
+	}
+	return canvas
+}
+
+// resampleNRGBA 按覆盖面积加权缩放图片，颜色按 alpha 预乘后平均，避免透明边缘发黑
+func resampleNRGBA(src image.Image, dw, dh int) *image.NRGBA {
+	bounds := src.Bounds()
+	sw, sh := bounds.Dx(), bounds.Dy()
+
+	// 预乘后的源像素，float32 足够表示 16 位颜色的加权平均
+	pixels := make([]float32, sw*sh*4)
+	for y := 0; y < sh; y++ {
+		for x := 0; x < sw; x++ {
+			r, g, b, a := src.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			i := (y*sw + x) * 4
+			pixels[i], pixels[i+1], pixels[i+2], pixels[i+3] = float32(r), float32(g), float32(b), float32(a)
+		}
+	}
+
+	horizontal := resampleAxis(pixels, sw, sh, dw, true)
+	vertical := resampleAxis(horizontal, dw, sh, dh, false)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for i := 0; i < len(vertical); i += 4 {
+		a := float64(vertical[i+3])
+		if a <= 0 {
+			continue
+		}
+		dst.Pix[i] = uint8(min(float64(vertical[i])/a*255+0.5, 255))
+		dst.Pix[i+1] = uint8(min(float64(vertical[i+1])/a*255+0.5, 255))
+		dst.Pix[i+2] = uint8(min(float64(vertical[i+2])/a*255+0.5, 255))
+		dst.Pix[i+3] = uint8(min(a/0xffff*255+0.5, 255))
+	}
+	return dst
+}
+
+// resampleAxis 沿一个方向做面积加权缩放，horizontal 为 true 时缩放宽度
+func resampleAxis(pixels []float32, w, h, target int, horizontal bool) []float32 {
+	srcLen, lines := w, h
+	outW, outH := target, h
+	if !horizontal {
+		srcLen, lines = h, w
+		outW, outH = w, target
+	}
+	out := make([]float32, outW*outH*4)
+	scale := float64(srcLen) / float64(target)
+
+	for line := 0; line < lines; line++ {
+		for d := 0; d < target; d++ {
+			start, end := float64(d)*scale, float64(d+1)*scale
+			var sum [4]float64
+			var total float64
+			for s := int(start); s < srcLen && float64(s) < end; s++ {
+				weight := min(end, float64(s+1)) - max(start, float64(s))
+				if weight <= 0 {
+					continue
+				}
+				si := (line*w + s) * 4
+				if !horizontal {
+					si = (s*w + line) * 4
+				}
+				for c := 0; c < 4; c++ {
+					sum[c] += float64(pixels[si+c]) * weight
+				}
+				total += weight
+			}
+			di := (line*outW + d) * 4
+			if !horizontal {
+				di = (d*outW + line) * 4
+			}
+			for c := 0; c < 4; c++ {
+				out[di+c] = float32(sum[c] / total)
+			}
+		}
+	}
+	return out
+}
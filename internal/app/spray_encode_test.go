@@ -0,0 +1,245 @@
+package app
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"vpk-manager/internal/parser"
+)
+
+func testSprayPNG(t *testing.T, width, height int, alpha uint8) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x), uint8(y), 200, alpha})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func decodeSprayVTF(t *testing.T, info *SprayVTFInfo) *parser.VTFHeader {
+	t.Helper()
+	data, err := base64.StdEncoding.DecodeString(info.VTFBase64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != info.Size || len(data) > sprayMaxVTFSize {
+		t.Fatalf("unexpected vtf size %d (reported %d)", len(data), info.Size)
+	}
+	header, err := parser.ParseVTFHeader(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return header
+}
+
+func TestEncodeSprayVTFFitsSizeLimit(t *testing.T) {
+	// 宽图按比例放入正方形，DXT1 的 1024 超出限制，降到 512
+	info, err := encodeSprayVTF(testSprayPNG(t, 2000, 1000, 255), defaultSprayEncodeOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := decodeSprayVTF(t, info)
+	if info.Width != 512 || info.Height != 512 || info.Format != "DXT1" || header.MipCount != 10 {
+		t.Fatalf("unexpected spray %+v", info)
+	}
+	if header.Flags&parser.VTFFlagOneBitAlpha == 0 || header.Flags&parser.VTFFlagClampS == 0 || header.Flags&parser.VTFFlagNoLOD == 0 {
+		t.Fatalf("unexpected flags %#x", header.Flags)
+	}
+
+	// 小图放得下时使用无损格式
+	info, err = encodeSprayVTF(testSprayPNG(t, 200, 200, 128), defaultSprayEncodeOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header = decodeSprayVTF(t, info)
+	if info.Width != 256 || info.Format != "BGRA8888" || header.Flags&parser.VTFFlagEightBitAlpha == 0 {
+		t.Fatalf("unexpected spray %+v", info)
+	}
+
+	// 指定无损格式时降低分辨率并去掉 mipmap
+	info, err = encodeSprayVTF(testSprayPNG(t, 600, 600, 128), SprayEncodeOptions{Format: "BGRA8888", Mipmaps: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	header = decodeSprayVTF(t, info)
+	if info.Width != 256 || info.Format != "BGRA8888" || header.MipCount != 9 {
+		t.Fatalf("unexpected spray %+v (mips %d)", info, header.MipCount)
+	}
+
+	info, err = encodeSprayVTF(testSprayPNG(t, 600, 600, 128), SprayEncodeOptions{Format: "dxt5", MaxSize: 128})
+	if err != nil {
+		t.Fatal(err)
+	}
+	header = decodeSprayVTF(t, info)
+	if info.Width != 128 || info.Format != "DXT5" || header.MipCount != 1 || header.Flags&parser.VTFFlagNoMip == 0 {
+		t.Fatalf("unexpected spray %+v", info)
+	}
+
+	if _, err := encodeSprayVTF(testSprayPNG(t, 8, 8, 255), SprayEncodeOptions{Format: "RGB565"}); err == nil {
+		t.Fatal("expected error for unsupported format")
+	}
+	if _, err := encodeSprayVTF([]byte("not an image"), defaultSprayEncodeOptions); err == nil {
+		t.Fatal("expected error for invalid image")
+	}
+}
+
+func TestLoadSprayImportFilesEncodesVTF(t *testing.T) {
+	dir := t.TempDir()
+	pngPath := filepath.Join(dir, "logo.png")
+	bmpPath := filepath.Join(dir, "logo.bmp")
+	if err := os.WriteFile(pngPath, testSprayPNG(t, 64, 64, 255), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(bmpPath, []byte("BM"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := (&App{}).LoadSprayImportFiles([]string{pngPath, bmpPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files[0].VTF == nil || files[0].VTF.Width != 64 || files[0].EncodeError != "" {
+		t.Fatalf("expected encoded png, got %+v", files[0])
+	}
+	// 其他格式仍交给前端转换
+	if files[1].VTF != nil || files[1].EncodeError != "" {
+		t.Fatalf("expected bmp to be left for the frontend, got %+v", files[1])
+	}
+}
+
+func testSprayGIF(t *testing.T) []byte {
+	t.Helper()
+	palette := color.Palette{color.Transparent, color.NRGBA{255, 0, 0, 255}, color.NRGBA{0, 0, 255, 255}}
+	full := image.NewPaletted(image.Rect(0, 0, 64, 64), palette)
+	for i := range full.Pix {
+		full.Pix[i] = 1
+	}
+	// 第二帧只覆盖左上角，其余部分沿用上一帧
+	partial := image.NewPaletted(image.Rect(0, 0, 32, 32), palette)
+	for i := range partial.Pix {
+		partial.Pix[i] = 2
+	}
+	cleared := image.NewPaletted(image.Rect(32, 32, 64, 64), palette)
+
+	var buf bytes.Buffer
+	err := gif.EncodeAll(&buf, &gif.GIF{
+		Image:    []*image.Paletted{full, partial, cleared},
+		Delay:    []int{10, 10, 10},
+		Disposal: []byte{gif.DisposalNone, gif.DisposalBackground, gif.DisposalNone},
+		Config:   image.Config{ColorModel: palette, Width: 64, Height: 64},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestEncodeAnimatedSpray(t *testing.T) {
+	data := testSprayGIF(t)
+	frames, err := decodeSprayFrames(data, sprayMaxSourceFrames)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 3 {
+		t.Fatalf("expected 3 frames, got %d", len(frames))
+	}
+	red, blue := color.NRGBA{255, 0, 0, 255}, color.NRGBA{0, 0, 255, 255}
+	at := func(img image.Image, x, y int) color.NRGBA {
+		return color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+	}
+	if at(frames[1], 0, 0) != blue || at(frames[1], 48, 48) != red {
+		t.Fatalf("unexpected second frame: %v %v", at(frames[1], 0, 0), at(frames[1], 48, 48))
+	}
+	// 超出帧数上限时只保留均匀抽取的帧，合成结果与完整序列一致
+	sampled, err := decodeSprayFrames(data, 2)
+	if err != nil || len(sampled) != 2 || !bytes.Equal(sampled[1].(*image.NRGBA).Pix, frames[1].(*image.NRGBA).Pix) {
+		t.Fatalf("unexpected sampled frames: %d, %v", len(sampled), err)
+	}
+	// 第二帧处置为背景，第三帧的左上角恢复透明；第三帧的透明像素不覆盖已有画面
+	if at(frames[2], 0, 0).A != 0 || at(frames[2], 48, 48) != red || at(frames[2], 40, 0) != red {
+		t.Fatalf("unexpected third frame: %v %v %v", at(frames[2], 0, 0), at(frames[2], 48, 48), at(frames[2], 40, 0))
+	}
+
+	app := &App{}
+	info, err := app.EncodeAnimatedSpray(SprayAnimationRequest{
+		Files:   []SprayImportFilePayload{{Name: "anim.gif", Base64: base64.StdEncoding.EncodeToString(data)}},
+		Options: defaultSprayEncodeOptions,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := decodeSprayVTF(t, info)
+	if info.Frames != 3 || header.Frames != 3 || info.SourceFrames != 3 || info.Width != 64 {
+		t.Fatalf("unexpected animated spray %+v", info)
+	}
+
+	// 帧序列放不下时降低分辨率并均匀丢帧
+	files := make([]SprayImportFilePayload, 100)
+	for i := range files {
+		files[i] = SprayImportFilePayload{Name: "frame.png", Base64: base64.StdEncoding.EncodeToString(testSprayPNG(t, 200, 200, 255))}
+	}
+	info, err = app.EncodeAnimatedSpray(SprayAnimationRequest{Files: files, Options: defaultSprayEncodeOptions})
+	if err != nil {
+		t.Fatal(err)
+	}
+	header = decodeSprayVTF(t, info)
+	if info.Width != 128 || info.Format != "DXT1" || info.SourceFrames != 100 || info.Frames < 2 || info.Frames >= 100 || header.MipCount != 1 {
+		t.Fatalf("unexpected dropped-frame spray %+v", info)
+	}
+}
+
+func TestEncodeFadingSpray(t *testing.T) {
+	solid := func(c color.NRGBA) string {
+		img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+		for i := 0; i < len(img.Pix); i += 4 {
+			img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			t.Fatal(err)
+		}
+		return base64.StdEncoding.EncodeToString(buf.Bytes())
+	}
+	red, blue := color.NRGBA{255, 0, 0, 255}, color.NRGBA{0, 0, 255, 255}
+
+	info, err := (&App{}).EncodeFadingSpray(SprayFadeRequest{
+		Near:      SprayImportFilePayload{Name: "near.png", Base64: solid(red)},
+		Far:       SprayImportFilePayload{Name: "far.png", Base64: solid(blue)},
+		FadeLevel: 2,
+		Options:   SprayEncodeOptions{Format: "BGRA8888"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := decodeSprayVTF(t, info)
+	if header.MipCount != 7 || header.Flags&parser.VTFFlagNoMip != 0 {
+		t.Fatalf("fading spray must keep mipmaps: %+v", header)
+	}
+	data, _ := base64.StdEncoding.DecodeString(info.VTFBase64)
+	for level, want := range []color.NRGBA{red, red, blue, blue} {
+		img, err := parser.DecodeVTF(data, level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c := color.NRGBAModel.Convert(img.At(0, 0)).(color.NRGBA); c != want {
+			t.Errorf("mip %d: expected %v, got %v", level, want, c)
+		}
+	}
+
+	if _, err := (&App{}).EncodeFadingSpray(SprayFadeRequest{FadeLevel: 4}); err == nil {
+		t.Fatal("expected error for invalid fade level")
+	}
+}
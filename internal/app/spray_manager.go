@@ -0,0 +1,442 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hymkor/trash-go"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"l4d2-manager-next/pkg/valve/vpk"
+	"vpk-manager/internal/parser"
+)
+
+// sprayLogosDir 游戏读取喷漆的目录
+const sprayLogosDir = "materials/vgui/logos"
+
+// sprayConfigPattern 匹配 config.cfg 中的 cl_logofile 设置
+var sprayConfigPattern = regexp.MustCompile(`(?m)^[ \t]*cl_logofile[ \t]+(?:"([^"\r\n]*)"|(\S+))[^\r\n]*`)
+
+// sprayConfigLinePattern 匹配 cl_logofile 整行及其换行符，用于删除该设置
+var sprayConfigLinePattern = regexp.MustCompile(sprayConfigPattern.String() + `(?:\r?\n)?`)
+
+type SprayInventoryItem struct {
+	Name       string `json:"name"`
+	Entry      string `json:"entry"`   // 游戏内路径，如 materials/vgui/logos/custom/foo.vtf
+	Source     string `json:"source"`  // "loose" 游戏目录中的文件，"vpk" 已安装的 VPK
+	VTFPath    string `json:"vtfPath"` // 散装喷漆的 VTF 完整路径
+	VMTPath    string `json:"vmtPath"` // 散装喷漆的 VMT 完整路径，不存在时为空
+	VPKPath    string `json:"vpkPath"` // 喷漆所在 VPK
+	SprayOnly  bool   `json:"sprayOnly"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	Frames     int    `json:"frames"`
+	Format     string `json:"format"`
+	Size       int64  `json:"size"`
+	PreviewURL string `json:"previewUrl"`
+	Active     bool   `json:"active"`
+	Error      string `json:"error,omitempty"`
+}
+
+type SprayInventory struct {
+	GameDir     string               `json:"gameDir"`
+	ActiveSpray string               `json:"activeSpray"` // config.cfg 中的 cl_logofile，未设置时为空
+	Sprays      []SprayInventoryItem `json:"sprays"`
+}
+
+// ListSprays 列出游戏目录 materials/vgui/logos 下的喷漆和已安装 VPK 中的喷漆
+func (a *App) ListSprays() (SprayInventory, error) {
+	inventory := SprayInventory{Sprays: []SprayInventoryItem{}}
+	gameDir, err := a.sprayGameDir()
+	if err != nil {
+		return inventory, err
+	}
+	inventory.GameDir = gameDir
+	inventory.ActiveSpray, _ = readActiveSpray(gameDir)
+
+	inventory.Sprays = append(inventory.Sprays, a.listLooseSprays(gameDir)...)
+	inventory.Sprays = append(inventory.Sprays, a.listVPKSprays()...)
+	for i := range inventory.Sprays {
+		inventory.Sprays[i].Active = inventory.ActiveSpray != "" && strings.EqualFold(inventory.Sprays[i].Entry, inventory.ActiveSpray)
+	}
+	return inventory, nil
+}
+
+// SetActiveSpray 将 cfg/config.cfg 中的 cl_logofile 设置为指定喷漆。游戏运行时退出会覆盖 config.cfg，需要在游戏关闭时设置
+func (a *App) SetActiveSpray(entry string) error {
+	gameDir, err := a.sprayGameDir()
+	if err != nil {
+		return err
+	}
+	cleanEntry, err := cleanSprayEntry(entry)
+	if err != nil {
+		return err
+	}
+	if err := writeActiveSpray(gameDir, cleanEntry); err != nil {
+		return err
+	}
+	log.Printf("已设置当前喷漆: %s", cleanEntry)
+	return nil
+}
+
+// RemoveSpray 删除散装喷漆的 VTF/VMT，或删除只包含喷漆的 VPK，文件移至回收站
+func (a *App) RemoveSpray(item SprayInventoryItem) error {
+	gameDir, err := a.sprayGameDir()
+	if err != nil {
+		return err
+	}
+
+	switch item.Source {
+	case "loose":
+		if !isLooseSprayPath(gameDir, item.VTFPath) {
+			return fmt.Errorf("不是游戏喷漆目录中的 VTF 文件: %s", item.VTFPath)
+		}
+		vmtPath := strings.TrimSuffix(item.VTFPath, filepath.Ext(item.VTFPath)) + ".vmt"
+		for _, filePath := range []string{item.VTFPath, vmtPath} {
+			if _, err := os.Stat(filePath); os.IsNotExist(err) {
+				continue
+			}
+			if err := trash.Throw(filePath); err != nil {
+				return fmt.Errorf("删除文件失败: %s", err.Error())
+			}
+		}
+	case "vpk":
+		if _, ok := a.vpkCache.Load(item.VPKPath); !ok {
+			return fmt.Errorf("文件未找到: %s", item.VPKPath)
+		}
+		sprayOnly, err := isSprayOnlyVPK(item.VPKPath)
+		if err != nil {
+			return err
+		}
+		if !sprayOnly {
+			return fmt.Errorf("%s 中还包含喷漆以外的文件，请在模组列表中处理", filepath.Base(item.VPKPath))
+		}
+		if err := a.DeleteVPKFile(item.VPKPath); err != nil {
+			return err
+		}
+		a.vpkCache.Delete(item.VPKPath)
+		if a.ctx != nil {
+			runtime.EventsEmit(a.ctx, "refresh_files", nil)
+		}
+	default:
+		return fmt.Errorf("未知的喷漆来源: %s", item.Source)
+	}
+	log.Printf("已删除喷漆: %s", item.Entry)
+
+	// 删除的是当前喷漆时移除 cl_logofile，游戏改用默认喷漆
+	if active, _ := readActiveSpray(gameDir); active != "" && strings.EqualFold(active, item.Entry) {
+		if err := clearActiveSpray(gameDir); err != nil {
+			return err
+		}
+		log.Printf("已清除当前喷漆设置: %s", active)
+	}
+	return nil
+}
+
+// sprayGameDir 返回 addons 目录的上级目录，即 left4dead2 目录
+func (a *App) sprayGameDir() (string, error) {
+	a.mu.RLock()
+	rootDir := strings.TrimSpace(a.rootDir)
+	a.mu.RUnlock()
+	if rootDir == "" {
+		return "", fmt.Errorf("请先设置 L4D2 addons 目录")
+	}
+	return filepath.Dir(filepath.Clean(rootDir)), nil
+}
+
+func (a *App) listLooseSprays(gameDir string) []SprayInventoryItem {
+	logosDir := filepath.Join(gameDir, filepath.FromSlash(sprayLogosDir))
+	sprays := make([]SprayInventoryItem, 0)
+	filepath.WalkDir(logosDir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.EqualFold(filepath.Ext(filePath), ".vtf") {
+			return nil
+		}
+		rel, err := filepath.Rel(gameDir, filePath)
+		if err != nil {
+			return nil
+		}
+
+		item := SprayInventoryItem{
+			Name:    strings.TrimSuffix(d.Name(), filepath.Ext(d.Name())),
+			Entry:   filepath.ToSlash(rel),
+			Source:  "loose",
+			VTFPath: filePath,
+		}
+		vmtPath := strings.TrimSuffix(filePath, filepath.Ext(filePath)) + ".vmt"
+		if _, err := os.Stat(vmtPath); err == nil {
+			item.VMTPath = vmtPath
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			item.Error = err.Error()
+			sprays = append(sprays, item)
+			return nil
+		}
+		item.Size = info.Size()
+		if header, err := readLooseVTFHeader(filePath); err != nil {
+			item.Error = err.Error()
+		} else {
+			item.Width, item.Height, item.Frames = header.Width, header.Height, header.Frames
+			item.Format = header.Format.String()
+			if header.Decodable() {
+				item.PreviewURL = a.previewServer.SprayURL(filePath, info)
+			}
+		}
+		sprays = append(sprays, item)
+		return nil
+	})
+	return sprays
+}
+
+func (a *App) listVPKSprays() []SprayInventoryItem {
+	sprays := make([]SprayInventoryItem, 0)
+	a.vpkCache.Range(func(key, value any) bool {
+		vpkPath := key.(string)
+		cache := value.(*VPKFileCache)
+
+		// 先用文件索引筛选，只打开包含喷漆的VPK
+		names, err := a.vpkInnerFileNames(vpkPath)
+		if err != nil || !hasSprayEntry(names) {
+			return true
+		}
+		opener := vpk.Single(vpkPath)
+		defer opener.Close()
+		archive, err := opener.ReadArchive()
+		if err != nil {
+			return true
+		}
+		textures := parser.ListVTFTexturesInDir(opener, archive, sprayLogosDir)
+		if len(textures) == 0 {
+			return true
+		}
+		sprayOnly := sprayOnlyArchive(archive)
+		for _, texture := range textures {
+			item := SprayInventoryItem{
+				Name:      strings.TrimSuffix(path.Base(texture.Path), path.Ext(texture.Path)),
+				Entry:     texture.Path,
+				Source:    "vpk",
+				VPKPath:   vpkPath,
+				SprayOnly: sprayOnly,
+				Width:     texture.Width,
+				Height:    texture.Height,
+				Frames:    texture.Frames,
+				Format:    texture.Format,
+				Size:      texture.FileSize,
+				Error:     texture.Error,
+			}
+			if texture.Decodable {
+				item.PreviewURL = a.previewServer.TextureURL(vpkPath, texture.Path, cache, true)
+			}
+			sprays = append(sprays, item)
+		}
+		return true
+	})
+	sort.Slice(sprays, func(i, j int) bool {
+		if sprays[i].VPKPath != sprays[j].VPKPath {
+			return sprays[i].VPKPath < sprays[j].VPKPath
+		}
+		return sprays[i].Entry < sprays[j].Entry
+	})
+	return sprays
+}
+
+// hasSprayEntry 判断VPK内部文件中是否有喷漆目录下的 VTF
+func hasSprayEntry(names []string) bool {
+	prefix := sprayLogosDir + "/"
+	for _, name := range names {
+		entry := strings.ToLower(strings.ReplaceAll(name, "\\", "/"))
+		if strings.HasPrefix(entry, prefix) && strings.HasSuffix(entry, ".vtf") {
+			return true
+		}
+	}
+	return false
+}
+
+func isSprayOnlyVPK(vpkPath string) (bool, error) {
+	opener := vpk.Single(vpkPath)
+	defer opener.Close()
+	archive, err := opener.ReadArchive()
+	if err != nil {
+		return false, fmt.Errorf("无法读取 VPK: %v", err)
+	}
+	return sprayOnlyArchive(archive), nil
+}
+
+// sprayOnlyArchive 判断 VPK 是否只包含喷漆，addoninfo 和封面图不计
+func sprayOnlyArchive(archive *vpk.Archive) bool {
+	prefix := sprayLogosDir + "/"
+	for i := range archive.Files {
+		name := strings.ToLower(archive.Files[i].Name())
+		if strings.HasPrefix(name, prefix) || name == "addoninfo.txt" || strings.HasPrefix(name, "addonimage.") {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// cleanSprayEntry 校验喷漆的游戏内路径，必须是 materials/vgui/logos 下的 VTF
+func cleanSprayEntry(entry string) (string, error) {
+	cleanEntry, err := cleanVPKEntryPath(entry)
+	if err != nil {
+		return "", err
+	}
+	cleanEntry = filepath.ToSlash(cleanEntry)
+	if !strings.HasPrefix(strings.ToLower(cleanEntry), sprayLogosDir+"/") || !strings.EqualFold(path.Ext(cleanEntry), ".vtf") {
+		return "", fmt.Errorf("不是有效的喷漆路径: %s", entry)
+	}
+	return cleanEntry, nil
+}
+
+func sprayConfigPath(gameDir string) string {
+	return filepath.Join(gameDir, "cfg", "config.cfg")
+}
+
+// readActiveSpray 读取 config.cfg 中的 cl_logofile
+func readActiveSpray(gameDir string) (string, error) {
+	content, err := os.ReadFile(sprayConfigPath(gameDir))
+	if err != nil {
+		return "", err
+	}
+	match := sprayConfigPattern.FindSubmatch(content)
+	if match == nil {
+		return "", nil
+	}
+	value := match[2]
+	if len(match[1]) > 0 {
+		value = match[1]
+	}
+	return strings.ReplaceAll(string(value), "\\", "/"), nil
+}
+
+// writeActiveSpray 替换 config.cfg 中的 cl_logofile，没有该设置时追加到末尾
+func writeActiveSpray(gameDir string, entry string) error {
+	configPath := sprayConfigPath(gameDir)
+	content, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("无法读取 %s: %v", configPath, err)
+	}
+
+	line := fmt.Sprintf("cl_logofile \"%s\"", entry)
+	if sprayConfigPattern.Match(content) {
+		content = sprayConfigPattern.ReplaceAllLiteral(content, []byte(line))
+	} else {
+		newline := "\n"
+		if bytes.Contains(content, []byte("\r\n")) {
+			newline = "\r\n"
+		}
+		if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+			content = append(content, newline...)
+		}
+		content = append(content, line+newline...)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return fmt.Errorf("创建 cfg 目录失败: %v", err)
+	}
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		return fmt.Errorf("写入 %s 失败: %v", configPath, err)
+	}
+	return nil
+}
+
+// clearActiveSpray 删除 config.cfg 中的 cl_logofile，保留其余设置
+func clearActiveSpray(gameDir string) error {
+	configPath := sprayConfigPath(gameDir)
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("无法读取 %s: %v", configPath, err)
+	}
+	if err := os.WriteFile(configPath, sprayConfigLinePattern.ReplaceAllLiteral(content, nil), 0644); err != nil {
+		return fmt.Errorf("写入 %s 失败: %v", configPath, err)
+	}
+	return nil
+}
+
+func readLooseVTFHeader(filePath string) (*parser.VTFHeader, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return parser.ReadVTFHeader(file)
+}
+
+// isSubPath 判断 target 是否位于 dir 内
+func isSubPath(dir string, target string) bool {
+	rel, err := filepath.Rel(dir, target)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// isLooseSprayPath 判断路径是否为游戏喷漆目录中的 VTF，读取和删除散装喷漆前都需校验
+func isLooseSprayPath(gameDir string, filePath string) bool {
+	return isSubPath(filepath.Join(gameDir, filepath.FromSlash(sprayLogosDir)), filePath) &&
+		strings.EqualFold(filepath.Ext(filePath), ".vtf")
+}
+
+// SprayURL 返回游戏目录中散装喷漆的缩略图地址。服务未启动时返回空字符串
+func (s *previewImageServer) SprayURL(filePath string, info fs.FileInfo) string {
+	if s == nil || s.port == 0 {
+		return ""
+	}
+	return s.endpointURL("spray", url.Values{"path": {filePath}, "v": {fmt.Sprintf("%d-%d", info.Size(), info.ModTime().UnixNano())}})
+}
+
+// handleSpray 将游戏喷漆目录中的 VTF 解码为 PNG 缩略图
+func (s *previewImageServer) handleSpray(w http.ResponseWriter, r *http.Request) {
+	filePath := r.URL.Query().Get("path")
+
+	// 只提供游戏喷漆目录中的 VTF
+	gameDir, err := s.app.sprayGameDir()
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	if !isLooseSprayPath(gameDir, filePath) {
+		http.NotFound(w, r)
+		return
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	header, err := parser.ParseVTFHeader(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	img, err := parser.DecodeVTF(data, header.MipForSize(previewThumbnailSize))
+	if err != nil {
+		log.Printf("解码喷漆失败: %s, 错误: %v", filePath, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "max-age=31536000, immutable")
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.Write(buf.Bytes())
+}
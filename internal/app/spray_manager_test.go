@@ -0,0 +1,149 @@
+package app
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSprayManagerListsSetsAndRemovesSprays(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	app, _ := newPreviewTestApp(t)
+	gameDir := t.TempDir()
+	app.rootDir = filepath.Join(gameDir, "addons")
+
+	logosDir := filepath.Join(gameDir, "materials", "vgui", "logos", "custom")
+	if err := os.MkdirAll(app.rootDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(logosDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	loosePath := filepath.Join(logosDir, "tank.vtf")
+	if err := os.WriteFile(loosePath, testVTF(4, 4, [4]byte{0, 255, 0, 255}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(logosDir, "tank.vmt"), []byte(buildSprayVMTText("tank")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(gameDir, "cfg", "config.cfg")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte("bind \"w\" \"+forward\"\r\ncl_logofile \"materials\\vgui\\logos\\custom\\tank.vtf\"\r\nvolume \"1\"\r\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sprayVPK := filepath.Join(app.rootDir, "spray_pack.vpk")
+	mixedVPK := filepath.Join(app.rootDir, "mixed.vpk")
+	for vpkPath, entries := range map[string]map[string][]byte{
+		sprayVPK: {
+			"materials/vgui/logos/witch.vtf": testVTF(8, 8, [4]byte{255, 0, 0, 255}),
+			"materials/vgui/logos/witch.vmt": []byte(buildSprayVMTText("witch")),
+		},
+		mixedVPK: {
+			"materials/vgui/logos/boomer.vtf": testVTF(8, 8, [4]byte{255, 0, 0, 255}),
+			"models/weapons/rifle.mdl":        []byte("IDST"),
+		},
+	} {
+		writeTestVPK(t, vpkPath, entries)
+		app.processVPKFileWithCache(vpkPath)
+	}
+
+	inventory, err := app.ListSprays()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inventory.Sprays) != 3 || inventory.ActiveSpray != "materials/vgui/logos/custom/tank.vtf" {
+		t.Fatalf("unexpected inventory: %+v", inventory)
+	}
+	loose := inventory.Sprays[0]
+	if loose.Source != "loose" || loose.Entry != "materials/vgui/logos/custom/tank.vtf" || !loose.Active || loose.VMTPath == "" || loose.Width != 4 {
+		t.Fatalf("unexpected loose spray: %+v", loose)
+	}
+	if status, _ := fetchPreview(t, loose.PreviewURL); status != http.StatusOK {
+		t.Fatalf("loose spray preview status %d", status)
+	}
+	var packed, mixed SprayInventoryItem
+	for _, item := range inventory.Sprays[1:] {
+		if item.VPKPath == sprayVPK {
+			packed = item
+		} else {
+			mixed = item
+		}
+	}
+	if packed.Entry != "materials/vgui/logos/witch.vtf" || !packed.SprayOnly || packed.PreviewURL == "" || mixed.SprayOnly {
+		t.Fatalf("unexpected vpk sprays: %+v %+v", packed, mixed)
+	}
+
+	// 只替换 cl_logofile 一行，保留其余设置和换行符
+	if err := app.SetActiveSpray(packed.Entry); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := os.ReadFile(configPath)
+	if string(content) != "bind \"w\" \"+forward\"\r\ncl_logofile \"materials/vgui/logos/witch.vtf\"\r\nvolume \"1\"\r\n" {
+		t.Fatalf("unexpected config.cfg: %q", content)
+	}
+	for _, entry := range []string{"materials/models/skin.vtf", "materials/vgui/logos/../../../cfg/x.vtf", "materials/vgui/logos/a.vmt"} {
+		if err := app.SetActiveSpray(entry); err == nil {
+			t.Errorf("expected error for %s", entry)
+		}
+	}
+
+	if err := app.RemoveSpray(mixed); err == nil || !strings.Contains(err.Error(), "mixed.vpk") {
+		t.Fatalf("expected mixed vpk to be kept, got %v", err)
+	}
+	if err := app.RemoveSpray(packed); err != nil {
+		t.Fatal(err)
+	}
+	// 删除当前喷漆后移除 cl_logofile，不再指向已删除的文件
+	content, _ = os.ReadFile(configPath)
+	if string(content) != "bind \"w\" \"+forward\"\r\nvolume \"1\"\r\n" {
+		t.Fatalf("expected active spray to be cleared, got %q", content)
+	}
+	// 散装喷漆只允许删除喷漆目录中的 VTF
+	readme := filepath.Join(logosDir, "readme.txt")
+	if err := os.WriteFile(readme, []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	notSpray := loose
+	notSpray.VTFPath = readme
+	if err := app.RemoveSpray(notSpray); err == nil {
+		t.Fatal("expected non-vtf file to be rejected")
+	}
+	if _, err := os.Stat(readme); err != nil {
+		t.Fatalf("expected non-vtf file to be kept: %v", err)
+	}
+	if err := app.RemoveSpray(loose); err != nil {
+		t.Fatal(err)
+	}
+	for _, filePath := range []string{sprayVPK, loosePath, filepath.Join(logosDir, "tank.vmt")} {
+		if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed", filePath)
+		}
+	}
+	if _, err := os.Stat(mixedVPK); err != nil {
+		t.Fatal(err)
+	}
+
+	inventory, err = app.ListSprays()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inventory.Sprays) != 1 || inventory.Sprays[0].VPKPath != mixedVPK {
+		t.Fatalf("unexpected inventory after removal: %+v", inventory.Sprays)
+	}
+}
+
+func TestWriteActiveSprayAppendsSetting(t *testing.T) {
+	gameDir := t.TempDir()
+	if err := writeActiveSpray(gameDir, "materials/vgui/logos/spray.vtf"); err != nil {
+		t.Fatal(err)
+	}
+	active, err := readActiveSpray(gameDir)
+	if err != nil || active != "materials/vgui/logos/spray.vtf" {
+		t.Fatalf("unexpected active spray %q: %v", active, err)
+	}
+}
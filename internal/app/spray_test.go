@@ -135,7 +135,11 @@ func TestLoadSprayImportFilesRejectsVideo(t *testing.T) {
 	}
 
 	app := &App{}
-	if _, err := app.LoadSprayImportFiles([]string{videoPath}); err == nil {
+	_, err := app.LoadSprayImportFiles([]string{videoPath})
+	if err == nil {
 		t.Fatal("expected video import to be rejected")
 	}
+	if !strings.Contains(err.Error(), "GIF") {
+		t.Fatalf("expected conversion hint, got %v", err)
+	}
 }
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"l4d2-manager-next/pkg/valve/vpk"
+	"vpk-manager/internal/parser"
+)
+
+// ListVPKTextures 列出VPK中所有 VTF 贴图的尺寸、格式和显存大小
+func (a *App) ListVPKTextures(vpkPath string) ([]parser.VTFTextureInfo, error) {
+	cached, ok := a.vpkCache.Load(vpkPath)
+	if !ok {
+		return nil, fmt.Errorf("文件未找到: %s", vpkPath)
+	}
+	cache := cached.(*VPKFileCache)
+
+	opener := vpk.Single(vpkPath)
+	defer opener.Close()
+	archive, err := opener.ReadArchive()
+	if err != nil {
+		return nil, fmt.Errorf("无法读取 VPK: %v", err)
+	}
+
+	textures := parser.ListVTFTextures(opener, archive)
+	for i := range textures {
+		if textures[i].Decodable {
+			textures[i].PreviewURL = a.previewServer.TextureURL(vpkPath, textures[i].Path, cache, true)
+		}
+	}
+	return textures, nil
+}
+
+// TextureURL 返回VPK内贴图的预览地址，thumbnail 为 true 时返回缩略图。服务未启动时返回空字符串
+func (s *previewImageServer) TextureURL(vpkPath string, entry string, cache *VPKFileCache, thumbnail bool) string {
+	if s == nil || s.port == 0 {
+		return ""
+	}
+	size := "full"
+	if thumbnail {
+		size = "thumb"
+	}
+	return s.endpointURL("texture", url.Values{"path": {vpkPath}, "entry": {entry}, "v": {previewCacheKey(vpkPath, cache)}, "size": {size}})
+}
+
+// handleTexture 将VPK内的 VTF 解码为 PNG。缩略图只解码接近目标尺寸的 mip 级别
+func (s *previewImageServer) handleTexture(w http.ResponseWriter, r *http.Request) {
+	entry, ok := s.resolvePreviewEntry(w, r, ".vtf")
+	if !ok {
+		return
+	}
+
+	maxSize := 0
+	if r.URL.Query().Get("size") == "thumb" {
+		maxSize = previewThumbnailSize
+	}
+	img, err := parser.ReadVTFImage(entry.vpkPath, entry.name, maxSize)
+	if err != nil {
+		log.Printf("解码贴图失败: %s:%s, 错误: %v", entry.vpkPath, entry.name, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "max-age=31536000, immutable")
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/png"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+// testVTF 生成 7.2 版本、单个 mip 的 BGRA8888 贴图
+func testVTF(width, height int, pixel [4]byte) []byte {
+	header := make([]byte, 80)
+	copy(header, "VTF\x00")
+	binary.LittleEndian.PutUint32(header[4:], 7)
+	binary.LittleEndian.PutUint32(header[8:], 2)
+	binary.LittleEndian.PutUint32(header[12:], 80)
+	binary.LittleEndian.PutUint16(header[16:], uint16(width))
+	binary.LittleEndian.PutUint16(header[18:], uint16(height))
+	binary.LittleEndian.PutUint16(header[24:], 1)
+	binary.LittleEndian.PutUint32(header[52:], 12)
+	header[56] = 1
+	binary.LittleEndian.PutUint32(header[57:], 0xffffffff)
+	binary.LittleEndian.PutUint16(header[63:], 1)
+	return append(header, bytes.Repeat(pixel[:], width*height)...)
+}
+
+func TestTextureBrowserListsAndServesVTF(t *testing.T) {
+	app, _ := newPreviewTestApp(t)
+	vpkPath := filepath.Join(app.rootDir, "skin.vpk")
+	writeTestVPK(t, vpkPath, map[string][]byte{
+		"materials/models/skin.vtf": testVTF(8, 4, [4]byte{0, 0, 255, 255}),
+		"materials/models/skin.vmt": []byte(`"VertexLitGeneric" {}`),
+	})
+	app.processVPKFileWithCache(vpkPath)
+
+	// 没有 addonimage 时使用贴图作为预览图
+	files := app.GetVPKFiles()
+	if len(files) != 1 || files[0].PreviewImage == "" {
+		t.Fatalf("expected texture fallback preview, got %+v", files)
+	}
+	if status, _ := fetchPreview(t, files[0].PreviewImage); status != http.StatusOK {
+		t.Fatalf("fallback preview status %d", status)
+	}
+
+	textures, err := app.ListVPKTextures(vpkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(textures) != 1 || textures[0].Width != 8 || textures[0].Format != "BGRA8888" || textures[0].MemorySize != 128 || textures[0].PreviewURL == "" {
+		t.Fatalf("unexpected textures: %+v", textures)
+	}
+
+	status, data := fetchPreview(t, app.previewServer.TextureURL(vpkPath, "materials/models/skin.vtf", &VPKFileCache{}, false))
+	if status != http.StatusOK {
+		t.Fatalf("texture status %d", status)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, _, _, _ := img.At(0, 0).RGBA(); img.Bounds().Dx() != 8 || r>>8 != 255 {
+		t.Fatalf("unexpected texture image %v", img.Bounds())
+	}
+
+	for entry, want := range map[string]int{
+		"../skin.vtf":               http.StatusBadRequest,
+		"materials/models/skin.vmt": http.StatusBadRequest,
+		"materials/missing.vtf":     http.StatusInternalServerError,
+	} {
+		if status, _ := fetchPreview(t, app.previewServer.TextureURL(vpkPath, entry, &VPKFileCache{}, true)); status != want {
+			t.Errorf("entry %q: expected %d, got %d", entry, want, status)
+		}
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"sync"
 	"time"
 
+	"vpk-manager/internal/network"
+
 	"github.com/blang/semver"
 	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
@@ -57,9 +59,7 @@ type MirrorWithLatency struct {
 // checkLatency 检测镜像源延迟
 func checkLatency(url string) int64 {
 	start := time.Now()
-	client := http.Client{
-		Timeout: 3 * time.Second,
-	}
+	client := network.NewHTTPClient(3 * time.Second)
 
 	// 尝试 HEAD 请求
 	resp, err := client.Head(url)
@@ -81,9 +81,9 @@ func checkLatency(url string) int64 {
 }
 
 // fetchReleases 获取最近的版本列表
-func fetchReleases(repo string) ([]GithubRelease, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/releases?per_page=10", repo)
-	client := &http.Client{Timeout: 10 * time.Second}
+func fetchReleases(apiBaseURL, repo string) ([]GithubRelease, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases?per_page=10", strings.TrimRight(apiBaseURL, "/"), repo)
+	client := network.NewHTTPClient(10 * time.Second)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -115,10 +115,8 @@ func fetchLatestTagFromMirror(repo, mirror string) (string, error) {
 	// 构造 URL: mirror + https://github.com/user/repo/releases/latest
 	target := fmt.Sprintf("%shttps://github.com/%s/releases/latest", mirror, repo)
 
-	client := &http.Client{
-		Timeout: 15 * time.Second,
-		// 默认会自动跟随重定向
-	}
+	// 默认会自动跟随重定向
+	client := network.NewHTTPClient(15 * time.Second)
 
 	resp, err := client.Get(target)
 	if err != nil {
@@ -148,8 +146,14 @@ func (a *App) CheckUpdate() UpdateInfo {
 	var release *GithubRelease
 	var fetchErr error
 
-	// 2. 尝试直连 GitHub API 获取列表
-	releases, err := fetchReleases(GithubRepo)
+	// 2. 尝试直连 GitHub API 获取列表（自定义 API 地址失败时回退到官方地址）
+	var releases []GithubRelease
+	for _, apiBaseURL := range a.endpointCandidates(endpointGithubAPI) {
+		releases, err = fetchReleases(apiBaseURL, GithubRepo)
+		if err == nil {
+			break
+		}
+	}
 	if err == nil && len(releases) > 0 {
 		// 寻找最新版本
 		var bestRel GithubRelease
@@ -199,7 +203,7 @@ func (a *App) CheckUpdate() UpdateInfo {
 	if fetchErr != nil {
 		fmt.Printf("直连失败: %v，尝试使用镜像源...\n", fetchErr)
 
-		for _, mirror := range MirrorList {
+		for _, mirror := range a.updateMirrors() {
 			tag, mirrorErr := fetchLatestTagFromMirror(GithubRepo, mirror)
 			if mirrorErr == nil && tag != "" {
 				fmt.Printf("通过镜像 %s 获取到版本: %s\n", mirror, tag)
@@ -287,7 +291,7 @@ func (a *App) CheckUpdate() UpdateInfo {
 
 // GetMirrors 获取镜像列表
 func (a *App) GetMirrors() []string {
-	return MirrorList
+	return a.updateMirrors()
 }
 
 // GetMirrorsInitial 获取镜像列表初始状态 (不含延迟)
@@ -298,7 +302,7 @@ func (a *App) GetMirrorsInitial() []MirrorWithLatency {
 	results = append(results, MirrorWithLatency{URL: "", Latency: 0}) // 0 表示未检测/检测中
 
 	// 2. 添加镜像源
-	for _, m := range MirrorList {
+	for _, m := range a.updateMirrors() {
 		results = append(results, MirrorWithLatency{URL: m, Latency: 0})
 	}
 
@@ -318,7 +322,7 @@ func (a *App) TestMirrorsLatency() {
 	}()
 
 	// 2. 镜像源检测
-	for _, mirror := range MirrorList {
+	for _, mirror := range a.updateMirrors() {
 		go func(m string) {
 			target := m
 			if pendingUpdateURL != "" {
@@ -357,7 +361,7 @@ func (a *App) GetMirrorsWithLatency() []MirrorWithLatency {
 	}()
 
 	// 2. 检测镜像源
-	for _, mirror := range MirrorList {
+	for _, mirror := range a.updateMirrors() {
 		wg.Add(1)
 		go func(m string) {
 			defer wg.Done()
@@ -452,7 +456,7 @@ func (a *App) DoUpdate(mirror string) string {
 
 // downloadWithProgress 下载文件并发送进度
 func (a *App) downloadWithProgress(url string, destPath string) error {
-	resp, err := http.Get(url)
+	resp, err := network.NewHTTPClient(0).Get(url)
 	if err != nil {
 		return err
 	}
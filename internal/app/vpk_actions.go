@@ -38,10 +38,10 @@ func validateWindowsRenamePath(path string, filename string) error {
 	return nil
 }
 
+// GetVPKPreviewImage 获取原尺寸预览图的地址，没有预览图时返回空字符串
 func (a *App) GetVPKPreviewImage(filePath string) string {
 	if cached, ok := a.vpkCache.Load(filePath); ok {
-		cache := cached.(*VPKFileCache)
-		return cache.File.PreviewImage
+		return a.previewServer.URL(filePath, cached.(*VPKFileCache), false)
 	}
 	return ""
 }
@@ -218,6 +218,10 @@ func (a *App) packVPKDirectoryWithOptions(sourceDir string, outputDir string, ou
 	result.OutputPath = outputPath
 	result.TotalFiles = len(entries)
 
+	// 写入过程中不让目录监听解析半成品
+	release := a.ignoreWatchPath(outputPath)
+	defer release()
+
 	out, err := os.Create(outputPath)
 	if err != nil {
 		return result, fmt.Errorf("无法创建 VPK 文件 %s: %v", outputPath, err)
@@ -0,0 +1,310 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// VPKQueryFilter 结构化筛选条件，未设置的条件不参与筛选
+type VPKQueryFilter struct {
+	Text           string   `json:"text"`           // 搜索表达式，普通文本模糊匹配标题、文件名或标签
+	PrimaryTag     string   `json:"primaryTag"`     // 一级标签
+	SecondaryTags  []string `json:"secondaryTags"`  // 二级标签，匹配任意一个
+	Locations      []string `json:"locations"`      // "root", "workshop", "disabled"，匹配任意一个
+	Enabled        *bool    `json:"enabled"`        // 是否启用
+	MinSize        int64    `json:"minSize"`        // 最小文件大小（字节），0 表示不限
+	MaxSize        int64    `json:"maxSize"`        // 最大文件大小（字节），0 表示不限
+	Author         string   `json:"author"`         // 作者包含该文本（不区分大小写）
+	HasUpdate      *bool    `json:"hasUpdate"`      // 是否有工坊更新
+	HasWorkshopID  *bool    `json:"hasWorkshopId"`  // 是否有工坊ID
+	ModifiedAfter  string   `json:"modifiedAfter"`  // 修改时间不早于，RFC3339 或 2006-01-02
+	ModifiedBefore string   `json:"modifiedBefore"` // 修改时间早于，RFC3339 或 2006-01-02
+}
+
+// VPKSortKey 排序字段，多个字段按顺序依次比较
+type VPKSortKey struct {
+	Field string `json:"field"` // name, title, size, modified, author, location, enabled, primaryTag
+	Desc  bool   `json:"desc"`
+}
+
+// VPKQuery 模组库查询参数
+type VPKQuery struct {
+	Filter VPKQueryFilter `json:"filter"`
+	Sort   []VPKSortKey   `json:"sort"`   // 为空时按文件名排序
+	Offset int            `json:"offset"` // 分页起始位置
+	Limit  int            `json:"limit"`  // 每页数量，0 表示返回全部
+}
+
+// VPKFacetCount 某个取值的文件数量
+type VPKFacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// VPKQueryFacets 分面统计。每个维度的统计忽略该维度自身的筛选条件，便于前端展示切换后的数量。
+type VPKQueryFacets struct {
+	PrimaryTags   []VPKFacetCount `json:"primaryTags"`
+	SecondaryTags []VPKFacetCount `json:"secondaryTags"`
+	Locations     []VPKFacetCount `json:"locations"`
+}
+
+// VPKQueryResult 查询结果
+type VPKQueryResult struct {
+	Files  []VPKFile      `json:"files"`
+	Total  int            `json:"total"` // 分页前的匹配总数
+	Offset int            `json:"offset"`
+	Limit  int            `json:"limit"`
+	Facets VPKQueryFacets `json:"facets"`
+}
+
+// 参与分面统计的维度，匹配时可跳过对应条件
+const (
+	facetNone          = ""
+	facetPrimaryTag    = "primaryTag"
+	facetSecondaryTags = "secondaryTags"
+	facetLocation      = "location"
+)
+
+// vpkFileComparators 支持的排序字段
+var vpkFileComparators = map[string]func(a, b *VPKFile) int{
+	"name":  func(a, b *VPKFile) int { return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)) },
+	"title": func(a, b *VPKFile) int { return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title)) },
+	"size":  func(a, b *VPKFile) int { return compareInt64(a.Size, b.Size) },
+	"modified": func(a, b *VPKFile) int {
+		return strings.Compare(a.LastModified, b.LastModified) // RFC3339 同时区可按字符串比较
+	},
+	"author":     func(a, b *VPKFile) int { return strings.Compare(strings.ToLower(a.Author), strings.ToLower(b.Author)) },
+	"location":   func(a, b *VPKFile) int { return strings.Compare(a.Location, b.Location) },
+	"primaryTag": func(a, b *VPKFile) int { return strings.Compare(a.PrimaryTag, b.PrimaryTag) },
+	"enabled": func(a, b *VPKFile) int {
+		switch {
+		case a.Enabled == b.Enabled:
+			return 0
+		case a.Enabled:
+			return 1
+		default:
+			return -1
+		}
+	},
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compiledVPKFilter 预处理后的筛选条件，避免每个文件重复解析
+type compiledVPKFilter struct {
+	VPKQueryFilter
+	expression     vpkPredicate
+	author         string
+	modifiedAfter  time.Time
+	modifiedBefore time.Time
+}
+
+func parseQueryTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("无法解析时间: %s", value)
+	}
+	return t, nil
+}
+
+func compileVPKFilter(filter VPKQueryFilter) (*compiledVPKFilter, error) {
+	expression, err := compileSearchQuery(filter.Text, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	compiled := &compiledVPKFilter{
+		VPKQueryFilter: filter,
+		expression:     expression,
+		author:         strings.ToLower(strings.TrimSpace(filter.Author)),
+	}
+	if filter.MinSize < 0 || filter.MaxSize < 0 || (filter.MaxSize > 0 && filter.MinSize > filter.MaxSize) {
+		return nil, fmt.Errorf("文件大小范围无效: %d - %d", filter.MinSize, filter.MaxSize)
+	}
+	if filter.ModifiedAfter != "" {
+		if compiled.modifiedAfter, err = parseQueryTime(filter.ModifiedAfter); err != nil {
+			return nil, err
+		}
+	}
+	if filter.ModifiedBefore != "" {
+		if compiled.modifiedBefore, err = parseQueryTime(filter.ModifiedBefore); err != nil {
+			return nil, err
+		}
+	}
+	return compiled, nil
+}
+
+// matches 判断文件是否满足筛选条件，skip 指定忽略的分面维度
+func (f *compiledVPKFilter) matches(file *VPKFile, skip string) bool {
+	if !f.expression(file) {
+		return false
+	}
+	if skip != facetPrimaryTag && f.PrimaryTag != "" && file.PrimaryTag != f.PrimaryTag {
+		return false
+	}
+	if skip != facetSecondaryTags && !matchesAnyTag(file.SecondaryTags, f.SecondaryTags) {
+		return false
+	}
+	if skip != facetLocation && !matchesAnyTag([]string{file.Location}, f.Locations) {
+		return false
+	}
+	if f.Enabled != nil && file.Enabled != *f.Enabled {
+		return false
+	}
+	if f.MinSize > 0 && file.Size < f.MinSize {
+		return false
+	}
+	if f.MaxSize > 0 && file.Size > f.MaxSize {
+		return false
+	}
+	if f.author != "" && !strings.Contains(strings.ToLower(file.Author), f.author) {
+		return false
+	}
+	if f.HasUpdate != nil && file.HasUpdate != *f.HasUpdate {
+		return false
+	}
+	if f.HasWorkshopID != nil && (file.WorkshopID != "") != *f.HasWorkshopID {
+		return false
+	}
+	if !f.modifiedAfter.IsZero() || !f.modifiedBefore.IsZero() {
+		modified, err := time.Parse(time.RFC3339, file.LastModified)
+		if err != nil {
+			return false
+		}
+		if !f.modifiedAfter.IsZero() && modified.Before(f.modifiedAfter) {
+			return false
+		}
+		if !f.modifiedBefore.IsZero() && !modified.Before(f.modifiedBefore) {
+			return false
+		}
+	}
+	return true
+}
+
+// newVPKFileLess 根据排序字段构造比较函数，最后按路径比较保证分页结果稳定
+func newVPKFileLess(keys []VPKSortKey) (func(a, b *VPKFile) bool, error) {
+	if len(keys) == 0 {
+		keys = []VPKSortKey{{Field: "name"}}
+	}
+	comparators := make([]func(a, b *VPKFile) int, len(keys))
+	for i, key := range keys {
+		compare, ok := vpkFileComparators[key.Field]
+		if !ok {
+			return nil, fmt.Errorf("不支持的排序字段: %s", key.Field)
+		}
+		if key.Desc {
+			comparators[i] = func(a, b *VPKFile) int { return -compare(a, b) }
+		} else {
+			comparators[i] = compare
+		}
+	}
+
+	return func(a, b *VPKFile) bool {
+		for _, compare := range comparators {
+			if c := compare(a, b); c != 0 {
+				return c < 0
+			}
+		}
+		return a.Path < b.Path
+	}, nil
+}
+
+// facetCounts 将计数转为按数量从多到少、同数量按名称排列的列表
+func facetCounts(counts map[string]int) []VPKFacetCount {
+	result := make([]VPKFacetCount, 0, len(counts))
+	for value, count := range counts {
+		result = append(result, VPKFacetCount{Value: value, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Value < result[j].Value
+	})
+	return result
+}
+
+// QueryVPKFiles 按结构化条件筛选、排序并分页返回模组，同时返回分面统计
+func (a *App) QueryVPKFiles(query VPKQuery) (VPKQueryResult, error) {
+	if query.Offset < 0 || query.Limit < 0 {
+		return VPKQueryResult{}, fmt.Errorf("分页参数无效: offset=%d, limit=%d", query.Offset, query.Limit)
+	}
+	filter, err := compileVPKFilter(query.Filter)
+	if err != nil {
+		return VPKQueryResult{}, err
+	}
+
+	less, err := newVPKFileLess(query.Sort)
+	if err != nil {
+		return VPKQueryResult{}, err
+	}
+
+	// 直接匹配缓存中的文件信息，只为返回的这一页构造预览地址
+	type entry struct {
+		path  string
+		cache *VPKFileCache
+	}
+	var matched []entry
+	primaryCounts := make(map[string]int)
+	secondaryCounts := make(map[string]int)
+	locationCounts := make(map[string]int)
+
+	a.vpkCache.Range(func(key, value interface{}) bool {
+		cache := value.(*VPKFileCache)
+		file := &cache.File
+		if filter.matches(file, facetNone) {
+			matched = append(matched, entry{path: key.(string), cache: cache})
+		}
+		if file.PrimaryTag != "" && filter.matches(file, facetPrimaryTag) {
+			primaryCounts[file.PrimaryTag]++
+		}
+		if filter.matches(file, facetSecondaryTags) {
+			for _, tag := range file.SecondaryTags {
+				secondaryCounts[tag]++
+			}
+		}
+		if filter.matches(file, facetLocation) {
+			locationCounts[file.Location]++
+		}
+		return true
+	})
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return less(&matched[i].cache.File, &matched[j].cache.File)
+	})
+
+	start := min(query.Offset, len(matched))
+	end := len(matched)
+	if query.Limit > 0 {
+		end = min(start+query.Limit, len(matched))
+	}
+	files := make([]VPKFile, 0, end-start)
+	for _, e := range matched[start:end] {
+		files = append(files, a.previewFile(e.path, e.cache))
+	}
+
+	return VPKQueryResult{
+		Files:  files,
+		Total:  len(matched),
+		Offset: query.Offset,
+		Limit:  query.Limit,
+		Facets: VPKQueryFacets{
+			PrimaryTags:   facetCounts(primaryCounts),
+			SecondaryTags: facetCounts(secondaryCounts),
+			Locations:     facetCounts(locationCounts),
+		},
+	}, nil
+}
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func storeQueryTestFile(app *App, file VPKFile) {
+	if file.LastModified == "" {
+		file.LastModified = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
+	}
+	app.vpkCache.Store(file.Path, &VPKFileCache{File: file})
+}
+
+func newQueryTestApp() *App {
+	app := &App{}
+	storeQueryTestFile(app, VPKFile{Name: "b.vpk", Path: "/addons/b.vpk", Size: 300, PrimaryTag: "人物", SecondaryTags: []string{"ellis"}, Location: "root", Enabled: true, Author: "Alice", WorkshopID: "1"})
+	storeQueryTestFile(app, VPKFile{Name: "a.vpk", Path: "/addons/a.vpk", Size: 100, PrimaryTag: "人物", SecondaryTags: []string{"nick"}, Location: "root", Enabled: true, Author: "bob"})
+	storeQueryTestFile(app, VPKFile{Name: "c.vpk", Path: "/addons/workshop/c.vpk", Size: 200, PrimaryTag: "武器", SecondaryTags: []string{"ak47"}, Location: "workshop", Enabled: true, Author: "alice", HasUpdate: true, WorkshopID: "3",
+		LastModified: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)})
+	storeQueryTestFile(app, VPKFile{Name: "d.vpk", Path: "/addons/disabled/d.vpk", Size: 50, PrimaryTag: "地图", Location: "disabled"})
+	return app
+}
+
+func queryNames(result VPKQueryResult) []string {
+	names := make([]string, len(result.Files))
+	for i, file := range result.Files {
+		names[i] = file.Name
+	}
+	return names
+}
+
+func assertQueryNames(t *testing.T, result VPKQueryResult, want ...string) {
+	t.Helper()
+	got := queryNames(result)
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestQueryVPKFilesFiltersAndSorts(t *testing.T) {
+	app := newQueryTestApp()
+	enabled := true
+	hasWorkshopID := true
+
+	result, err := app.QueryVPKFiles(VPKQuery{
+		Filter: VPKQueryFilter{Enabled: &enabled, Author: "ALICE", HasWorkshopID: &hasWorkshopID},
+		Sort:   []VPKSortKey{{Field: "size", Desc: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertQueryNames(t, result, "b.vpk", "c.vpk")
+
+	result, err = app.QueryVPKFiles(VPKQuery{
+		Filter: VPKQueryFilter{MinSize: 100, MaxSize: 250, ModifiedAfter: "2026-02-01"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertQueryNames(t, result, "c.vpk")
+
+	// 多字段排序：先按位置，再按文件名倒序
+	result, err = app.QueryVPKFiles(VPKQuery{Sort: []VPKSortKey{{Field: "location"}, {Field: "name", Desc: true}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertQueryNames(t, result, "d.vpk", "b.vpk", "a.vpk", "c.vpk")
+
+	if _, err := app.QueryVPKFiles(VPKQuery{Sort: []VPKSortKey{{Field: "unknown"}}}); err == nil {
+		t.Fatal("expected error for unknown sort field")
+	}
+	if _, err := app.QueryVPKFiles(VPKQuery{Filter: VPKQueryFilter{ModifiedBefore: "yesterday"}}); err == nil {
+		t.Fatal("expected error for invalid date")
+	}
+}
+
+func TestQueryVPKFilesPaginatesAndCountsFacets(t *testing.T) {
+	app := newQueryTestApp()
+
+	result, err := app.QueryVPKFiles(VPKQuery{
+		Filter: VPKQueryFilter{PrimaryTag: "人物", Locations: []string{"root"}},
+		Offset: 1,
+		Limit:  1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 2 {
+		t.Fatalf("expected total 2, got %d", result.Total)
+	}
+	assertQueryNames(t, result, "b.vpk")
+
+	// 一级标签的统计忽略一级标签自身的筛选，只受位置筛选影响
+	if len(result.Facets.PrimaryTags) != 1 || result.Facets.PrimaryTags[0] != (VPKFacetCount{Value: "人物", Count: 2}) {
+		t.Fatalf("unexpected primary tag facets: %+v", result.Facets.PrimaryTags)
+	}
+	// 位置的统计忽略位置筛选
+	want := []VPKFacetCount{{Value: "root", Count: 2}}
+	if len(result.Facets.Locations) != len(want) || result.Facets.Locations[0] != want[0] {
+		t.Fatalf("unexpected location facets: %+v", result.Facets.Locations)
+	}
+	if len(result.Facets.SecondaryTags) != 2 {
+		t.Fatalf("unexpected secondary tag facets: %+v", result.Facets.SecondaryTags)
+	}
+
+	result, err = app.QueryVPKFiles(VPKQuery{Offset: 10, Limit: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 4 || len(result.Files) != 0 {
+		t.Fatalf("expected empty page past the end, got %+v", result)
+	}
+}
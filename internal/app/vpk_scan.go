@@ -15,14 +15,19 @@ import (
 )
 
 func (a *App) SetRootDirectory(path string) error {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return fmt.Errorf("目录不存在: %s", path)
 	}
 
+	a.mu.Lock()
+	restart := a.rootDir != path || a.addonWatcher == nil
 	a.rootDir = path
+	a.mu.Unlock()
+
+	// 根目录变化后重建目录监听
+	if restart {
+		a.startAddonWatcher()
+	}
 	return nil
 }
 
@@ -88,6 +93,7 @@ func (a *App) ScanVPKFiles() error {
 		}
 		return true
 	})
+	a.pathIndex.prune(currentPaths)
 
 	// 并发处理所有文件（使用智能缓存）
 	for _, path := range vpkPaths {
@@ -100,6 +106,12 @@ func (a *App) ScanVPKFiles() error {
 	}
 	wg.Wait()
 
+	if err := a.pathIndex.save(); err != nil {
+		log.Printf("保存文件索引失败: %v", err)
+	}
+
+	// 扫描结果变化后重新计算智能合集
+	a.refreshSmartCollections()
 	return nil
 }
 
@@ -176,6 +188,7 @@ func (a *App) processVPKFileWithCache(filePath string) {
 
 			// 更新缓存
 			a.vpkCache.Store(filePath, cache)
+			a.pathIndex.update(filePath, modTime, size)
 			log.Printf("使用缓存: %s (未变化)", filepath.Base(filePath))
 			return
 		}
@@ -184,7 +197,7 @@ func (a *App) processVPKFileWithCache(filePath string) {
 	}
 
 	// 文件不在缓存中或已变化，需要重新解析
-	vpkFile, err := parser.ParseVPKFile(filePath)
+	vpkFile, entries, err := parser.ParseVPKFileWithEntries(filePath)
 	if err != nil {
 		a.LogError("VPK解析", err.Error(), filePath)
 		return
@@ -233,6 +246,9 @@ func (a *App) processVPKFileWithCache(filePath string) {
 		CachedAt:     time.Now(),
 	}
 	a.vpkCache.Store(filePath, cache)
+	if a.pathIndex != nil {
+		a.pathIndex.store(filePath, modTime, size, entries)
+	}
 
 	log.Printf("已解析并缓存: %s", filepath.Base(filePath))
 }
@@ -260,80 +276,43 @@ func (a *App) GetVPKFiles() []VPKFile {
 	result := make([]VPKFile, 0)
 
 	a.vpkCache.Range(func(key, value interface{}) bool {
-		cache := value.(*VPKFileCache)
-		file := cache.File
-		// 性能优化：列表请求不返回预览图数据，由前端按需加载
-		file.PreviewImage = ""
-		result = append(result, file)
+		// 列表只携带缩略图地址，图片由前端按需从预览图服务加载
+		result = append(result, a.previewFile(key.(string), value.(*VPKFileCache)))
 		return true
 	})
 
 	return result
 }
 
-func (a *App) SearchVPKFiles(query string, primaryTag string, secondaryTags []string) []VPKFile {
+// SearchVPKFiles 按搜索表达式和标签筛选文件，表达式语法见 search_query.go，语法错误时返回错误
+func (a *App) SearchVPKFiles(query string, primaryTag string, secondaryTags []string) ([]VPKFile, error) {
 	result := make([]VPKFile, 0)
-	query = strings.ToLower(query)
+	textMatches, err := compileSearchQuery(query, time.Now())
+	if err != nil {
+		return nil, err
+	}
 
 	a.vpkCache.Range(func(key, value interface{}) bool {
 		cache := value.(*VPKFileCache)
-		vpkFile := cache.File
-
-		// 搜索文本匹配：标题、文件名或标签名
-		textMatch := query == ""
-		if query != "" {
-			// 匹配标题
-			if fuzzyMatch(query, strings.ToLower(vpkFile.Title)) {
-				textMatch = true
-			}
-			// 匹配文件名
-			if !textMatch && fuzzyMatch(query, strings.ToLower(vpkFile.Name)) {
-				textMatch = true
-			}
-			// 匹配主标签
-			if !textMatch && fuzzyMatch(query, strings.ToLower(vpkFile.PrimaryTag)) {
-				textMatch = true
-			}
-			// 匹配二级标签
-			if !textMatch {
-				for _, tag := range vpkFile.SecondaryTags {
-					if fuzzyMatch(query, strings.ToLower(tag)) {
-						textMatch = true
-						break
-					}
-				}
-			}
-		}
+		vpkFile := &cache.File
+
+		// 搜索表达式匹配，普通文本模糊匹配标题、文件名或标签名
+		textMatch := textMatches(vpkFile)
 
 		// 主标签筛选匹配
 		primaryMatch := primaryTag == "" || vpkFile.PrimaryTag == primaryTag
 
 		// 二级标签筛选匹配
-		secondaryMatch := len(secondaryTags) == 0
-		if len(secondaryTags) > 0 {
-			for _, tag := range secondaryTags {
-				for _, vpkTag := range vpkFile.SecondaryTags {
-					if vpkTag == tag {
-						secondaryMatch = true
-						break
-					}
-				}
-				if secondaryMatch {
-					break
-				}
-			}
-		}
+		secondaryMatch := matchesAnyTag(vpkFile.SecondaryTags, secondaryTags)
 
 		if textMatch && primaryMatch && secondaryMatch {
-			// 性能优化：列表请求不返回预览图数据，由前端按需加载
-			vpkFile.PreviewImage = ""
-			result = append(result, vpkFile)
+			result = append(result, a.previewFile(key.(string), cache))
 		}
 
 		return true
 	})
 
-	return result
+	return result, nil
 }
 
 // GetPrimaryTags 获取所有主要标签
@@ -354,6 +333,38 @@ func (a *App) GetSecondaryTags(primaryTag string) []string {
 	return parser.GetSecondaryTags(vpkFiles, primaryTag)
 }
 
+// matchesVPKText 判断文件的标题、文件名或标签是否模糊匹配查询词，query 需为小写，为空时总是匹配
+func matchesVPKText(file *VPKFile, query string) bool {
+	if query == "" {
+		return true
+	}
+	if fuzzyMatch(query, strings.ToLower(file.Title)) || fuzzyMatch(query, strings.ToLower(file.Name)) ||
+		fuzzyMatch(query, strings.ToLower(file.PrimaryTag)) {
+		return true
+	}
+	for _, tag := range file.SecondaryTags {
+		if fuzzyMatch(query, strings.ToLower(tag)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAnyTag 判断标签列表是否包含任意一个筛选标签，筛选为空时总是匹配
+func matchesAnyTag(tags []string, filter []string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, want := range filter {
+		for _, tag := range tags {
+			if tag == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func fuzzyMatch(source, target string) bool {
 	// 转换为 rune 数组以支持 Unicode
 	srcRunes := []rune(source)
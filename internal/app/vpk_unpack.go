@@ -174,6 +174,25 @@ func sanitizeVPKOutputDirName(value string) string {
 }
 
 func safeVPKOutputPath(outputDir string, entryName string) (string, error) {
+	cleanName, err := cleanVPKEntryPath(entryName)
+	if err != nil {
+		return "", err
+	}
+
+	targetPath := filepath.Join(outputDir, cleanName)
+	rel, err := filepath.Rel(outputDir, targetPath)
+	if err != nil {
+		return "", fmt.Errorf("无法校验输出路径 %s: %v", entryName, err)
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || filepath.IsAbs(rel) {
+		return "", fmt.Errorf("VPK 内存在路径穿越文件: %s", entryName)
+	}
+
+	return targetPath, nil
+}
+
+// cleanVPKEntryPath 校验VPK内部路径，拒绝空路径、绝对路径和路径穿越，返回本地分隔符的相对路径
+func cleanVPKEntryPath(entryName string) (string, error) {
 	name := strings.ReplaceAll(strings.TrimSpace(entryName), "\\", "/")
 	if name == "" || name == "." {
 		return "", fmt.Errorf("VPK 内存在空文件路径")
@@ -189,17 +208,7 @@ func safeVPKOutputPath(outputDir string, entryName string) (string, error) {
 	if cleanName == ".." || strings.HasPrefix(cleanName, ".."+string(filepath.Separator)) {
 		return "", fmt.Errorf("VPK 内存在路径穿越文件: %s", entryName)
 	}
-
-	targetPath := filepath.Join(outputDir, cleanName)
-	rel, err := filepath.Rel(outputDir, targetPath)
-	if err != nil {
-		return "", fmt.Errorf("无法校验输出路径 %s: %v", entryName, err)
-	}
-	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || filepath.IsAbs(rel) {
-		return "", fmt.Errorf("VPK 内存在路径穿越文件: %s", entryName)
-	}
-
-	return targetPath, nil
+	return cleanName, nil
 }
 
 func hasWindowsDrivePrefix(value string) bool {
@@ -88,6 +88,12 @@ func TestSafeVPKOutputPathRejectsUnsafePaths(t *testing.T) {
 
 func writeTestVPK(t *testing.T, filePath string, entries map[string][]byte) {
 	t.Helper()
+	writeTestVPKWithPreload(t, filePath, entries, 0)
+}
+
+// writeTestVPKWithPreload 每个文件的前 preload 字节作为预载数据写入目录树，其余写入数据区
+func writeTestVPKWithPreload(t *testing.T, filePath string, entries map[string][]byte, preload int) {
+	t.Helper()
 
 	names := make([]string, 0, len(entries))
 	for name := range entries {
@@ -117,17 +123,19 @@ func writeTestVPK(t *testing.T, filePath string, entries map[string][]byte) {
 	for _, name := range names {
 		parts := splitVPKTestPath(name)
 		data := entries[name]
+		metadata := data[:min(preload, len(data))]
 		archive.Files = append(archive.Files, vpk.File{
 			Dir:  parts.dir,
 			Base: parts.base,
 			Ext:  parts.ext,
 			DirEntry: vpk.DirEntry{
 				CRC:           crc32.ChecksumIEEE(data),
-				DataLocation:  []vpk.DataChunk{{ArchiveIndex: 0x7fff, EntryOffset: offset, EntryLength: uint32(len(data))}},
-				MetadataBytes: 0,
+				DataLocation:  []vpk.DataChunk{{ArchiveIndex: 0x7fff, EntryOffset: offset, EntryLength: uint32(len(data) - len(metadata))}},
+				MetadataBytes: uint16(len(metadata)),
 			},
+			Metadata: metadata,
 		})
-		offset += uint32(len(data))
+		offset += uint32(len(data) - len(metadata))
 	}
 
 	var buffer bytes.Buffer
@@ -135,7 +143,7 @@ func writeTestVPK(t *testing.T, filePath string, entries map[string][]byte) {
 		t.Fatalf("write vpk directory: %v", err)
 	}
 	for _, name := range names {
-		buffer.Write(entries[name])
+		buffer.Write(entries[name][min(preload, len(entries[name])):])
 	}
 
 	if err := os.WriteFile(filePath, buffer.Bytes(), 0644); err != nil {
@@ -13,6 +13,7 @@ import (
 	"sync"
 	"time"
 
+	"vpk-manager/internal/network"
 	"vpk-manager/internal/platform/protocol"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
@@ -53,23 +54,25 @@ type WorkshopDetailsResult struct {
 }
 
 type DownloadTask struct {
-	ID             string             `json:"id"`
-	WorkshopID     string             `json:"workshop_id"`
-	Title          string             `json:"title"`
-	Filename       string             `json:"filename"`
-	FilePath       string             `json:"file_path"`
-	PreviewUrl     string             `json:"preview_url"`
-	FileUrl        string             `json:"file_url"` // Added for retry
-	UseOptimizedIP bool               `json:"use_optimized_ip"`
-	Status         string             `json:"status"` // "pending", "downloading", "completed", "failed", "cancelled"
-	Progress       int                `json:"progress"`
-	TotalSize      int64              `json:"total_size"`
-	DownloadedSize int64              `json:"downloaded_size"`
-	Speed          string             `json:"speed"`
-	Error          string             `json:"error"`
-	Description    string             `json:"description"`
-	CreatedAt      string             `json:"created_at"`
-	cancelFunc     context.CancelFunc `json:"-"`
+	ID             string               `json:"id"`
+	WorkshopID     string               `json:"workshop_id"`
+	Title          string               `json:"title"`
+	Filename       string               `json:"filename"`
+	FilePath       string               `json:"file_path"`
+	PreviewUrl     string               `json:"preview_url"`
+	FileUrl        string               `json:"file_url"` // Added for retry
+	UseOptimizedIP bool                 `json:"use_optimized_ip"`
+	Status         string               `json:"status"` // "pending", "downloading", "insufficient_space", "completed", "failed", "cancelled"
+	Progress       int                  `json:"progress"`
+	TotalSize      int64                `json:"total_size"`
+	DownloadedSize int64                `json:"downloaded_size"`
+	Speed          string               `json:"speed"`
+	CurrentIP      string               `json:"current_ip,omitempty"`  // 当前使用的CDN IP，下载中途可能切换
+	Diagnostics    *DownloadDiagnostics `json:"diagnostics,omitempty"` // 分块下载的线程与分块调度信息
+	Error          string               `json:"error"`
+	Description    string               `json:"description"`
+	CreatedAt      string               `json:"created_at"`
+	cancelFunc     context.CancelFunc   `json:"-"`
 }
 
 // TaskManager manages download tasks
@@ -88,7 +91,7 @@ func (a *App) HasActiveDownloads() bool {
 	defer taskManager.mu.RUnlock()
 
 	for _, task := range taskManager.tasks {
-		if task.Status == "downloading" || task.Status == "pending" {
+		if task.Status == "downloading" || task.Status == "pending" || task.Status == "insufficient_space" {
 			return true
 		}
 	}
@@ -99,7 +102,7 @@ func (a *App) HasActiveDownloads() bool {
 func (a *App) CancelDownloadTask(taskID string) {
 	taskManager.mu.Lock()
 	task, exists := taskManager.tasks[taskID]
-	if exists && task.cancelFunc != nil && (task.Status == "pending" || task.Status == "downloading") {
+	if exists && task.cancelFunc != nil && (task.Status == "pending" || task.Status == "downloading" || task.Status == "insufficient_space") {
 		task.cancelFunc()
 		task.Status = "cancelled"
 		task.Error = "Cancelled by user"
@@ -111,15 +114,21 @@ func (a *App) CancelDownloadTask(taskID string) {
 	}
 }
 
-// RetryDownloadTask retries a failed or cancelled task
+// RetryDownloadTask retries a failed or cancelled task.
+// Tasks held for disk space re-check the free space immediately instead.
 func (a *App) RetryDownloadTask(taskID string) {
 	taskManager.mu.Lock()
 	task, exists := taskManager.tasks[taskID]
+	held := exists && task.Status == "insufficient_space"
 	taskManager.mu.Unlock()
 
 	if !exists {
 		return
 	}
+	if held {
+		spaceReservations.requestRecheck(taskID)
+		return
+	}
 
 	// Only retry if failed or cancelled
 	if task.Status != "failed" && task.Status != "cancelled" {
@@ -134,6 +143,8 @@ func (a *App) RetryDownloadTask(taskID string) {
 	task.Error = ""
 	task.Speed = ""
 	task.FilePath = ""
+	task.CurrentIP = ""
+	task.Diagnostics = nil
 
 	// Create new context
 	ctx, cancel := context.WithCancel(context.Background())
@@ -363,34 +374,42 @@ func buildWorkshopDetailsGroup(rootID string, main WorkshopFileDetails, children
 }
 
 func (a *App) fetchWorkshopDetails(payload string) ([]WorkshopFileDetails, error) {
-	apiUrl := "https://l4d2-workshop-parse.laoyutang.cn"
+	var lastErr error
+	for _, apiUrl := range a.endpointCandidates(endpointWorkshopParse) {
+		details, statusCode, err := fetchWorkshopDetailsFrom(apiUrl, payload)
+		if !shouldFallbackEndpoint(statusCode, err) {
+			return details, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
 
+func fetchWorkshopDetailsFrom(apiUrl string, payload string) ([]WorkshopFileDetails, int, error) {
 	req, err := http.NewRequest("POST", apiUrl, bytes.NewBuffer([]byte(payload)))
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
 
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
+	client := network.NewHTTPClient(30 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status: %d", resp.StatusCode)
+		return nil, resp.StatusCode, fmt.Errorf("API request failed with status: %d", resp.StatusCode)
 	}
 
 	var details []WorkshopFileDetails
 	if err := json.NewDecoder(resp.Body).Decode(&details); err != nil {
-		return nil, err
+		return nil, resp.StatusCode, err
 	}
 
-	return details, nil
+	return details, resp.StatusCode, nil
 }
 
 func (a *App) processDetails(details []WorkshopFileDetails) ([]WorkshopFileDetails, error) {
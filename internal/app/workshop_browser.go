@@ -1,16 +1,16 @@
 package app
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
-	"net"
 	"net/http"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"vpk-manager/internal/network"
+
 	"github.com/go-resty/resty/v2"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
@@ -109,19 +109,10 @@ var (
 
 func getWorkshopClient() *resty.Client {
 	workshopClientOnce.Do(func() {
-		dialer := &net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}
-		transport := &http.Transport{
-			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
-				return dialer.DialContext(ctx, "tcp4", addr)
-			},
-		}
 		workshopClient = resty.New()
 		workshopClient.SetTimeout(15 * time.Second)
 		workshopClient.SetRetryCount(2)
-		workshopClient.SetTransport(transport)
+		workshopClient.SetTransport(network.NewTransport(network.TransportOptions{}))
 	})
 	return workshopClient
 }
@@ -179,7 +170,7 @@ func (a *App) FetchWorkshopList(opts WorkshopQueryOptions) (WorkshopListResult,
 	fmt.Printf("[Workshop] Fetching List: Page=%d, Q=%s, Sort=%s, FileType=%s\n", opts.Page, opts.SearchText, opts.Sort, opts.FileType)
 
 	// 发起请求到 Cloudflare Worker (Path: /list)
-	resp, err := req.Get(WorkshopWorkerURL + "/list")
+	resp, err := a.getFromWorkshopWorker(req, "/list")
 
 	if err != nil {
 		runtime.LogErrorf(a.ctx, "Failed to fetch workshop list: %v", err)
@@ -233,7 +224,7 @@ func (a *App) FetchWorkshopDetail(id string) (WorkshopItemDetail, error) {
 		SetQueryParam("id", id).
 		SetResult(&SteamDetailResponse{})
 
-	resp, err := req.Get(WorkshopWorkerURL + "/detail")
+	resp, err := a.getFromWorkshopWorker(req, "/detail")
 	if err != nil {
 		return WorkshopItemDetail{}, err
 	}
@@ -264,6 +255,24 @@ func (a *App) FetchWorkshopDetail(id string) (WorkshopItemDetail, error) {
 	return item, nil
 }
 
+// getFromWorkshopWorker 依次尝试自定义和默认的 Worker 地址
+func (a *App) getFromWorkshopWorker(req *resty.Request, path string) (*resty.Response, error) {
+	var resp *resty.Response
+	var err error
+	for _, baseURL := range a.endpointCandidates(endpointWorkshopWorker) {
+		resp, err = req.Get(baseURL + path)
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode()
+		}
+		if !shouldFallbackEndpoint(statusCode, err) {
+			return resp, nil
+		}
+		fmt.Printf("[Workshop] Worker %s failed, trying next endpoint\n", baseURL)
+	}
+	return resp, err
+}
+
 func (a *App) processWorkshopImage(url string) string {
 	if a.GetWorkshopPreferredIP() && a.proxyServer != nil {
 		return a.proxyServer.GetProxyUrl(url)
@@ -14,7 +14,6 @@ import (
 	"io"
 	"log"
 	"mime"
-	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -106,6 +105,14 @@ func (a *App) processDownloadTask(ctx context.Context, task *DownloadTask, downl
 		task.UseOptimizedIP = true
 	}
 
+	// 代理接管该主机且策略为 skip 时，优选IP不会生效，直接跳过测速
+	if task.UseOptimizedIP {
+		if u, err := url.Parse(downloadUrl); err == nil && !network.PinnedIPAllowed(u.Hostname()) {
+			fmt.Printf("[Download] Proxy handles %s, skipping IP optimization\n", u.Hostname())
+			task.UseOptimizedIP = false
+		}
+	}
+
 	if task.UseOptimizedIP {
 		updateStatus("selecting_ip", "")
 		// 使用 proxy.go 相同的优选IP获取逻辑
@@ -114,6 +121,9 @@ func (a *App) processDownloadTask(ctx context.Context, task *DownloadTask, downl
 		if bestIP == "" {
 			bestIP = network.GlobalIPSelector.GetBestIP(downloadUrl)
 		}
+		taskManager.mu.Lock()
+		task.CurrentIP = bestIP
+		taskManager.mu.Unlock()
 		updateStatus("downloading", "")
 	}
 
@@ -136,6 +146,14 @@ func (a *App) processDownloadTask(ctx context.Context, task *DownloadTask, downl
 		}
 	}
 
+	// Reserve disk space for the file (and its extraction) before writing anything.
+	// Tasks that don't fit are held as insufficient_space until space frees up.
+	if !a.waitForDiskSpace(ctx, task, updateStatus) {
+		updateStatus("cancelled", "Cancelled by user")
+		return
+	}
+	defer spaceReservations.release(task.ID)
+
 	// Calculate optimal thread count based on file size (max 8 threads)
 	threadCount := network.CalculateThreadCount(totalSize, 8)
 
@@ -149,6 +167,11 @@ func (a *App) processDownloadTask(ctx context.Context, task *DownloadTask, downl
 			// Check if server does not support Range, fallback to single-thread
 			if errors.Is(err, errRangeNotSupported) {
 				fmt.Printf("[Download] Server does not support Range, falling back to single-thread download\n")
+				// The preallocated file was removed; reserve space as if nothing was written
+				taskManager.mu.Lock()
+				task.DownloadedSize = 0
+				taskManager.mu.Unlock()
+				spaceReservations.setPreallocated(task.ID, false)
 				// Continue to single-thread download below
 			} else if ctx.Err() != nil {
 				updateStatus("cancelled", "Cancelled by user")
@@ -234,39 +257,10 @@ func (a *App) processDownloadTask(ctx context.Context, task *DownloadTask, downl
 	}()
 
 	// Use a transport with timeouts and keep-alive
-	transport := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
-		ForceAttemptHTTP2:     true,
-		MaxIdleConns:          100,
-		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   15 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
-		ResponseHeaderTimeout: 60 * time.Second, // Increased timeout
-	}
-
-	if task.UseOptimizedIP {
-		if bestIP != "" {
-			dialer := &net.Dialer{
-				Timeout:   30 * time.Second,
-				KeepAlive: 30 * time.Second,
-			}
-			transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
-				host, port, _ := net.SplitHostPort(addr)
-
-				// Check if the host matches the download URL's host
-				u, parseErr := url.Parse(downloadUrl)
-				if parseErr == nil && u.Hostname() == host {
-					return dialer.DialContext(ctx, network, net.JoinHostPort(bestIP, port))
-				}
-				return dialer.DialContext(ctx, network, addr)
-			}
-			fmt.Printf("[Download] Using optimized IP: %s for %s\n", bestIP, downloadUrl)
-		}
+	if task.UseOptimizedIP && bestIP != "" {
+		fmt.Printf("[Download] Using optimized IP: %s for %s\n", bestIP, downloadUrl)
 	}
+	transport := buildDownloadTransport(bestIP, downloadUrl)
 
 	client := &http.Client{
 		Transport: transport,
@@ -446,9 +440,7 @@ func (a *App) processDownloadTask(ctx context.Context, task *DownloadTask, downl
 		// 同步下载图片，确保任务完成时图片已就绪
 		// 设置较短的超时，避免长时间阻塞
 		func(url, vpkPath string) {
-			client := &http.Client{
-				Timeout: 10 * time.Second,
-			}
+			client := network.NewHTTPClient(10 * time.Second)
 			resp, err := client.Get(url)
 			if err != nil {
 				return
@@ -9,7 +9,6 @@ import (
 	_ "image/jpeg"
 	_ "image/png"
 	"io"
-	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -18,10 +17,14 @@ import (
 	"sync"
 	"time"
 
+	"vpk-manager/internal/network"
+
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
-func (a *App) processChunkedDownload(ctx context.Context, task *DownloadTask, downloadUrl string, bestIP string, totalSize int64, workerCount int, tempDir string) (string, error) {
+// processChunkedDownload downloads with up to maxWorkers parallel workers. The engine starts
+// below the cap and scales the worker count based on measured throughput.
+func (a *App) processChunkedDownload(ctx context.Context, task *DownloadTask, downloadUrl string, bestIP string, totalSize int64, maxWorkers int, tempDir string) (string, error) {
 	finalPath := filepath.Join(tempDir, task.ID+"_final")
 
 	// 1. Preallocate final file
@@ -29,8 +32,11 @@ func (a *App) processChunkedDownload(ctx context.Context, task *DownloadTask, do
 	if err != nil {
 		return "", err
 	}
+	spaceReservations.setPreallocated(task.ID, true)
 
-	// 2. Create BlockManager with 5MB blocks
+	// 2. Create BlockManager with 5MB base blocks (shrinking near the end of the file)
+	scaler := newWorkerScaler(maxWorkers)
+	workerCount := scaler.initialWorkers()
 	bm := NewBlockManager(totalSize, workerCount, 5*1024*1024)
 
 	// 3. Link external context cancellation to BlockManager
@@ -39,50 +45,85 @@ func (a *App) processChunkedDownload(ctx context.Context, task *DownloadTask, do
 		bm.cancel()
 	}()
 
-	fmt.Printf("[ChunkedDownload] Starting dynamic %d-worker download for %s (Size: %.2f MB, Blocks: %d)\n",
-		workerCount, task.Filename, float64(totalSize)/1024/1024, len(bm.blocks))
+	fmt.Printf("[ChunkedDownload] Starting adaptive download for %s with %d/%d workers (Size: %.2f MB, Block: %.2f MB)\n",
+		task.Filename, workerCount, maxWorkers, float64(totalSize)/1024/1024, float64(bm.blockSize)/1024/1024)
+	updateTaskDiagnostics(task, func(d *DownloadDiagnostics) {
+		d.Workers = workerCount
+		d.MaxWorkers = maxWorkers
+		d.BlockSize = bm.blockSize
+	})
+	addTaskDiagnosticEvent(task, "以 %d 个线程开始下载，上限 %d", workerCount, maxWorkers)
+
+	// 4. Prepare CDN IP failover shared by all workers
+	failover := newIPFailover(bestIP, network.GlobalIPSelector.FallbackIPs(bestIP), func(from, to string) {
+		fmt.Printf("[ChunkedDownload] IP %s stalled or failing for %s, switching to %s\n", from, task.Filename, to)
+		network.GlobalIPSelector.ReplaceCachedBestIP(from, to)
+		taskManager.mu.Lock()
+		task.CurrentIP = to
+		taskManager.mu.Unlock()
+		addTaskDiagnosticEvent(task, "IP %s 卡顿或连续失败，切换到 %s", from, to)
+		runtime.EventsEmit(a.ctx, "task_updated", task)
+	})
+	defer func() {
+		if err := network.GlobalIPHealth.Save(); err != nil {
+			fmt.Printf("[ChunkedDownload] Failed to save IP health history: %v\n", err)
+		}
+	}()
 
-	// 4. Start progress reporter
+	// 5. Start progress reporter
 	stopReporter := make(chan struct{})
 	go a.progressReporter(bm, task, stopReporter)
 
-	// 5. Start workers
+	// 6. Start workers and the throughput-based scaler
 	var wg sync.WaitGroup
-	for i := range workerCount {
+	nextWorkerID := 0
+	startWorker := func() {
+		bm.activeWorkers.Add(1)
 		wg.Add(1)
 		go func(id int) {
 			defer wg.Done()
-			a.downloadWorker(id, bm, file, downloadUrl, bestIP)
-		}(i)
+			a.downloadWorker(id, bm, file, downloadUrl, failover)
+		}(nextWorkerID)
+		nextWorkerID++
+	}
+	for range workerCount {
+		startWorker()
 	}
 
-	// 6. Wait for all workers to finish
+	// The scaler holds its own WaitGroup slot so workers are never added after Wait returns
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		a.scaleDownloadWorkers(bm, scaler, task, startWorker)
+	}()
+
+	// 7. Wait for all workers to finish
 	wg.Wait()
 	close(stopReporter)
 
-	// 7. Close file
+	// 8. Close file
 	file.Close()
 
-	// 8. Check for cancellation
+	// 9. Check for cancellation
 	if ctx.Err() != nil {
 		os.Remove(finalPath)
 		return "", ctx.Err()
 	}
 
-	// 9. Check for fatal errors
+	// 10. Check for fatal errors
 	if fatalErr := bm.HasFatalError(); fatalErr != nil {
 		os.Remove(finalPath)
 		return "", fatalErr
 	}
 
-	// 10. Verify final file size
+	// 11. Verify final file size
 	stat, err := os.Stat(finalPath)
 	if err != nil || stat.Size() != totalSize {
 		os.Remove(finalPath)
 		return "", fmt.Errorf("final size mismatch: expected %d, got %d", totalSize, stat.Size())
 	}
 
-	// 11. Emit final progress
+	// 12. Emit final progress
 	taskManager.mu.Lock()
 	task.DownloadedSize = totalSize
 	task.Progress = 100
@@ -94,31 +135,60 @@ func (a *App) processChunkedDownload(ctx context.Context, task *DownloadTask, do
 	return finalPath, nil
 }
 
-// getFileSize gets file size via HEAD request
-func (a *App) getFileSize(ctx context.Context, downloadUrl string, bestIP string) int64 {
-	transport := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout:   10 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
-		TLSHandshakeTimeout: 10 * time.Second,
+// scaleDownloadWorkers samples total throughput every scaleInterval and adjusts the worker
+// count. It returns once the file is fully assigned, the download is cancelled, or all
+// workers have exited.
+func (a *App) scaleDownloadWorkers(bm *BlockManager, scaler *workerScaler, task *DownloadTask, startWorker func()) {
+	ticker := time.NewTicker(scaleInterval)
+	defer ticker.Stop()
+
+	lastBytes := bm.completedBytes.Load()
+	lastTime := time.Now()
+	for {
+		select {
+		case <-bm.ctx.Done():
+			return
+		case now := <-ticker.C:
+			remaining := bm.RemainingBytes()
+			active := int(bm.activeWorkers.Load())
+			if remaining == 0 || active == 0 {
+				return
+			}
+
+			currentBytes := bm.completedBytes.Load()
+			throughput := float64(max(currentBytes-lastBytes, 0)) / now.Sub(lastTime).Seconds()
+			lastBytes, lastTime = currentBytes, now
+
+			target, reason := scaler.decide(active, throughput, remaining > bm.blockSize)
+			bm.targetWorkers.Store(int32(target))
+			for range target - active {
+				startWorker()
+			}
+
+			updateTaskDiagnostics(task, func(d *DownloadDiagnostics) {
+				d.Workers = target
+				d.BlockSize = bm.LastBlockSize()
+				d.Throughput = formatSpeed(throughput)
+				d.PerWorker = formatSpeed(throughput / float64(active))
+			})
+			if reason != "" {
+				fmt.Printf("[ChunkedDownload] %s: %s\n", task.Filename, reason)
+				addTaskDiagnosticEvent(task, "%s", reason)
+			}
+		}
 	}
+}
 
+// getFileSize gets file size via HEAD request
+func (a *App) getFileSize(ctx context.Context, downloadUrl string, bestIP string) int64 {
+	options := network.TransportOptions{DialTimeout: 10 * time.Second}
 	if bestIP != "" {
-		dialer := &net.Dialer{
-			Timeout:   10 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}
-		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
-			host, port, _ := net.SplitHostPort(addr)
-			u, parseErr := url.Parse(downloadUrl)
-			if parseErr == nil && u.Hostname() == host {
-				return dialer.DialContext(ctx, network, net.JoinHostPort(bestIP, port))
-			}
-			return dialer.DialContext(ctx, network, addr)
+		if u, err := url.Parse(downloadUrl); err == nil {
+			options.PinnedIP = bestIP
+			options.PinnedHost = u.Hostname()
 		}
 	}
+	transport := network.NewTransport(options)
 
 	client := &http.Client{
 		Transport: transport,
@@ -152,7 +222,7 @@ func (a *App) downloadPreviewImage(task *DownloadTask, targetPath string) {
 		return
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := network.NewHTTPClient(10 * time.Second)
 	resp, err := client.Get(task.PreviewUrl)
 	if err != nil {
 		return
@@ -14,6 +14,8 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"vpk-manager/internal/network"
 )
 
 const (
@@ -28,7 +30,7 @@ const (
 var (
 	yandexTranslateBaseURL    = "https://translate.yandex.net/api/v1/tr.json"
 	microsoftTranslateBaseURL = "https://api.cognitive.microsofttranslator.com"
-	workshopTranslateClient   = &http.Client{Timeout: 15 * time.Second}
+	workshopTranslateClient   = network.NewHTTPClient(15 * time.Second)
 )
 
 type WorkshopTranslationResult struct {
@@ -81,7 +83,7 @@ func (a *App) TranslateWorkshopDescription(text string) (WorkshopTranslationResu
 	provider := a.GetWorkshopTranslateProvider()
 	switch provider {
 	case workshopTranslateProviderYandex:
-		translated, err := translateWorkshopDescriptionWithYandex(text)
+		translated, err := translateWithEndpointFallback(a.endpointCandidates(endpointYandexTranslate), text, translateWorkshopDescriptionWithYandex)
 		if err != nil {
 			return WorkshopTranslationResult{}, err
 		}
@@ -93,7 +95,7 @@ func (a *App) TranslateWorkshopDescription(text string) (WorkshopTranslationResu
 		}
 		return WorkshopTranslationResult{Provider: workshopTranslateProviderCustom, Text: translated}, nil
 	default:
-		translated, err := translateWorkshopDescriptionWithMicrosoft(text)
+		translated, err := translateWithEndpointFallback(a.endpointCandidates(endpointMicrosoftTranslate), text, translateWorkshopDescriptionWithMicrosoft)
 		if err != nil {
 			return WorkshopTranslationResult{}, err
 		}
@@ -101,8 +103,21 @@ func (a *App) TranslateWorkshopDescription(text string) (WorkshopTranslationResu
 	}
 }
 
-func translateWorkshopDescriptionWithYandex(text string) (string, error) {
-	sourceLang := detectWorkshopDescriptionYandexLanguage(text)
+// translateWithEndpointFallback 依次使用候选地址翻译，自定义地址失败时回退到默认地址
+func translateWithEndpointFallback(baseURLs []string, text string, translate func(baseURL string, text string) (string, error)) (string, error) {
+	var lastErr error
+	for _, baseURL := range baseURLs {
+		translated, err := translate(baseURL, text)
+		if err == nil {
+			return translated, nil
+		}
+		lastErr = err
+	}
+	return "", lastErr
+}
+
+func translateWorkshopDescriptionWithYandex(baseURL string, text string) (string, error) {
+	sourceLang := detectWorkshopDescriptionYandexLanguage(baseURL, text)
 	if sourceLang == workshopTranslateTargetYandex {
 		return text, nil
 	}
@@ -110,7 +125,7 @@ func translateWorkshopDescriptionWithYandex(text string) (string, error) {
 		sourceLang = "en"
 	}
 
-	endpoint := strings.TrimRight(yandexTranslateBaseURL, "/") + "/translate"
+	endpoint := strings.TrimRight(baseURL, "/") + "/translate"
 	values := url.Values{}
 	values.Set("ucid", newWorkshopTranslateUCID())
 	values.Set("srv", "android")
@@ -158,8 +173,8 @@ func translateWorkshopDescriptionWithYandex(text string) (string, error) {
 	return result.Text[0], nil
 }
 
-func detectWorkshopDescriptionYandexLanguage(text string) string {
-	endpoint := strings.TrimRight(yandexTranslateBaseURL, "/") + "/detect"
+func detectWorkshopDescriptionYandexLanguage(baseURL string, text string) string {
+	endpoint := strings.TrimRight(baseURL, "/") + "/detect"
 	values := url.Values{}
 	values.Set("id", newWorkshopTranslateUCID()+"-0-0")
 	values.Set("srv", "android")
@@ -225,8 +240,8 @@ func normalizeYandexDetectedLanguage(lang string) string {
 	return lang
 }
 
-func translateWorkshopDescriptionWithMicrosoft(text string) (string, error) {
-	endpoint := strings.TrimRight(microsoftTranslateBaseURL, "/") + "/translate?api-version=3.0&to=" + url.QueryEscape(workshopTranslateTargetMicrosoft)
+func translateWorkshopDescriptionWithMicrosoft(baseURL string, text string) (string, error) {
+	endpoint := strings.TrimRight(baseURL, "/") + "/translate?api-version=3.0&to=" + url.QueryEscape(workshopTranslateTargetMicrosoft)
 	signaturePath, err := signaturePathFromURL(endpoint)
 	if err != nil {
 		return "", err
@@ -381,7 +396,7 @@ func (a *App) translateWorkshopDescriptionWithCustom(text string) (string, error
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+apiKey)
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := network.NewHTTPClient(30 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", err
@@ -0,0 +1,212 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// ipBadFailureThreshold 连续失败达到该次数的IP视为不可用
+	ipBadFailureThreshold = 3
+	// ipBadRetention 不可用IP的屏蔽时长，过期后重新参与测速
+	ipBadRetention = 24 * time.Hour
+	// ipSpeedSmoothing 平均速度的指数平滑系数，越大越偏向最近一次结果
+	ipSpeedSmoothing = 0.3
+)
+
+// IPHealthRecord 单个IP的历史成功率与速度
+type IPHealthRecord struct {
+	IP                  string    `json:"ip"`
+	Successes           int       `json:"successes"`
+	Failures            int       `json:"failures"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	AvgSpeed            float64   `json:"avg_speed"` // MB/s，指数平滑
+	LastSuccess         time.Time `json:"last_success,omitempty"`
+	LastFailure         time.Time `json:"last_failure,omitempty"`
+}
+
+// IPHealthStore 记录各IP的下载/测速历史，持久化到磁盘以便重启后沿用
+type IPHealthStore struct {
+	path    string
+	records map[string]*IPHealthRecord
+	dirty   bool
+	now     func() time.Time
+	mu      sync.Mutex
+}
+
+// GlobalIPHealth 全局IP健康记录
+var GlobalIPHealth = NewIPHealthStore()
+
+func NewIPHealthStore() *IPHealthStore {
+	return &IPHealthStore{
+		records: make(map[string]*IPHealthRecord),
+		now:     time.Now,
+	}
+}
+
+// Load 设置持久化路径并读取历史记录，文件不存在时从空记录开始
+func (h *IPHealthStore) Load(path string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.path = path
+	h.records = make(map[string]*IPHealthRecord)
+	h.dirty = false
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var records []IPHealthRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return fmt.Errorf("解析IP健康记录失败: %w", err)
+	}
+	for _, record := range records {
+		ip := NormalizeIP(record.IP)
+		if ip == "" {
+			continue
+		}
+		record.IP = ip
+		h.records[ip] = &record
+	}
+	return nil
+}
+
+// Save 将有变化的记录写回磁盘
+func (h *IPHealthStore) Save() error {
+	h.mu.Lock()
+	if !h.dirty || h.path == "" {
+		h.mu.Unlock()
+		return nil
+	}
+	records := h.snapshotLocked()
+	path := h.path
+	h.dirty = false
+	h.mu.Unlock()
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (h *IPHealthStore) recordLocked(ip string) *IPHealthRecord {
+	ip = NormalizeIP(ip)
+	record, ok := h.records[ip]
+	if !ok {
+		record = &IPHealthRecord{IP: ip}
+		h.records[ip] = record
+	}
+	h.dirty = true
+	return record
+}
+
+// RecordSuccess 记录一次成功的传输，speed 单位为 MB/s（为 0 时不更新平均速度）
+func (h *IPHealthStore) RecordSuccess(ip string, speed float64) {
+	if ip == "" {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	record := h.recordLocked(ip)
+	record.Successes++
+	record.ConsecutiveFailures = 0
+	record.LastSuccess = h.now()
+	if speed > 0 {
+		if record.AvgSpeed == 0 {
+			record.AvgSpeed = speed
+		} else {
+			record.AvgSpeed = record.AvgSpeed*(1-ipSpeedSmoothing) + speed*ipSpeedSmoothing
+		}
+	}
+}
+
+// RecordFailure 记录一次失败（连接失败、测速失败、下载卡顿等）
+func (h *IPHealthStore) RecordFailure(ip string) {
+	if ip == "" {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	record := h.recordLocked(ip)
+	record.Failures++
+	record.ConsecutiveFailures++
+	record.LastFailure = h.now()
+}
+
+// IsKnownBad 判断IP是否近期连续失败，优选时可直接跳过
+func (h *IPHealthStore) IsKnownBad(ip string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	record, ok := h.records[NormalizeIP(ip)]
+	if !ok {
+		return false
+	}
+	return record.ConsecutiveFailures >= ipBadFailureThreshold && h.now().Sub(record.LastFailure) < ipBadRetention
+}
+
+// FilterKnownBad 过滤掉已知不可用的IP。若全部不可用则原样返回，避免无IP可选。
+func (h *IPHealthStore) FilterKnownBad(ips []string) (usable []string, skipped []string) {
+	for _, ip := range ips {
+		if h.IsKnownBad(ip) {
+			skipped = append(skipped, ip)
+		} else {
+			usable = append(usable, ip)
+		}
+	}
+	if len(usable) == 0 {
+		return ips, nil
+	}
+	return usable, skipped
+}
+
+// AvgSpeed 返回IP的历史平均速度，没有记录时返回 0
+func (h *IPHealthStore) AvgSpeed(ip string) float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if record, ok := h.records[NormalizeIP(ip)]; ok {
+		return record.AvgSpeed
+	}
+	return 0
+}
+
+// Snapshot 返回所有记录，按平均速度从高到低排列
+func (h *IPHealthStore) Snapshot() []IPHealthRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.snapshotLocked()
+}
+
+func (h *IPHealthStore) snapshotLocked() []IPHealthRecord {
+	records := make([]IPHealthRecord, 0, len(h.records))
+	for _, record := range h.records {
+		records = append(records, *record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].AvgSpeed != records[j].AvgSpeed {
+			return records[i].AvgSpeed > records[j].AvgSpeed
+		}
+		return records[i].IP < records[j].IP
+	})
+	return records
+}
+
+// Clear 清空所有历史记录
+func (h *IPHealthStore) Clear() error {
+	h.mu.Lock()
+	h.records = make(map[string]*IPHealthRecord)
+	h.dirty = true
+	h.mu.Unlock()
+	return h.Save()
+}
@@ -0,0 +1,111 @@
+package network
+
+import (
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func useIPHealthStoreForTest(t *testing.T) *IPHealthStore {
+	t.Helper()
+	previous := GlobalIPHealth
+	GlobalIPHealth = NewIPHealthStore()
+	t.Cleanup(func() { GlobalIPHealth = previous })
+	return GlobalIPHealth
+}
+
+func TestIPHealthStoreMarksConsecutiveFailuresAsBad(t *testing.T) {
+	store := NewIPHealthStore()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	for i := 0; i < ipBadFailureThreshold; i++ {
+		store.RecordFailure("23.59.72.59")
+	}
+	if !store.IsKnownBad("23.59.72.59") {
+		t.Fatalf("expected IP to be known bad after %d failures", ipBadFailureThreshold)
+	}
+
+	usable, skipped := store.FilterKnownBad([]string{"23.59.72.59", "23.59.72.42"})
+	if len(usable) != 1 || usable[0] != "23.59.72.42" || len(skipped) != 1 {
+		t.Fatalf("unexpected filter result: usable=%v skipped=%v", usable, skipped)
+	}
+	if usable, _ := store.FilterKnownBad([]string{"23.59.72.59"}); len(usable) != 1 {
+		t.Fatalf("expected all-bad list to be returned unchanged, got %v", usable)
+	}
+
+	now = now.Add(ipBadRetention + time.Minute)
+	if store.IsKnownBad("23.59.72.59") {
+		t.Fatalf("expected bad mark to expire after retention")
+	}
+
+	now = now.Add(-ipBadRetention)
+	store.RecordSuccess("23.59.72.59", 4)
+	if store.IsKnownBad("23.59.72.59") {
+		t.Fatalf("expected success to reset consecutive failures")
+	}
+}
+
+func TestIPHealthStorePersistsAcrossLoads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ip_health.json")
+	store := NewIPHealthStore()
+	if err := store.Load(path); err != nil {
+		t.Fatalf("load missing file: %v", err)
+	}
+	store.RecordSuccess("[2001:db8::1]", 6)
+	store.RecordSuccess("2001:db8::1", 2)
+	store.RecordFailure("23.59.72.42")
+	if err := store.Save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	reloaded := NewIPHealthStore()
+	if err := reloaded.Load(path); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	records := reloaded.Snapshot()
+	if len(records) != 2 || records[0].IP != "2001:db8::1" || records[0].Successes != 2 {
+		t.Fatalf("unexpected reloaded records: %#v", records)
+	}
+	if want := 6*(1-ipSpeedSmoothing) + 2*ipSpeedSmoothing; math.Abs(records[0].AvgSpeed-want) > 1e-9 {
+		t.Fatalf("expected smoothed speed %.2f, got %.2f", want, records[0].AvgSpeed)
+	}
+	if records[1].Failures != 1 || records[1].ConsecutiveFailures != 1 {
+		t.Fatalf("expected failure to persist, got %#v", records[1])
+	}
+}
+
+func TestRankTestedIPsOrdersBySpeedThenLatency(t *testing.T) {
+	ranking := rankTestedIPs(
+		[]string{"a", "b", "c", "d", "e"},
+		map[string]float64{"b": 3, "c": 8},
+		map[string]bool{"a": true},
+	)
+	if got := strings.Join(ranking, ","); got != "c,b,d,e,a" {
+		t.Fatalf("unexpected ranking: %s", got)
+	}
+}
+
+func TestFallbackIPsSkipsCurrentAndKnownBad(t *testing.T) {
+	health := useIPHealthStoreForTest(t)
+	for i := 0; i < ipBadFailureThreshold; i++ {
+		health.RecordFailure("23.59.72.42")
+	}
+
+	selector := &IPSelector{}
+	selector.mu.Lock()
+	selector.cachedRanking = []string{"23.59.72.59", "23.59.72.42", "23.55.51.221"}
+	selector.mu.Unlock()
+	if got := strings.Join(selector.FallbackIPs("23.59.72.59"), ","); got != "23.55.51.221" {
+		t.Fatalf("unexpected fallbacks from ranking: %s", got)
+	}
+
+	fixed := &IPSelector{}
+	fixed.setIPOptionsForTest([]IPOption{{IP: "23.59.72.59"}, {IP: "23.67.75.74"}, {IP: "23.206.175.162"}})
+	health.RecordSuccess("23.206.175.162", 9)
+	if got := strings.Join(fixed.FallbackIPs("23.59.72.59"), ","); got != "23.206.175.162,23.67.75.74" {
+		t.Fatalf("expected history-ordered fallbacks, got %s", got)
+	}
+}
@@ -52,11 +52,28 @@ func decodeIPOptions(body io.Reader) ([]IPOption, error) {
 	return normalizeIPOptions(options), nil
 }
 
+// NormalizeIP 规范化 IPv4/IPv6 地址，去除 IPv6 的方括号并统一为标准写法。
+// 无法解析的输入原样返回（去除首尾空白）。
+func NormalizeIP(raw string) string {
+	ip := strings.TrimSpace(raw)
+	ip = strings.TrimSuffix(strings.TrimPrefix(ip, "["), "]")
+	if parsed := net.ParseIP(ip); parsed != nil {
+		return parsed.String()
+	}
+	return strings.TrimSpace(raw)
+}
+
+// isIPv6 判断地址是否为 IPv6（IPv4 映射地址视为 IPv4）
+func isIPv6(ip string) bool {
+	parsed := net.ParseIP(ip)
+	return parsed != nil && parsed.To4() == nil
+}
+
 func normalizeIPOptions(options []IPOption) []IPOption {
 	normalized := make([]IPOption, 0, len(options))
 	seen := make(map[string]struct{}, len(options))
 	for _, option := range options {
-		ip := strings.TrimSpace(option.IP)
+		ip := NormalizeIP(option.IP)
 		if ip == "" || net.ParseIP(ip) == nil {
 			continue
 		}
@@ -82,16 +99,50 @@ func ipOptionsToIPs(options []IPOption) []string {
 	return ips
 }
 
+// DefaultIPListURL 内置的 IP 列表服务地址
+const DefaultIPListURL = "https://lytvpk-get-ips.laoyutang.cn"
+
+var (
+	ipListURL   string
+	ipListURLMu sync.RWMutex
+)
+
+// SetIPListURL 设置自定义 IP 列表服务地址，留空则使用默认地址
+func SetIPListURL(rawURL string) {
+	ipListURLMu.Lock()
+	defer ipListURLMu.Unlock()
+	ipListURL = strings.TrimRight(strings.TrimSpace(rawURL), "/")
+}
+
+// ipListURLCandidates 返回按优先级排列的 IP 列表服务地址，自定义地址失败时回退到默认地址
+func ipListURLCandidates() []string {
+	ipListURLMu.RLock()
+	custom := ipListURL
+	ipListURLMu.RUnlock()
+	if custom == "" || custom == DefaultIPListURL {
+		return []string{DefaultIPListURL}
+	}
+	return []string{custom, DefaultIPListURL}
+}
+
 func fetchRemoteIPOptions(domain string) ([]IPOption, error) {
-	apiURL := fmt.Sprintf("https://lytvpk-get-ips.laoyutang.cn/?domain=%s&format=entries", url.QueryEscape(domain))
-	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	var lastErr error
+	for _, baseURL := range ipListURLCandidates() {
+		options, err := fetchRemoteIPOptionsFrom(baseURL, domain)
+		if err == nil {
+			return options, nil
+		}
+		fmt.Printf("[IPSelector] IP list from %s failed: %v\n", baseURL, err)
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+func fetchRemoteIPOptionsFrom(baseURL string, domain string) ([]IPOption, error) {
+	apiURL := fmt.Sprintf("%s/?domain=%s&format=entries", baseURL, url.QueryEscape(domain))
 	client := &http.Client{
-		Timeout: 5 * time.Second,
-		Transport: &http.Transport{
-			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
-				return dialer.DialContext(ctx, "tcp4", addr)
-			},
-		},
+		Timeout:   5 * time.Second,
+		Transport: NewTransport(TransportOptions{DialTimeout: 5 * time.Second}),
 	}
 
 	resp, err := client.Get(apiURL)
@@ -128,7 +179,8 @@ func GetSteamCDNIPOptions() []IPOption {
 type IPSelector struct {
 	cachedBestIP       string
 	cachedBestCategory string
-	cachedSpeed        float64 // Cached download speed in MB/s
+	cachedSpeed        float64  // Cached download speed in MB/s
+	cachedRanking      []string // 最近一次优选的IP排名，用于下载中途切换
 	lastCheck          time.Time
 	isSelecting        bool
 	fixedIP            string
@@ -142,6 +194,7 @@ type IPSelector struct {
 var GlobalIPSelector = &IPSelector{}
 
 func (s *IPSelector) SetFixedIP(ip string) {
+	ip = NormalizeIP(ip)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.fixedIP = ip
@@ -149,7 +202,21 @@ func (s *IPSelector) SetFixedIP(ip string) {
 		s.cachedBestIP = ""
 		s.cachedBestCategory = ""
 		s.cachedSpeed = 0
+		s.cachedRanking = nil
+	}
+}
+
+// ReplaceCachedBestIP 下载中途发现优选IP不可用并切换后，更新缓存的优选IP，
+// 使后续下载直接使用新IP。设置了固定IP时不做修改。
+func (s *IPSelector) ReplaceCachedBestIP(from string, to string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fixedIP != "" || s.cachedBestIP != from || to == "" {
+		return
 	}
+	s.cachedBestIP = to
+	s.cachedBestCategory = s.categoryForIPLocked(to)
+	s.cachedSpeed = GlobalIPHealth.AvgSpeed(to)
 }
 
 func (s *IPSelector) GetFixedIP() string {
@@ -354,13 +421,17 @@ func (s *IPSelector) refreshBestIP(testUrl string) string {
 	fmt.Printf("[IPSelector] Using %d candidate IPs\n", len(candidateOptions))
 
 	candidateIPs := ipOptionsToIPs(candidateOptions)
-	bestIP, speed := selectBestIP(candidateIPs, testUrl)
+	bestIP, speed, ranking := selectBestIP(candidateIPs, testUrl)
+	if err := GlobalIPHealth.Save(); err != nil {
+		fmt.Printf("[IPSelector] Failed to save IP health history: %v\n", err)
+	}
 
 	s.mu.Lock()
 	if bestIP != "" {
 		s.cachedBestIP = bestIP
 		s.cachedBestCategory = s.categoryForIPLocked(bestIP)
 		s.cachedSpeed = speed
+		s.cachedRanking = ranking
 		s.lastCheck = time.Now()
 	}
 	result := s.cachedBestIP
@@ -369,13 +440,49 @@ func (s *IPSelector) refreshBestIP(testUrl string) string {
 	return result
 }
 
-func selectBestIP(ips []string, testUrl string) (string, float64) {
+// FallbackIPs 返回下载中途可切换的备用IP（不含 current 与已知不可用IP）。
+// 优先使用最近一次优选的排名，没有排名时（如固定IP）按历史平均速度排列候选列表。
+func (s *IPSelector) FallbackIPs(current string) []string {
+	current = NormalizeIP(current)
+	s.mu.RLock()
+	ranking := append([]string(nil), s.cachedRanking...)
+	options := cloneIPOptions(s.ipOptions)
+	s.mu.RUnlock()
+
+	if len(ranking) == 0 {
+		if len(options) == 0 {
+			options = defaultIPOptions()
+		}
+		ranking = ipOptionsToIPs(options)
+		sort.SliceStable(ranking, func(i, j int) bool {
+			return GlobalIPHealth.AvgSpeed(ranking[i]) > GlobalIPHealth.AvgSpeed(ranking[j])
+		})
+	}
+
+	fallbacks := make([]string, 0, len(ranking))
+	for _, ip := range ranking {
+		if ip == current || GlobalIPHealth.IsKnownBad(ip) {
+			continue
+		}
+		fallbacks = append(fallbacks, ip)
+	}
+	return fallbacks
+}
+
+// selectBestIP 测速并返回最优IP、其速度以及全部可用IP的排名（测速成功的按速度，其余按延迟）
+func selectBestIP(ips []string, testUrl string) (string, float64, []string) {
 	totalStart := time.Now() // 记录总开始时间
 	type result struct {
 		ip      string
 		latency time.Duration
 	}
 
+	// 跳过历史记录中连续失败的IP，无需重新测试
+	ips, skipped := GlobalIPHealth.FilterKnownBad(ips)
+	if len(skipped) > 0 {
+		fmt.Printf("[IPSelector] Skipping %d known-bad IPs: %v\n", len(skipped), skipped)
+	}
+
 	fmt.Printf("[IPSelector] Starting ping test for %d IPs...\n", len(ips))
 
 	// 1. TCP Ping Test
@@ -411,9 +518,19 @@ func selectBestIP(ips []string, testUrl string) (string, float64) {
 	}
 PING_DONE:
 
+	reachable := make(map[string]bool, len(pingResults))
+	for _, res := range pingResults {
+		reachable[res.ip] = true
+	}
+	for _, ip := range ips {
+		if !reachable[ip] {
+			GlobalIPHealth.RecordFailure(ip)
+		}
+	}
+
 	if len(pingResults) == 0 {
 		fmt.Println("[IPSelector] No reachable IP found via Ping")
-		return "", 0.0
+		return "", 0.0, nil
 	}
 
 	// Sort by latency
@@ -427,12 +544,24 @@ PING_DONE:
 	}
 	fmt.Println("[IPSelector] --------------------------------")
 
+	// Happy Eyeballs: 按协议族交替排列，保证 IPv4/IPv6 都有机会进入测速
+	sortedIPs := make([]string, 0, len(pingResults))
+	latencyOf := make(map[string]time.Duration, len(pingResults))
+	for _, res := range pingResults {
+		sortedIPs = append(sortedIPs, res.ip)
+		latencyOf[res.ip] = res.latency
+	}
+	interleaved := interleaveIPFamilies(sortedIPs)
+
 	// Take top 5 for speed test
 	topCount := 5
-	if len(pingResults) < topCount {
-		topCount = len(pingResults)
+	if len(interleaved) < topCount {
+		topCount = len(interleaved)
+	}
+	topCandidates := make([]result, 0, topCount)
+	for _, ip := range interleaved[:topCount] {
+		topCandidates = append(topCandidates, result{ip: ip, latency: latencyOf[ip]})
 	}
-	topCandidates := pingResults[:topCount]
 
 	fmt.Printf("[IPSelector] Top %d IPs by latency: %v\n", topCount, topCandidates)
 
@@ -465,6 +594,8 @@ PING_DONE:
 
 	var bestIP string
 	var maxSpeed float64 = -1
+	speedOf := make(map[string]float64, topCount)
+	failed := make(map[string]bool, topCount)
 
 	// 等待所有结果返回，而不是一旦找到一个就认为结束（虽然逻辑是选最快，但这里是等待所有协程完成）
 	// 注意：之前的逻辑是遍历 topCount 次，每次接收一个结果。
@@ -496,9 +627,13 @@ PING_DONE:
 		case res := <-speedResults:
 			if res.err != nil {
 				fmt.Printf("[IPSelector] Speed test failed for %s: %v\n", res.ip, res.err)
+				GlobalIPHealth.RecordFailure(res.ip)
+				failed[res.ip] = true
 				continue
 			}
 			fmt.Printf("[IPSelector] %s - Speed: %.2f MB/s\n", res.ip, res.speed)
+			GlobalIPHealth.RecordSuccess(res.ip, res.speed)
+			speedOf[res.ip] = res.speed
 			if res.speed > maxSpeed {
 				maxSpeed = res.speed
 				bestIP = res.ip
@@ -509,17 +644,74 @@ PING_DONE:
 	}
 SPEED_DONE:
 
+	ranking := rankTestedIPs(interleaved, speedOf, failed)
+
 	totalDuration := time.Since(totalStart)
 	if bestIP != "" {
 		fmt.Printf("[IPSelector] Best IP selected: %s (Speed: %.2f MB/s)\n", bestIP, maxSpeed)
 		fmt.Printf("[IPSelector] Total selection time: %v\n", totalDuration)
-		return bestIP, maxSpeed
+		return bestIP, maxSpeed, ranking
 	}
 
 	// Fallback to lowest latency if speed test failed for all
 	fmt.Printf("[IPSelector] Speed test failed for all, falling back to lowest latency: %s\n", topCandidates[0].ip)
 	fmt.Printf("[IPSelector] Total selection time: %v\n", totalDuration)
-	return topCandidates[0].ip, 0.0
+	return topCandidates[0].ip, 0.0, ranking
+}
+
+// rankTestedIPs 生成IP排名：测速成功的按速度从高到低，其余可达IP保持延迟顺序，测速失败的排在最后
+func rankTestedIPs(byLatency []string, speedOf map[string]float64, failed map[string]bool) []string {
+	ranking := make([]string, 0, len(byLatency))
+	for _, ip := range byLatency {
+		if _, ok := speedOf[ip]; ok {
+			ranking = append(ranking, ip)
+		}
+	}
+	sort.SliceStable(ranking, func(i, j int) bool {
+		return speedOf[ranking[i]] > speedOf[ranking[j]]
+	})
+	for _, ip := range byLatency {
+		if _, ok := speedOf[ip]; !ok && !failed[ip] {
+			ranking = append(ranking, ip)
+		}
+	}
+	for _, ip := range byLatency {
+		if failed[ip] {
+			ranking = append(ranking, ip)
+		}
+	}
+	return ranking
+}
+
+// interleaveIPFamilies 按 RFC 8305 (Happy Eyeballs v2) 的方式交替排列 IPv4/IPv6 地址。
+// 输入已按延迟排序，输出以最快地址所属协议族开头，两族各自保持原有顺序。
+func interleaveIPFamilies(ips []string) []string {
+	if len(ips) == 0 {
+		return ips
+	}
+	var v4, v6 []string
+	for _, ip := range ips {
+		if isIPv6(ip) {
+			v6 = append(v6, ip)
+		} else {
+			v4 = append(v4, ip)
+		}
+	}
+	first, second := v4, v6
+	if isIPv6(ips[0]) {
+		first, second = v6, v4
+	}
+
+	interleaved := make([]string, 0, len(ips))
+	for i := 0; i < len(first) || i < len(second); i++ {
+		if i < len(first) {
+			interleaved = append(interleaved, first[i])
+		}
+		if i < len(second) {
+			interleaved = append(interleaved, second[i])
+		}
+	}
+	return interleaved
 }
 
 func testDownloadSpeed(ctx context.Context, ip string, downloadUrl string) (float64, error) {
@@ -607,8 +799,9 @@ func testDownloadSpeed(ctx context.Context, ip string, downloadUrl string) (floa
 	return speedMBps, nil
 }
 
-// CalculateThreadCount determines the number of parallel download workers.
-// With dynamic 5MB blocks, worker count is capped by the number of blocks
+// CalculateThreadCount determines the maximum number of parallel download workers.
+// The download engine starts below this cap and scales with measured throughput.
+// With 5MB base blocks, the cap is limited by the number of blocks
 // (no point spawning more workers than there are tasks).
 func CalculateThreadCount(fileSize int64, maxThreads int) int {
 	if maxThreads <= 0 {
@@ -98,3 +98,53 @@ func TestGetIPOptionsUsesProcessCache(t *testing.T) {
 		t.Fatalf("expected cached copy to remain intact, got %#v", second)
 	}
 }
+
+func TestIPListURLCandidatesFallBackToDefault(t *testing.T) {
+	t.Cleanup(func() { SetIPListURL("") })
+
+	if got := ipListURLCandidates(); len(got) != 1 || got[0] != DefaultIPListURL {
+		t.Fatalf("expected default only, got %#v", got)
+	}
+
+	SetIPListURL("http://127.0.0.1:9000/")
+	got := ipListURLCandidates()
+	if len(got) != 2 || got[0] != "http://127.0.0.1:9000" || got[1] != DefaultIPListURL {
+		t.Fatalf("expected custom then default, got %#v", got)
+	}
+}
+
+func TestDecodeIPOptionsAcceptsIPv6(t *testing.T) {
+	options, err := decodeIPOptions(strings.NewReader(`[
+		{"ip":"[2600:1406:3a00::17c8:4a3b]","category":"steam官方-IPv6"},
+		{"ip":"2600:1406:3a00:0:0:0:17c8:4a3b","category":"重复"},
+		{"ip":"23.59.72.59","category":"steam官方-韩国"}
+	]`))
+	if err != nil {
+		t.Fatalf("decodeIPOptions returned error: %v", err)
+	}
+	if len(options) != 2 || options[0].IP != "2600:1406:3a00::17c8:4a3b" || options[1].IP != "23.59.72.59" {
+		t.Fatalf("expected canonical IPv6 and IPv4 options, got %#v", options)
+	}
+}
+
+func TestInterleaveIPFamilies(t *testing.T) {
+	got := interleaveIPFamilies([]string{"2001:db8::1", "2001:db8::2", "2001:db8::3", "23.59.72.59", "23.59.72.42"})
+	want := []string{"2001:db8::1", "23.59.72.59", "2001:db8::2", "23.59.72.42", "2001:db8::3"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	got = interleaveIPFamilies([]string{"23.59.72.59", "23.59.72.42", "2001:db8::1"})
+	want = []string{"23.59.72.59", "2001:db8::1", "23.59.72.42"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestFixedIPNormalizesIPv6Brackets(t *testing.T) {
+	selector := &IPSelector{}
+	selector.SetFixedIP(" [2001:DB8::10] ")
+	if got := selector.GetCachedBestIP(); got != "2001:db8::10" {
+		t.Fatalf("expected normalized IPv6 fixed IP, got %q", got)
+	}
+}
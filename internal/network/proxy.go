@@ -1,7 +1,6 @@
 package network
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"net"
@@ -76,34 +75,14 @@ func (s *ImageProxyServer) handleProxy(w http.ResponseWriter, r *http.Request) {
 	// 如果没有优选IP，就直接连接
 	bestIP := s.selector.GetCachedBestIP()
 
-	transport := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
-		ForceAttemptHTTP2:     true,
-		MaxIdleConns:          100,
-		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   10 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
-	}
-
-	// 如果有优选IP，强制使用该IP连接
+	// 如果有优选IP，强制使用该IP连接（只对目标主机使用优选IP）
+	options := TransportOptions{}
 	if bestIP != "" {
-		dialer := &net.Dialer{
-			Timeout:   10 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}
-		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
-			host, port, _ := net.SplitHostPort(addr)
-			// 只对目标主机使用优选IP
-			if host == targetUrl.Hostname() {
-				return dialer.DialContext(ctx, network, net.JoinHostPort(bestIP, port))
-			}
-			return dialer.DialContext(ctx, network, addr)
-		}
+		options.DialTimeout = 10 * time.Second
+		options.PinnedIP = bestIP
+		options.PinnedHost = targetUrl.Hostname()
 	}
+	transport := NewTransport(options)
 
 	client := &http.Client{
 		Transport: transport,
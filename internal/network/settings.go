@@ -0,0 +1,349 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 代理模式
+const (
+	ProxyModeNone   = "none"   // 不使用代理，直连
+	ProxyModeSystem = "system" // 使用系统环境变量 (HTTP_PROXY / HTTPS_PROXY / NO_PROXY)
+	ProxyModeHTTP   = "http"
+	ProxyModeHTTPS  = "https"
+	ProxyModeSOCKS5 = "socks5"
+)
+
+// 优选IP与代理同时生效时的处理策略
+const (
+	// PinnedIPPolicySkip 走代理的请求不使用优选IP，由代理自行解析域名。
+	// 优选IP是在本机网络下测出来的，对代理出口不一定有效。
+	PinnedIPPolicySkip = "skip"
+	// PinnedIPPolicyDirect 命中优选IP的主机绕过代理，直连优选IP；其余流量仍走代理。
+	PinnedIPPolicyDirect = "direct"
+)
+
+// ProxyConfig 上游代理配置（运行时，密码为明文）
+type ProxyConfig struct {
+	Mode           string   `json:"mode"`
+	Host           string   `json:"host"`
+	Port           int      `json:"port"`
+	Username       string   `json:"username,omitempty"`
+	Password       string   `json:"password,omitempty"`
+	Bypass         []string `json:"bypass,omitempty"`
+	PinnedIPPolicy string   `json:"pinnedIPPolicy,omitempty"`
+}
+
+var (
+	proxyConfig   = ProxyConfig{Mode: ProxyModeSystem, PinnedIPPolicy: PinnedIPPolicySkip}
+	proxyConfigMu sync.RWMutex
+)
+
+// NormalizeProxyConfig 校验并规范化代理配置
+func NormalizeProxyConfig(config ProxyConfig) (ProxyConfig, error) {
+	config.Mode = strings.ToLower(strings.TrimSpace(config.Mode))
+	if config.Mode == "" {
+		config.Mode = ProxyModeSystem
+	}
+	config.Host = strings.TrimSpace(config.Host)
+	config.Username = strings.TrimSpace(config.Username)
+
+	switch config.Mode {
+	case ProxyModeNone, ProxyModeSystem:
+	case ProxyModeHTTP, ProxyModeHTTPS, ProxyModeSOCKS5:
+		if config.Host == "" {
+			return ProxyConfig{}, fmt.Errorf("代理地址不能为空")
+		}
+		if strings.Contains(config.Host, "://") || strings.ContainsAny(config.Host, "/@") {
+			return ProxyConfig{}, fmt.Errorf("代理地址只需填写主机名或IP: %s", config.Host)
+		}
+		if config.Port <= 0 || config.Port > 65535 {
+			return ProxyConfig{}, fmt.Errorf("代理端口无效: %d", config.Port)
+		}
+	default:
+		return ProxyConfig{}, fmt.Errorf("不支持的代理类型: %s", config.Mode)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(config.PinnedIPPolicy)) {
+	case "", PinnedIPPolicySkip:
+		config.PinnedIPPolicy = PinnedIPPolicySkip
+	case PinnedIPPolicyDirect:
+		config.PinnedIPPolicy = PinnedIPPolicyDirect
+	default:
+		return ProxyConfig{}, fmt.Errorf("不支持的优选IP策略: %s", config.PinnedIPPolicy)
+	}
+
+	bypass := make([]string, 0, len(config.Bypass))
+	for _, rule := range config.Bypass {
+		rule = strings.ToLower(strings.TrimSpace(rule))
+		if rule == "" {
+			continue
+		}
+		if strings.Contains(rule, "/") {
+			if _, _, err := net.ParseCIDR(rule); err != nil {
+				return ProxyConfig{}, fmt.Errorf("无效的绕过规则 %q: %w", rule, err)
+			}
+		}
+		bypass = append(bypass, rule)
+	}
+	config.Bypass = bypass
+	return config, nil
+}
+
+// SetProxyConfig 设置全局代理配置，对之后发起的所有请求生效
+func SetProxyConfig(config ProxyConfig) error {
+	normalized, err := NormalizeProxyConfig(config)
+	if err != nil {
+		return err
+	}
+	proxyConfigMu.Lock()
+	changed := !reflect.DeepEqual(proxyConfig, normalized)
+	proxyConfig = normalized
+	proxyConfigMu.Unlock()
+	if changed {
+		// 连接池里的连接可能是按旧配置（直连或旧代理）建立的
+		resetSharedTransports()
+	}
+	return nil
+}
+
+// GetProxyConfig 获取当前代理配置
+func GetProxyConfig() ProxyConfig {
+	proxyConfigMu.RLock()
+	defer proxyConfigMu.RUnlock()
+	config := proxyConfig
+	config.Bypass = append([]string(nil), proxyConfig.Bypass...)
+	return config
+}
+
+// proxyURL 根据配置构造代理地址，none/system 模式返回 nil
+func (c ProxyConfig) proxyURL() *url.URL {
+	switch c.Mode {
+	case ProxyModeHTTP, ProxyModeHTTPS, ProxyModeSOCKS5:
+	default:
+		return nil
+	}
+	u := &url.URL{
+		Scheme: c.Mode,
+		Host:   net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+	}
+	if c.Username != "" {
+		u.User = url.UserPassword(c.Username, c.Password)
+	}
+	return u
+}
+
+// bypasses 判断目标主机是否命中绕过规则。回环地址始终直连，
+// 以保证本地图片代理和本地测试服务可用。
+func (c ProxyConfig) bypasses(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	if ip != nil && ip.IsLoopback() {
+		return true
+	}
+
+	for _, rule := range c.Bypass {
+		if rule == "*" {
+			return true
+		}
+		if strings.Contains(rule, "/") {
+			if _, cidr, err := net.ParseCIDR(rule); err == nil && ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		rule = strings.TrimPrefix(rule, "*")
+		if strings.HasPrefix(rule, ".") {
+			if strings.HasSuffix(host, rule) || host == rule[1:] {
+				return true
+			}
+			continue
+		}
+		// 与 NO_PROXY 语义一致：example.com 同时匹配其子域名
+		if host == rule || strings.HasSuffix(host, "."+rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// proxyForRequest 返回请求使用的代理地址，nil 表示直连
+func (c ProxyConfig) proxyForRequest(req *http.Request) (*url.URL, error) {
+	switch c.Mode {
+	case ProxyModeSystem:
+		return http.ProxyFromEnvironment(req)
+	case ProxyModeNone:
+		return nil, nil
+	}
+	if c.bypasses(req.URL.Hostname()) {
+		return nil, nil
+	}
+	return c.proxyURL(), nil
+}
+
+// ProxyForHost 返回访问指定主机时使用的代理地址，nil 表示直连
+func ProxyForHost(host string) (*url.URL, error) {
+	req := &http.Request{URL: &url.URL{Scheme: "https", Host: host}}
+	return GetProxyConfig().proxyForRequest(req)
+}
+
+// PinnedIPAllowed 判断访问指定主机时是否应使用优选IP。
+// 请求会走代理且策略为 skip 时返回 false，调用方可据此跳过IP优选。
+func PinnedIPAllowed(host string) bool {
+	if GetProxyConfig().PinnedIPPolicy == PinnedIPPolicyDirect {
+		return true
+	}
+	proxy, err := ProxyForHost(host)
+	return err == nil && proxy == nil
+}
+
+// proxyFunc 供 http.Transport 使用，每次请求时读取最新代理配置
+func proxyFunc(pinnedHost string) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		config := GetProxyConfig()
+		if pinnedHost != "" && req.URL.Hostname() == pinnedHost && config.PinnedIPPolicy == PinnedIPPolicyDirect {
+			return nil, nil
+		}
+		return config.proxyForRequest(req)
+	}
+}
+
+// TransportOptions 构造 http.Transport 的参数，同时作为共享 Transport 的缓存键
+type TransportOptions struct {
+	DialTimeout time.Duration
+	// PinnedIP / PinnedHost 用于优选IP：访问 PinnedHost 时直接连接 PinnedIP（IPv4 或 IPv6）。
+	// 请求走代理时 DialContext 连接的是代理服务器，优选IP自然不会生效，
+	// 除非策略为 direct（此时该主机绕过代理）。
+	PinnedIP   string
+	PinnedHost string
+	// 为 0 时使用默认值
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+}
+
+// maxPinnedTransports 限制按优选IP缓存的 Transport 数量。优选IP会随测速结果变化，
+// 超出时丢弃最久未使用的一个，避免缓存只增不减
+const maxPinnedTransports = 8
+
+type sharedTransport struct {
+	transport *http.Transport
+	lastUsed  uint64
+}
+
+var (
+	// sharedTransports 按参数缓存的 Transport，使各处请求复用 keep-alive 连接。
+	// 代理配置变化时整体丢弃，已有的空闲连接随之关闭
+	sharedTransports   = make(map[TransportOptions]*sharedTransport)
+	sharedTransportsMu sync.Mutex
+	sharedTransportUse uint64
+)
+
+// NewTransport 返回遵循全局代理配置的 http.Transport，所有对外请求都应通过它发出。
+// 参数相同的调用共用同一个 Transport，调用方不能修改返回值的字段，也不应关闭其空闲连接
+func NewTransport(options TransportOptions) *http.Transport {
+	if options.DialTimeout <= 0 {
+		options.DialTimeout = 30 * time.Second
+	}
+	if options.TLSHandshakeTimeout <= 0 {
+		options.TLSHandshakeTimeout = 10 * time.Second
+	}
+	options.PinnedIP = NormalizeIP(options.PinnedIP)
+	if options.PinnedIP == "" {
+		options.PinnedHost = ""
+	}
+
+	sharedTransportsMu.Lock()
+	defer sharedTransportsMu.Unlock()
+	sharedTransportUse++
+	if shared, ok := sharedTransports[options]; ok {
+		shared.lastUsed = sharedTransportUse
+		return shared.transport
+	}
+	transport := newTransport(options)
+	sharedTransports[options] = &sharedTransport{transport: transport, lastUsed: sharedTransportUse}
+	if options.PinnedIP != "" {
+		evictPinnedTransportsLocked()
+	}
+	return transport
+}
+
+// evictPinnedTransportsLocked 丢弃最久未使用的优选IP Transport，直到数量不超过上限。
+// 仍持有被丢弃 Transport 的请求可以继续使用，只是其连接不再被复用
+func evictPinnedTransportsLocked() {
+	for {
+		var oldest TransportOptions
+		var oldestUse uint64
+		pinned := 0
+		for options, shared := range sharedTransports {
+			if options.PinnedIP == "" {
+				continue
+			}
+			pinned++
+			if oldestUse == 0 || shared.lastUsed < oldestUse {
+				oldest, oldestUse = options, shared.lastUsed
+			}
+		}
+		if pinned <= maxPinnedTransports {
+			return
+		}
+		sharedTransports[oldest].transport.CloseIdleConnections()
+		delete(sharedTransports, oldest)
+	}
+}
+
+// resetSharedTransports 丢弃所有共享 Transport 并关闭其空闲连接，之后的请求按新的代理配置重新建立连接
+func resetSharedTransports() {
+	sharedTransportsMu.Lock()
+	defer sharedTransportsMu.Unlock()
+	for options, shared := range sharedTransports {
+		shared.transport.CloseIdleConnections()
+		delete(sharedTransports, options)
+	}
+}
+
+func newTransport(options TransportOptions) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   options.DialTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	pinnedIP, pinnedHost := options.PinnedIP, options.PinnedHost
+
+	return &http.Transport{
+		Proxy: proxyFunc(pinnedHost),
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if pinnedHost != "" {
+				host, port, err := net.SplitHostPort(addr)
+				if err == nil && host == pinnedHost {
+					// JoinHostPort 会为 IPv6 地址加上方括号
+					return dialer.DialContext(ctx, network, net.JoinHostPort(pinnedIP, port))
+				}
+			}
+			return dialer.DialContext(ctx, network, addr)
+		},
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   options.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: options.ResponseHeaderTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+// NewHTTPClient 创建遵循全局代理配置的 http.Client，底层共用 NewTransport 的连接池
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: NewTransport(TransportOptions{}),
+		Timeout:   timeout,
+	}
+}
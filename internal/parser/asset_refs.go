@@ -0,0 +1,225 @@
+package parser
+
+import (
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"l4d2-manager-next/pkg/valve/vdf"
+	"l4d2-manager-next/pkg/valve/vpk"
+)
+
+// vmtTextureParams 检查的 VMT 贴图参数
+var vmtTextureParams = []string{"$basetexture", "$bumpmap", "$envmapmask"}
+
+// AssetReference 模型或材质引用的资源，Candidates 中任一路径存在即视为找到
+type AssetReference struct {
+	Kind       string   `json:"kind"`            // "material" 或 "texture"
+	Name       string   `json:"name"`            // 引用中写的名称
+	Source     string   `json:"source"`          // 引用所在的 .mdl 或 .vmt
+	Param      string   `json:"param,omitempty"` // VMT 参数名，如 $basetexture
+	Candidates []string `json:"candidates"`      // 可能的游戏内路径，小写、以 / 分隔
+}
+
+// CollectVPKAssetReferences 读取VPK中的模型和材质，返回模型引用的材质和材质引用的贴图
+func CollectVPKAssetReferences(filePath string) ([]AssetReference, error) {
+	opener := vpk.Single(filePath)
+	defer opener.Close()
+
+	archive, err := opener.ReadArchive()
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]AssetReference, 0)
+	for i := range archive.Files {
+		file := &archive.Files[i]
+		name := normalizeModelStatPath(file.Name())
+		switch {
+		case isModelMDLPath(name):
+			data, err := readVPKFileBytes(opener, file)
+			if err != nil {
+				continue
+			}
+			refs = append(refs, mdlMaterialReferences(name, data)...)
+		case strings.HasPrefix(name, "materials/") && strings.HasSuffix(name, ".vmt"):
+			data, err := readVPKFileBytes(opener, file)
+			if err != nil {
+				continue
+			}
+			refs = append(refs, vmtAssetReferences(name, data)...)
+		}
+	}
+
+	sort.SliceStable(refs, func(i, j int) bool {
+		if refs[i].Source != refs[j].Source {
+			return refs[i].Source < refs[j].Source
+		}
+		return refs[i].Name < refs[j].Name
+	})
+	return refs, nil
+}
+
+// mdlMaterialReferences 按 $cdmaterials 搜索路径组合模型引用的材质，头部无法解析时返回空
+func mdlMaterialReferences(source string, data []byte) []AssetReference {
+	var stat ModelStat
+	if err := parseMDLHeader(data, &stat); err != nil {
+		return nil
+	}
+	return modelMaterialReferences(source, &stat)
+}
+
+// modelMaterialReferences 按已解析的 MDL 头部组合材质引用
+func modelMaterialReferences(source string, stat *ModelStat) []AssetReference {
+	refs := make([]AssetReference, 0, len(stat.Materials))
+	for _, material := range stat.Materials {
+		if material == "" {
+			continue
+		}
+		refs = append(refs, AssetReference{
+			Kind:       "material",
+			Name:       material,
+			Source:     source,
+			Candidates: modelMaterialCandidates(stat, material),
+		})
+	}
+	return refs
+}
+
+// modelMaterialCandidates 返回材质在各 $cdmaterials 目录下的 VMT 路径，按搜索顺序排列
+func modelMaterialCandidates(stat *ModelStat, material string) []string {
+	searchPaths := stat.MaterialPaths
+	if len(searchPaths) == 0 {
+		searchPaths = []string{""}
+	}
+	candidates := make([]string, 0, len(searchPaths))
+	for _, dir := range searchPaths {
+		if dir != "" && !strings.HasSuffix(dir, "/") {
+			dir += "/"
+		}
+		if candidate := materialAssetPath(dir+material, ".vmt"); !slices.Contains(candidates, candidate) {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
+
+// vmtAssetReferences 读取 VMT 的贴图参数，Patch 材质的 include 作为材质引用
+func vmtAssetReferences(source string, data []byte) []AssetReference {
+	refs := make([]AssetReference, 0)
+	for _, param := range readVMTParamList(data) {
+		key, value := param.Key, param.Value
+		if value == "" {
+			continue
+		}
+
+		switch {
+		case key == "include":
+			refs = append(refs, AssetReference{
+				Kind:       "material",
+				Name:       value,
+				Source:     source,
+				Param:      key,
+				Candidates: []string{materialAssetPath(value, ".vmt")},
+			})
+		case slices.Contains(vmtTextureParams, key):
+			// 渲染目标和引擎内置贴图不在文件系统中
+			lower := strings.ToLower(value)
+			if strings.HasPrefix(lower, "_rt_") || lower == "env_cubemap" {
+				continue
+			}
+			refs = append(refs, AssetReference{
+				Kind:       "texture",
+				Name:       value,
+				Source:     source,
+				Param:      key,
+				Candidates: []string{materialAssetPath(value, ".vtf")},
+			})
+		}
+	}
+	return refs
+}
+
+// modelTextureMemory 统计模型材质在同一VPK内引用的贴图数量和图像数据大小，
+// 每个材质取第一个存在的候选路径，sizes 缓存已读取的贴图大小供同一VPK内的模型共用
+func modelTextureMemory(opener *vpk.Opener, files map[string]*vpk.File, source string, stat *ModelStat, sizes map[string]int64) (int, int64) {
+	seen := make(map[string]struct{})
+	var memory int64
+	for _, material := range modelMaterialReferences(source, stat) {
+		for _, candidate := range material.Candidates {
+			file := files[candidate]
+			if file == nil {
+				continue
+			}
+			data, err := readVPKFileBytes(opener, file)
+			if err != nil {
+				break
+			}
+			for _, texture := range vmtAssetReferences(candidate, data) {
+				if texture.Kind != "texture" {
+					continue
+				}
+				texturePath := texture.Candidates[0]
+				if _, ok := seen[texturePath]; ok || files[texturePath] == nil {
+					continue
+				}
+				seen[texturePath] = struct{}{}
+				size, ok := sizes[texturePath]
+				if !ok {
+					if header, err := readVTFFileHeader(opener, files[texturePath]); err == nil {
+						size = header.ImageDataSize()
+					}
+					sizes[texturePath] = size
+				}
+				memory += size
+			}
+			break
+		}
+	}
+	return len(seen), memory
+}
+
+// materialAssetPath 将材质或贴图名称转换为 materials/ 下的完整路径
+func materialAssetPath(name string, ext string) string {
+	name = normalizeModelStatPath(name)
+	name = strings.TrimPrefix(name, "/")
+	name = strings.TrimPrefix(name, "materials/")
+	if strings.EqualFold(path.Ext(name), ext) {
+		name = strings.TrimSuffix(name, path.Ext(name))
+	}
+	return "materials/" + name + ext
+}
+
+// vmtParam VMT 中的一个参数，Key 为小写
+type vmtParam struct {
+	Key   string
+	Value string
+}
+
+// readKeyValuesText 解析 VMT 或实体文本。这类文本中的反斜杠是路径分隔符而不是转义字符，
+// 解析前统一替换为 /
+func readKeyValuesText(text string) (*vdf.KeyValues, error) {
+	return vdf.ReadTolerant(strings.NewReader(strings.ReplaceAll(text, `\`, "/")))
+}
+
+// readVMTParamList 按出现顺序返回 VMT 各层级块内的参数，包括 Patch 材质 insert/replace 块中的参数
+func readVMTParamList(data []byte) []vmtParam {
+	root, err := readKeyValuesText(string(data))
+	if err != nil {
+		return nil
+	}
+	params := make([]vmtParam, 0)
+	var walk func(kv *vdf.KeyValues)
+	walk = func(kv *vdf.KeyValues) {
+		for ; kv != nil; kv = kv.NextSubKey() {
+			if kv.HasValue {
+				params = append(params, vmtParam{Key: strings.ToLower(kv.Key), Value: strings.TrimSpace(kv.Value)})
+			} else {
+				walk(kv.FirstSubKey())
+			}
+		}
+	}
+	walk(root)
+	return params
+}
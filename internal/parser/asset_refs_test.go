@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestVMTAssetReferences(t *testing.T) {
+	vmt := []byte(`"VertexLitGeneric"
+{
+	"$basetexture" "models\survivors\coach\Coach_Body"
+	$bumpmap models/survivors/coach/coach_normal.vtf
+	"$envmapmask"	"_rt_Camera"
+	"$envmap" "env_cubemap"
+	// "$detail" "ignored"
+}
+`)
+	refs := vmtAssetReferences("materials/models/survivors/coach/coach_body.vmt", vmt)
+	if len(refs) != 2 {
+		t.Fatalf("expected 2 texture references, got %+v", refs)
+	}
+	if refs[0].Param != "$basetexture" || refs[0].Candidates[0] != "materials/models/survivors/coach/coach_body.vtf" {
+		t.Fatalf("unexpected basetexture reference: %+v", refs[0])
+	}
+	if refs[1].Param != "$bumpmap" || refs[1].Candidates[0] != "materials/models/survivors/coach/coach_normal.vtf" {
+		t.Fatalf("unexpected bumpmap reference: %+v", refs[1])
+	}
+
+	patch := vmtAssetReferences("materials/a.vmt", []byte("\"Patch\"\n{\n\tinclude \"materials/models/base.vmt\"\n}\n"))
+	if len(patch) != 1 || patch[0].Kind != "material" || patch[0].Candidates[0] != "materials/models/base.vmt" {
+		t.Fatalf("unexpected patch reference: %+v", patch)
+	}
+
+	// 反斜杠是路径分隔符，\v 不能被当作转义字符
+	inserted := vmtAssetReferences("materials/b.vmt", []byte(`"Patch" { "include" "materials\models\base.vmt" "insert" { "$bumpmap" "models\v_models\hands_normal" } }`))
+	if len(inserted) != 2 || inserted[1].Param != "$bumpmap" || inserted[1].Candidates[0] != "materials/models/v_models/hands_normal.vtf" {
+		t.Fatalf("unexpected inserted reference: %+v", inserted)
+	}
+}
+
+func TestCollectVPKAssetReferences(t *testing.T) {
+	vpkPath := filepath.Join(t.TempDir(), "coach.vpk")
+	writeParserTestVPK(t, vpkPath, map[string][]byte{
+		"models/survivors/survivor_coach.mdl":             buildMDLFixture(t),
+		"materials/models/survivors/coach/coach_body.vmt": []byte(`"VertexLitGeneric" { "$basetexture" "models/survivors/coach/coach_body" }`),
+	})
+
+	refs, err := CollectVPKAssetReferences(vpkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refs) != 3 {
+		t.Fatalf("expected 3 references, got %+v", refs)
+	}
+	// materials/ 下的 VMT 排在 models/ 前面
+	if refs[0].Kind != "texture" || refs[0].Candidates[0] != "materials/models/survivors/coach/coach_body.vtf" {
+		t.Fatalf("unexpected texture reference: %+v", refs[0])
+	}
+	if refs[1].Kind != "material" || refs[1].Name != "coach_body" || refs[1].Candidates[0] != "materials/models/survivors/coach/coach_body.vmt" {
+		t.Fatalf("unexpected material reference: %+v", refs[1])
+	}
+}
+
+func TestAnalyzeVPKModelStatsTextureMemory(t *testing.T) {
+	vpkPath := filepath.Join(t.TempDir(), "coach.vpk")
+	writeParserTestVPK(t, vpkPath, map[string][]byte{
+		"models/survivors/survivor_coach.mdl":             buildMDLFixture(t),
+		"materials/models/survivors/coach/coach_body.vmt": []byte(`"VertexLitGeneric" { "$basetexture" "models/survivors/coach/coach_body" "$bumpmap" "models/survivors/coach/missing_normal" }`),
+		"materials/models/survivors/coach/coach_head.vmt": []byte(`"VertexLitGeneric" { "$basetexture" "models/survivors/coach/coach_head" "$envmapmask" "models/survivors/coach/coach_body" }`),
+		"materials/models/survivors/coach/coach_body.vtf": buildTestVTF(2, VTFFormatRGBA8888, 4, 4, 0, make([]byte, 64)),
+		"materials/models/survivors/coach/coach_head.vtf": buildTestVTF(2, VTFFormatRGBA8888, 8, 8, 0, make([]byte, 256)),
+	})
+
+	stats, err := AnalyzeVPKModelStats(vpkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.Models) != 1 {
+		t.Fatalf("expected 1 model, got %+v", stats.Models)
+	}
+	// 缺失的法线贴图不计入，两个材质共用的贴图只计一次
+	if model := stats.Models[0]; model.Textures != 2 || model.TextureMemory != 64+256 {
+		t.Fatalf("unexpected texture memory: %d textures, %d bytes", model.Textures, model.TextureMemory)
+	}
+}
@@ -0,0 +1,422 @@
+package parser
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"l4d2-manager-next/pkg/valve/vpk"
+)
+
+const (
+	bspIdent       = "VBSP"
+	bspLumpCount   = 64
+	bspHeaderSize  = 8 + bspLumpCount*16 + 4
+	bspLumpEntity  = 0
+	bspLumpGame    = 35
+	bspLumpPakfile = 40
+
+	// 实体文本和静态模型字典的读取上限，防止损坏文件申请过大内存
+	bspMaxEntityLumpSize = 64 << 20
+	bspMaxStaticPropDict = 65536
+	bspStaticPropName    = 128
+)
+
+// bspEntityBlockStart 匹配实体文本中每个实体块的起始花括号
+var bspEntityBlockStart = regexp.MustCompile(`(?m)^(\s*)\{`)
+
+// bspLumpNames Source 引擎 BSP 的 64 个数据块名称，下标即块编号
+var bspLumpNames = [bspLumpCount]string{
+	"entities", "planes", "texdata", "vertexes", "visibility", "nodes", "texinfo", "faces",
+	"lighting", "occlusion", "leafs", "faceids", "edges", "surfedges", "models", "worldlights",
+	"leaffaces", "leafbrushes", "brushes", "brushsides", "areas", "areaportals", "propcollision", "prophulls",
+	"prophullverts", "proptris", "dispinfo", "originalfaces", "physdisp", "physcollide", "vertnormals", "vertnormalindices",
+	"disp_lightmap_alphas", "disp_verts", "disp_lightmap_sample_positions", "game_lump", "leafwaterdata", "primitives", "primverts", "primindices",
+	"pakfile", "clipportalverts", "cubemaps", "texdata_string_data", "texdata_string_table", "overlays", "leafmindisttowater", "face_macro_texture_info",
+	"disp_tris", "physcollidesurface", "wateroverlays", "leaf_ambient_index_hdr", "leaf_ambient_index", "lighting_hdr", "worldlights_hdr", "leaf_ambient_lighting_hdr",
+	"leaf_ambient_lighting", "xzippakfile", "faces_hdr", "map_flags", "overlay_fades", "overlay_system_levels", "physlevel", "disp_multiblend",
+}
+
+// BSPLump BSP 数据块目录项，只列出长度不为 0 的块
+type BSPLump struct {
+	Index   int    `json:"index"`
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+	Offset  int64  `json:"offset"`
+	Length  int64  `json:"length"`
+}
+
+// BSPPlayerStart info_player_start 实体
+type BSPPlayerStart struct {
+	TargetName string `json:"targetName"`
+	Origin     string `json:"origin"`
+}
+
+// BSPChangeLevel info_changelevel 实体，Map 为过关后载入的地图
+type BSPChangeLevel struct {
+	Map        string `json:"map"`
+	Landmark   string `json:"landmark"`
+	TargetName string `json:"targetName"`
+}
+
+// BSPPakFile 地图内嵌 pakfile 中的文件
+type BSPPakFile struct {
+	Name           string `json:"name"` // 小写、以 / 分隔
+	Size           int64  `json:"size"`
+	CompressedSize int64  `json:"compressedSize"`
+	CRC            uint32 `json:"crc"`
+}
+
+// BSPMapInfo 单张地图的 BSP 分析结果
+type BSPMapInfo struct {
+	Path           string           `json:"path"` // VPK 内部路径
+	Name           string           `json:"name"` // 地图名（不含 .bsp）
+	Version        int              `json:"version"`
+	Revision       int              `json:"revision"`
+	Size           int64            `json:"size"`
+	Lumps          []BSPLump        `json:"lumps,omitempty"`
+	EntityCount    int              `json:"entityCount"`
+	PlayerStarts   []BSPPlayerStart `json:"playerStarts"`
+	ChangeLevels   []BSPChangeLevel `json:"changeLevels"`
+	PropModels     []string         `json:"propModels,omitempty"`
+	PropModelCount int              `json:"propModelCount"`
+	PakFiles       []BSPPakFile     `json:"pakFiles,omitempty"`
+	PakFileCount   int              `json:"pakFileCount"`
+	PakFileSize    int64            `json:"pakFileSize"` // pakfile 数据块大小（压缩后）
+	Message        string           `json:"message,omitempty"`
+}
+
+// Summary 去掉数据块、模型和 pakfile 明细，用于随扫描结果缓存
+func (m BSPMapInfo) Summary() BSPMapInfo {
+	m.Lumps = nil
+	m.PropModels = nil
+	m.PakFiles = nil
+	return m
+}
+
+type bspLumpEntry struct {
+	version int
+	offset  int64
+	length  int64
+}
+
+// AnalyzeBSP 读取 BSP 头、实体数据块、静态模型字典和内嵌 pakfile。
+// 只有头部无法读取时返回错误，其余数据块的问题记录在 Message 中
+func AnalyzeBSP(r io.ReaderAt, size int64) (BSPMapInfo, error) {
+	info := BSPMapInfo{Size: size, PlayerStarts: []BSPPlayerStart{}, ChangeLevels: []BSPChangeLevel{}}
+
+	lumps, version, revision, err := readBSPHeader(r, size)
+	if err != nil {
+		return info, err
+	}
+	info.Version = version
+	info.Revision = revision
+	for i, lump := range lumps {
+		if lump.length == 0 {
+			continue
+		}
+		info.Lumps = append(info.Lumps, BSPLump{Index: i, Name: bspLumpNames[i], Version: lump.version, Offset: lump.offset, Length: lump.length})
+	}
+
+	props := make(map[string]bool)
+	if err := readBSPEntities(r, lumps[bspLumpEntity], &info, props); err != nil {
+		info.Message = appendModelStatMessage(info.Message, err.Error())
+	}
+	if err := readBSPStaticProps(r, size, lumps[bspLumpGame], props); err != nil {
+		info.Message = appendModelStatMessage(info.Message, err.Error())
+	}
+	info.PropModels = make([]string, 0, len(props))
+	for model := range props {
+		info.PropModels = append(info.PropModels, model)
+	}
+	sort.Strings(info.PropModels)
+	info.PropModelCount = len(info.PropModels)
+
+	if pak := lumps[bspLumpPakfile]; pak.length > 0 {
+		info.PakFileSize = pak.length
+		files, err := readBSPPakfile(r, pak)
+		if err != nil {
+			info.Message = appendModelStatMessage(info.Message, err.Error())
+		}
+		info.PakFiles = files
+		info.PakFileCount = len(files)
+	}
+	return info, nil
+}
+
+// readBSPHeader 读取头部。L4D2 的数据块目录字段顺序为 version/offset/length/fourCC，
+// 与其他 Source 游戏的 offset/length/version/fourCC 不同，按第一个数据块的偏移判断
+func readBSPHeader(r io.ReaderAt, size int64) ([bspLumpCount]bspLumpEntry, int, int, error) {
+	var lumps [bspLumpCount]bspLumpEntry
+	if size < bspHeaderSize {
+		return lumps, 0, 0, fmt.Errorf("文件过小，不是有效的 BSP")
+	}
+	header := make([]byte, bspHeaderSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return lumps, 0, 0, fmt.Errorf("读取 BSP 头失败: %w", err)
+	}
+	if string(header[:4]) != bspIdent {
+		return lumps, 0, 0, fmt.Errorf("不是有效的 BSP 文件")
+	}
+	version := int(int32(binary.LittleEndian.Uint32(header[4:])))
+
+	field := func(lump, index int) int64 {
+		return int64(int32(binary.LittleEndian.Uint32(header[8+lump*16+index*4:])))
+	}
+	l4d2Layout := field(bspLumpEntity, 0) < bspHeaderSize && field(bspLumpEntity, 1) >= bspHeaderSize
+	for i := range lumps {
+		if l4d2Layout {
+			lumps[i] = bspLumpEntry{version: int(field(i, 0)), offset: field(i, 1), length: field(i, 2)}
+		} else {
+			lumps[i] = bspLumpEntry{offset: field(i, 0), length: field(i, 1), version: int(field(i, 2))}
+		}
+		if lumps[i].offset < 0 || lumps[i].length < 0 || lumps[i].offset+lumps[i].length > size {
+			lumps[i] = bspLumpEntry{}
+		}
+	}
+	revision := int(int32(binary.LittleEndian.Uint32(header[bspHeaderSize-4:])))
+	return lumps, version, revision, nil
+}
+
+// readBSPEntities 解析实体文本，收集出生点、换图触发器和 prop_* 实体使用的模型
+func readBSPEntities(r io.ReaderAt, lump bspLumpEntry, info *BSPMapInfo, props map[string]bool) error {
+	if lump.length == 0 {
+		return fmt.Errorf("缺少实体数据")
+	}
+	if lump.length > bspMaxEntityLumpSize {
+		return fmt.Errorf("实体数据过大: %d 字节", lump.length)
+	}
+	data := make([]byte, lump.length)
+	if _, err := r.ReadAt(data, lump.offset); err != nil {
+		return fmt.Errorf("读取实体数据失败: %w", err)
+	}
+
+	// 实体块前没有键名，补上键名后按 KeyValues 解析，每个顶层键是一个实体
+	text := bspEntityBlockStart.ReplaceAllString(strings.TrimRight(string(data), "\x00"), "${1}\"entity\" {")
+	root, err := readKeyValuesText(text)
+	if err != nil {
+		return fmt.Errorf("解析实体数据失败: %w", err)
+	}
+	for block := root; block != nil; block = block.NextSubKey() {
+		entity := make(map[string]string)
+		for kv := block.FirstValue(); kv != nil; kv = kv.NextValue() {
+			key := strings.ToLower(kv.Key)
+			if _, exists := entity[key]; !exists {
+				entity[key] = kv.Value
+			}
+		}
+		info.EntityCount++
+
+		classname := strings.ToLower(entity["classname"])
+		switch {
+		case classname == "info_player_start":
+			info.PlayerStarts = append(info.PlayerStarts, BSPPlayerStart{TargetName: entity["targetname"], Origin: entity["origin"]})
+		case classname == "info_changelevel":
+			info.ChangeLevels = append(info.ChangeLevels, BSPChangeLevel{Map: entity["map"], Landmark: entity["landmark"], TargetName: entity["targetname"]})
+		case strings.HasPrefix(classname, "prop_"):
+			if model := normalizeBSPModelPath(entity["model"]); model != "" {
+				props[model] = true
+			}
+		}
+	}
+	return nil
+}
+
+// readBSPStaticProps 从游戏数据块的 sprp 项读取 prop_static 模型字典，
+// 编译后的地图不会在实体文本中保留 prop_static
+func readBSPStaticProps(r io.ReaderAt, size int64, lump bspLumpEntry, props map[string]bool) error {
+	if lump.length < 4 {
+		return nil
+	}
+	buf := make([]byte, 4)
+	if _, err := r.ReadAt(buf, lump.offset); err != nil {
+		return fmt.Errorf("读取游戏数据块失败: %w", err)
+	}
+	count := int64(int32(binary.LittleEndian.Uint32(buf)))
+	if count < 0 || 4+count*16 > lump.length {
+		return fmt.Errorf("游戏数据块目录无效")
+	}
+	entries := make([]byte, count*16)
+	if _, err := r.ReadAt(entries, lump.offset+4); err != nil {
+		return fmt.Errorf("读取游戏数据块失败: %w", err)
+	}
+	for i := int64(0); i < count; i++ {
+		entry := entries[i*16:]
+		if binary.LittleEndian.Uint32(entry) != 0x73707270 { // 'sprp'
+			continue
+		}
+		if binary.LittleEndian.Uint16(entry[4:])&1 != 0 {
+			return fmt.Errorf("静态模型数据已压缩，跳过")
+		}
+		offset := int64(int32(binary.LittleEndian.Uint32(entry[8:])))
+		length := int64(int32(binary.LittleEndian.Uint32(entry[12:])))
+		if offset < 0 || length < 4 || offset+length > size {
+			return fmt.Errorf("静态模型数据位置无效")
+		}
+		if _, err := r.ReadAt(buf, offset); err != nil {
+			return fmt.Errorf("读取静态模型数据失败: %w", err)
+		}
+		dictEntries := int64(int32(binary.LittleEndian.Uint32(buf)))
+		if dictEntries < 0 || dictEntries > bspMaxStaticPropDict || 4+dictEntries*bspStaticPropName > length {
+			return fmt.Errorf("静态模型字典无效")
+		}
+		names := make([]byte, dictEntries*bspStaticPropName)
+		if _, err := r.ReadAt(names, offset+4); err != nil {
+			return fmt.Errorf("读取静态模型字典失败: %w", err)
+		}
+		for j := int64(0); j < dictEntries; j++ {
+			name := names[j*bspStaticPropName : (j+1)*bspStaticPropName]
+			if end := strings.IndexByte(string(name), 0); end >= 0 {
+				name = name[:end]
+			}
+			if model := normalizeBSPModelPath(string(name)); model != "" {
+				props[model] = true
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// readBSPPakfile 将 pakfile 数据块作为 zip 读取文件列表
+func readBSPPakfile(r io.ReaderAt, lump bspLumpEntry) ([]BSPPakFile, error) {
+	reader, err := zip.NewReader(io.NewSectionReader(r, lump.offset, lump.length), lump.length)
+	if err != nil {
+		return []BSPPakFile{}, fmt.Errorf("读取内嵌 pakfile 失败: %w", err)
+	}
+	files := make([]BSPPakFile, 0, len(reader.File))
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		files = append(files, BSPPakFile{
+			Name:           normalizeModelStatPath(file.Name),
+			Size:           int64(file.UncompressedSize64),
+			CompressedSize: int64(file.CompressedSize64),
+			CRC:            file.CRC32,
+		})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files, nil
+}
+
+func normalizeBSPModelPath(model string) string {
+	model = strings.TrimSpace(model)
+	if model == "" || strings.HasPrefix(model, "*") { // *N 为地图内置的笔刷模型
+		return ""
+	}
+	return normalizeModelStatPath(model)
+}
+
+func isBSPMapPath(name string) bool {
+	return strings.HasPrefix(name, "maps/") && strings.HasSuffix(name, ".bsp")
+}
+
+// analyzeArchiveMaps 分析VPK中的所有地图，按路径排序
+func analyzeArchiveMaps(opener *vpk.Opener, archive *vpk.Archive) []BSPMapInfo {
+	maps := make([]BSPMapInfo, 0)
+	for i := range archive.Files {
+		file := &archive.Files[i]
+		name := normalizeModelStatPath(file.Name())
+		if !isBSPMapPath(name) {
+			continue
+		}
+		info := analyzeVPKMapFile(opener, file)
+		info.Path = name
+		info.Name = strings.TrimSuffix(path.Base(name), ".bsp")
+		maps = append(maps, info)
+	}
+	sort.Slice(maps, func(i, j int) bool { return maps[i].Path < maps[j].Path })
+	return maps
+}
+
+// analyzeVPKMapFile 按偏移读取地图的文件头和数据块，地图动辄上百 MB，不读取整个文件
+func analyzeVPKMapFile(opener *vpk.Opener, file *vpk.File) BSPMapInfo {
+	size := int64(file.Size())
+	reader, err := OpenVPKFileAt(opener, file)
+	if err != nil {
+		return BSPMapInfo{Size: size, Message: err.Error()}
+	}
+	info, err := AnalyzeBSP(reader, size)
+	if err != nil {
+		info.Message = err.Error()
+	}
+	return info
+}
+
+// maxCachedVPKMaps 限制地图分析结果缓存的VPK数量。结果含实体和 pakfile 列表，
+// 删除或替换的VPK不会主动移除，超出时丢弃最久未使用的一个
+const maxCachedVPKMaps = 32
+
+// bspMapCacheEntry VPK中所有地图的分析结果，VPK大小或修改时间变化后失效
+type bspMapCacheEntry struct {
+	ModTime  time.Time
+	Size     int64
+	Maps     []BSPMapInfo
+	lastUsed uint64
+}
+
+var (
+	// bspMapCache 按VPK路径缓存地图分析结果，解析VPK和冲突检测共用，每个VPK只分析一次
+	bspMapCache    = make(map[string]*bspMapCacheEntry)
+	bspMapCacheMu  sync.Mutex
+	bspMapCacheUse uint64
+)
+
+// cachedVPKMaps 返回VPK的地图分析结果，VPK未变化时使用缓存，否则调用 analyze 并写入缓存
+func cachedVPKMaps(filePath string, analyze func() ([]BSPMapInfo, error)) ([]BSPMapInfo, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return analyze()
+	}
+	bspMapCacheMu.Lock()
+	if entry, ok := bspMapCache[filePath]; ok && entry.ModTime.Equal(info.ModTime()) && entry.Size == info.Size() {
+		bspMapCacheUse++
+		entry.lastUsed = bspMapCacheUse
+		bspMapCacheMu.Unlock()
+		return entry.Maps, nil
+	}
+	bspMapCacheMu.Unlock()
+
+	maps, err := analyze()
+	if err != nil {
+		return nil, err
+	}
+	bspMapCacheMu.Lock()
+	defer bspMapCacheMu.Unlock()
+	bspMapCacheUse++
+	bspMapCache[filePath] = &bspMapCacheEntry{ModTime: info.ModTime(), Size: info.Size(), Maps: maps, lastUsed: bspMapCacheUse}
+	for len(bspMapCache) > maxCachedVPKMaps {
+		var oldest string
+		var oldestUse uint64
+		for cachedPath, entry := range bspMapCache {
+			if oldestUse == 0 || entry.lastUsed < oldestUse {
+				oldest, oldestUse = cachedPath, entry.lastUsed
+			}
+		}
+		delete(bspMapCache, oldest)
+	}
+	return maps, nil
+}
+
+// AnalyzeVPKMaps 读取VPK中所有 maps/*.bsp 的完整分析结果，VPK未变化时返回缓存
+func AnalyzeVPKMaps(filePath string) ([]BSPMapInfo, error) {
+	return cachedVPKMaps(filePath, func() ([]BSPMapInfo, error) {
+		opener := vpk.Single(filePath)
+		defer opener.Close()
+
+		archive, err := opener.ReadArchive()
+		if err != nil {
+			return nil, err
+		}
+		return analyzeArchiveMaps(opener, archive), nil
+	})
+}
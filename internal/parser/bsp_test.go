@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"vpk-manager/internal/parser/parsertest"
+)
+
+const testBSPEntities = `{
+"classname" "worldspawn"
+"mapversion" "12"
+}
+{
+"classname" "info_player_start"
+"targetname" "survivor_start"
+"origin" "10 20 30"
+}
+{
+"classname" "info_changelevel"
+"map" "c1m2_streets"
+"landmark" "checkpoint_landmark"
+}
+{
+"classname" "prop_dynamic"
+"model" "Models\Props_Doors\Door01.mdl"
+}
+{
+"classname" "prop_physics"
+"model" "*3"
+}
+{
+"classname" "prop_door_rotating"
+"model" "models/props_doors/door01.mdl"
+}
+`
+
+func TestAnalyzeBSP(t *testing.T) {
+	for _, l4d2 := range []bool{true, false} {
+		data := parsertest.BuildBSP(t, l4d2, testBSPEntities,
+			[]string{"models/props_street/trashbin01.mdl", "Models/Props_Doors/door01.mdl"},
+			map[string][]byte{
+				"materials/maps/c1m1_test/cubemap.vtf": []byte("vtf"),
+				"Scripts/VScripts/Director.nut":        []byte("Msg(1)"),
+			})
+
+		info, err := AnalyzeBSP(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Version != 21 || info.Revision != 77 || info.Message != "" {
+			t.Fatalf("l4d2=%v: unexpected header %+v", l4d2, info)
+		}
+		if len(info.Lumps) != 3 || info.Lumps[0].Name != "entities" || info.Lumps[0].Offset != bspHeaderSize || info.Lumps[2].Name != "pakfile" {
+			t.Fatalf("l4d2=%v: unexpected lumps %+v", l4d2, info.Lumps)
+		}
+		if info.EntityCount != 6 || len(info.PlayerStarts) != 1 || info.PlayerStarts[0].Origin != "10 20 30" {
+			t.Fatalf("l4d2=%v: unexpected entities %+v", l4d2, info)
+		}
+		if len(info.ChangeLevels) != 1 || info.ChangeLevels[0].Map != "c1m2_streets" || info.ChangeLevels[0].Landmark != "checkpoint_landmark" {
+			t.Fatalf("l4d2=%v: unexpected changelevels %+v", l4d2, info.ChangeLevels)
+		}
+		if info.PropModelCount != 2 || info.PropModels[0] != "models/props_doors/door01.mdl" || info.PropModels[1] != "models/props_street/trashbin01.mdl" {
+			t.Fatalf("l4d2=%v: unexpected prop models %v", l4d2, info.PropModels)
+		}
+		if info.PakFileCount != 2 || info.PakFiles[1].Name != "scripts/vscripts/director.nut" || info.PakFiles[1].Size != 6 {
+			t.Fatalf("l4d2=%v: unexpected pakfile %+v", l4d2, info.PakFiles)
+		}
+	}
+
+	if _, err := AnalyzeBSP(bytes.NewReader([]byte("IBSP")), 4); err == nil {
+		t.Fatal("expected error for truncated bsp")
+	}
+}
+
+func TestParseVPKFileMapSummary(t *testing.T) {
+	vpkPath := filepath.Join(t.TempDir(), "map.vpk")
+	writeParserTestVPK(t, vpkPath, map[string][]byte{
+		"maps/c1m1_test.bsp": parsertest.BuildBSP(t, true, testBSPEntities, nil, map[string][]byte{"materials/maps/c1m1_test/cubemap.vtf": []byte("vtf")}),
+		"maps/c1m2_test.bsp": []byte("broken"),
+	})
+
+	vpkFile, err := ParseVPKFile(vpkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpkFile.PrimaryTag != "地图" || len(vpkFile.Maps) != 2 {
+		t.Fatalf("expected two map summaries, got %q %+v", vpkFile.PrimaryTag, vpkFile.Maps)
+	}
+	first := vpkFile.Maps[0]
+	if first.Name != "c1m1_test" || first.PakFileCount != 1 || first.PropModelCount != 1 || first.PakFiles != nil || first.Lumps != nil {
+		t.Fatalf("unexpected summary %+v", first)
+	}
+	if vpkFile.Maps[1].Message == "" {
+		t.Fatal("expected broken bsp to report a message")
+	}
+
+	// 解析时的分析结果已写入缓存，AnalyzeVPKMaps 不再重新分析
+	bspMapCacheMu.Lock()
+	cached, ok := bspMapCache[vpkPath]
+	bspMapCacheMu.Unlock()
+	if !ok {
+		t.Fatal("expected map analysis to be cached after parsing")
+	}
+	maps, err := AnalyzeVPKMaps(vpkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(maps) != 2 || &maps[0] != &cached.Maps[0] {
+		t.Fatalf("expected cached map info, got %+v", maps)
+	}
+	if len(maps[0].PakFiles) != 1 || maps[0].PakFiles[0].Name != "materials/maps/c1m1_test/cubemap.vtf" {
+		t.Fatalf("unexpected full map info %+v", maps)
+	}
+}
+
+func TestAnalyzeVPKMapsWithPreloadedHeader(t *testing.T) {
+	vpkPath := filepath.Join(t.TempDir(), "preload.vpk")
+	// 文件头的前 16 字节存放在目录树的预载数据中
+	writeParserTestVPKWithPreload(t, vpkPath, map[string][]byte{
+		"maps/c1m1_test.bsp": parsertest.BuildBSP(t, true, testBSPEntities, nil, map[string][]byte{"materials/maps/c1m1_test/cubemap.vtf": []byte("vtf")}),
+	}, 16)
+
+	maps, err := AnalyzeVPKMaps(vpkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(maps) != 1 || maps[0].Message != "" || len(maps[0].PakFiles) != 1 || maps[0].PropModelCount != 1 {
+		t.Fatalf("unexpected preloaded map info %+v", maps)
+	}
+}
+
+func TestCachedVPKMapsEvictsLeastRecentlyUsed(t *testing.T) {
+	bspMapCacheMu.Lock()
+	bspMapCache = make(map[string]*bspMapCacheEntry)
+	bspMapCacheMu.Unlock()
+
+	dir := t.TempDir()
+	paths := make([]string, maxCachedVPKMaps+1)
+	for i := range paths {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("map%d.vpk", i))
+		if err := os.WriteFile(paths[i], []byte("vpk"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	analyzed := 0
+	analyze := func() ([]BSPMapInfo, error) {
+		analyzed++
+		return []BSPMapInfo{}, nil
+	}
+
+	for _, vpkPath := range paths[:maxCachedVPKMaps] {
+		cachedVPKMaps(vpkPath, analyze)
+	}
+	cachedVPKMaps(paths[0], analyze)
+	// 超出上限时丢弃最久未使用的 paths[1]，刚用过的 paths[0] 保留
+	cachedVPKMaps(paths[maxCachedVPKMaps], analyze)
+	if analyzed != maxCachedVPKMaps+1 || len(bspMapCache) != maxCachedVPKMaps {
+		t.Fatalf("expected %d analyses and %d cached, got %d and %d", maxCachedVPKMaps+1, maxCachedVPKMaps, analyzed, len(bspMapCache))
+	}
+	cachedVPKMaps(paths[0], analyze)
+	cachedVPKMaps(paths[1], analyze)
+	if analyzed != maxCachedVPKMaps+2 {
+		t.Fatalf("expected only the evicted VPK to be analyzed again, got %d analyses", analyzed)
+	}
+}
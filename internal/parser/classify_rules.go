@@ -0,0 +1,366 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// ClassificationRule 分类规则
+//
+// 未设置 SecondaryTag 的规则是类型规则，用于确定一级标签：按优先级从高到低，第一个命中的类型规则决定一级标签，
+// 都未命中时为"其他"。设置了 SecondaryTag 的规则是标签规则，只在 VPK 的一级标签等于 PrimaryTag 时生效：
+// 先按优先级检查元数据正则（addoninfo 的标题和描述），再按文件顺序为每个文件选出优先级最高的命中规则。
+// 命中 Exclusive 规则后不再添加其他二级标签。
+//
+// PathGlobs 不区分大小写，* 和 ? 不跨越 /，** 可跨越 /，{a,b} 匹配任一项。
+// 规则只要任一文件命中 PathGlobs（且未命中 ExcludeGlobs）或元数据命中 MetadataRegex 即视为命中；
+// AllFiles 规则还要求除 ExcludeGlobs 外的所有文件都命中 PathGlobs，用于识别纯 cfg 等单一内容的模组。
+type ClassificationRule struct {
+	ID            string   `json:"id"`
+	PrimaryTag    string   `json:"primaryTag"`
+	SecondaryTag  string   `json:"secondaryTag,omitempty"`
+	Priority      int      `json:"priority"`
+	PathGlobs     []string `json:"pathGlobs,omitempty"`
+	ExcludeGlobs  []string `json:"excludeGlobs,omitempty"`
+	MetadataRegex string   `json:"metadataRegex,omitempty"`
+	Exclusive     bool     `json:"exclusive,omitempty"`
+	AllFiles      bool     `json:"allFiles,omitempty"`
+	Disabled      bool     `json:"disabled,omitempty"` // 用于停用同 ID 的默认规则
+}
+
+// TagMatch 标签的来源
+type TagMatch struct {
+	Tag      string `json:"tag"`
+	Primary  bool   `json:"primary"`
+	Source   string `json:"source"` // "path", "metadata", "mission"
+	RuleID   string `json:"ruleId,omitempty"`
+	Priority int    `json:"priority"`
+	File     string `json:"file,omitempty"`    // 命中的 VPK 内部文件
+	Pattern  string `json:"pattern,omitempty"` // 命中的通配符或正则
+	Text     string `json:"text,omitempty"`    // 元数据中命中的文字
+}
+
+// Classification 分类结果及每个标签的来源
+type Classification struct {
+	PrimaryTag       string     `json:"primaryTag"`
+	SecondaryTags    []string   `json:"secondaryTags"`
+	Matches          []TagMatch `json:"matches"`
+	FilenameOverride bool       `json:"filenameOverride"` // 文件名中的自定义标签覆盖了规则结果
+}
+
+type compiledGlob struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+type compiledRule struct {
+	ClassificationRule
+	include  []compiledGlob
+	exclude  []compiledGlob
+	metadata *regexp.Regexp
+}
+
+// Classifier 编译后的规则集
+type Classifier struct {
+	primaryTags []string // 规则中出现的一级标签，按定义顺序
+	typeRules   []*compiledRule
+	tagRules    map[string][]*compiledRule // key: 一级标签
+}
+
+// NewClassifier 编译规则，停用的规则被跳过
+func NewClassifier(rules []ClassificationRule) (*Classifier, error) {
+	c := &Classifier{tagRules: make(map[string][]*compiledRule)}
+	seen := make(map[string]bool)
+	for _, rule := range rules {
+		rule.ID = strings.TrimSpace(rule.ID)
+		if rule.ID == "" {
+			return nil, fmt.Errorf("分类规则缺少 id")
+		}
+		if seen[rule.ID] {
+			return nil, fmt.Errorf("分类规则 id 重复: %s", rule.ID)
+		}
+		seen[rule.ID] = true
+		if rule.Disabled {
+			continue
+		}
+		if !slices.Contains(c.primaryTags, rule.PrimaryTag) {
+			c.primaryTags = append(c.primaryTags, rule.PrimaryTag)
+		}
+
+		compiled, err := compileRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		if rule.SecondaryTag == "" {
+			c.typeRules = append(c.typeRules, compiled)
+		} else {
+			c.tagRules[rule.PrimaryTag] = append(c.tagRules[rule.PrimaryTag], compiled)
+		}
+	}
+
+	sortRules(c.typeRules)
+	for _, rules := range c.tagRules {
+		sortRules(rules)
+	}
+	return c, nil
+}
+
+// sortRules 按优先级从高到低排序，同优先级保持定义顺序
+func sortRules(rules []*compiledRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Priority > rules[j].Priority
+	})
+}
+
+func compileRule(rule ClassificationRule) (*compiledRule, error) {
+	if strings.TrimSpace(rule.PrimaryTag) == "" {
+		return nil, fmt.Errorf("分类规则 %s 缺少 primaryTag", rule.ID)
+	}
+	if len(rule.PathGlobs) == 0 && rule.MetadataRegex == "" {
+		return nil, fmt.Errorf("分类规则 %s 至少需要 pathGlobs 或 metadataRegex", rule.ID)
+	}
+
+	compiled := &compiledRule{ClassificationRule: rule}
+	for _, pattern := range rule.PathGlobs {
+		glob, err := compileGlob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("分类规则 %s: %w", rule.ID, err)
+		}
+		compiled.include = append(compiled.include, glob)
+	}
+	for _, pattern := range rule.ExcludeGlobs {
+		glob, err := compileGlob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("分类规则 %s: %w", rule.ID, err)
+		}
+		compiled.exclude = append(compiled.exclude, glob)
+	}
+	if rule.MetadataRegex != "" {
+		re, err := regexp.Compile("(?i)" + rule.MetadataRegex)
+		if err != nil {
+			return nil, fmt.Errorf("分类规则 %s: 正则无效: %v", rule.ID, err)
+		}
+		compiled.metadata = re
+	}
+	return compiled, nil
+}
+
+// compileGlob 将通配符转换为正则，匹配时路径已转为小写并以 / 分隔
+func compileGlob(pattern string) (compiledGlob, error) {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(pattern), "\\", "/"))
+	if normalized == "" {
+		return compiledGlob{}, fmt.Errorf("通配符不能为空")
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	inBrace := false
+	for i := 0; i < len(normalized); i++ {
+		ch := normalized[i]
+		switch {
+		case ch == '*' && i+1 < len(normalized) && normalized[i+1] == '*':
+			b.WriteString(".*")
+			i++
+		case ch == '*':
+			b.WriteString("[^/]*")
+		case ch == '?':
+			b.WriteString("[^/]")
+		case ch == '{' && !inBrace:
+			b.WriteString("(?:")
+			inBrace = true
+		case ch == '}' && inBrace:
+			b.WriteString(")")
+			inBrace = false
+		case ch == ',' && inBrace:
+			b.WriteString("|")
+		case ch == '{' || ch == '}':
+			return compiledGlob{}, fmt.Errorf("通配符 %q 的花括号不匹配", pattern)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	if inBrace {
+		return compiledGlob{}, fmt.Errorf("通配符 %q 的花括号不匹配", pattern)
+	}
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return compiledGlob{}, fmt.Errorf("通配符 %q 无效: %v", pattern, err)
+	}
+	return compiledGlob{pattern: pattern, re: re}, nil
+}
+
+// matchFile 返回命中的通配符，未命中时返回空字符串
+func (r *compiledRule) matchFile(normalized string) string {
+	if r.excluded(normalized) {
+		return ""
+	}
+	for _, glob := range r.include {
+		if glob.re.MatchString(normalized) {
+			return glob.pattern
+		}
+	}
+	return ""
+}
+
+// matchAllFiles 检查 AllFiles 条件：至少有一个未排除的文件，且所有未排除的文件都命中 PathGlobs
+func (r *compiledRule) matchAllFiles(normalized []string) bool {
+	if !r.AllFiles {
+		return true
+	}
+	matched := false
+	for _, name := range normalized {
+		if r.excluded(name) {
+			continue
+		}
+		if r.matchFile(name) == "" {
+			return false
+		}
+		matched = true
+	}
+	return matched
+}
+
+func (r *compiledRule) excluded(normalized string) bool {
+	for _, glob := range r.exclude {
+		if glob.re.MatchString(normalized) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *compiledRule) matchMetadata(text string) string {
+	if r.metadata == nil || text == "" {
+		return ""
+	}
+	return r.metadata.FindString(text)
+}
+
+func normalizeRulePath(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "\\", "/"))
+}
+
+// Classify 根据VPK内部文件列表和元数据文本（标题和描述）计算标签
+func (c *Classifier) Classify(files []string, metadata string) *Classification {
+	normalized := make([]string, len(files))
+	for i, name := range files {
+		normalized[i] = normalizeRulePath(name)
+	}
+
+	result := &Classification{PrimaryTag: "其他", SecondaryTags: []string{}, Matches: []TagMatch{}}
+	for _, rule := range c.typeRules {
+		if match, ok := rule.match(files, normalized, metadata); ok {
+			match.Tag = rule.PrimaryTag
+			match.Primary = true
+			result.PrimaryTag = rule.PrimaryTag
+			result.Matches = append(result.Matches, match)
+			break
+		}
+	}
+
+	var rules []*compiledRule
+	for _, rule := range c.tagRules[result.PrimaryTag] {
+		if rule.matchAllFiles(normalized) {
+			rules = append(rules, rule)
+		}
+	}
+	added := make(map[string]bool)
+	add := func(rule *compiledRule, match TagMatch) bool {
+		if !added[rule.SecondaryTag] {
+			added[rule.SecondaryTag] = true
+			match.Tag = rule.SecondaryTag
+			result.SecondaryTags = append(result.SecondaryTags, rule.SecondaryTag)
+			result.Matches = append(result.Matches, match)
+		}
+		return rule.Exclusive
+	}
+
+	// 元数据优先于文件名
+	for _, rule := range rules {
+		if text := rule.matchMetadata(metadata); text != "" {
+			if add(rule, TagMatch{Source: "metadata", RuleID: rule.ID, Priority: rule.Priority, Pattern: rule.MetadataRegex, Text: text}) {
+				return result
+			}
+		}
+	}
+
+	// 每个文件只取优先级最高的命中规则
+	for i, name := range normalized {
+		for _, rule := range rules {
+			pattern := rule.matchFile(name)
+			if pattern == "" {
+				continue
+			}
+			if add(rule, TagMatch{Source: "path", RuleID: rule.ID, Priority: rule.Priority, File: files[i], Pattern: pattern}) {
+				return result
+			}
+			break
+		}
+	}
+	return result
+}
+
+// match 检查类型规则，优先报告元数据命中
+func (r *compiledRule) match(files, normalized []string, metadata string) (TagMatch, bool) {
+	if !r.matchAllFiles(normalized) {
+		return TagMatch{}, false
+	}
+	if text := r.matchMetadata(metadata); text != "" {
+		return TagMatch{Source: "metadata", RuleID: r.ID, Priority: r.Priority, Pattern: r.MetadataRegex, Text: text}, true
+	}
+	for i, name := range normalized {
+		if pattern := r.matchFile(name); pattern != "" {
+			return TagMatch{Source: "path", RuleID: r.ID, Priority: r.Priority, File: files[i], Pattern: pattern}, true
+		}
+	}
+	return TagMatch{}, false
+}
+
+// MergeClassificationRules 合并默认规则和用户规则：同 ID 的用户规则替换默认规则，其余追加在后
+func MergeClassificationRules(defaults, overrides []ClassificationRule) []ClassificationRule {
+	merged := append([]ClassificationRule(nil), defaults...)
+	index := make(map[string]int, len(merged))
+	for i, rule := range merged {
+		index[rule.ID] = i
+	}
+	for _, rule := range overrides {
+		if i, ok := index[rule.ID]; ok {
+			merged[i] = rule
+			continue
+		}
+		index[rule.ID] = len(merged)
+		merged = append(merged, rule)
+	}
+	return merged
+}
+
+// PrimaryTags 返回规则集中的一级标签，"其他"固定在最后
+func (c *Classifier) PrimaryTags() []string {
+	tags := make([]string, 0, len(c.primaryTags)+1)
+	for _, tag := range c.primaryTags {
+		if tag != "其他" {
+			tags = append(tags, tag)
+		}
+	}
+	return append(tags, "其他")
+}
+
+var activeClassifier atomic.Pointer[Classifier]
+
+// SetClassifier 设置解析VPK时使用的规则集，传入 nil 时恢复默认规则
+func SetClassifier(c *Classifier) {
+	activeClassifier.Store(c)
+}
+
+func currentClassifier() *Classifier {
+	if c := activeClassifier.Load(); c != nil {
+		return c
+	}
+	return defaultClassifier()
+}
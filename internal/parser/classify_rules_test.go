@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultClassificationRulesMatchBuiltInDetection(t *testing.T) {
+	classifier := defaultClassifier()
+	cases := []struct {
+		name     string
+		files    []string
+		metadata string
+		primary  string
+		tags     string
+	}{
+		{"map wins", []string{"maps/c1m1.bsp", "models/weapons/v_rif_ak47.mdl"}, "", "地图", ""},
+		{"weapon metadata first", []string{"models/weapons/v_rif_ak47.mdl"}, "Desert Rifle replacement", "武器", "三连发"},
+		{"scar needs a whole word", []string{"models/weapons/v_rif_ak47.mdl"}, "Oscar's pack", "武器", "AK47"},
+		{"specific weapon keyword wins", []string{"models/weapons/w_shotgun_chrome.mdl"}, "", "武器", "铁喷"},
+		{"weapon ignores other files", []string{"scripts/weapons/knife.txt", "materials/weapons/magnum.vmt"}, "", "武器", "马格南"},
+		{"one tag per weapon", []string{"models/weapons/v_smg_uzi.mdl", "models/weapons/v_smg_mp5.mdl"}, "", "武器", "乌兹"},
+		{"survivors per file", []string{"models/survivors/survivor_coach.mdl", "models/survivors/survivor_Mechanic.mdl"}, "", "人物", "Coach,Ellis"},
+		{"survivor variant", []string{"models/survivors/survivor_namvet_death.mdl", "models/survivors/survivor_bill_death.mdl"}, "", "人物", "Bill,BillDeathPose"},
+		{"infected", []string{"models/infected/hulk.mdl", "models/infected/common_male_ceda.mdl"}, "", "感染者", "tank,uncommon_infected"},
+		{"character excludes ui", []string{"resource/ui/survivor_panel.res", "scripts/survivor_names.txt"}, "", "界面", ""},
+		{"survivor voice is a sound pack", []string{"sound/player/survivor/voice/coach/hurrah01.wav", "sound/weapons/ak47/gunfire/rifle_fire_1.wav"}, "", "声音", "幸存者语音,武器音效"},
+		{"hud", []string{"scripts/hudlayout.res", "resource/ui/scoreboard_versus.res", "resource/fonts/mine.ttf"}, "", "界面", "HUD,计分板,字体"},
+		{"effects", []string{"particles/weapon_fx.pcf", "materials/effects/muzzleflash1.vtf"}, "", "特效", "粒子,枪口火焰"},
+		{"vscript", []string{"scripts/vscripts/director_base_addon.nut", "scripts/vscripts/helper.nut"}, "", "脚本", "导演脚本,VScript"},
+		{"skybox", []string{"materials/skybox/sky_l4d_rural02_hdrbk.vtf", "materials/skybox/sky_l4d_rural02_hdrbk.vmt"}, "", "贴图", "天空盒"},
+		{"cfg only", []string{"addoninfo.txt", "cfg/autoexec.cfg", "cfg/rates.cfg"}, "", "配置", "autoexec"},
+		{"cfg with other content", []string{"cfg/autoexec.cfg", "models/props/crate.mdl"}, "", "其他", ""},
+	}
+	for _, tc := range cases {
+		result := classifier.Classify(tc.files, tc.metadata)
+		if result.PrimaryTag != tc.primary || strings.Join(result.SecondaryTags, ",") != tc.tags {
+			t.Errorf("%s: expected %s [%s], got %s %v", tc.name, tc.primary, tc.tags, result.PrimaryTag, result.SecondaryTags)
+		}
+	}
+}
+
+func TestClassificationExplainsMatchedRuleAndFile(t *testing.T) {
+	result := defaultClassifier().Classify([]string{"sound/misc.wav", "models/Weapons/melee/v_katana.mdl"}, "")
+	if len(result.Matches) != 2 {
+		t.Fatalf("expected primary and secondary matches, got %+v", result.Matches)
+	}
+	primary, secondary := result.Matches[0], result.Matches[1]
+	if !primary.Primary || primary.RuleID != "type-weapon" || primary.File != "models/Weapons/melee/v_katana.mdl" || primary.Pattern != "**models/weapons/**" {
+		t.Fatalf("unexpected primary match: %+v", primary)
+	}
+	if secondary.Tag != "武士刀" || secondary.Source != "path" || secondary.File != "models/Weapons/melee/v_katana.mdl" {
+		t.Fatalf("unexpected secondary match: %+v", secondary)
+	}
+}
+
+func TestUserClassificationRulesOverrideAndExtendDefaults(t *testing.T) {
+	rules := MergeClassificationRules(DefaultClassificationRules(), []ClassificationRule{
+		{ID: "type-weapon", Disabled: true},
+		{ID: "type-hud", PrimaryTag: "HUD", Priority: 250, PathGlobs: []string{"resource/{ui,scripts}/**.res"}},
+		{ID: "hud-scoreboard", PrimaryTag: "HUD", SecondaryTag: "计分板", PathGlobs: []string{"**/scoreboard*.res"}},
+		{ID: "weapon-meta-m1911", PrimaryTag: "武器", SecondaryTag: "M1911", Priority: 500, MetadataRegex: `m1911|colt`, Exclusive: true},
+	})
+	classifier, err := NewClassifier(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := classifier.Classify([]string{"resource/ui/scoreboard_versus.res", "models/survivors/survivor_coach.mdl"}, "")
+	if result.PrimaryTag != "HUD" || strings.Join(result.SecondaryTags, ",") != "计分板" {
+		t.Fatalf("expected custom HUD rule, got %s %v", result.PrimaryTag, result.SecondaryTags)
+	}
+	if result := classifier.Classify([]string{"models/weapons/v_pistol.mdl"}, "Colt M1911"); result.PrimaryTag != "其他" {
+		t.Fatalf("expected disabled weapon type rule, got %s", result.PrimaryTag)
+	}
+}
+
+func TestNewClassifierRejectsInvalidRules(t *testing.T) {
+	cases := map[string]ClassificationRule{
+		"缺少 id":         {PrimaryTag: "武器", PathGlobs: []string{"**"}},
+		"缺少 primaryTag": {ID: "a", PathGlobs: []string{"**"}},
+		"至少需要":          {ID: "a", PrimaryTag: "武器"},
+		"花括号不匹配":        {ID: "a", PrimaryTag: "武器", PathGlobs: []string{"**.{mdl,vtf"}},
+		"正则无效":          {ID: "a", PrimaryTag: "武器", MetadataRegex: "("},
+	}
+	for want, rule := range cases {
+		if _, err := NewClassifier([]ClassificationRule{rule}); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error containing %q, got %v", want, err)
+		}
+	}
+	if _, err := NewClassifier([]ClassificationRule{{ID: "a", PrimaryTag: "x", PathGlobs: []string{"**"}}, {ID: "a", PrimaryTag: "y", PathGlobs: []string{"**"}}}); err == nil {
+		t.Error("expected duplicate id error")
+	}
+}
@@ -21,3 +21,36 @@ func GetVPKFileList(filePath string) ([]string, error) {
 
 	return files, nil
 }
+
+// VPKEntryInfo VPK内部文件的路径、大小和CRC
+type VPKEntryInfo struct {
+	Name string
+	Size int64
+	CRC  uint32
+}
+
+// GetVPKEntries 获取VPK文件中所有文件的路径、大小和CRC
+func GetVPKEntries(filePath string) ([]VPKEntryInfo, error) {
+	opener := vpk.Single(filePath)
+	defer opener.Close()
+
+	archive, err := opener.ReadArchive()
+	if err != nil {
+		return nil, err
+	}
+
+	return archiveEntries(archive), nil
+}
+
+func archiveEntries(archive *vpk.Archive) []VPKEntryInfo {
+	entries := make([]VPKEntryInfo, 0, len(archive.Files))
+	for i := range archive.Files {
+		file := &archive.Files[i]
+		entries = append(entries, VPKEntryInfo{
+			Name: file.Name(),
+			Size: int64(file.Size()),
+			CRC:  file.CRC,
+		})
+	}
+	return entries
+}
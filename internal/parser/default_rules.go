@@ -0,0 +1,349 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// weaponFileExts 武器标签只检查这些类型的文件
+const weaponFileExts = "{mdl,vmt,vtf,wav,mp3}"
+
+// weaponMetadataRules addoninfo 标题和描述中的武器关键词，按顺序匹配，先命中者优先
+var weaponMetadataRules = []struct {
+	tag   string
+	regex string
+}{
+	// 步枪
+	{"AK47", `ak-?47`},
+	{"M16", `m16`},
+	{"sg552", `sg552`},
+	{"三连发", `\bscar\b|combat[ -]rifle|desert[ -]rifle`}, // scar 需完整单词，防止匹配到 oscar 等词
+	{"M60", `m60`},
+
+	// 冲锋枪
+	{"乌兹", `uzi`},
+	{"消音", `silenced[ -]smg|mac[ -]?10`},
+	{"MP5", `mp5`},
+
+	// 狙击枪
+	{"猎枪", `hunting[ -]rifle|mini14`},
+	{"军狙", `military[ -]sniper`},
+	{"鸟狙", `scout`},
+	{"大狙", `awp`},
+
+	// 霰弹枪
+	{"铁喷", `chrome`},
+	{"木喷", `pump[ -]shotgun`},
+	{"一代连喷", `auto[ -]?shotgun`},
+	{"二代连喷", `spas`},
+
+	// 手枪
+	{"马格南", `magnum|desert[ -]eagle`},
+	{"小手枪", `glock|p220|pistol`},
+
+	// 发射器
+	{"榴弹", `grenade[ -]launcher`},
+
+	// 近战武器
+	{"砍刀", `machete`},
+	{"武士刀", `katana`},
+	{"棒球棍", `baseball bat`},
+	{"匕首", `knife`},
+	{"电锯", `chainsaw`},
+	{"撬棍", `crowbar`},
+	{"消防斧", `fireaxe`},
+	{"平底锅", `frying pan`},
+	{"吉他", `guitar`},
+	{"板球拍", `cricket bat`},
+	{"警棍", `tonfa|nightstick`},
+	{"高尔夫球杆", `golf club`},
+	{"铁铲", `shovel`},
+	{"草叉", `pitchfork`},
+}
+
+// weaponFileRules 武器文件名关键词。desert 和 w_shotgun 会出现在其他武器的文件名中，优先级较低
+var weaponFileRules = []struct {
+	tag      string
+	priority int
+	keywords []string
+}{
+	// 步枪
+	{"AK47", 50, []string{"ak47"}},
+	{"三连发", 40, []string{"desert", "desert_rifle"}},
+	{"M16", 50, []string{"m16", "m16a2"}},
+	{"sg552", 50, []string{"sg552"}},
+	{"M60", 50, []string{"m60"}},
+
+	// 狙击枪
+	{"大狙", 50, []string{"awp"}},
+	{"军狙", 50, []string{"sniper_military", "sniper_a"}},
+	{"猎枪", 50, []string{"hunting_rifle", "w_sniper_mini14"}},
+	{"鸟狙", 50, []string{"sniper_scout"}},
+
+	// 霰弹枪
+	{"铁喷", 50, []string{"chrome", "m1014"}},
+	{"木喷", 40, []string{"w_shotgun"}},
+	{"一代连喷", 50, []string{"autoshotgun"}},
+	{"二代连喷", 50, []string{"spas"}},
+
+	// 冲锋枪
+	{"乌兹", 50, []string{"uzi"}},
+	{"消音", 50, []string{"smg_a", "smg_silenced"}},
+	{"MP5", 50, []string{"mp5"}},
+
+	// 手枪
+	{"马格南", 50, []string{"magnum", "w_desert_eagle"}},
+	{"小手枪", 50, []string{"pistol_glock", "w_pistol_glock", "w_pistol_b"}},
+
+	// 发射器
+	{"榴弹", 50, []string{"grenade_launcher"}},
+
+	// 近战武器
+	{"砍刀", 50, []string{"machete"}},
+	{"武士刀", 50, []string{"katana"}},
+	{"棒球棍", 50, []string{"baseball_bat", "w_bat"}},
+	{"匕首", 50, []string{"knife"}},
+	{"电锯", 50, []string{"chainsaw"}},
+	{"撬棍", 50, []string{"crowbar"}},
+	{"消防斧", 50, []string{"fireaxe"}},
+	{"平底锅", 50, []string{"frying_pan"}},
+	{"吉他", 50, []string{"electric_guitar", "w_guitar"}},
+	{"板球拍", 50, []string{"cricket_bat"}},
+	{"警棍", 50, []string{"tonfa"}},
+	{"高尔夫球杆", 50, []string{"golf_club"}},
+	{"铁铲", 50, []string{"shovel"}},
+	{"草叉", 50, []string{"pitchfork"}},
+}
+
+// characterRules 幸存者和感染者关键词 - 基于NekoVpk识别模式。文件路径需同时包含 anchors 之一和关键词
+var characterRules = []struct {
+	id       string
+	primary  string
+	tag      string
+	priority int
+	anchors  []string
+	keywords []string
+}{
+	// 幸存者特殊变体优先于普通角色
+	{"survivor-bill-death", "人物", "BillDeathPose", 20, []string{"survivor"}, []string{"bill_death", "billdeath", "bill_corpse", "billcorpse"}},
+	{"survivor-francis-light", "人物", "FrancisLight", 20, []string{"survivor"}, []string{"francis_light", "francislight", "francis_flashlight", "francisflashlight"}},
+	{"survivor-zoey-light", "人物", "ZoeyLight", 20, []string{"survivor"}, []string{"zoey_light", "zoeylight", "zoey_flashlight", "zoeyflashlight"}},
+
+	// 幸存者（使用英文代码而非中文名）
+	{"survivor-bill", "人物", "Bill", 10, []string{"survivor"}, []string{"bill", "namvet"}},
+	{"survivor-francis", "人物", "Francis", 10, []string{"survivor"}, []string{"francis", "biker"}},
+	{"survivor-louis", "人物", "Louis", 10, []string{"survivor"}, []string{"louis", "manager"}},
+	{"survivor-zoey", "人物", "Zoey", 10, []string{"survivor"}, []string{"zoey", "teenangst"}},
+	{"survivor-coach", "人物", "Coach", 10, []string{"survivor"}, []string{"coach"}},
+	{"survivor-ellis", "人物", "Ellis", 10, []string{"survivor"}, []string{"ellis", "mechanic"}},
+	{"survivor-nick", "人物", "Nick", 10, []string{"survivor"}, []string{"nick", "gambler"}},
+	{"survivor-rochelle", "人物", "Rochelle", 10, []string{"survivor"}, []string{"rochelle", "producer"}},
+
+	// 特殊感染者
+	{"infected-tank", "感染者", "tank", 20, []string{"infected", "zombie"}, []string{"tank", "hulk"}}, // hulk 是 L4D1 中 Tank 的内部名称
+	{"infected-witch", "感染者", "witch", 20, []string{"infected", "zombie"}, []string{"witch"}},
+	{"infected-hunter", "感染者", "hunter", 20, []string{"infected", "zombie"}, []string{"hunter"}},
+	{"infected-smoker", "感染者", "smoker", 20, []string{"infected", "zombie"}, []string{"smoker"}},
+	{"infected-boomer", "感染者", "boomer", 20, []string{"infected", "zombie"}, []string{"boomer"}},
+	{"infected-charger", "感染者", "charger", 20, []string{"infected", "zombie"}, []string{"charger"}},
+	{"infected-jockey", "感染者", "jockey", 20, []string{"infected", "zombie"}, []string{"jockey"}},
+	{"infected-spitter", "感染者", "spitter", 20, []string{"infected", "zombie"}, []string{"spitter"}},
+
+	// 普通感染者，特殊普通感染者（CEDA、小丑、泥人、道路工人、吉米·吉布斯Jr、防暴警察、堕落幸存者）优先
+	{"infected-uncommon", "感染者", "uncommon_infected", 15, []string{"infected", "zombie"}, []string{"uncommon", "ceda", "clown", "mud", "roadcrew", "jimmy", "riot", "fallen"}},
+	{"infected-common", "感染者", "common", 10, []string{"infected", "zombie"}, []string{"common", "zombie", "infected"}},
+}
+
+// characterExcludeGlobs 人物和感染者类型不考虑的文件
+var characterExcludeGlobs = []string{"**resource/ui/**", "**scripts/**", "**.res**", "sound/**"}
+
+// contentTagRules 其他类型的二级标签，依据命中的目录或文件名
+var contentTagRules = []struct {
+	id       string
+	primary  string
+	tag      string
+	priority int
+	globs    []string
+}{
+	// 脚本
+	{"script-director", "脚本", "导演脚本", 20, []string{"scripts/vscripts/**director**"}},
+	{"script-mutation", "脚本", "突变模式", 20, []string{"scripts/vscripts/**mutation**", "modes/**"}},
+	{"script-melee", "脚本", "近战脚本", 20, []string{"scripts/melee/**"}},
+	{"script-talker", "脚本", "对话", 20, []string{"scripts/talker/**"}},
+	{"script-population", "脚本", "感染者生成", 20, []string{"scripts/population.txt"}},
+	{"script-vscript", "脚本", "VScript", 10, []string{"**.{nut,nuc}"}},
+
+	// 界面
+	{"hud-scoreboard", "界面", "计分板", 30, []string{"**scoreboard**"}},
+	{"hud-mainmenu", "界面", "主菜单", 30, []string{"resource/ui/**mainmenu**"}},
+	{"hud-loading", "界面", "加载界面", 30, []string{"materials/vgui/loadingscreen**", "resource/ui/**loading**"}},
+	{"hud-crosshair", "界面", "准星", 30, []string{"**crosshair**"}},
+	{"hud-font", "界面", "字体", 20, []string{"**.{ttf,otf}", "resource/clientscheme.res"}},
+	{"hud-hud", "界面", "HUD", 10, []string{"scripts/hudlayout.res", "resource/ui/hud*", "resource/ui/hud*/**", "materials/vgui/hud/**"}},
+
+	// 特效
+	{"effect-muzzleflash", "特效", "枪口火焰", 30, []string{"**muzzleflash**"}},
+	{"effect-blood", "特效", "血液", 20, []string{"**blood**"}},
+	{"effect-explosion", "特效", "爆炸", 20, []string{"**explosion**"}},
+	{"effect-fire", "特效", "火焰", 20, []string{"**fire**", "**flame**"}},
+	{"effect-smoke", "特效", "烟雾", 20, []string{"**smoke**"}},
+	{"effect-particles", "特效", "粒子", 10, []string{"particles/**"}},
+
+	// 声音，按替换的目录标记
+	{"sound-voice", "声音", "幸存者语音", 20, []string{"sound/player/survivor/**"}},
+	{"sound-infected", "声音", "感染者音效", 20, []string{"sound/npc/**", "sound/player/{boomer,charger,hunter,jockey,smoker,spitter,tank,witch}/**"}},
+	{"sound-weapons", "声音", "武器音效", 20, []string{"sound/weapons/**"}},
+	{"sound-music", "声音", "音乐", 20, []string{"sound/music/**"}},
+	{"sound-ambient", "声音", "环境音", 20, []string{"sound/ambient/**"}},
+	{"sound-ui", "声音", "界面音效", 20, []string{"sound/ui/**"}},
+	{"sound-physics", "声音", "物理音效", 20, []string{"sound/physics/**"}},
+	{"sound-items", "声音", "道具音效", 20, []string{"sound/items/**"}},
+	{"sound-scripts", "声音", "声音脚本", 20, []string{"scripts/game_sounds*.txt"}},
+
+	// 贴图
+	{"texture-skybox", "贴图", "天空盒", 20, []string{"materials/skybox/**"}},
+	{"texture-decals", "贴图", "贴花", 20, []string{"materials/decals/**"}},
+	{"texture-props", "贴图", "道具", 20, []string{"materials/models/props*/**"}},
+	{"texture-world", "贴图", "场景", 10, []string{"materials/{brick,concrete,metal,wood,nature,tile,plaster,stone,glass,building_template}/**"}},
+
+	// 配置
+	{"config-autoexec", "配置", "autoexec", 20, []string{"**autoexec.cfg"}},
+	{"config-server", "配置", "服务器配置", 20, []string{"**server*.cfg"}},
+}
+
+// anchoredGlobs 生成"路径同时包含 anchor 和 keyword"的通配符
+func anchoredGlobs(anchors, keywords []string) []string {
+	var globs []string
+	for _, keyword := range keywords {
+		for _, anchor := range anchors {
+			switch {
+			case strings.Contains(keyword, anchor):
+				globs = append(globs, "**"+keyword+"**")
+			case strings.Contains(anchor, keyword):
+				globs = append(globs, "**"+anchor+"**")
+			default:
+				globs = append(globs, "**"+anchor+"**"+keyword+"**", "**"+keyword+"**"+anchor+"**")
+			}
+		}
+	}
+	return globs
+}
+
+// DefaultClassificationRules 内置的分类规则，用户规则可通过相同 ID 覆盖或停用
+func DefaultClassificationRules() []ClassificationRule {
+	rules := []ClassificationRule{
+		// 地图最高优先级，发现 .bsp 就直接确定类型
+		{ID: "type-map", PrimaryTag: "地图", Priority: 300, PathGlobs: []string{"**.bsp"}},
+		// 幸存者文件，排除UI/HUD、脚本和语音
+		{
+			ID:           "type-character",
+			PrimaryTag:   "人物",
+			Priority:     200,
+			PathGlobs:    []string{"**survivor**"},
+			ExcludeGlobs: characterExcludeGlobs,
+		},
+		{
+			ID:           "type-infected",
+			PrimaryTag:   "感染者",
+			Priority:     190,
+			PathGlobs:    []string{"**infected**", "**zombie**"},
+			ExcludeGlobs: characterExcludeGlobs,
+		},
+		{
+			ID:         "type-weapon",
+			PrimaryTag: "武器",
+			Priority:   100,
+			PathGlobs:  []string{"**models/weapons/**", "**scripts/weapons/**", "**materials/weapons/**"},
+		},
+		{
+			ID:         "type-script",
+			PrimaryTag: "脚本",
+			Priority:   90,
+			PathGlobs:  []string{"scripts/vscripts/**", "**.{nut,nuc}", "scripts/melee/**", "scripts/talker/**", "scripts/population.txt", "modes/**"},
+		},
+		{
+			ID:         "type-hud",
+			PrimaryTag: "界面",
+			Priority:   80,
+			PathGlobs:  []string{"resource/**", "scripts/hudlayout.res", "materials/vgui/**", "**.{ttf,otf}"},
+		},
+		{
+			ID:         "type-effect",
+			PrimaryTag: "特效",
+			Priority:   70,
+			PathGlobs:  []string{"particles/**", "materials/{particle,particles,effects,sprites}/**"},
+		},
+		{ID: "type-sound", PrimaryTag: "声音", Priority: 60, PathGlobs: []string{"sound/**", "scripts/game_sounds*.txt"}},
+		{ID: "type-texture", PrimaryTag: "贴图", Priority: 50, PathGlobs: []string{"materials/**.{vtf,vmt}"}},
+		// 只包含 cfg 的模组，忽略根目录的说明文件和 addonimage
+		{
+			ID:           "type-config",
+			PrimaryTag:   "配置",
+			Priority:     40,
+			PathGlobs:    []string{"cfg/**", "**.cfg"},
+			ExcludeGlobs: []string{"*.txt", "addonimage.*"},
+			AllFiles:     true,
+		},
+	}
+
+	for i, rule := range weaponMetadataRules {
+		rules = append(rules, ClassificationRule{
+			ID:            fmt.Sprintf("weapon-metadata-%02d", i+1),
+			PrimaryTag:    "武器",
+			SecondaryTag:  rule.tag,
+			Priority:      100 - i,
+			MetadataRegex: rule.regex,
+			Exclusive:     true,
+		})
+	}
+	for i, rule := range weaponFileRules {
+		globs := make([]string, len(rule.keywords))
+		for j, keyword := range rule.keywords {
+			globs[j] = "**" + keyword + "**." + weaponFileExts
+		}
+		rules = append(rules, ClassificationRule{
+			ID:           fmt.Sprintf("weapon-file-%02d", i+1),
+			PrimaryTag:   "武器",
+			SecondaryTag: rule.tag,
+			Priority:     rule.priority,
+			PathGlobs:    globs,
+			Exclusive:    true,
+		})
+	}
+	for _, rule := range characterRules {
+		rules = append(rules, ClassificationRule{
+			ID:           rule.id,
+			PrimaryTag:   rule.primary,
+			SecondaryTag: rule.tag,
+			Priority:     rule.priority,
+			PathGlobs:    anchoredGlobs(rule.anchors, rule.keywords),
+		})
+	}
+	for _, rule := range contentTagRules {
+		rules = append(rules, ClassificationRule{
+			ID:           rule.id,
+			PrimaryTag:   rule.primary,
+			SecondaryTag: rule.tag,
+			Priority:     rule.priority,
+			PathGlobs:    rule.globs,
+		})
+	}
+	return rules
+}
+
+var (
+	defaultClassifierOnce  sync.Once
+	defaultClassifierValue *Classifier
+)
+
+func defaultClassifier() *Classifier {
+	defaultClassifierOnce.Do(func() {
+		c, err := NewClassifier(DefaultClassificationRules())
+		if err != nil {
+			panic(fmt.Sprintf("内置分类规则无效: %v", err))
+		}
+		defaultClassifierValue = c
+	})
+	return defaultClassifierValue
+}
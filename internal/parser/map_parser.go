@@ -104,6 +104,23 @@ func ProcessMapVPK(opener *vpk.Opener, archive *vpk.Archive, vpkFile *VPKFile, s
 	if firstMode != "" {
 		vpkFile.Mode = firstMode
 	}
+
+	// 读取每张地图的 BSP 头、实体和内嵌 pakfile，只保留摘要；完整结果留在缓存中供地图详情和冲突检测使用
+	maps, _ := cachedVPKMaps(vpkFile.Path, func() ([]BSPMapInfo, error) {
+		return analyzeArchiveMaps(opener, archive), nil
+	})
+	for _, info := range maps {
+		if info.Message != "" {
+			log.Printf("地图分析: %s, %s", info.Path, info.Message)
+		}
+		vpkFile.Maps = append(vpkFile.Maps, info.Summary())
+	}
+
+	// 校验战役完整性：章节地图的 BSP/NAV、模式格式和必需字段
+	vpkFile.MissionWarnings = validateArchiveMissions(opener, archive, maps)
+	if len(vpkFile.MissionWarnings) > 0 {
+		log.Printf("战役校验发现 %d 个问题: %s", len(vpkFile.MissionWarnings), vpkFile.Name)
+	}
 }
 
 // ParseMissionFile 解析mission文件，提取战役和章节信息
@@ -0,0 +1,199 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"l4d2-manager-next/pkg/valve/vdf"
+	"l4d2-manager-next/pkg/valve/vpk"
+)
+
+// 战役校验问题类型
+const (
+	MissionWarningParse            = "parse"             // mission 文件无法解析
+	MissionWarningNoMission        = "no_mission"        // 有地图但没有 missions/*.txt
+	MissionWarningMissingKey       = "missing_key"       // 缺少必需字段
+	MissionWarningMode             = "mode"              // 模式或章节格式错误
+	MissionWarningMissingBSP       = "missing_bsp"       // 章节地图缺少 .bsp
+	MissionWarningMissingNAV       = "missing_nav"       // 章节地图缺少 .nav
+	MissionWarningDuplicateChapter = "duplicate_chapter" // 章节代码与其他已启用地图重复
+)
+
+// missionRequiredKeys mission 根节点必需的字段，缺少时战役不会出现在游戏菜单中
+var missionRequiredKeys = []string{"Name", "Version", "DisplayTitle", "modes"}
+
+// missionKnownModes mission 文件 modes 下可出现的游戏模式
+var missionKnownModes = map[string]bool{
+	"coop":       true,
+	"versus":     true,
+	"survival":   true,
+	"scavenge":   true,
+	"realism":    true,
+	"holdout":    true,
+	"dash":       true,
+	"shootzones": true,
+}
+
+// MissionWarning 战役完整性或 mission 文件格式问题
+type MissionWarning struct {
+	Code    string `json:"code"`              // 问题类型，见 MissionWarning* 常量
+	File    string `json:"file,omitempty"`    // mission 文件路径
+	Chapter string `json:"chapter,omitempty"` // 章节代码（地图名）
+	Message string `json:"message"`
+}
+
+// validateArchiveMissions 校验VPK中所有 missions/*.txt。maps 为地图分析结果，
+// 内嵌 pakfile 中的 .nav 同样会被引擎加载，视为存在
+func validateArchiveMissions(opener *vpk.Opener, archive *vpk.Archive, maps []BSPMapInfo) []MissionWarning {
+	files := make(map[string]bool, len(archive.Files))
+	missionFiles := make([]*vpk.File, 0)
+	for i := range archive.Files {
+		name := normalizeModelStatPath(archive.Files[i].Name())
+		files[name] = true
+		if strings.HasPrefix(name, "missions/") && strings.HasSuffix(name, ".txt") {
+			missionFiles = append(missionFiles, &archive.Files[i])
+		}
+	}
+	for _, m := range maps {
+		for _, file := range m.PakFiles {
+			files[file.Name] = true
+		}
+	}
+
+	warnings := make([]MissionWarning, 0)
+	if len(missionFiles) == 0 {
+		if len(maps) > 0 {
+			warnings = append(warnings, MissionWarning{
+				Code:    MissionWarningNoMission,
+				Message: "包含地图但没有 missions/*.txt，战役不会出现在游戏菜单中",
+			})
+		}
+		return warnings
+	}
+	for _, file := range missionFiles {
+		name := normalizeModelStatPath(file.Name())
+		data, err := readVPKFileBytes(opener, file)
+		if err != nil {
+			warnings = append(warnings, MissionWarning{Code: MissionWarningParse, File: name, Message: fmt.Sprintf("无法读取: %v", err)})
+			continue
+		}
+		warnings = append(warnings, ValidateMissionContent(name, data, func(path string) bool { return files[path] })...)
+	}
+	return warnings
+}
+
+// ValidateMissionContent 校验 mission 文件的必需字段、模式和章节格式，
+// 并检查每个章节地图的 .bsp 和 .nav 是否存在。hasFile 接收小写、以 / 分隔的路径
+func ValidateMissionContent(name string, data []byte, hasFile func(path string) bool) []MissionWarning {
+	warnings := make([]MissionWarning, 0)
+	add := func(code, chapter, format string, args ...any) {
+		warnings = append(warnings, MissionWarning{Code: code, File: name, Chapter: chapter, Message: fmt.Sprintf(format, args...)})
+	}
+
+	root, err := vdf.ReadTolerant(bytes.NewReader(data))
+	if err != nil {
+		add(MissionWarningParse, "", "解析失败: %v", err)
+		return warnings
+	}
+	if !strings.EqualFold(root.Key, "mission") {
+		add(MissionWarningParse, "", "根节点应为 \"mission\"，实际为 %q", root.Key)
+	}
+	for _, key := range missionRequiredKeys {
+		value := root.FindKey(key)
+		if value == nil || (value.HasValue && strings.TrimSpace(value.Value) == "") {
+			add(MissionWarningMissingKey, "", "缺少必需字段 %q", key)
+		}
+	}
+
+	modes := root.FindKey("modes")
+	if modes == nil {
+		return warnings
+	}
+	if modes.HasValue {
+		add(MissionWarningMode, "", "\"modes\" 应为包含各模式的块")
+		return warnings
+	}
+
+	chapterMaps := make(map[string]string) // 小写地图名 -> 原始写法
+	for mode := modes.FirstSubKey(); mode != nil; mode = mode.NextSubKey() {
+		modeName := strings.ToLower(mode.Key)
+		if !missionKnownModes[modeName] {
+			add(MissionWarningMode, "", "未知的游戏模式 %q", mode.Key)
+		}
+		if mode.HasValue {
+			add(MissionWarningMode, "", "模式 %q 应为包含章节的块", mode.Key)
+			continue
+		}
+
+		numbers := make([]int, 0)
+		modeMaps := make(map[string]bool)
+		for chapter := mode.FirstSubKey(); chapter != nil; chapter = chapter.NextSubKey() {
+			number, err := strconv.Atoi(chapter.Key)
+			if err != nil || number < 1 {
+				add(MissionWarningMode, "", "模式 %q 的章节编号 %q 应为从 1 开始的数字", mode.Key, chapter.Key)
+			} else {
+				numbers = append(numbers, number)
+			}
+			if chapter.HasValue {
+				add(MissionWarningMode, "", "模式 %q 的章节 %q 应为块", mode.Key, chapter.Key)
+				continue
+			}
+
+			mapName := missionChapterValue(chapter, "Map")
+			if mapName == "" {
+				add(MissionWarningMissingKey, "", "模式 %q 的章节 %q 缺少 \"Map\"", mode.Key, chapter.Key)
+				continue
+			}
+			if missionChapterValue(chapter, "DisplayName") == "" {
+				add(MissionWarningMissingKey, mapName, "模式 %q 的章节 %q 缺少 \"DisplayName\"", mode.Key, chapter.Key)
+			}
+			lowerMap := strings.ToLower(mapName)
+			if modeMaps[lowerMap] {
+				add(MissionWarningMode, mapName, "地图 %s 在模式 %q 中重复出现", mapName, mode.Key)
+			}
+			modeMaps[lowerMap] = true
+			if _, exists := chapterMaps[lowerMap]; !exists {
+				chapterMaps[lowerMap] = mapName
+			}
+		}
+
+		if len(numbers) == 0 {
+			add(MissionWarningMode, "", "模式 %q 没有任何章节", mode.Key)
+			continue
+		}
+		sort.Ints(numbers)
+		for i, number := range numbers {
+			if number != i+1 {
+				add(MissionWarningMode, "", "模式 %q 的章节编号不连续，应为 1 到 %d", mode.Key, len(numbers))
+				break
+			}
+		}
+	}
+
+	lowerMaps := make([]string, 0, len(chapterMaps))
+	for lowerMap := range chapterMaps {
+		lowerMaps = append(lowerMaps, lowerMap)
+	}
+	sort.Strings(lowerMaps)
+	for _, lowerMap := range lowerMaps {
+		mapName := chapterMaps[lowerMap]
+		if !hasFile("maps/" + lowerMap + ".bsp") {
+			add(MissionWarningMissingBSP, mapName, "章节地图缺少 maps/%s.bsp", mapName)
+		}
+		if !hasFile("maps/" + lowerMap + ".nav") {
+			add(MissionWarningMissingNAV, mapName, "章节地图缺少 maps/%s.nav，特感和队友无法寻路", mapName)
+		}
+	}
+	return warnings
+}
+
+func missionChapterValue(chapter *vdf.KeyValues, key string) string {
+	value := chapter.FindKey(key)
+	if value == nil || !value.HasValue {
+		return ""
+	}
+	return strings.TrimSpace(value.Value)
+}
@@ -0,0 +1,135 @@
+package parser
+
+import (
+	"path/filepath"
+	"testing"
+
+	"vpk-manager/internal/parser/parsertest"
+)
+
+func missionWarningCodes(warnings []MissionWarning) map[string]int {
+	codes := make(map[string]int)
+	for _, warning := range warnings {
+		codes[warning.Code]++
+	}
+	return codes
+}
+
+func TestValidateMissionContent(t *testing.T) {
+	valid := `"mission"
+{
+	"Name" "TestCampaign"
+	"Version" "1"
+	"DisplayTitle" "Test Campaign"
+	"modes"
+	{
+		"coop"
+		{
+			"1" { "Map" "c1m1_test" "DisplayName" "Start" }
+			"2" { "Map" "c1m2_test" "DisplayName" "End" }
+		}
+		"versus"
+		{
+			"1" { "Map" "c1m1_test" "DisplayName" "Start" }
+			"2" { "Map" "c1m2_test" "DisplayName" "End" }
+		}
+	}
+}`
+	files := map[string]bool{
+		"maps/c1m1_test.bsp": true, "maps/c1m1_test.nav": true,
+		"maps/c1m2_test.bsp": true, "maps/c1m2_test.nav": true,
+	}
+	hasFile := func(path string) bool { return files[path] }
+	if warnings := ValidateMissionContent("missions/test.txt", []byte(valid), hasFile); len(warnings) != 0 {
+		t.Fatalf("expected no warnings, got %+v", warnings)
+	}
+
+	broken := `"mission"
+{
+	"Name" "TestCampaign"
+	"DisplayTitle" ""
+	"modes"
+	{
+		"coop"
+		{
+			"1" { "Map" "C1M1_Test" "DisplayName" "Start" }
+			"3" { "Map" "c1m3_missing" }
+			"x" { "Map" "c1m1_test" "DisplayName" "Again" }
+		}
+		"coop_plus"
+		{
+		}
+		"survival" "c1m1_test"
+	}
+}`
+	delete(files, "maps/c1m1_test.nav")
+	warnings := ValidateMissionContent("missions/test.txt", []byte(broken), hasFile)
+	codes := missionWarningCodes(warnings)
+	// Version 和 DisplayTitle 缺失，c1m3_missing 缺少 DisplayName
+	if codes[MissionWarningMissingKey] != 3 {
+		t.Fatalf("expected 3 missing key warnings, got %+v", warnings)
+	}
+	// 编号 x、编号不连续、coop 内重复地图、未知模式、空模式、survival 不是块
+	if codes[MissionWarningMode] != 6 {
+		t.Fatalf("expected 6 mode warnings, got %+v", warnings)
+	}
+	if codes[MissionWarningMissingBSP] != 1 || codes[MissionWarningMissingNAV] != 2 {
+		t.Fatalf("expected missing bsp/nav warnings, got %+v", warnings)
+	}
+	for _, warning := range warnings {
+		if warning.Code == MissionWarningMissingBSP && warning.Chapter != "c1m3_missing" {
+			t.Fatalf("unexpected missing bsp chapter %+v", warning)
+		}
+		if warning.File != "missions/test.txt" {
+			t.Fatalf("expected mission file path on every warning, got %+v", warning)
+		}
+	}
+
+	if warnings := ValidateMissionContent("missions/bad.txt", []byte(`"mission" { "Name" `), hasFile); len(warnings) != 1 || warnings[0].Code != MissionWarningParse {
+		t.Fatalf("expected parse warning, got %+v", warnings)
+	}
+}
+
+func TestParseVPKFileMissionWarnings(t *testing.T) {
+	vpkPath := filepath.Join(t.TempDir(), "campaign.vpk")
+	writeParserTestVPK(t, vpkPath, map[string][]byte{
+		"missions/test.txt": []byte(`"mission"
+{
+	"Name" "TestCampaign"
+	"Version" "1"
+	"DisplayTitle" "Test Campaign"
+	"modes"
+	{
+		"coop"
+		{
+			"1" { "Map" "c1m1_test" "DisplayName" "Start" }
+			"2" { "Map" "c1m2_test" "DisplayName" "End" }
+		}
+	}
+}`),
+		"maps/c1m1_test.bsp": parsertest.BuildBSP(t, true, testBSPEntities, nil, map[string][]byte{"maps/c1m1_test.nav": []byte("nav")}),
+	})
+
+	vpkFile, err := ParseVPKFile(vpkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := vpkFile.Chapters["c1m1_test"]; !ok {
+		t.Fatalf("expected chapters to be parsed, got %+v", vpkFile.Chapters)
+	}
+	// c1m1_test 的 .nav 打包在内嵌 pakfile 中，只有 c1m2_test 缺文件
+	codes := missionWarningCodes(vpkFile.MissionWarnings)
+	if len(vpkFile.MissionWarnings) != 2 || codes[MissionWarningMissingBSP] != 1 || codes[MissionWarningMissingNAV] != 1 || vpkFile.MissionWarnings[0].Chapter != "c1m2_test" {
+		t.Fatalf("unexpected mission warnings %+v", vpkFile.MissionWarnings)
+	}
+
+	mapOnly := filepath.Join(t.TempDir(), "maponly.vpk")
+	writeParserTestVPK(t, mapOnly, map[string][]byte{"maps/c1m1_test.bsp": []byte("VBSP")})
+	vpkFile, err = ParseVPKFile(mapOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vpkFile.MissionWarnings) != 1 || vpkFile.MissionWarnings[0].Code != MissionWarningNoMission {
+		t.Fatalf("expected missing mission warning, got %+v", vpkFile.MissionWarnings)
+	}
+}
@@ -0,0 +1,912 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+
+	"l4d2-manager-next/pkg/valve/vpk"
+)
+
+const (
+	mdlBoneSize   = 216
+	mdlModelSize  = 148
+	mdlMeshSize   = 116
+	vvdVertexSize = 48
+	vvdFixupSize  = 12
+	vtxVertexSize = 9
+
+	// gltfUnitScale converts Source units (inches) to glTF meters.
+	gltfUnitScale = 0.0254
+	// gltfMaxTextureSize limits the edge of textures embedded into the GLB.
+	gltfMaxTextureSize = 1024
+	// vmtMaxIncludeDepth limits how many Patch materials are followed.
+	vmtMaxIncludeDepth = 4
+
+	gltfComponentUnsignedShort = 5123
+	gltfComponentUnsignedInt   = 5125
+	gltfComponentFloat         = 5126
+	gltfTargetArrayBuffer      = 34962
+	gltfTargetElementBuffer    = 34963
+)
+
+// gltfSourceRotation rotates Source's Z-up space into glTF's Y-up space
+// (-90 degrees around X).
+var gltfSourceRotation = []float32{-math.Sqrt2 / 2, 0, 0, math.Sqrt2 / 2}
+
+// ModelGLBInfo summarizes a model converted by ConvertVPKModelToGLB.
+type ModelGLBInfo struct {
+	Path            string   `json:"path"`
+	Vertices        int      `json:"vertices"`
+	Triangles       int      `json:"triangles"`
+	Primitives      int      `json:"primitives"`
+	Bones           int      `json:"bones"`
+	Textures        int      `json:"textures"`
+	MissingTextures []string `json:"missingTextures"` // materials without a decodable $basetexture in the VPK
+}
+
+// mdlBone is one bone of the MDL reference pose. PoseToBone is the 3x4
+// row-major matrix transforming model space into bone space.
+type mdlBone struct {
+	Name       string
+	Parent     int
+	Position   [3]float32
+	Quaternion [4]float32
+	PoseToBone [12]float32
+}
+
+// mdlMesh is one mesh of a body part's default model. VertexBase is the
+// index of the mesh's first vertex in the LOD0 VVD vertex list.
+type mdlMesh struct {
+	Material   int
+	VertexBase int
+}
+
+// mdlGeometry holds the MDL data needed to assemble LOD0 geometry.
+type mdlGeometry struct {
+	Bones []mdlBone
+	// BodyParts holds the meshes of model 0 of every body part.
+	BodyParts [][]mdlMesh
+	// SkinRefs maps mesh material indices to texture indices for skin family 0.
+	SkinRefs []int
+}
+
+type vvdVertex struct {
+	Weights  [3]float32
+	Bones    [3]int8
+	NumBones uint8
+	Position [3]float32
+	Normal   [3]float32
+	UV       [2]float32
+}
+
+// gltfMaterialSource is the VMT data used for one glTF material.
+type gltfMaterialSource struct {
+	Name        string
+	PNG         []byte
+	AlphaMode   string
+	AlphaCutoff float32
+	DoubleSided bool
+}
+
+// ConvertVPKModelToGLB converts LOD0 of a model inside a VPK to a binary glTF
+// 2.0 file with normals, UVs, bone weights and the decoded VTF base textures.
+// Default body group models and skin family 0 are used.
+func ConvertVPKModelToGLB(filePath string, modelPath string) ([]byte, ModelGLBInfo, error) {
+	opener := vpk.Single(filePath)
+	defer opener.Close()
+
+	archive, err := opener.ReadArchive()
+	if err != nil {
+		return nil, ModelGLBInfo{}, err
+	}
+	files := make(map[string]*vpk.File, len(archive.Files))
+	for i := range archive.Files {
+		files[normalizeModelStatPath(archive.Files[i].Name())] = &archive.Files[i]
+	}
+
+	mdlPath := normalizeModelStatPath(modelPath)
+	if !isModelMDLPath(mdlPath) {
+		return nil, ModelGLBInfo{}, fmt.Errorf("不是模型文件: %s", modelPath)
+	}
+	base := strings.TrimSuffix(mdlPath, ".mdl")
+	sources := make(map[string][]byte, 3)
+	for _, name := range []string{mdlPath, base + ".vvd", base + ".dx90.vtx"} {
+		file := files[name]
+		if file == nil {
+			return nil, ModelGLBInfo{}, fmt.Errorf("VPK 中未找到 %s", name)
+		}
+		data, err := readVPKFileBytes(opener, file)
+		if err != nil {
+			return nil, ModelGLBInfo{}, fmt.Errorf("读取 %s 失败: %w", name, err)
+		}
+		sources[name] = data
+	}
+
+	var stat ModelStat
+	if err := parseMDLHeader(sources[mdlPath], &stat); err != nil {
+		return nil, ModelGLBInfo{}, fmt.Errorf("解析 .mdl 失败: %w", err)
+	}
+	materials := make([]gltfMaterialSource, len(stat.Materials))
+	for i, material := range stat.Materials {
+		materials[i] = loadGLTFMaterial(opener, files, &stat, material)
+	}
+
+	data, info, err := convertModelToGLB(stat.Name, sources[mdlPath], sources[base+".vvd"], sources[base+".dx90.vtx"], materials)
+	if err != nil {
+		return nil, ModelGLBInfo{}, err
+	}
+	info.Path = mdlPath
+	return data, info, nil
+}
+
+// convertModelToGLB assembles the GLB from MDL/VVD/VTX data. materials is
+// indexed by MDL texture index.
+func convertModelToGLB(name string, mdlData, vvdData, vtxData []byte, materials []gltfMaterialSource) ([]byte, ModelGLBInfo, error) {
+	geometry, err := parseMDLGeometry(mdlData)
+	if err != nil {
+		return nil, ModelGLBInfo{}, fmt.Errorf("解析 .mdl 失败: %w", err)
+	}
+	vertices, err := parseVVDLOD0Vertices(vvdData)
+	if err != nil {
+		return nil, ModelGLBInfo{}, fmt.Errorf("解析 .vvd 失败: %w", err)
+	}
+	meshIndices, err := parseVTXLOD0MeshIndices(vtxData)
+	if err != nil {
+		return nil, ModelGLBInfo{}, fmt.Errorf("解析 .dx90.vtx 失败: %w", err)
+	}
+
+	builder := &gltfBuilder{}
+	info := ModelGLBInfo{Vertices: len(vertices), Bones: len(geometry.Bones), MissingTextures: []string{}}
+	attributes := builder.addVertexAttributes(vertices, len(geometry.Bones))
+
+	materialIndex := make(map[int]int)
+	mesh := gltfMesh{Name: name}
+	for bp, meshes := range geometry.BodyParts {
+		if bp >= len(meshIndices) {
+			break
+		}
+		for m, source := range meshes {
+			if m >= len(meshIndices[bp]) || len(meshIndices[bp][m]) == 0 {
+				continue
+			}
+			indices := make([]uint32, len(meshIndices[bp][m]))
+			for i, local := range meshIndices[bp][m] {
+				index := source.VertexBase + local
+				if index < 0 || index >= len(vertices) {
+					return nil, ModelGLBInfo{}, fmt.Errorf("顶点索引越界: %d", index)
+				}
+				indices[i] = uint32(index)
+			}
+
+			texture := source.Material
+			if texture >= 0 && texture < len(geometry.SkinRefs) {
+				texture = geometry.SkinRefs[texture]
+			}
+			if _, ok := materialIndex[texture]; !ok && texture >= 0 && texture < len(materials) {
+				material := materials[texture]
+				if material.PNG != nil {
+					info.Textures++
+				} else {
+					info.MissingTextures = append(info.MissingTextures, material.Name)
+				}
+				materialIndex[texture] = builder.addMaterial(material)
+			}
+
+			primitive := gltfPrimitive{Attributes: attributes, Indices: builder.addIndices(indices)}
+			if index, ok := materialIndex[texture]; ok {
+				primitive.Material = &index
+			}
+			mesh.Primitives = append(mesh.Primitives, primitive)
+			info.Triangles += len(indices) / 3
+		}
+	}
+	if len(mesh.Primitives) == 0 {
+		return nil, ModelGLBInfo{}, fmt.Errorf("模型 LOD0 没有三角形")
+	}
+	info.Primitives = len(mesh.Primitives)
+
+	builder.addScene(name, mesh, geometry.Bones)
+	data, err := builder.encode()
+	if err != nil {
+		return nil, ModelGLBInfo{}, err
+	}
+	return data, info, nil
+}
+
+// parseMDLGeometry reads bones, default body group meshes and the first skin
+// family. The header must already have been validated by parseMDLHeader.
+func parseMDLGeometry(data []byte) (mdlGeometry, error) {
+	if len(data) < mdlHeaderSize {
+		return mdlGeometry{}, fmt.Errorf("文件头过小")
+	}
+	geometry := mdlGeometry{}
+
+	numBones, boneIndex := int32At(data, 156), int32At(data, 160)
+	if numBones < 0 || numBones > mdlMaxArrayLength {
+		return mdlGeometry{}, fmt.Errorf("bone 数量无效")
+	}
+	geometry.Bones = make([]mdlBone, numBones)
+	for i := range geometry.Bones {
+		boneOff := boneIndex + i*mdlBoneSize
+		if err := requireRange(data, boneOff, mdlBoneSize); err != nil {
+			return mdlGeometry{}, fmt.Errorf("bone 偏移无效: %w", err)
+		}
+		bone := &geometry.Bones[i]
+		bone.Name = cStringAt(data, boneOff+int32At(data, boneOff), mdlMaxNameLength)
+		bone.Parent = int32At(data, boneOff+4)
+		if bone.Parent >= i {
+			return mdlGeometry{}, fmt.Errorf("bone %d 的父骨骼无效", i)
+		}
+		readFloat32s(data, boneOff+32, bone.Position[:])
+		readFloat32s(data, boneOff+44, bone.Quaternion[:])
+		readFloat32s(data, boneOff+96, bone.PoseToBone[:])
+	}
+
+	numSkinRefs, skinIndex := int32At(data, 220), int32At(data, 228)
+	if numSkinRefs < 0 || numSkinRefs > mdlMaxArrayLength {
+		return mdlGeometry{}, fmt.Errorf("skinref 数量无效")
+	}
+	if int32At(data, 224) > 0 {
+		if err := requireRange(data, skinIndex, numSkinRefs*2); err != nil {
+			return mdlGeometry{}, fmt.Errorf("skin 偏移无效: %w", err)
+		}
+		geometry.SkinRefs = make([]int, numSkinRefs)
+		for i := range geometry.SkinRefs {
+			geometry.SkinRefs[i] = int(int16(binary.LittleEndian.Uint16(data[skinIndex+i*2:])))
+		}
+	}
+
+	numBodyParts, bodyPartIndex := int32At(data, 232), int32At(data, 236)
+	if numBodyParts < 0 || numBodyParts > mdlMaxArrayLength {
+		return mdlGeometry{}, fmt.Errorf("bodypart 数量无效")
+	}
+	geometry.BodyParts = make([][]mdlMesh, numBodyParts)
+	for part := range geometry.BodyParts {
+		partOff := bodyPartIndex + part*mdlBodyPartSize
+		if err := requireRange(data, partOff, mdlBodyPartSize); err != nil {
+			return mdlGeometry{}, fmt.Errorf("bodypart 偏移无效: %w", err)
+		}
+		if int32At(data, partOff+4) <= 0 {
+			continue
+		}
+		modelOff := partOff + int32At(data, partOff+12)
+		if err := requireRange(data, modelOff, mdlModelSize); err != nil {
+			return mdlGeometry{}, fmt.Errorf("model 偏移无效: %w", err)
+		}
+		numMeshes, meshIndex := int32At(data, modelOff+72), int32At(data, modelOff+76)
+		if numMeshes < 0 || numMeshes > mdlMaxArrayLength {
+			return mdlGeometry{}, fmt.Errorf("mesh 数量无效")
+		}
+		modelVertexBase := int32At(data, modelOff+84) / vvdVertexSize
+		meshes := make([]mdlMesh, numMeshes)
+		for m := range meshes {
+			meshOff := modelOff + meshIndex + m*mdlMeshSize
+			if err := requireRange(data, meshOff, mdlMeshSize); err != nil {
+				return mdlGeometry{}, fmt.Errorf("mesh 偏移无效: %w", err)
+			}
+			meshes[m] = mdlMesh{
+				Material:   int32At(data, meshOff),
+				VertexBase: modelVertexBase + int32At(data, meshOff+12),
+			}
+		}
+		geometry.BodyParts[part] = meshes
+	}
+	return geometry, nil
+}
+
+// parseVVDLOD0Vertices returns the LOD0 vertex list, applying the fixup table
+// when the VVD has one.
+func parseVVDLOD0Vertices(data []byte) ([]vvdVertex, error) {
+	counts, err := parseVVDLODVertexCounts(data)
+	if err != nil {
+		return nil, err
+	}
+	numFixups, fixupStart, vertexStart := int32At(data, 48), int32At(data, 52), int32At(data, 56)
+	if numFixups < 0 || numFixups > mdlMaxArrayLength {
+		return nil, fmt.Errorf("fixup 数量无效")
+	}
+	if err := requireRange(data, vertexStart, counts[0]*vvdVertexSize); err != nil {
+		return nil, fmt.Errorf("顶点数据偏移无效: %w", err)
+	}
+
+	readVertex := func(index int) vvdVertex {
+		off := vertexStart + index*vvdVertexSize
+		var vertex vvdVertex
+		readFloat32s(data, off, vertex.Weights[:])
+		for i := range vertex.Bones {
+			vertex.Bones[i] = int8(data[off+12+i])
+		}
+		vertex.NumBones = data[off+15]
+		readFloat32s(data, off+16, vertex.Position[:])
+		readFloat32s(data, off+28, vertex.Normal[:])
+		readFloat32s(data, off+40, vertex.UV[:])
+		return vertex
+	}
+
+	vertices := make([]vvdVertex, 0, counts[0])
+	if numFixups == 0 {
+		for i := 0; i < counts[0]; i++ {
+			vertices = append(vertices, readVertex(i))
+		}
+		return vertices, nil
+	}
+
+	if err := requireRange(data, fixupStart, numFixups*vvdFixupSize); err != nil {
+		return nil, fmt.Errorf("fixup 偏移无效: %w", err)
+	}
+	for i := 0; i < numFixups; i++ {
+		fixupOff := fixupStart + i*vvdFixupSize
+		lod, source, count := int32At(data, fixupOff), int32At(data, fixupOff+4), int32At(data, fixupOff+8)
+		if lod < 0 {
+			continue
+		}
+		if source < 0 || count < 0 || source+count > counts[0] {
+			return nil, fmt.Errorf("fixup %d 范围无效", i)
+		}
+		for v := source; v < source+count; v++ {
+			vertices = append(vertices, readVertex(v))
+		}
+	}
+	return vertices, nil
+}
+
+// parseVTXLOD0MeshIndices returns triangle lists for model 0 of every body
+// part at LOD0, indexed by body part and mesh. Indices are mesh-relative
+// vertex IDs; triangles use glTF's counter-clockwise winding.
+func parseVTXLOD0MeshIndices(data []byte) ([][][]int, error) {
+	if len(data) < vtxHeaderSize {
+		return nil, fmt.Errorf("文件头过小")
+	}
+	if version := int32At(data, 0); version != vtxFileVersion {
+		return nil, fmt.Errorf("VTX 版本不支持: %d", version)
+	}
+	numBodyParts, err := parseVTXBodyPartCount(data, 28)
+	if err != nil {
+		return nil, err
+	}
+	bodyPartOffset, err := safeI32(data, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([][][]int, numBodyParts)
+	for bp := range parts {
+		bpOff := bodyPartOffset + bp*vtxBodyPartSize
+		if err := requireRange(data, bpOff, vtxBodyPartSize); err != nil {
+			return nil, fmt.Errorf("bodypart 偏移无效: %w", err)
+		}
+		if int32At(data, bpOff) <= 0 {
+			continue
+		}
+		modelOff := bpOff + int32At(data, bpOff+4)
+		if err := requireRange(data, modelOff, vtxModelSize); err != nil {
+			return nil, fmt.Errorf("model 偏移无效: %w", err)
+		}
+		if int32At(data, modelOff) <= 0 {
+			continue
+		}
+		lodOff := modelOff + int32At(data, modelOff+4)
+		if err := requireRange(data, lodOff, vtxModelLODSize); err != nil {
+			return nil, fmt.Errorf("lod 偏移无效: %w", err)
+		}
+		numMeshes, meshOffset := int32At(data, lodOff), int32At(data, lodOff+4)
+		if numMeshes < 0 || numMeshes > 65536 {
+			return nil, fmt.Errorf("mesh 数量无效")
+		}
+
+		meshes := make([][]int, numMeshes)
+		for mesh := range meshes {
+			meshOff := lodOff + meshOffset + mesh*vtxMeshSize
+			if err := requireRange(data, meshOff, vtxMeshSize); err != nil {
+				return nil, fmt.Errorf("mesh 偏移无效: %w", err)
+			}
+			numStripGroups, stripGroupOffset := int32At(data, meshOff), int32At(data, meshOff+4)
+			if numStripGroups < 0 || numStripGroups > 65536 {
+				return nil, fmt.Errorf("stripgroup 数量无效")
+			}
+			for sg := 0; sg < numStripGroups; sg++ {
+				triangles, err := parseVTXStripGroupTriangles(data, meshOff+stripGroupOffset+sg*vtxStripGroupSize)
+				if err != nil {
+					return nil, err
+				}
+				meshes[mesh] = append(meshes[mesh], triangles...)
+			}
+		}
+		parts[bp] = meshes
+	}
+	return parts, nil
+}
+
+// parseVTXStripGroupTriangles expands the strips of one strip group into a
+// triangle list of mesh vertex IDs. VTX triangles are clockwise, so every
+// triangle is emitted in reverse order.
+func parseVTXStripGroupTriangles(data []byte, sgOff int) ([]int, error) {
+	if err := requireRange(data, sgOff, vtxStripGroupSize); err != nil {
+		return nil, fmt.Errorf("stripgroup 偏移无效: %w", err)
+	}
+	numVerts, vertOffset := int32At(data, sgOff), int32At(data, sgOff+4)
+	numIndices, indexOffset := int32At(data, sgOff+8), int32At(data, sgOff+12)
+	numStrips, stripOffset := int32At(data, sgOff+16), int32At(data, sgOff+20)
+	if numVerts < 0 || numIndices < 0 || numStrips < 0 || numStrips > 65536 {
+		return nil, fmt.Errorf("strip 数据无效")
+	}
+	if err := requireRange(data, sgOff+vertOffset, numVerts*vtxVertexSize); err != nil {
+		return nil, fmt.Errorf("stripgroup 顶点偏移无效: %w", err)
+	}
+	if err := requireRange(data, sgOff+indexOffset, numIndices*2); err != nil {
+		return nil, fmt.Errorf("stripgroup 索引偏移无效: %w", err)
+	}
+
+	meshVertex := func(i int) (int, error) {
+		if i < 0 || i >= numIndices {
+			return 0, fmt.Errorf("strip 索引越界: %d", i)
+		}
+		vertex := int(binary.LittleEndian.Uint16(data[sgOff+indexOffset+i*2:]))
+		if vertex >= numVerts {
+			return 0, fmt.Errorf("stripgroup 顶点越界: %d", vertex)
+		}
+		return int(binary.LittleEndian.Uint16(data[sgOff+vertOffset+vertex*vtxVertexSize+4:])), nil
+	}
+	appendTriangle := func(triangles []int, a, b, c int) ([]int, error) {
+		var ids [3]int
+		for i, index := range [3]int{a, c, b} {
+			id, err := meshVertex(index)
+			if err != nil {
+				return nil, err
+			}
+			ids[i] = id
+		}
+		return append(triangles, ids[:]...), nil
+	}
+
+	triangles := make([]int, 0, numIndices)
+	var err error
+	if numStrips == 0 {
+		for i := 0; i+2 < numIndices; i += 3 {
+			if triangles, err = appendTriangle(triangles, i, i+1, i+2); err != nil {
+				return nil, err
+			}
+		}
+		return triangles, nil
+	}
+	for strip := 0; strip < numStrips; strip++ {
+		stripOff := sgOff + stripOffset + strip*vtxStripSize
+		if err := requireRange(data, stripOff, vtxStripSize); err != nil {
+			return nil, fmt.Errorf("strip 偏移无效: %w", err)
+		}
+		count, start := int32At(data, stripOff), int32At(data, stripOff+4)
+		if data[stripOff+18]&vtxStripIsTriStrip != 0 {
+			for i := 0; i+2 < count; i++ {
+				a, b := start+i, start+i+1
+				if i%2 == 1 {
+					a, b = b, a
+				}
+				if triangles, err = appendTriangle(triangles, a, b, start+i+2); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		for i := 0; i+2 < count; i += 3 {
+			if triangles, err = appendTriangle(triangles, start+i, start+i+1, start+i+2); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return triangles, nil
+}
+
+// loadGLTFMaterial resolves a model material through $cdmaterials, follows
+// Patch includes and decodes its $basetexture when present in the VPK.
+func loadGLTFMaterial(opener *vpk.Opener, files map[string]*vpk.File, stat *ModelStat, material string) gltfMaterialSource {
+	source := gltfMaterialSource{Name: material}
+	var params map[string]string
+	for _, candidate := range modelMaterialCandidates(stat, material) {
+		if params = readVMTParams(opener, files, candidate, 0); params != nil {
+			break
+		}
+	}
+	if params == nil {
+		return source
+	}
+
+	switch {
+	case params["$translucent"] == "1":
+		source.AlphaMode = "BLEND"
+	case params["$alphatest"] == "1":
+		source.AlphaMode = "MASK"
+		source.AlphaCutoff = 0.5
+		if value, err := strconv.ParseFloat(params["$alphatestreference"], 32); err == nil && value > 0 {
+			source.AlphaCutoff = float32(value)
+		}
+	}
+	source.DoubleSided = params["$nocull"] == "1"
+
+	baseTexture := params["$basetexture"]
+	if baseTexture == "" {
+		return source
+	}
+	file := files[materialAssetPath(baseTexture, ".vtf")]
+	if file == nil {
+		return source
+	}
+	data, err := readVPKFileBytes(opener, file)
+	if err != nil {
+		return source
+	}
+	header, err := ParseVTFHeader(data)
+	if err != nil || !header.Decodable() {
+		return source
+	}
+	img, err := decodeVTFImage(header, data, header.MipForSize(gltfMaxTextureSize), 0)
+	if err != nil {
+		return source
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err == nil {
+		source.PNG = buf.Bytes()
+	}
+	return source
+}
+
+// readVMTParams returns the lowercase parameters of a VMT in the VPK, or nil
+// when it does not exist. Parameters of an included material are inherited
+// unless the Patch material overrides them.
+func readVMTParams(opener *vpk.Opener, files map[string]*vpk.File, vmtPath string, depth int) map[string]string {
+	file := files[vmtPath]
+	if file == nil || depth > vmtMaxIncludeDepth {
+		return nil
+	}
+	data, err := readVPKFileBytes(opener, file)
+	if err != nil {
+		return nil
+	}
+
+	params := make(map[string]string)
+	for _, param := range readVMTParamList(data) {
+		if _, ok := params[param.Key]; !ok {
+			params[param.Key] = param.Value
+		}
+	}
+	if include := params["include"]; include != "" {
+		for key, value := range readVMTParams(opener, files, materialAssetPath(include, ".vmt"), depth+1) {
+			if _, ok := params[key]; !ok {
+				params[key] = value
+			}
+		}
+	}
+	return params
+}
+
+func readFloat32s(data []byte, offset int, values []float32) {
+	for i := range values {
+		values[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[offset+i*4:]))
+	}
+}
+
+type gltfDocument struct {
+	Asset       gltfAsset        `json:"asset"`
+	Scene       int              `json:"scene"`
+	Scenes      []gltfScene      `json:"scenes"`
+	Nodes       []gltfNode       `json:"nodes"`
+	Meshes      []gltfMesh       `json:"meshes"`
+	Skins       []gltfSkin       `json:"skins,omitempty"`
+	Materials   []gltfMaterial   `json:"materials,omitempty"`
+	Textures    []gltfTexture    `json:"textures,omitempty"`
+	Images      []gltfImage      `json:"images,omitempty"`
+	Samplers    []gltfSampler    `json:"samplers,omitempty"`
+	Accessors   []gltfAccessor   `json:"accessors"`
+	BufferViews []gltfBufferView `json:"bufferViews"`
+	Buffers     []gltfBuffer     `json:"buffers"`
+}
+
+type gltfAsset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator,omitempty"`
+}
+
+type gltfScene struct {
+	Nodes []int `json:"nodes"`
+}
+
+type gltfNode struct {
+	Name        string    `json:"name,omitempty"`
+	Children    []int     `json:"children,omitempty"`
+	Mesh        *int      `json:"mesh,omitempty"`
+	Skin        *int      `json:"skin,omitempty"`
+	Translation []float32 `json:"translation,omitempty"`
+	Rotation    []float32 `json:"rotation,omitempty"`
+	Scale       []float32 `json:"scale,omitempty"`
+}
+
+type gltfMesh struct {
+	Name       string          `json:"name,omitempty"`
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    int            `json:"indices"`
+	Material   *int           `json:"material,omitempty"`
+}
+
+type gltfSkin struct {
+	InverseBindMatrices int   `json:"inverseBindMatrices"`
+	Joints              []int `json:"joints"`
+	Skeleton            *int  `json:"skeleton,omitempty"`
+}
+
+type gltfMaterial struct {
+	Name                 string  `json:"name,omitempty"`
+	PBRMetallicRoughness gltfPBR `json:"pbrMetallicRoughness"`
+	AlphaMode            string  `json:"alphaMode,omitempty"`
+	AlphaCutoff          float32 `json:"alphaCutoff,omitempty"`
+	DoubleSided          bool    `json:"doubleSided,omitempty"`
+}
+
+type gltfPBR struct {
+	BaseColorTexture *gltfTextureInfo `json:"baseColorTexture,omitempty"`
+	BaseColorFactor  []float32        `json:"baseColorFactor,omitempty"`
+	MetallicFactor   float32          `json:"metallicFactor"`
+}
+
+type gltfTextureInfo struct {
+	Index int `json:"index"`
+}
+
+type gltfTexture struct {
+	Sampler int `json:"sampler"`
+	Source  int `json:"source"`
+}
+
+type gltfImage struct {
+	BufferView int    `json:"bufferView"`
+	MimeType   string `json:"mimeType"`
+}
+
+type gltfSampler struct {
+	MagFilter int `json:"magFilter"`
+	MinFilter int `json:"minFilter"`
+	WrapS     int `json:"wrapS"`
+	WrapT     int `json:"wrapT"`
+}
+
+type gltfAccessor struct {
+	BufferView    int       `json:"bufferView"`
+	ComponentType int       `json:"componentType"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Min           []float32 `json:"min,omitempty"`
+	Max           []float32 `json:"max,omitempty"`
+}
+
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	Target     int `json:"target,omitempty"`
+}
+
+type gltfBuffer struct {
+	ByteLength int `json:"byteLength"`
+}
+
+// gltfBuilder collects the glTF document and its single binary buffer.
+type gltfBuilder struct {
+	doc gltfDocument
+	bin bytes.Buffer
+}
+
+func (b *gltfBuilder) addBufferView(data []byte, target int) int {
+	for b.bin.Len()%4 != 0 {
+		b.bin.WriteByte(0)
+	}
+	b.doc.BufferViews = append(b.doc.BufferViews, gltfBufferView{
+		ByteOffset: b.bin.Len(),
+		ByteLength: len(data),
+		Target:     target,
+	})
+	b.bin.Write(data)
+	return len(b.doc.BufferViews) - 1
+}
+
+func (b *gltfBuilder) addAccessor(data []byte, target, componentType, count int, accessorType string, minValues, maxValues []float32) int {
+	b.doc.Accessors = append(b.doc.Accessors, gltfAccessor{
+		BufferView:    b.addBufferView(data, target),
+		ComponentType: componentType,
+		Count:         count,
+		Type:          accessorType,
+		Min:           minValues,
+		Max:           maxValues,
+	})
+	return len(b.doc.Accessors) - 1
+}
+
+// addVertexAttributes writes the shared vertex attributes used by every
+// primitive. Joints and weights are only written for skinned models.
+func (b *gltfBuilder) addVertexAttributes(vertices []vvdVertex, numBones int) map[string]int {
+	count := len(vertices)
+	positions := make([]byte, 0, count*12)
+	normals := make([]byte, 0, count*12)
+	uvs := make([]byte, 0, count*8)
+	joints := make([]byte, 0, count*8)
+	weights := make([]byte, 0, count*16)
+	minPos := []float32{math.MaxFloat32, math.MaxFloat32, math.MaxFloat32}
+	maxPos := []float32{-math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32}
+
+	for _, vertex := range vertices {
+		for i, value := range vertex.Position {
+			minPos[i] = min(minPos[i], value)
+			maxPos[i] = max(maxPos[i], value)
+			positions = binary.LittleEndian.AppendUint32(positions, math.Float32bits(value))
+		}
+		normal := normalizeVector(vertex.Normal)
+		for _, value := range normal {
+			normals = binary.LittleEndian.AppendUint32(normals, math.Float32bits(value))
+		}
+		for _, value := range vertex.UV {
+			uvs = binary.LittleEndian.AppendUint32(uvs, math.Float32bits(value))
+		}
+
+		var boneIDs [4]uint16
+		var boneWeights [4]float32
+		var total float32
+		for i := 0; i < int(min(vertex.NumBones, 3)); i++ {
+			if bone := int(vertex.Bones[i]); bone >= 0 && bone < numBones && vertex.Weights[i] > 0 {
+				boneIDs[i], boneWeights[i] = uint16(bone), vertex.Weights[i]
+				total += vertex.Weights[i]
+			}
+		}
+		if total <= 0 {
+			boneIDs, boneWeights, total = [4]uint16{}, [4]float32{1}, 1
+		}
+		for i := range boneIDs {
+			joints = binary.LittleEndian.AppendUint16(joints, boneIDs[i])
+			weights = binary.LittleEndian.AppendUint32(weights, math.Float32bits(boneWeights[i]/total))
+		}
+	}
+
+	attributes := map[string]int{
+		"POSITION":   b.addAccessor(positions, gltfTargetArrayBuffer, gltfComponentFloat, count, "VEC3", minPos, maxPos),
+		"NORMAL":     b.addAccessor(normals, gltfTargetArrayBuffer, gltfComponentFloat, count, "VEC3", nil, nil),
+		"TEXCOORD_0": b.addAccessor(uvs, gltfTargetArrayBuffer, gltfComponentFloat, count, "VEC2", nil, nil),
+	}
+	if numBones > 0 {
+		attributes["JOINTS_0"] = b.addAccessor(joints, gltfTargetArrayBuffer, gltfComponentUnsignedShort, count, "VEC4", nil, nil)
+		attributes["WEIGHTS_0"] = b.addAccessor(weights, gltfTargetArrayBuffer, gltfComponentFloat, count, "VEC4", nil, nil)
+	}
+	return attributes
+}
+
+func (b *gltfBuilder) addIndices(indices []uint32) int {
+	data := make([]byte, 0, len(indices)*4)
+	for _, index := range indices {
+		data = binary.LittleEndian.AppendUint32(data, index)
+	}
+	return b.addAccessor(data, gltfTargetElementBuffer, gltfComponentUnsignedInt, len(indices), "SCALAR", nil, nil)
+}
+
+// addMaterial adds a material, embedding its base texture as PNG. Materials
+// without a texture are rendered light grey.
+func (b *gltfBuilder) addMaterial(source gltfMaterialSource) int {
+	material := gltfMaterial{
+		Name:        source.Name,
+		AlphaMode:   source.AlphaMode,
+		AlphaCutoff: source.AlphaCutoff,
+		DoubleSided: source.DoubleSided,
+	}
+	if source.PNG != nil {
+		if len(b.doc.Samplers) == 0 {
+			// LINEAR / LINEAR_MIPMAP_LINEAR / REPEAT
+			b.doc.Samplers = append(b.doc.Samplers, gltfSampler{MagFilter: 9729, MinFilter: 9987, WrapS: 10497, WrapT: 10497})
+		}
+		b.doc.Images = append(b.doc.Images, gltfImage{BufferView: b.addBufferView(source.PNG, 0), MimeType: "image/png"})
+		b.doc.Textures = append(b.doc.Textures, gltfTexture{Sampler: 0, Source: len(b.doc.Images) - 1})
+		material.PBRMetallicRoughness.BaseColorTexture = &gltfTextureInfo{Index: len(b.doc.Textures) - 1}
+	} else {
+		material.PBRMetallicRoughness.BaseColorFactor = []float32{0.8, 0.8, 0.8, 1}
+	}
+	b.doc.Materials = append(b.doc.Materials, material)
+	return len(b.doc.Materials) - 1
+}
+
+// addScene builds the node tree: a root node converting Source space to glTF
+// space, the mesh node and the bone hierarchy used as skin joints.
+func (b *gltfBuilder) addScene(name string, mesh gltfMesh, bones []mdlBone) {
+	b.doc.Meshes = append(b.doc.Meshes, mesh)
+	meshIndex := 0
+	const rootNode, meshNode, firstBoneNode = 0, 1, 2
+
+	b.doc.Nodes = []gltfNode{
+		{Name: name, Children: []int{meshNode}, Rotation: gltfSourceRotation, Scale: []float32{gltfUnitScale, gltfUnitScale, gltfUnitScale}},
+		{Name: "mesh", Mesh: &meshIndex},
+	}
+	if len(bones) == 0 {
+		return
+	}
+
+	joints := make([]int, len(bones))
+	matrices := make([]byte, 0, len(bones)*64)
+	for i, bone := range bones {
+		joints[i] = firstBoneNode + i
+		b.doc.Nodes = append(b.doc.Nodes, gltfNode{
+			Name:        bone.Name,
+			Translation: bone.Position[:],
+			Rotation:    normalizeQuaternion(bone.Quaternion),
+		})
+		if bone.Parent < 0 {
+			b.doc.Nodes[rootNode].Children = append(b.doc.Nodes[rootNode].Children, joints[i])
+		} else {
+			parent := &b.doc.Nodes[firstBoneNode+bone.Parent]
+			parent.Children = append(parent.Children, joints[i])
+		}
+
+		// 3x4 row-major to 4x4 column-major
+		m := bone.PoseToBone
+		for _, value := range [16]float32{m[0], m[4], m[8], 0, m[1], m[5], m[9], 0, m[2], m[6], m[10], 0, m[3], m[7], m[11], 1} {
+			matrices = binary.LittleEndian.AppendUint32(matrices, math.Float32bits(value))
+		}
+	}
+
+	skin := gltfSkin{
+		InverseBindMatrices: b.addAccessor(matrices, 0, gltfComponentFloat, len(bones), "MAT4", nil, nil),
+		Joints:              joints,
+	}
+	skinIndex := 0
+	b.doc.Skins = append(b.doc.Skins, skin)
+	b.doc.Nodes[meshNode].Skin = &skinIndex
+}
+
+// encode writes the GLB container: a 12-byte header, the JSON chunk padded
+// with spaces and the binary chunk padded with zeros.
+func (b *gltfBuilder) encode() ([]byte, error) {
+	b.doc.Asset = gltfAsset{Version: "2.0", Generator: "LytVPK"}
+	b.doc.Scenes = []gltfScene{{Nodes: []int{0}}}
+	for b.bin.Len()%4 != 0 {
+		b.bin.WriteByte(0)
+	}
+	b.doc.Buffers = []gltfBuffer{{ByteLength: b.bin.Len()}}
+
+	jsonData, err := json.Marshal(b.doc)
+	if err != nil {
+		return nil, err
+	}
+	for len(jsonData)%4 != 0 {
+		jsonData = append(jsonData, ' ')
+	}
+
+	total := 12 + 8 + len(jsonData) + 8 + b.bin.Len()
+	out := make([]byte, 0, total)
+	out = binary.LittleEndian.AppendUint32(out, 0x46546C67) // "glTF"
+	out = binary.LittleEndian.AppendUint32(out, 2)
+	out = binary.LittleEndian.AppendUint32(out, uint32(total))
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(jsonData)))
+	out = binary.LittleEndian.AppendUint32(out, 0x4E4F534A) // "JSON"
+	out = append(out, jsonData...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(b.bin.Len()))
+	out = binary.LittleEndian.AppendUint32(out, 0x004E4942) // "BIN\0"
+	out = append(out, b.bin.Bytes()...)
+	return out, nil
+}
+
+func normalizeVector(v [3]float32) [3]float32 {
+	length := float32(math.Sqrt(float64(v[0]*v[0] + v[1]*v[1] + v[2]*v[2])))
+	if length == 0 || math.IsNaN(float64(length)) {
+		return [3]float32{0, 0, 1}
+	}
+	return [3]float32{v[0] / length, v[1] / length, v[2] / length}
+}
+
+func normalizeQuaternion(q [4]float32) []float32 {
+	length := float32(math.Sqrt(float64(q[0]*q[0] + q[1]*q[1] + q[2]*q[2] + q[3]*q[3])))
+	if length == 0 || math.IsNaN(float64(length)) {
+		return []float32{0, 0, 0, 1}
+	}
+	return []float32{q[0] / length, q[1] / length, q[2] / length, q[3] / length}
+}
@@ -0,0 +1,218 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"image/png"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// buildGLTFVVDFixture builds a VVD with a quad; fixups are (lod, source, count).
+func buildGLTFVVDFixture(t *testing.T, fixups ...[3]int) []byte {
+	t.Helper()
+
+	fixupStart := 64
+	vertexStart := fixupStart + len(fixups)*vvdFixupSize
+	data := make([]byte, vertexStart+4*vvdVertexSize)
+	put := func(offset, value int) {
+		binary.LittleEndian.PutUint32(data[offset:], uint32(int32(value)))
+	}
+	putFloats := func(offset int, values ...float32) {
+		for i, value := range values {
+			binary.LittleEndian.PutUint32(data[offset+i*4:], math.Float32bits(value))
+		}
+	}
+
+	copy(data, "IDSV")
+	put(4, vvdFileVersion)
+	put(12, 1)
+	put(16, 4)
+	put(48, len(fixups))
+	put(52, fixupStart)
+	put(56, vertexStart)
+	for i, fixup := range fixups {
+		put(fixupStart+i*vvdFixupSize, fixup[0])
+		put(fixupStart+i*vvdFixupSize+4, fixup[1])
+		put(fixupStart+i*vvdFixupSize+8, fixup[2])
+	}
+	for i, corner := range [][2]float32{{0, 0}, {10, 0}, {10, 10}, {0, 10}} {
+		offset := vertexStart + i*vvdVertexSize
+		putFloats(offset, 0.5, 0.5, 0)
+		data[offset+12], data[offset+13], data[offset+15] = 0, byte(i%2), 2
+		putFloats(offset+16, corner[0], corner[1], float32(i))
+		putFloats(offset+28, 0, 0, 2)
+		putFloats(offset+40, corner[0]/10, corner[1]/10)
+	}
+	return data
+}
+
+// buildGLTFVTXFixture builds a VTX with one mesh drawing the quad as a triangle list.
+func buildGLTFVTXFixture(t *testing.T) []byte {
+	t.Helper()
+
+	const (
+		bodyPartOffset = vtxHeaderSize
+		modelOffset    = bodyPartOffset + vtxBodyPartSize
+		lodOffset      = modelOffset + vtxModelSize
+		meshOffset     = lodOffset + vtxModelLODSize
+		sgOffset       = meshOffset + vtxMeshSize
+		stripOffset    = sgOffset + vtxStripGroupSize
+		vertexOffset   = stripOffset + vtxStripSize
+		indexOffset    = vertexOffset + 4*vtxVertexSize
+	)
+	indices := []uint16{0, 1, 2, 0, 2, 3}
+	data := make([]byte, indexOffset+len(indices)*2)
+	put := func(offset, value int) {
+		binary.LittleEndian.PutUint32(data[offset:], uint32(int32(value)))
+	}
+
+	put(0, vtxFileVersion)
+	put(20, 1)
+	put(28, 1)
+	put(32, bodyPartOffset)
+	put(bodyPartOffset, 1)
+	put(bodyPartOffset+4, modelOffset-bodyPartOffset)
+	put(modelOffset, 1)
+	put(modelOffset+4, lodOffset-modelOffset)
+	put(lodOffset, 1)
+	put(lodOffset+4, meshOffset-lodOffset)
+	put(meshOffset, 1)
+	put(meshOffset+4, sgOffset-meshOffset)
+	put(sgOffset, 4)
+	put(sgOffset+4, vertexOffset-sgOffset)
+	put(sgOffset+8, len(indices))
+	put(sgOffset+12, indexOffset-sgOffset)
+	put(sgOffset+16, 1)
+	put(sgOffset+20, stripOffset-sgOffset)
+	put(stripOffset, len(indices))
+	data[stripOffset+18] = vtxStripIsTriList
+	for i := 0; i < 4; i++ {
+		binary.LittleEndian.PutUint16(data[vertexOffset+i*vtxVertexSize+4:], uint16(i))
+	}
+	for i, index := range indices {
+		binary.LittleEndian.PutUint16(data[indexOffset+i*2:], index)
+	}
+	return data
+}
+
+func TestParseVVDLOD0VerticesAppliesFixups(t *testing.T) {
+	vertices, err := parseVVDLOD0Vertices(buildGLTFVVDFixture(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vertices) != 4 || vertices[2].Position != [3]float32{10, 10, 2} || vertices[1].Bones[1] != 1 || vertices[1].NumBones != 2 {
+		t.Fatalf("unexpected vertices %+v", vertices)
+	}
+
+	// LOD 为 -1 的 fixup 不属于任何 LOD，其余按表中顺序拼接
+	vertices, err = parseVVDLOD0Vertices(buildGLTFVVDFixture(t, [3]int{0, 2, 2}, [3]int{-1, 1, 1}, [3]int{0, 0, 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vertices) != 3 || vertices[0].Position[2] != 2 || vertices[1].Position[2] != 3 || vertices[2].Position[2] != 0 {
+		t.Fatalf("unexpected fixup vertices %+v", vertices)
+	}
+
+	if _, err := parseVVDLOD0Vertices(buildGLTFVVDFixture(t, [3]int{0, 3, 2})); err == nil {
+		t.Fatal("expected error for out-of-range fixup")
+	}
+}
+
+func TestConvertVPKModelToGLB(t *testing.T) {
+	vpkPath := filepath.Join(t.TempDir(), "weapon.vpk")
+	texture := make([]byte, 4*4*4)
+	for i := 0; i < len(texture); i += 4 {
+		texture[i], texture[i+3] = 255, 255
+	}
+	writeParserTestVPK(t, vpkPath, map[string][]byte{
+		"models/survivors/survivor_coach.mdl":             buildMDLFixture(t, mdlFixtureBone{"root", -1, 0}, mdlFixtureBone{"child", 0, 10}),
+		"models/survivors/survivor_coach.vvd":             buildGLTFVVDFixture(t),
+		"models/survivors/survivor_coach.dx90.vtx":        buildGLTFVTXFixture(t),
+		"materials/models/survivors/coach/coach_body.vmt": []byte(`"Patch" { include "materials/models/survivors/coach/base.vmt" replace { "$alphatest" "1" } }`),
+		"materials/models/survivors/coach/base.vmt":       []byte(`"VertexLitGeneric" { "$basetexture" "models\survivors\coach\skin_color" "$nocull" "1" }`),
+		"materials/models/survivors/coach/skin_color.vtf": buildTestVTF(2, VTFFormatRGBA8888, 4, 4, 0, texture),
+	})
+
+	data, info, err := ConvertVPKModelToGLB(vpkPath, "Models\\Survivors\\Survivor_Coach.mdl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Path != "models/survivors/survivor_coach.mdl" || info.Vertices != 4 || info.Triangles != 2 || info.Primitives != 1 ||
+		info.Bones != 2 || info.Textures != 1 || len(info.MissingTextures) != 0 {
+		t.Fatalf("unexpected info %+v", info)
+	}
+
+	if string(data[:4]) != "glTF" || binary.LittleEndian.Uint32(data[4:]) != 2 || int(binary.LittleEndian.Uint32(data[8:])) != len(data) {
+		t.Fatalf("invalid GLB header")
+	}
+	jsonLength := int(binary.LittleEndian.Uint32(data[12:]))
+	var doc gltfDocument
+	if err := json.Unmarshal(data[20:20+jsonLength], &doc); err != nil {
+		t.Fatal(err)
+	}
+	binStart := 20 + jsonLength + 8
+	if binary.LittleEndian.Uint32(data[binStart-4:]) != 0x004E4942 || binStart+doc.Buffers[0].ByteLength != len(data) {
+		t.Fatalf("invalid GLB binary chunk")
+	}
+	view := func(index int) []byte {
+		bv := doc.BufferViews[index]
+		return data[binStart+bv.ByteOffset : binStart+bv.ByteOffset+bv.ByteLength]
+	}
+
+	if len(doc.Nodes) != 4 || len(doc.Skins) != 1 || len(doc.Skins[0].Joints) != 2 || *doc.Nodes[1].Skin != 0 {
+		t.Fatalf("unexpected nodes %+v skins %+v", doc.Nodes, doc.Skins)
+	}
+	if root := doc.Nodes[0]; len(root.Children) != 2 || root.Children[1] != 2 || root.Scale[0] != gltfUnitScale {
+		t.Fatalf("unexpected root node %+v", root)
+	}
+	if child := doc.Nodes[3]; child.Name != "child" || len(doc.Nodes[2].Children) != 1 || doc.Nodes[2].Children[0] != 3 || child.Translation[2] != 10 {
+		t.Fatalf("unexpected bone nodes %+v", doc.Nodes[2:])
+	}
+	ibm := view(doc.Accessors[doc.Skins[0].InverseBindMatrices].BufferView)
+	if translation := math.Float32frombits(binary.LittleEndian.Uint32(ibm[64+14*4:])); translation != -10 {
+		t.Fatalf("unexpected inverse bind translation %v", translation)
+	}
+
+	primitive := doc.Meshes[0].Primitives[0]
+	position := doc.Accessors[primitive.Attributes["POSITION"]]
+	if position.Count != 4 || position.Max[0] != 10 || position.Min[2] != 0 || position.Max[2] != 3 {
+		t.Fatalf("unexpected position accessor %+v", position)
+	}
+	normals := view(doc.Accessors[primitive.Attributes["NORMAL"]].BufferView)
+	if z := math.Float32frombits(binary.LittleEndian.Uint32(normals[8:])); z != 1 {
+		t.Fatalf("expected normalized normal, got z=%v", z)
+	}
+	joints := view(doc.Accessors[primitive.Attributes["JOINTS_0"]].BufferView)
+	weights := view(doc.Accessors[primitive.Attributes["WEIGHTS_0"]].BufferView)
+	// 第二个顶点的两个骨骼权重各占一半
+	if binary.LittleEndian.Uint16(joints[8+2:]) != 1 || math.Float32frombits(binary.LittleEndian.Uint32(weights[16+4:])) != 0.5 {
+		t.Fatalf("unexpected bone weights")
+	}
+
+	indices := view(doc.Accessors[primitive.Indices].BufferView)
+	want := []uint32{0, 2, 1, 0, 3, 2}
+	for i, index := range want {
+		if got := binary.LittleEndian.Uint32(indices[i*4:]); got != index {
+			t.Fatalf("index %d: expected %d, got %d", i, index, got)
+		}
+	}
+
+	material := doc.Materials[*primitive.Material]
+	if material.Name != "coach_body" || material.AlphaMode != "MASK" || material.AlphaCutoff != 0.5 || !material.DoubleSided || material.PBRMetallicRoughness.BaseColorTexture == nil {
+		t.Fatalf("unexpected material %+v", material)
+	}
+	img, err := png.Decode(bytes.NewReader(view(doc.Images[0].BufferView)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 4 || nrgbaAt(t, img, 0, 0).R != 255 {
+		t.Fatalf("unexpected embedded texture")
+	}
+
+	if _, _, err := ConvertVPKModelToGLB(vpkPath, "models/survivors/missing.mdl"); err == nil {
+		t.Fatal("expected error for missing model")
+	}
+}
@@ -1,9 +1,11 @@
 package parser
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"path"
 	"strings"
 
@@ -11,8 +13,19 @@ import (
 )
 
 const (
+	mdlFileID         = 0x54534449
+	mdlMinVersion     = 44
+	mdlMaxVersion     = 49
+	mdlHeaderSize     = 408
+	mdlTextureSize    = 64
+	mdlHitboxSetSize  = 12
+	mdlBodyPartSize   = 16
+	mdlMaxNameLength  = 256
+	mdlMaxArrayLength = 65536
+
 	vvdFileID      = 0x56534449
 	vvdFileVersion = 4
+	vvdMaxLODs     = 8
 
 	vtxFileVersion = 7
 
@@ -28,11 +41,24 @@ const (
 	vtxStripIsTriStrip = 0x02
 )
 
-// ModelStat describes one Source model's render-time LOD0 geometry counts.
+// ModelStat describes one Source model: MDL header contents, render-time LOD0
+// geometry counts and the geometry of every LOD in the VTX.
 type ModelStat struct {
 	Path                   string                `json:"path"`
 	VVDPath                string                `json:"vvdPath,omitempty"`
 	VTXPath                string                `json:"vtxPath,omitempty"`
+	Name                   string                `json:"name,omitempty"`
+	MDLVersion             int                   `json:"mdlVersion,omitempty"`
+	Bones                  int                   `json:"bones"`
+	Attachments            int                   `json:"attachments"`
+	Hitboxes               int                   `json:"hitboxes"`
+	HitboxSets             int                   `json:"hitboxSets"`
+	BodyGroups             []ModelBodyGroup      `json:"bodyGroups"`
+	SkinFamilies           int                   `json:"skinFamilies"`
+	MaterialPaths          []string              `json:"materialPaths"`
+	Materials              []string              `json:"materials"`
+	Textures               int                   `json:"textures"`      // textures referenced by the model's materials inside the VPK
+	TextureMemory          int64                 `json:"textureMemory"` // summed image data size of those textures
 	LOD                    int                   `json:"lod"`
 	Vertices               int                   `json:"vertices"`
 	Triangles              int                   `json:"triangles"`
@@ -43,9 +69,28 @@ type ModelStat struct {
 	StripGroupCount        int                   `json:"stripGroupCount"`
 	TriangleStripEstimated bool                  `json:"triangleStripEstimated"`
 	StripGroups            []ModelStripGroupStat `json:"stripGroups"`
+	LODs                   []ModelLODStat        `json:"lods"`
 	Message                string                `json:"message,omitempty"`
 }
 
+// ModelBodyGroup is one MDL body part; parts with more than one model are
+// switchable body groups.
+type ModelBodyGroup struct {
+	Name   string `json:"name"`
+	Models int    `json:"models"`
+}
+
+// ModelLODStat describes the geometry of one VTX LOD. SwitchPoint is taken
+// from the first model that has the LOD.
+type ModelLODStat struct {
+	LOD                    int     `json:"lod"`
+	Vertices               int     `json:"vertices"`
+	Triangles              int     `json:"triangles"`
+	StripGroupCount        int     `json:"stripGroupCount"`
+	SwitchPoint            float32 `json:"switchPoint"`
+	TriangleStripEstimated bool    `json:"triangleStripEstimated"`
+}
+
 // ModelStripGroupStat describes one LOD0 VTX strip group inside a model.
 type ModelStripGroupStat struct {
 	BodyPart               int  `json:"bodyPart"`
@@ -68,7 +113,8 @@ type VPKModelStats struct {
 	Message        string      `json:"message,omitempty"`
 }
 
-// AnalyzeVPKModelStats reads a VPK and returns LOD0 vertex/triangle counts for contained models.
+// AnalyzeVPKModelStats reads a VPK and returns MDL header details, LOD0
+// vertex/triangle counts and per-LOD geometry for contained models.
 func AnalyzeVPKModelStats(filePath string) (VPKModelStats, error) {
 	opener := vpk.Single(filePath)
 	defer opener.Close()
@@ -97,6 +143,7 @@ func AnalyzeVPKModelStats(filePath string) (VPKModelStats, error) {
 		return result, nil
 	}
 
+	textureSizes := make(map[string]int64)
 	for _, mdlPath := range modelPaths {
 		stat := ModelStat{
 			Path: mdlPath,
@@ -106,6 +153,15 @@ func AnalyzeVPKModelStats(filePath string) (VPKModelStats, error) {
 		vvdPath := base + ".vvd"
 		vtxPath := base + ".dx90.vtx"
 
+		if data, err := readVPKFileBytes(opener, files[mdlPath]); err != nil {
+			stat.Message = appendModelStatMessage(stat.Message, "读取 .mdl 失败: "+err.Error())
+		} else if err := parseMDLHeader(data, &stat); err != nil {
+			stat.Message = appendModelStatMessage(stat.Message, "解析 .mdl 失败: "+err.Error())
+		} else {
+			stat.Textures, stat.TextureMemory = modelTextureMemory(opener, files, mdlPath, &stat, textureSizes)
+		}
+
+		var lodVertices []int
 		vvdFile := files[vvdPath]
 		if vvdFile == nil {
 			stat.Message = appendModelStatMessage(stat.Message, "缺少 .vvd")
@@ -114,10 +170,11 @@ func AnalyzeVPKModelStats(filePath string) (VPKModelStats, error) {
 			data, err := readVPKFileBytes(opener, vvdFile)
 			if err != nil {
 				stat.Message = appendModelStatMessage(stat.Message, "读取 .vvd 失败: "+err.Error())
-			} else if vertices, err := parseVVDLOD0VertexCount(data); err != nil {
+			} else if vertices, err := parseVVDLODVertexCounts(data); err != nil {
 				stat.Message = appendModelStatMessage(stat.Message, "解析 .vvd 失败: "+err.Error())
 			} else {
-				stat.Vertices = vertices
+				lodVertices = vertices
+				stat.Vertices = vertices[0]
 			}
 		}
 
@@ -129,9 +186,10 @@ func AnalyzeVPKModelStats(filePath string) (VPKModelStats, error) {
 			data, err := readVPKFileBytes(opener, vtxFile)
 			if err != nil {
 				stat.Message = appendModelStatMessage(stat.Message, "读取 .dx90.vtx 失败: "+err.Error())
-			} else if vtxStats, err := parseVTXLOD0Stats(data); err != nil {
+			} else if lods, err := parseVTXStats(data); err != nil {
 				stat.Message = appendModelStatMessage(stat.Message, "解析 .dx90.vtx 失败: "+err.Error())
 			} else {
+				vtxStats := lods[0]
 				stat.Triangles = vtxStats.Triangles
 				stat.StripGroupVertices = vtxStats.TotalStripGroupVertices
 				stat.StripGroupIndices = vtxStats.TotalStripGroupIndices
@@ -140,6 +198,7 @@ func AnalyzeVPKModelStats(filePath string) (VPKModelStats, error) {
 				stat.StripGroupCount = len(vtxStats.StripGroups)
 				stat.TriangleStripEstimated = vtxStats.TriangleStripEstimated
 				stat.StripGroups = vtxStats.StripGroups
+				stat.LODs = modelLODStats(lods, lodVertices)
 			}
 		}
 
@@ -151,28 +210,138 @@ func AnalyzeVPKModelStats(filePath string) (VPKModelStats, error) {
 	return result, nil
 }
 
-func parseVVDLOD0VertexCount(data []byte) (int, error) {
+// parseVVDLODVertexCounts returns the vertex count of every LOD stored in the VVD.
+func parseVVDLODVertexCounts(data []byte) ([]int, error) {
 	if len(data) < 64 {
-		return 0, fmt.Errorf("文件头过小")
+		return nil, fmt.Errorf("文件头过小")
 	}
 	if id := int32At(data, 0); id != vvdFileID {
-		return 0, fmt.Errorf("VVD 标识无效")
+		return nil, fmt.Errorf("VVD 标识无效")
 	}
 	if version := int32At(data, 4); version != vvdFileVersion {
-		return 0, fmt.Errorf("VVD 版本不支持: %d", version)
+		return nil, fmt.Errorf("VVD 版本不支持: %d", version)
 	}
 	numLODs := int32At(data, 12)
-	if numLODs <= 0 || numLODs > 8 {
-		return 0, fmt.Errorf("LOD 数量无效")
+	if numLODs <= 0 || numLODs > vvdMaxLODs {
+		return nil, fmt.Errorf("LOD 数量无效")
+	}
+	vertices := make([]int, numLODs)
+	for lod := range vertices {
+		vertices[lod] = int32At(data, 16+lod*4)
+		if vertices[lod] < 0 {
+			return nil, fmt.Errorf("LOD%d 顶点数无效", lod)
+		}
+	}
+	return vertices, nil
+}
+
+// parseMDLHeader fills the MDL header fields of stat: bones, attachments,
+// hitboxes, body groups, skin families, $cdmaterials paths and material names.
+func parseMDLHeader(data []byte, stat *ModelStat) error {
+	if len(data) < mdlHeaderSize {
+		return fmt.Errorf("文件头过小")
+	}
+	if id := int32At(data, 0); id != mdlFileID {
+		return fmt.Errorf("MDL 标识无效")
+	}
+	version := int32At(data, 4)
+	if version < mdlMinVersion || version > mdlMaxVersion {
+		return fmt.Errorf("MDL 版本不支持: %d", version)
+	}
+	stat.MDLVersion = version
+	stat.Name = cStringAt(data, 12, 64)
+
+	// Checked in header order so the first invalid count is always the one reported.
+	counts := []struct {
+		name  string
+		value int
+	}{
+		{"bone", int32At(data, 156)},
+		{"hitboxset", int32At(data, 172)},
+		{"texture", int32At(data, 204)},
+		{"cdtexture", int32At(data, 212)},
+		{"skinfamily", int32At(data, 224)},
+		{"bodypart", int32At(data, 232)},
+		{"attachment", int32At(data, 240)},
+	}
+	for _, count := range counts {
+		if count.value < 0 || count.value > mdlMaxArrayLength {
+			return fmt.Errorf("%s 数量无效", count.name)
+		}
+	}
+	stat.Bones = counts[0].value
+	stat.HitboxSets = counts[1].value
+	textureCount := counts[2].value
+	cdTextureCount := counts[3].value
+	stat.SkinFamilies = counts[4].value
+	bodyPartCount := counts[5].value
+	stat.Attachments = counts[6].value
+
+	hitboxSetIndex := int32At(data, 176)
+	for set := 0; set < stat.HitboxSets; set++ {
+		setOff := hitboxSetIndex + set*mdlHitboxSetSize
+		if err := requireRange(data, setOff, mdlHitboxSetSize); err != nil {
+			return fmt.Errorf("hitboxset 偏移无效: %w", err)
+		}
+		stat.Hitboxes += max(0, int32At(data, setOff+4))
+	}
+
+	textureIndex := int32At(data, 208)
+	stat.Materials = make([]string, 0, textureCount)
+	for texture := 0; texture < textureCount; texture++ {
+		textureOff := textureIndex + texture*mdlTextureSize
+		if err := requireRange(data, textureOff, mdlTextureSize); err != nil {
+			return fmt.Errorf("texture 偏移无效: %w", err)
+		}
+		stat.Materials = append(stat.Materials, normalizeMDLMaterialPath(cStringAt(data, textureOff+int32At(data, textureOff), mdlMaxNameLength)))
 	}
-	vertices := int32At(data, 16)
-	if vertices < 0 {
-		return 0, fmt.Errorf("LOD0 顶点数无效")
+
+	cdTextureIndex := int32At(data, 216)
+	stat.MaterialPaths = make([]string, 0, cdTextureCount)
+	for cd := 0; cd < cdTextureCount; cd++ {
+		pathOff, err := safeI32(data, cdTextureIndex+cd*4)
+		if err != nil {
+			return fmt.Errorf("cdmaterials 偏移无效: %w", err)
+		}
+		stat.MaterialPaths = append(stat.MaterialPaths, normalizeMDLMaterialPath(cStringAt(data, pathOff, mdlMaxNameLength)))
+	}
+
+	bodyPartIndex := int32At(data, 236)
+	stat.BodyGroups = make([]ModelBodyGroup, 0, bodyPartCount)
+	for part := 0; part < bodyPartCount; part++ {
+		partOff := bodyPartIndex + part*mdlBodyPartSize
+		if err := requireRange(data, partOff, mdlBodyPartSize); err != nil {
+			return fmt.Errorf("bodypart 偏移无效: %w", err)
+		}
+		stat.BodyGroups = append(stat.BodyGroups, ModelBodyGroup{
+			Name:   cStringAt(data, partOff+int32At(data, partOff), mdlMaxNameLength),
+			Models: max(0, int32At(data, partOff+4)),
+		})
+	}
+	return nil
+}
+
+// cStringAt reads a NUL-terminated string of at most limit bytes; an
+// out-of-range offset yields an empty string.
+func cStringAt(data []byte, offset, limit int) string {
+	if offset < 0 || offset >= len(data) {
+		return ""
 	}
-	return int(vertices), nil
+	end := min(len(data), offset+limit)
+	value := data[offset:end]
+	if i := bytes.IndexByte(value, 0); i >= 0 {
+		value = value[:i]
+	}
+	return string(value)
+}
+
+// normalizeMDLMaterialPath lowercases a material path and uses forward slashes,
+// keeping the trailing slash of $cdmaterials directories.
+func normalizeMDLMaterialPath(value string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(value), "\\", "/"))
 }
 
-type vtxLOD0Stats struct {
+type vtxLODStats struct {
 	Triangles               int
 	TotalStripGroupVertices int
 	TotalStripGroupIndices  int
@@ -180,57 +349,89 @@ type vtxLOD0Stats struct {
 	MaxStripGroupIndices    int
 	TriangleStripEstimated  bool
 	StripGroups             []ModelStripGroupStat
+	SwitchPoint             float32
+	hasSwitchPoint          bool
 }
 
-func parseVTXLOD0Stats(data []byte) (vtxLOD0Stats, error) {
+// modelLODStats converts the per-LOD VTX totals to ModelLODStat. vvdVertices
+// supplies the vertex count of each LOD when the VVD was readable.
+func modelLODStats(lods []vtxLODStats, vvdVertices []int) []ModelLODStat {
+	result := make([]ModelLODStat, 0, len(lods))
+	for lod, stats := range lods {
+		item := ModelLODStat{
+			LOD:                    lod,
+			Triangles:              stats.Triangles,
+			StripGroupCount:        len(stats.StripGroups),
+			SwitchPoint:            stats.SwitchPoint,
+			TriangleStripEstimated: stats.TriangleStripEstimated,
+		}
+		if lod < len(vvdVertices) {
+			item.Vertices = vvdVertices[lod]
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// parseVTXStats walks the VTX once and sums the geometry of every LOD across
+// all bodyparts and models. The header's LOD count sizes the result; when it is
+// out of range only LOD0 is collected.
+func parseVTXStats(data []byte) ([]vtxLODStats, error) {
 	if len(data) < vtxHeaderSize {
-		return vtxLOD0Stats{}, fmt.Errorf("文件头过小")
+		return nil, fmt.Errorf("文件头过小")
 	}
 	if version := int32At(data, 0); version != vtxFileVersion {
-		return vtxLOD0Stats{}, fmt.Errorf("VTX 版本不支持: %d", version)
+		return nil, fmt.Errorf("VTX 版本不支持: %d", version)
+	}
+	numLODs := int32At(data, 20)
+	if numLODs <= 0 || numLODs > vvdMaxLODs {
+		numLODs = 1
 	}
 
 	numBodyParts, err := parseVTXBodyPartCount(data, 28)
 	if err != nil {
-		return vtxLOD0Stats{}, err
+		return nil, err
 	}
 	bodyPartOffset, err := safeI32(data, 32)
 	if err != nil {
-		return vtxLOD0Stats{}, err
+		return nil, err
 	}
-	stats := vtxLOD0Stats{}
+	lods := make([]vtxLODStats, numLODs)
 	for bp := 0; bp < numBodyParts; bp++ {
 		bpOff := bodyPartOffset + bp*vtxBodyPartSize
 		if err := requireRange(data, bpOff, vtxBodyPartSize); err != nil {
-			return vtxLOD0Stats{}, fmt.Errorf("bodypart 偏移无效: %w", err)
+			return nil, fmt.Errorf("bodypart 偏移无效: %w", err)
 		}
 		numModels := int32At(data, bpOff)
 		modelOffset := int32At(data, bpOff+4)
 		if numModels < 0 || numModels > 4096 {
-			return vtxLOD0Stats{}, fmt.Errorf("model 数量无效")
+			return nil, fmt.Errorf("model 数量无效")
 		}
 
 		for model := 0; model < numModels; model++ {
 			modelOff := bpOff + modelOffset + model*vtxModelSize
 			if err := requireRange(data, modelOff, vtxModelSize); err != nil {
-				return vtxLOD0Stats{}, fmt.Errorf("model 偏移无效: %w", err)
+				return nil, fmt.Errorf("model 偏移无效: %w", err)
 			}
-			numLODs := int32At(data, modelOff)
+			modelLODs := min(int32At(data, modelOff), numLODs)
 			lodOffset := int32At(data, modelOff+4)
-			if numLODs <= 0 {
-				continue
-			}
 
-			lodOff := modelOff + lodOffset
-			lodStats, err := parseVTXLOD0Model(data, lodOff, bp, model)
-			if err != nil {
-				return vtxLOD0Stats{}, err
+			for lod := 0; lod < modelLODs; lod++ {
+				lodOff := modelOff + lodOffset + lod*vtxModelLODSize
+				lodStats, err := parseVTXLODModel(data, lodOff, bp, model)
+				if err != nil {
+					return nil, err
+				}
+				if !lods[lod].hasSwitchPoint {
+					lods[lod].SwitchPoint = lodStats.SwitchPoint
+					lods[lod].hasSwitchPoint = true
+				}
+				lods[lod].add(lodStats)
 			}
-			stats.add(lodStats)
 		}
 	}
 
-	return stats, nil
+	return lods, nil
 }
 
 func parseVTXBodyPartCount(data []byte, offset int) (int, error) {
@@ -254,7 +455,7 @@ func parseVTXBodyPartCount(data []byte, offset int) (int, error) {
 	return 0, fmt.Errorf("bodypart 数量无效")
 }
 
-func (stats *vtxLOD0Stats) add(next vtxLOD0Stats) {
+func (stats *vtxLODStats) add(next vtxLODStats) {
 	stats.Triangles += next.Triangles
 	stats.TotalStripGroupVertices += next.TotalStripGroupVertices
 	stats.TotalStripGroupIndices += next.TotalStripGroupIndices
@@ -264,33 +465,33 @@ func (stats *vtxLOD0Stats) add(next vtxLOD0Stats) {
 	stats.StripGroups = append(stats.StripGroups, next.StripGroups...)
 }
 
-func parseVTXLOD0Model(data []byte, lodOff, bodyPartIndex, modelIndex int) (vtxLOD0Stats, error) {
+func parseVTXLODModel(data []byte, lodOff, bodyPartIndex, modelIndex int) (vtxLODStats, error) {
 	if err := requireRange(data, lodOff, vtxModelLODSize); err != nil {
-		return vtxLOD0Stats{}, fmt.Errorf("lod 偏移无效: %w", err)
+		return vtxLODStats{}, fmt.Errorf("lod 偏移无效: %w", err)
 	}
 	numMeshes := int32At(data, lodOff)
 	meshOffset := int32At(data, lodOff+4)
 	if numMeshes < 0 || numMeshes > 65536 {
-		return vtxLOD0Stats{}, fmt.Errorf("mesh 数量无效")
+		return vtxLODStats{}, fmt.Errorf("mesh 数量无效")
 	}
 
-	stats := vtxLOD0Stats{}
+	stats := vtxLODStats{SwitchPoint: math.Float32frombits(binary.LittleEndian.Uint32(data[lodOff+8:]))}
 	for mesh := 0; mesh < numMeshes; mesh++ {
 		meshOff := lodOff + meshOffset + mesh*vtxMeshSize
 		if err := requireRange(data, meshOff, vtxMeshSize); err != nil {
-			return vtxLOD0Stats{}, fmt.Errorf("mesh 偏移无效: %w", err)
+			return vtxLODStats{}, fmt.Errorf("mesh 偏移无效: %w", err)
 		}
 		numStripGroups := int32At(data, meshOff)
 		stripGroupOffset := int32At(data, meshOff+4)
 		if numStripGroups < 0 || numStripGroups > 65536 {
-			return vtxLOD0Stats{}, fmt.Errorf("stripgroup 数量无效")
+			return vtxLODStats{}, fmt.Errorf("stripgroup 数量无效")
 		}
 
 		for sg := 0; sg < numStripGroups; sg++ {
 			sgOff := meshOff + stripGroupOffset + sg*vtxStripGroupSize
 			group, err := parseVTXStripGroup(data, sgOff)
 			if err != nil {
-				return vtxLOD0Stats{}, err
+				return vtxLODStats{}, err
 			}
 			group.BodyPart = bodyPartIndex
 			group.Model = modelIndex
@@ -2,23 +2,26 @@ package parser
 
 import (
 	"encoding/binary"
+	"math"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
-func TestParseVVDLOD0VertexCount(t *testing.T) {
+func TestParseVVDLODVertexCounts(t *testing.T) {
 	data := make([]byte, 64)
 	binary.LittleEndian.PutUint32(data[0:], uint32(vvdFileID))
 	binary.LittleEndian.PutUint32(data[4:], 4)
 	binary.LittleEndian.PutUint32(data[12:], 3)
 	binary.LittleEndian.PutUint32(data[16:], 19838)
 
-	count, err := parseVVDLOD0VertexCount(data)
+	counts, err := parseVVDLODVertexCounts(data)
 	if err != nil {
 		t.Fatalf("parse VVD vertices: %v", err)
 	}
-	if count != 19838 {
-		t.Fatalf("expected 19838 vertices, got %d", count)
+	if len(counts) != 3 || counts[0] != 19838 {
+		t.Fatalf("expected 19838 LOD0 vertices, got %v", counts)
 	}
 }
 
@@ -29,7 +32,7 @@ func TestParseVVDRejectsInvalidHeader(t *testing.T) {
 	binary.LittleEndian.PutUint32(data[12:], 1)
 	binary.LittleEndian.PutUint32(data[16:], 19838)
 
-	if _, err := parseVVDLOD0VertexCount(data); err == nil {
+	if _, err := parseVVDLODVertexCounts(data); err == nil {
 		t.Fatalf("expected invalid VVD header to fail")
 	}
 }
@@ -37,15 +40,15 @@ func TestParseVVDRejectsInvalidHeader(t *testing.T) {
 func TestParseVTXLOD0TriangleCountTriListPackOne(t *testing.T) {
 	data := buildMinimalVTXFixture(t, 300, 0x01)
 
-	stats, err := parseVTXLOD0Stats(data)
+	lods, err := parseVTXStats(data)
 	if err != nil {
 		t.Fatalf("parse VTX triangles: %v", err)
 	}
-	if stats.TriangleStripEstimated {
+	if lods[0].TriangleStripEstimated {
 		t.Fatalf("tri-list should not be marked estimated")
 	}
-	if stats.Triangles != 100 {
-		t.Fatalf("expected 100 triangles, got %d", stats.Triangles)
+	if lods[0].Triangles != 100 {
+		t.Fatalf("expected 100 triangles, got %d", lods[0].Triangles)
 	}
 }
 
@@ -53,26 +56,26 @@ func TestParseVTXLOD0StatsReadsStripGroupVerticesAndIndices(t *testing.T) {
 	data := buildMinimalVTXFixture(t, 300, 0x01)
 	binary.LittleEndian.PutUint32(data[stripGroupFixtureOffset:], 1234)
 
-	stats, err := parseVTXLOD0Stats(data)
+	lods, err := parseVTXStats(data)
 	if err != nil {
 		t.Fatalf("parse VTX strip groups: %v", err)
 	}
-	if stats.TotalStripGroupVertices != 1234 {
-		t.Fatalf("expected total strip group vertices 1234, got %d", stats.TotalStripGroupVertices)
+	if lods[0].TotalStripGroupVertices != 1234 {
+		t.Fatalf("expected total strip group vertices 1234, got %d", lods[0].TotalStripGroupVertices)
 	}
-	if stats.MaxStripGroupVertices != 1234 {
-		t.Fatalf("expected max strip group vertices 1234, got %d", stats.MaxStripGroupVertices)
+	if lods[0].MaxStripGroupVertices != 1234 {
+		t.Fatalf("expected max strip group vertices 1234, got %d", lods[0].MaxStripGroupVertices)
 	}
-	if stats.TotalStripGroupIndices != 300 {
-		t.Fatalf("expected total strip group indices 300, got %d", stats.TotalStripGroupIndices)
+	if lods[0].TotalStripGroupIndices != 300 {
+		t.Fatalf("expected total strip group indices 300, got %d", lods[0].TotalStripGroupIndices)
 	}
-	if stats.MaxStripGroupIndices != 300 {
-		t.Fatalf("expected max strip group indices 300, got %d", stats.MaxStripGroupIndices)
+	if lods[0].MaxStripGroupIndices != 300 {
+		t.Fatalf("expected max strip group indices 300, got %d", lods[0].MaxStripGroupIndices)
 	}
-	if len(stats.StripGroups) != 1 {
-		t.Fatalf("expected one strip group, got %d", len(stats.StripGroups))
+	if len(lods[0].StripGroups) != 1 {
+		t.Fatalf("expected one strip group, got %d", len(lods[0].StripGroups))
 	}
-	group := stats.StripGroups[0]
+	group := lods[0].StripGroups[0]
 	if group.BodyPart != 0 || group.Model != 0 || group.Mesh != 0 || group.StripGroup != 0 {
 		t.Fatalf("unexpected strip group location: %+v", group)
 	}
@@ -84,15 +87,15 @@ func TestParseVTXLOD0StatsReadsStripGroupVerticesAndIndices(t *testing.T) {
 func TestParseVTXLOD0TriangleCountTriStripMarksEstimated(t *testing.T) {
 	data := buildMinimalVTXFixture(t, 102, 0x02)
 
-	stats, err := parseVTXLOD0Stats(data)
+	lods, err := parseVTXStats(data)
 	if err != nil {
 		t.Fatalf("parse VTX triangles: %v", err)
 	}
-	if !stats.TriangleStripEstimated {
+	if !lods[0].TriangleStripEstimated {
 		t.Fatalf("tri-strip should be marked estimated")
 	}
-	if stats.Triangles != 100 {
-		t.Fatalf("expected 100 triangles, got %d", stats.Triangles)
+	if lods[0].Triangles != 100 {
+		t.Fatalf("expected 100 triangles, got %d", lods[0].Triangles)
 	}
 }
 
@@ -100,7 +103,7 @@ func TestParseVTXRejectsOutOfRangePackedOffsets(t *testing.T) {
 	data := buildMinimalVTXFixture(t, 300, 0x01)
 	binary.LittleEndian.PutUint32(data[32:], 999999)
 
-	if _, err := parseVTXLOD0Stats(data); err == nil {
+	if _, err := parseVTXStats(data); err == nil {
 		t.Fatalf("expected bad bodypart offset to fail")
 	}
 }
@@ -109,12 +112,12 @@ func TestParseVTXLOD0StatsAcceptsDirtyHighBytesInBodyPartCount(t *testing.T) {
 	data := buildMinimalVTXFixture(t, 300, 0x01)
 	binary.LittleEndian.PutUint32(data[28:], 0x00238401)
 
-	stats, err := parseVTXLOD0Stats(data)
+	lods, err := parseVTXStats(data)
 	if err != nil {
 		t.Fatalf("parse VTX with dirty bodypart count high bytes: %v", err)
 	}
-	if stats.Triangles != 100 {
-		t.Fatalf("expected 100 triangles, got %d", stats.Triangles)
+	if lods[0].Triangles != 100 {
+		t.Fatalf("expected 100 triangles, got %d", lods[0].Triangles)
 	}
 }
 
@@ -122,7 +125,7 @@ func TestParseVTXRejectsUnsupportedVersion(t *testing.T) {
 	data := buildMinimalVTXFixture(t, 300, 0x01)
 	binary.LittleEndian.PutUint32(data[0:], 6)
 
-	if _, err := parseVTXLOD0Stats(data); err == nil {
+	if _, err := parseVTXStats(data); err == nil {
 		t.Fatalf("expected unsupported VTX version to fail")
 	}
 }
@@ -188,3 +191,252 @@ func buildMinimalVTXFixture(t *testing.T, stripIndices int, flags byte) []byte {
 }
 
 const stripGroupFixtureOffset = 36 + 8 + 8 + 12 + 9
+
+func TestParseMDLHeader(t *testing.T) {
+	var stat ModelStat
+	if err := parseMDLHeader(buildMDLFixture(t), &stat); err != nil {
+		t.Fatalf("parse MDL header: %v", err)
+	}
+	if stat.MDLVersion != 48 || stat.Name != "survivors/survivor_coach.mdl" || stat.Bones != 72 || stat.Attachments != 9 || stat.SkinFamilies != 2 {
+		t.Fatalf("unexpected MDL header: %+v", stat)
+	}
+	if stat.HitboxSets != 1 || stat.Hitboxes != 15 {
+		t.Fatalf("expected 15 hitboxes in 1 set, got %d in %d", stat.Hitboxes, stat.HitboxSets)
+	}
+	if len(stat.Materials) != 2 || stat.Materials[0] != "coach_body" || stat.Materials[1] != "coach_head" {
+		t.Fatalf("unexpected materials: %v", stat.Materials)
+	}
+	if len(stat.MaterialPaths) != 1 || stat.MaterialPaths[0] != "models/survivors/coach/" {
+		t.Fatalf("unexpected $cdmaterials: %v", stat.MaterialPaths)
+	}
+	if len(stat.BodyGroups) != 2 || stat.BodyGroups[0] != (ModelBodyGroup{Name: "body", Models: 1}) || stat.BodyGroups[1] != (ModelBodyGroup{Name: "hats", Models: 3}) {
+		t.Fatalf("unexpected body groups: %+v", stat.BodyGroups)
+	}
+}
+
+func TestParseMDLRejectsInvalidHeader(t *testing.T) {
+	for name, mutate := range map[string]func([]byte){
+		"id":       func(data []byte) { copy(data, "IDSQ") },
+		"version":  func(data []byte) { binary.LittleEndian.PutUint32(data[4:], 37) },
+		"count":    func(data []byte) { binary.LittleEndian.PutUint32(data[156:], 0xffffffff) },
+		"textures": func(data []byte) { binary.LittleEndian.PutUint32(data[208:], 999999) },
+	} {
+		data := buildMDLFixture(t)
+		mutate(data)
+		if err := parseMDLHeader(data, &ModelStat{}); err == nil {
+			t.Errorf("%s: expected invalid MDL to fail", name)
+		}
+	}
+
+	// 多个数量无效时总是报告文件头中靠前的一个
+	data := buildMDLFixture(t)
+	binary.LittleEndian.PutUint32(data[156:], 0xffffffff)
+	binary.LittleEndian.PutUint32(data[240:], 0xffffffff)
+	if err := parseMDLHeader(data, &ModelStat{}); err == nil || !strings.HasPrefix(err.Error(), "bone ") {
+		t.Fatalf("expected bone count error, got %v", err)
+	}
+}
+
+func TestParseVTXLODsReadsEveryLOD(t *testing.T) {
+	stats, err := parseVTXStats(buildMultiLODVTXFixture(t, 300, 150, 30))
+	if err != nil {
+		t.Fatalf("parse VTX LODs: %v", err)
+	}
+	lods := modelLODStats(stats, []int{500, 260, 40})
+	if len(lods) != 3 {
+		t.Fatalf("expected 3 LODs, got %d", len(lods))
+	}
+	for i, want := range []ModelLODStat{
+		{LOD: 0, Vertices: 500, Triangles: 100, StripGroupCount: 1, SwitchPoint: 0},
+		{LOD: 1, Vertices: 260, Triangles: 50, StripGroupCount: 1, SwitchPoint: 20},
+		{LOD: 2, Vertices: 40, Triangles: 10, StripGroupCount: 1, SwitchPoint: 40},
+	} {
+		if lods[i] != want {
+			t.Errorf("LOD %d: expected %+v, got %+v", i, want, lods[i])
+		}
+	}
+}
+
+func TestAnalyzeVPKModelStatsReadsMDLAndLODs(t *testing.T) {
+	vvd := make([]byte, 64)
+	binary.LittleEndian.PutUint32(vvd[0:], uint32(vvdFileID))
+	binary.LittleEndian.PutUint32(vvd[4:], 4)
+	binary.LittleEndian.PutUint32(vvd[12:], 2)
+	binary.LittleEndian.PutUint32(vvd[16:], 500)
+	binary.LittleEndian.PutUint32(vvd[20:], 260)
+
+	vpkPath := filepath.Join(t.TempDir(), "coach.vpk")
+	writeParserTestVPK(t, vpkPath, map[string][]byte{
+		"models/survivors/survivor_coach.mdl":      buildMDLFixture(t),
+		"models/survivors/survivor_coach.vvd":      vvd,
+		"models/survivors/survivor_coach.dx90.vtx": buildMultiLODVTXFixture(t, 300, 150),
+	})
+
+	stats, err := AnalyzeVPKModelStats(vpkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.Models) != 1 {
+		t.Fatalf("expected one model, got %+v", stats)
+	}
+	model := stats.Models[0]
+	if model.Message != "" || model.Bones != 72 || model.Vertices != 500 || model.Triangles != 100 || len(model.Materials) != 2 {
+		t.Fatalf("unexpected model stat: %+v", model)
+	}
+	if len(model.LODs) != 2 || model.LODs[1].Vertices != 260 || model.LODs[1].Triangles != 50 {
+		t.Fatalf("unexpected LODs: %+v", model.LODs)
+	}
+}
+
+// mdlFixtureBone is a bone written by buildMDLFixture, placed z units above
+// the origin.
+type mdlFixtureBone struct {
+	name   string
+	parent int
+	z      float32
+}
+
+// buildMDLFixture builds a version 48 MDL header with one hitbox set, two
+// textures, one $cdmaterials path and two body parts. When bones are given it
+// also writes them, an identity skin table and one model per body part; only
+// the first model has a mesh, using material 0 and four vertices.
+func buildMDLFixture(t *testing.T, bones ...mdlFixtureBone) []byte {
+	t.Helper()
+
+	const (
+		hitboxSetOffset = mdlHeaderSize
+		textureOffset   = hitboxSetOffset + mdlHitboxSetSize
+		cdTextureOffset = textureOffset + 2*mdlTextureSize
+		bodyPartOffset  = cdTextureOffset + 4
+		boneOffset      = bodyPartOffset + 2*mdlBodyPartSize
+	)
+	skinOffset := boneOffset + len(bones)*mdlBoneSize
+	modelOffset := skinOffset + 8
+	meshOffset := modelOffset + 2*mdlModelSize
+	stringOffset := boneOffset
+	if len(bones) > 0 {
+		stringOffset = meshOffset + mdlMeshSize
+	}
+
+	data := make([]byte, stringOffset)
+	stringOffsets := map[string]int{}
+	addString := func(value string) int {
+		if offset, ok := stringOffsets[value]; ok {
+			return offset
+		}
+		offset := len(data)
+		data = append(data, value...)
+		data = append(data, 0)
+		stringOffsets[value] = offset
+		return offset
+	}
+	put := func(offset, value int) {
+		binary.LittleEndian.PutUint32(data[offset:], uint32(int32(value)))
+	}
+	putFloats := func(offset int, values ...float32) {
+		for i, value := range values {
+			binary.LittleEndian.PutUint32(data[offset+i*4:], math.Float32bits(value))
+		}
+	}
+
+	copy(data, "IDST")
+	put(4, 48)
+	copy(data[12:], "survivors/survivor_coach.mdl")
+	put(156, 72)
+	put(172, 1)
+	put(176, hitboxSetOffset)
+	put(204, 2)
+	put(208, textureOffset)
+	put(212, 1)
+	put(216, cdTextureOffset)
+	put(220, 2)
+	put(224, 2)
+	put(232, 2)
+	put(236, bodyPartOffset)
+	put(240, 9)
+
+	put(hitboxSetOffset, addString("default")-hitboxSetOffset)
+	put(hitboxSetOffset+4, 15)
+	for i, name := range []string{"coach_body", "Coach_Head"} {
+		offset := textureOffset + i*mdlTextureSize
+		put(offset, addString(name)-offset)
+	}
+	put(cdTextureOffset, addString("models\\Survivors\\Coach\\"))
+	for i, part := range []ModelBodyGroup{{Name: "body", Models: 1}, {Name: "hats", Models: 3}} {
+		offset := bodyPartOffset + i*mdlBodyPartSize
+		put(offset, addString(part.Name)-offset)
+		put(offset+4, part.Models)
+		if len(bones) > 0 {
+			put(offset+12, modelOffset+i*mdlModelSize-offset)
+		}
+	}
+
+	if len(bones) > 0 {
+		put(156, len(bones))
+		put(160, boneOffset)
+		for i, bone := range bones {
+			offset := boneOffset + i*mdlBoneSize
+			put(offset, addString(bone.name)-offset)
+			put(offset+4, bone.parent)
+			putFloats(offset+32, 0, 0, bone.z)
+			putFloats(offset+44, 0, 0, 0, 1)
+			putFloats(offset+96, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, -bone.z)
+		}
+		put(228, skinOffset)
+		for i, ref := range []uint16{0, 1, 0, 1} {
+			binary.LittleEndian.PutUint16(data[skinOffset+i*2:], ref)
+		}
+		put(modelOffset+72, 1)
+		put(modelOffset+76, meshOffset-modelOffset)
+		put(modelOffset+80, 4)
+	}
+	put(76, len(data))
+	return data
+}
+
+// buildMultiLODVTXFixture builds a VTX with one bodypart and model whose LODs
+// each hold one mesh, strip group and tri-list strip. LOD n switches at 20*n.
+func buildMultiLODVTXFixture(t *testing.T, lodIndices ...int) []byte {
+	t.Helper()
+
+	const (
+		bodyPartOffset = vtxHeaderSize
+		modelOffset    = bodyPartOffset + vtxBodyPartSize
+		lodOffset      = modelOffset + vtxModelSize
+		blockSize      = vtxMeshSize + vtxStripGroupSize + vtxStripSize
+	)
+
+	meshBase := lodOffset + len(lodIndices)*vtxModelLODSize
+	data := make([]byte, meshBase+len(lodIndices)*blockSize)
+	put := func(offset, value int) {
+		binary.LittleEndian.PutUint32(data[offset:], uint32(int32(value)))
+	}
+
+	put(0, vtxFileVersion)
+	put(20, len(lodIndices))
+	put(28, 1)
+	put(32, bodyPartOffset)
+	put(bodyPartOffset, 1)
+	put(bodyPartOffset+4, modelOffset-bodyPartOffset)
+	put(modelOffset, len(lodIndices))
+	put(modelOffset+4, lodOffset-modelOffset)
+
+	for lod, indices := range lodIndices {
+		lodOff := lodOffset + lod*vtxModelLODSize
+		meshOff := meshBase + lod*blockSize
+		sgOff := meshOff + vtxMeshSize
+		stripOff := sgOff + vtxStripGroupSize
+
+		put(lodOff, 1)
+		put(lodOff+4, meshOff-lodOff)
+		binary.LittleEndian.PutUint32(data[lodOff+8:], math.Float32bits(float32(20*lod)))
+		put(meshOff, 1)
+		put(meshOff+4, sgOff-meshOff)
+		put(sgOff+8, indices)
+		put(sgOff+16, 1)
+		put(sgOff+20, stripOff-sgOff)
+		put(stripOff, indices)
+		data[stripOff+18] = vtxStripIsTriList
+	}
+	return data
+}
@@ -2,15 +2,18 @@ package parser
 
 import (
 	"bytes"
-	"encoding/base64"
+	"fmt"
 	"image"
 	_ "image/gif"
 	_ "image/jpeg"
-	_ "image/png"
+	"image/png"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
 
 	"l4d2-manager-next/pkg/valve/vpk"
@@ -19,13 +22,45 @@ import (
 // ParseVPKFile 解析VPK文件的主入口函数
 // 输入文件路径,返回解析后的VPKFile结构
 func ParseVPKFile(filePath string) (*VPKFile, error) {
+	vpkFile, _, _, err := parseVPKFile(filePath)
+	return vpkFile, err
+}
+
+// ParseVPKFileWithEntries 解析VPK，同时返回已读取的内部文件列表，避免调用方再次读取目录
+func ParseVPKFileWithEntries(filePath string) (*VPKFile, []VPKEntryInfo, error) {
+	vpkFile, _, entries, err := parseVPKFile(filePath)
+	return vpkFile, entries, err
+}
+
+// ExplainVPKTags 使用当前分类规则重新解析VPK，返回每个标签由哪条规则、哪个文件得出
+func ExplainVPKTags(filePath string) (*Classification, error) {
+	_, classification, _, err := parseVPKFile(filePath)
+	return classification, err
+}
+
+// ReclassifyVPKFile 使用当前分类规则，按内部文件列表重新计算已解析VPK的标签，文件名中的自定义标签保持不变。
+// 地图的战役标签需要读取 mission 文件，分类前后涉及地图时返回 false，由调用方重新解析
+func ReclassifyVPKFile(vpkFile *VPKFile, files []string) bool {
+	if _, _, _, ok := ParseFilenameTags(vpkFile.Name); ok {
+		return true
+	}
+	classification := currentClassifier().Classify(files, strings.TrimSpace(vpkFile.Title+" "+vpkFile.Desc))
+	if classification.PrimaryTag == "地图" || vpkFile.PrimaryTag == "地图" {
+		return false
+	}
+	vpkFile.PrimaryTag = classification.PrimaryTag
+	vpkFile.SecondaryTags = append([]string{}, classification.SecondaryTags...)
+	return true
+}
+
+func parseVPKFile(filePath string) (*VPKFile, *Classification, []VPKEntryInfo, error) {
 	// 打开VPK文件
 	opener := vpk.Single(filePath)
 	defer opener.Close()
 
 	archive, err := opener.ReadArchive()
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	// 创建基础文件信息
@@ -37,37 +72,35 @@ func ParseVPKFile(filePath string) (*VPKFile, error) {
 		Chapters:      make(map[string]ChapterInfo),
 	}
 
-	// 第一步:确定VPK的主要类型
-	vpkType := DetermineVPKType(archive)
-
-	// 提前提取资源信息（预览图和addoninfo），为后续处理提供元数据支持
+	// 提前提取资源信息（预览图和addoninfo），为分类规则提供元数据
 	ExtractVPKResources(opener, archive, vpkFile, filePath)
 
+	// 第一步:按分类规则确定一级标签和二级标签
+	files := make([]string, len(archive.Files))
+	for i := range archive.Files {
+		files[i] = archive.Files[i].Name()
+	}
+	classification := currentClassifier().Classify(files, strings.TrimSpace(vpkFile.Title+" "+vpkFile.Desc))
+	vpkFile.PrimaryTag = classification.PrimaryTag
+	vpkFile.Sound = AnalyzeSounds(opener, archive)
+
 	secondaryTags := make(map[string]bool)
+	for _, tag := range classification.SecondaryTags {
+		secondaryTags[tag] = true
+	}
 	chapters := make(map[string]ChapterInfo)
 
-	// 第二步：根据类型进行专门的检测
-	switch vpkType {
-	case "地图":
+	// 第二步：地图从mission文件读取战役名和章节
+	if vpkFile.PrimaryTag == "地图" {
 		ProcessMapVPK(opener, archive, vpkFile, secondaryTags, chapters)
-	case "人物":
-		ProcessCharacterVPK(archive, vpkFile, secondaryTags)
-	case "武器":
-		ProcessWeaponVPK(archive, vpkFile, secondaryTags)
-	default:
-		// 其他类型
-		vpkFile.PrimaryTag = "其他"
-		vpkFile.SecondaryTags = []string{}
-		vpkFile.Chapters = make(map[string]ChapterInfo)
-		// 注意：不在这里 return，让它继续执行提取预览图的逻辑
+		for _, tag := range sortedNewTags(secondaryTags, classification.SecondaryTags) {
+			classification.SecondaryTags = append(classification.SecondaryTags, tag)
+			classification.Matches = append(classification.Matches, TagMatch{Tag: tag, Source: "mission"})
+		}
 	}
 
 	// 设置最终的标签
-	vpkFile.SecondaryTags = []string{}
-	for tag := range secondaryTags {
-		vpkFile.SecondaryTags = append(vpkFile.SecondaryTags, tag)
-	}
-
+	vpkFile.SecondaryTags = append([]string{}, classification.SecondaryTags...)
 	vpkFile.Chapters = chapters
 
 	// 检查自定义标签并覆盖
@@ -78,9 +111,24 @@ func ParseVPKFile(filePath string) (*VPKFile, error) {
 		// 如果 [] 空的，len(tagParts)==1 ("") -> primaryTag=""
 		vpkFile.PrimaryTag = pTag
 		vpkFile.SecondaryTags = sTags
+		classification.FilenameOverride = true
+		classification.PrimaryTag = pTag
+		classification.SecondaryTags = append([]string{}, sTags...)
 	}
 
-	return vpkFile, nil
+	return vpkFile, classification, archiveEntries(archive), nil
+}
+
+// sortedNewTags 返回 tags 中不在 known 里的标签
+func sortedNewTags(tags map[string]bool, known []string) []string {
+	var result []string
+	for tag := range tags {
+		if !slices.Contains(known, tag) {
+			result = append(result, tag)
+		}
+	}
+	sort.Strings(result)
+	return result
 }
 
 var tagRegex = regexp.MustCompile(`^(_)?\[(.*?)\](.*)$`)
@@ -131,9 +179,9 @@ func ParseFilenameTags(filename string) (string, []string, string, bool) {
 	return primaryTag, secondaryTags, matches[1] + matches[3], true
 }
 
-// GetPrimaryTags 获取所有主要标签
+// GetPrimaryTags 获取所有主要标签，包括用户分类规则中新增的标签
 func GetPrimaryTags() []string {
-	return []string{"地图", "人物", "武器", "其他"}
+	return currentClassifier().PrimaryTags()
 }
 
 // GetSecondaryTags 获取指定主标签下的所有二级标签
@@ -156,82 +204,6 @@ func GetSecondaryTags(vpkFiles []VPKFile, primaryTag string) []string {
 	return result
 }
 
-// ExtractPreviewImage 从VPK中提取预览图并转换为Base64
-// 采用三级查找策略:
-// 1. 优先查找 addonimage.jpg (Steam 创意工坊标准)
-// 2. 查找内部其他预览图
-// 3. 查找外部同名 .jpg 文件
-func ExtractPreviewImage(opener *vpk.Opener, archive *vpk.Archive, vpkFilePath string) string {
-	// ========== 优先级 1: 查找 addonimage.jpg ==========
-	// Steam 创意工坊的标准缩略图文件名
-	addonImageFile := findFileInArchive(archive, "addonimage.jpg")
-	if addonImageFile != nil {
-		base64Data := readAndEncodeImage(opener, addonImageFile)
-		if base64Data != "" {
-			return base64Data
-		}
-	}
-
-	// ========== 优先级 2: 查找其他预览图 (原有逻辑) ==========
-	// 常见的预览图路径模式
-	previewPatterns := []string{
-		".jpg",
-		".jpeg",
-		".png",
-		"materials/vgui/maps/menu/",
-		"materials/vgui/loadingscreen",
-		"resource/overviews/",
-	}
-
-	var previewFile *vpk.File
-
-	// 遍历所有文件，查找预览图
-	for i := range archive.Files {
-		file := &archive.Files[i]
-		filename := strings.ToLower(file.Name())
-
-		// 检查是否匹配预览图模式
-		for _, pattern := range previewPatterns {
-			if strings.Contains(filename, pattern) {
-				// 确保是图片文件
-				if strings.HasSuffix(filename, ".png") ||
-					strings.HasSuffix(filename, ".jpg") ||
-					strings.HasSuffix(filename, ".jpeg") ||
-					strings.HasSuffix(filename, ".gif") {
-					previewFile = file
-					break
-				}
-			}
-		}
-
-		if previewFile != nil {
-			break
-		}
-	}
-
-	if previewFile != nil {
-		if base64Data := readAndEncodeImage(opener, previewFile); base64Data != "" {
-			return base64Data
-		}
-	}
-
-	// ========== 优先级 3: 查找外部同名图片文件 (.jpg, .png, .jpeg) ==========
-	// 例如: xxx.vpk -> xxx.jpg
-	basePath := strings.TrimSuffix(vpkFilePath, filepath.Ext(vpkFilePath))
-	exts := []string{".jpg", ".png", ".jpeg", ".gif"}
-
-	for _, ext := range exts {
-		externalPath := basePath + ext
-		if fileExists(externalPath) {
-			if base64Data := readExternalImageFile(externalPath); base64Data != "" {
-				return base64Data
-			}
-		}
-	}
-
-	return ""
-}
-
 // findFileInArchive 在 VPK 中查找指定文件名（不区分大小写）
 func findFileInArchive(archive *vpk.Archive, targetName string) *vpk.File {
 	targetLower := strings.ToLower(targetName)
@@ -244,69 +216,28 @@ func findFileInArchive(archive *vpk.Archive, targetName string) *vpk.File {
 	return nil
 }
 
-// readAndEncodeImage 读取 VPK 内部文件并编码为 Base64
-func readAndEncodeImage(opener *vpk.Opener, file *vpk.File) string {
-	reader, err := file.Open(opener)
-	if err != nil {
-		return ""
-	}
-	defer reader.Close()
-
-	data, err := io.ReadAll(reader)
-	if err != nil {
-		return ""
-	}
-
-	return encodeImageToBase64(data)
-}
-
-// readExternalImageFile 读取外部图片文件并编码为 Base64
-func readExternalImageFile(filePath string) string {
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return ""
+// OpenVPKFileAt 打开VPK内的文件供按偏移读取，只读取用到的区间。
+// 不调用 Close：Close 会读完整个文件校验 CRC；文件句柄由 opener 持有。
+// vpk 库的 ReadAt 没有扣除目录内预载数据（Metadata）的长度，带预载数据的文件整体读入内存后再按偏移读取
+func OpenVPKFileAt(opener *vpk.Opener, file *vpk.File) (*io.SectionReader, error) {
+	size := int64(file.Size())
+	if len(file.Metadata) > 0 {
+		data, err := file.Bytes(opener)
+		if err != nil {
+			return nil, err
+		}
+		return io.NewSectionReader(bytes.NewReader(data), 0, size), nil
 	}
 
-	return encodeImageToBase64(data)
-}
-
-// encodeImageToBase64 将图片数据编码为 Base64 Data URL
-func encodeImageToBase64(data []byte) string {
-	// 尝试解码图片以验证格式
-	_, format, err := image.Decode(bytes.NewReader(data))
+	reader, err := file.Open(opener)
 	if err != nil {
-		return ""
-	}
-
-	// 如果是VTF格式（Valve纹理格式），我们暂时跳过
-	// 因为需要特殊的VTF解码器
-	if format != "png" && format != "jpeg" && format != "gif" {
-		return ""
+		return nil, err
 	}
-
-	// 将图片数据转换为Base64
-	base64Str := base64.StdEncoding.EncodeToString(data)
-
-	// 根据格式添加Data URL前缀
-	var dataURL string
-	switch format {
-	case "png":
-		dataURL = "data:image/png;base64," + base64Str
-	case "jpeg":
-		dataURL = "data:image/jpeg;base64," + base64Str
-	case "gif":
-		dataURL = "data:image/gif;base64," + base64Str
-	default:
-		return ""
+	readerAt, ok := reader.(io.ReaderAt)
+	if !ok {
+		return nil, fmt.Errorf("VPK 文件不支持随机读取")
 	}
-
-	return dataURL
-}
-
-// fileExists 检查文件是否存在
-func fileExists(filePath string) bool {
-	_, err := os.Stat(filePath)
-	return err == nil
+	return io.NewSectionReader(readerAt, 0, size), nil
 }
 
 // ExtractVPKResources 一次性提取VPK中的预览图和addoninfo信息
@@ -315,6 +246,7 @@ func ExtractVPKResources(opener *vpk.Opener, archive *vpk.Archive, vpkFile *VPKF
 	var addonImageFile *vpk.File
 	var addonInfoFile *vpk.File
 	var previewFile *vpk.File
+	var vtfFile *vpk.File
 
 	// 预览图匹配模式
 	previewPatterns := []string{
@@ -355,50 +287,145 @@ func ExtractVPKResources(opener *vpk.Opener, archive *vpk.Archive, vpkFile *VPKF
 			}
 		}
 
+		// 没有可用图片时用贴图生成预览
+		if strings.HasSuffix(filename, ".vtf") && vtfPreviewRank(file) > vtfPreviewRank(vtfFile) {
+			vtfFile = file
+		}
+
 		// 如果所有需要的文件都找到了，提前退出循环
 		if addonImageFile != nil && addonInfoFile != nil {
 			break
 		}
 	}
 
-	// 处理预览图
-	vpkFile.PreviewImage = extractPreviewImageFromFiles(opener, addonImageFile, previewFile, vpkFilePath)
+	// 处理预览图：只记录来源，图片数据由预览图服务按需读取
+	vpkFile.PreviewSource = previewSourceFromFiles(opener, addonImageFile, previewFile, vtfFile, vpkFilePath)
 
 	// 处理addoninfo
 	parseAddonInfoFromFile(opener, addonInfoFile, vpkFile)
 }
 
-// extractPreviewImageFromFiles 从找到的文件中提取预览图
-func extractPreviewImageFromFiles(opener *vpk.Opener, addonImageFile, previewFile *vpk.File, vpkFilePath string) string {
+// vtfPreviewRank 贴图作为预览图的优先级：addonimage.vtf 最高，其次是界面贴图，
+// 其余按文件大小选最大的；法线等非颜色贴图不参与。nil 返回 -1
+func vtfPreviewRank(file *vpk.File) int64 {
+	if file == nil {
+		return -1
+	}
+	name := strings.ToLower(file.Name())
+	switch {
+	case name == "addonimage.vtf":
+		return 1 << 62
+	case strings.HasPrefix(name, "materials/vgui/"):
+		return 1<<61 + int64(file.Size())
+	}
+	base := strings.TrimSuffix(path.Base(name), ".vtf")
+	for _, suffix := range []string{"normal", "bump", "_n", "exponent", "phong", "mask", "envmap", "spec"} {
+		if strings.HasSuffix(base, suffix) {
+			return -1
+		}
+	}
+	return int64(file.Size())
+}
+
+// previewSidecarPrefix 预览图来源为VPK旁的同名图片时的前缀，后接扩展名。
+// 只记录扩展名，VPK被移动或重命名后按新路径查找；VPK内部路径不含冒号，不会与之混淆
+const previewSidecarPrefix = "sidecar:"
+
+// previewSidecarPath 返回VPK旁指定扩展名的同名图片路径
+func previewSidecarPath(vpkFilePath string, ext string) string {
+	return strings.TrimSuffix(vpkFilePath, filepath.Ext(vpkFilePath)) + ext
+}
+
+// previewSourceFromFiles 确定预览图来源，只读取图片头部验证格式，不读取完整图片数据。
+// 返回外部同名图片的扩展名（带 previewSidecarPrefix 前缀）或 VPK 内部文件名，没有可用预览图时返回空字符串。
+func previewSourceFromFiles(opener *vpk.Opener, addonImageFile, previewFile, vtfFile *vpk.File, vpkFilePath string) string {
 	// 优先级1: 外部同名图片文件
-	basePath := strings.TrimSuffix(vpkFilePath, filepath.Ext(vpkFilePath))
 	exts := []string{".jpg", ".png", ".jpeg", ".gif"}
 	for _, ext := range exts {
-		externalPath := basePath + ext
-		if fileExists(externalPath) {
-			if base64Data := readExternalImageFile(externalPath); base64Data != "" {
-				return base64Data
+		if file, err := os.Open(previewSidecarPath(vpkFilePath, ext)); err == nil {
+			valid := isSupportedImage(file)
+			file.Close()
+			if valid {
+				return previewSidecarPrefix + ext
 			}
 		}
 	}
 
-	// 优先级2: addonimage.jpg
-	if addonImageFile != nil {
-		if base64Data := readAndEncodeImage(opener, addonImageFile); base64Data != "" {
-			return base64Data
+	// 优先级2: addonimage.jpg，优先级3: 其他预览图
+	for _, file := range []*vpk.File{addonImageFile, previewFile} {
+		if file == nil {
+			continue
+		}
+		reader, err := file.Open(opener)
+		if err != nil {
+			continue
+		}
+		valid := isSupportedImage(reader)
+		reader.Close()
+		if valid {
+			return file.Name()
 		}
 	}
 
-	// 优先级3: 其他预览图
-	if previewFile != nil {
-		if base64Data := readAndEncodeImage(opener, previewFile); base64Data != "" {
-			return base64Data
+	// 优先级4: 能解码的颜色贴图
+	if vtfFile != nil {
+		header, err := readVTFFileHeader(opener, vtfFile)
+		if err == nil && header.Decodable() && !header.IsNormalMap() && !header.IsEnvMap() {
+			return vtfFile.Name()
 		}
 	}
 
 	return ""
 }
 
+// isSupportedImage 根据图片头部判断是否为前端可直接显示的格式
+func isSupportedImage(r io.Reader) bool {
+	_, format, err := image.DecodeConfig(r)
+	if err != nil {
+		return false
+	}
+	return format == "png" || format == "jpeg" || format == "gif"
+}
+
+// ReadPreviewImage 按 VPKFile.PreviewSource 读取预览图的原始数据，VTF 贴图转换为 PNG
+func ReadPreviewImage(vpkFilePath string, source string) ([]byte, error) {
+	if source == "" {
+		return nil, fmt.Errorf("没有预览图")
+	}
+	if ext, ok := strings.CutPrefix(source, previewSidecarPrefix); ok {
+		return os.ReadFile(previewSidecarPath(vpkFilePath, ext))
+	}
+	if strings.EqualFold(path.Ext(source), ".vtf") {
+		img, err := ReadVTFImage(vpkFilePath, source, vtfPreviewMaxSize)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	opener := vpk.Single(vpkFilePath)
+	defer opener.Close()
+
+	archive, err := opener.ReadArchive()
+	if err != nil {
+		return nil, err
+	}
+	file := findFileInArchive(archive, source)
+	if file == nil {
+		return nil, fmt.Errorf("VPK 中未找到预览图: %s", source)
+	}
+	reader, err := file.Open(opener)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
 // parseAddonInfoFromFile 从addoninfo.txt文件解析信息
 func parseAddonInfoFromFile(opener *vpk.Opener, addonInfoFile *vpk.File, vpkFile *VPKFile) {
 	// 初始化默认值
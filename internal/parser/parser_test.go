@@ -0,0 +1,50 @@
+package parser
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSidecarPreviewFollowsMovedVPK(t *testing.T) {
+	var sidecar bytes.Buffer
+	if err := png.Encode(&sidecar, image.NewNRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	vpkPath := filepath.Join(dir, "skin.vpk")
+	writeParserTestVPK(t, vpkPath, map[string][]byte{"models/weapons/rifle.mdl": []byte("IDST")})
+	if err := os.WriteFile(filepath.Join(dir, "skin.png"), sidecar.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	vpkFile, err := ParseVPKFile(vpkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpkFile.PreviewSource != previewSidecarPrefix+".png" {
+		t.Fatalf("expected sidecar preview source, got %q", vpkFile.PreviewSource)
+	}
+
+	// 移动VPK及同名图片后，按新路径读取
+	movedDir := filepath.Join(dir, "disabled")
+	if err := os.Mkdir(movedDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	movedPath := filepath.Join(movedDir, "skin.vpk")
+	for _, name := range []string{"skin.vpk", "skin.png"} {
+		if err := os.Rename(filepath.Join(dir, name), filepath.Join(movedDir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := ReadPreviewImage(movedPath, vpkFile.PreviewSource)
+	if err != nil || !bytes.Equal(data, sidecar.Bytes()) {
+		t.Fatalf("expected sidecar image next to the moved vpk, got %d bytes, %v", len(data), err)
+	}
+	if _, err := ReadPreviewImage(filepath.Join(dir, "renamed.vpk"), vpkFile.PreviewSource); err == nil {
+		t.Fatal("expected error when no sidecar sits next to the vpk")
+	}
+}
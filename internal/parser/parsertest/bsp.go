@@ -0,0 +1,72 @@
+// Package parsertest 提供 parser 及上层包测试共用的文件构造工具
+package parsertest
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// 与 parser 中的 BSP 文件头和数据块编号一致
+const (
+	bspHeaderSize     = 8 + 64*16 + 4
+	bspLumpEntity     = 0
+	bspLumpGame       = 35
+	bspLumpPakfile    = 40
+	bspStaticPropName = 128
+)
+
+// BuildBSP 生成包含实体、静态模型字典和 pakfile 的最小 BSP。l4d2 为 true 时使用 L4D2 的数据块目录字段顺序
+func BuildBSP(t testing.TB, l4d2 bool, entities string, staticProps []string, pak map[string][]byte) []byte {
+	t.Helper()
+
+	var pakData bytes.Buffer
+	writer := zip.NewWriter(&pakData)
+	for name, content := range pak {
+		w, err := writer.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(content)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data := make([]byte, bspHeaderSize)
+	copy(data, "VBSP")
+	binary.LittleEndian.PutUint32(data[4:], 21)
+	binary.LittleEndian.PutUint32(data[bspHeaderSize-4:], 77)
+	setLump := func(index int, payload []byte) {
+		offset := len(data)
+		data = append(data, payload...)
+		fields := []uint32{uint32(offset), uint32(len(payload)), 1}
+		if l4d2 {
+			fields = []uint32{1, uint32(offset), uint32(len(payload))}
+		}
+		for i, value := range fields {
+			binary.LittleEndian.PutUint32(data[8+index*16+i*4:], value)
+		}
+	}
+
+	setLump(bspLumpEntity, append([]byte(entities), 0))
+
+	gameLumpOffset := len(data)
+	sprp := binary.LittleEndian.AppendUint32(nil, uint32(len(staticProps)))
+	for _, name := range staticProps {
+		entry := make([]byte, bspStaticPropName)
+		copy(entry, name)
+		sprp = append(sprp, entry...)
+	}
+	game := binary.LittleEndian.AppendUint32(nil, 1)
+	game = binary.LittleEndian.AppendUint32(game, 0x73707270)
+	game = binary.LittleEndian.AppendUint16(game, 0)
+	game = binary.LittleEndian.AppendUint16(game, 6)
+	game = binary.LittleEndian.AppendUint32(game, uint32(gameLumpOffset+4+16))
+	game = binary.LittleEndian.AppendUint32(game, uint32(len(sprp)))
+	setLump(bspLumpGame, append(game, sprp...))
+
+	setLump(bspLumpPakfile, pakData.Bytes())
+	return data
+}
@@ -0,0 +1,467 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"l4d2-manager-next/pkg/valve/vdf"
+	"l4d2-manager-next/pkg/valve/vpk"
+)
+
+// mp3SyncSearchLimit 跳过 ID3 标签后查找第一个 MPEG 帧头的最大字节数
+const mp3SyncSearchLimit = 64 * 1024
+
+// SoundFileInfo 单个声音文件的格式信息
+type SoundFileInfo struct {
+	Path          string   `json:"path"`
+	Format        string   `json:"format"`          // 实际格式: "wav", "mp3", "unknown"
+	Codec         string   `json:"codec,omitempty"` // 如 "PCM", "MS ADPCM", "MPEG-1 Layer III"
+	Duration      float64  `json:"duration"`        // 秒
+	SampleRate    int      `json:"sampleRate"`
+	Channels      int      `json:"channels"`
+	BitsPerSample int      `json:"bitsPerSample,omitempty"`
+	Bitrate       int      `json:"bitrate,omitempty"` // kbps，仅 MP3
+	Warnings      []string `json:"warnings,omitempty"`
+}
+
+// SoundDirectoryCount 每个声音目录替换的文件数
+type SoundDirectoryCount struct {
+	Dir   string `json:"dir"`
+	Count int    `json:"count"`
+}
+
+// SoundScriptOverride 覆盖的声音脚本及其中的条目
+type SoundScriptOverride struct {
+	File    string   `json:"file"`
+	Entries []string `json:"entries"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// SoundSummary VPK中声音文件和声音脚本的汇总，文件和脚本明细见 AnalyzeSoundDetails
+type SoundSummary struct {
+	FileCount     int                   `json:"fileCount"`
+	TotalDuration float64               `json:"totalDuration"`
+	WarningCount  int                   `json:"warningCount"` // 有格式问题的文件数
+	ScriptCount   int                   `json:"scriptCount"`
+	Directories   []SoundDirectoryCount `json:"directories"`
+}
+
+// SoundDetails VPK中每个声音文件的格式信息和覆盖的声音脚本
+type SoundDetails struct {
+	Files        []SoundFileInfo       `json:"files"` // 有警告的文件排在前面
+	SoundScripts []SoundScriptOverride `json:"soundScripts"`
+}
+
+func isSoundFilePath(name string) bool {
+	return strings.HasPrefix(name, "sound/") && (strings.HasSuffix(name, ".wav") || strings.HasSuffix(name, ".mp3"))
+}
+
+func isSoundScriptPath(name string) bool {
+	return strings.HasPrefix(name, "scripts/game_sounds") && strings.HasSuffix(name, ".txt")
+}
+
+// AnalyzeSounds 读取VPK中声音文件的头部，汇总数量、时长和问题文件数，VPK不含声音内容时返回 nil
+func AnalyzeSounds(opener *vpk.Opener, archive *vpk.Archive) *SoundSummary {
+	var summary *SoundSummary
+	dirs := make(map[string]int)
+
+	for i := range archive.Files {
+		file := &archive.Files[i]
+		name := normalizeModelStatPath(file.Name())
+		isSound, isScript := isSoundFilePath(name), isSoundScriptPath(name)
+		if !isSound && !isScript {
+			continue
+		}
+		if summary == nil {
+			summary = &SoundSummary{Directories: []SoundDirectoryCount{}}
+		}
+
+		if isScript {
+			summary.ScriptCount++
+			continue
+		}
+
+		info := readSoundFileInfo(opener, file, name)
+		summary.FileCount++
+		summary.TotalDuration += info.Duration
+		if len(info.Warnings) > 0 {
+			summary.WarningCount++
+		}
+		dirs[path.Dir(name)]++
+	}
+	if summary == nil {
+		return nil
+	}
+
+	for dir, count := range dirs {
+		summary.Directories = append(summary.Directories, SoundDirectoryCount{Dir: dir, Count: count})
+	}
+	sort.Slice(summary.Directories, func(i, j int) bool {
+		return summary.Directories[i].Dir < summary.Directories[j].Dir
+	})
+	return summary
+}
+
+// AnalyzeSoundDetails 读取VPK中每个声音文件的格式信息和声音脚本中的条目
+func AnalyzeSoundDetails(opener *vpk.Opener, archive *vpk.Archive) *SoundDetails {
+	details := &SoundDetails{Files: []SoundFileInfo{}, SoundScripts: []SoundScriptOverride{}}
+	for i := range archive.Files {
+		file := &archive.Files[i]
+		name := normalizeModelStatPath(file.Name())
+		switch {
+		case isSoundFilePath(name):
+			details.Files = append(details.Files, readSoundFileInfo(opener, file, name))
+		case isSoundScriptPath(name):
+			details.SoundScripts = append(details.SoundScripts, readSoundScript(opener, file, name))
+		}
+	}
+
+	files := details.Files
+	sort.SliceStable(files, func(i, j int) bool {
+		wi, wj := len(files[i].Warnings) > 0, len(files[j].Warnings) > 0
+		if wi != wj {
+			return wi
+		}
+		return files[i].Path < files[j].Path
+	})
+	sort.Slice(details.SoundScripts, func(i, j int) bool {
+		return details.SoundScripts[i].File < details.SoundScripts[j].File
+	})
+	return details
+}
+
+// ReadVPKSoundDetails 打开VPK并读取声音文件和声音脚本明细
+func ReadVPKSoundDetails(filePath string) (*SoundDetails, error) {
+	opener := vpk.Single(filePath)
+	defer opener.Close()
+	archive, err := opener.ReadArchive()
+	if err != nil {
+		return nil, err
+	}
+	return AnalyzeSoundDetails(opener, archive), nil
+}
+
+func readSoundFileInfo(opener *vpk.Opener, file *vpk.File, name string) SoundFileInfo {
+	info := SoundFileInfo{Path: name, Format: "unknown"}
+	reader, err := file.Open(opener)
+	if err != nil {
+		info.Warnings = append(info.Warnings, fmt.Sprintf("无法读取: %v", err))
+		return info
+	}
+	defer reader.Close()
+
+	parsed, err := ReadSoundHeader(bufio.NewReader(reader), int64(file.Size()))
+	if err != nil {
+		info.Warnings = append(info.Warnings, err.Error())
+		return info
+	}
+	parsed.Path = name
+	if ext := strings.TrimPrefix(path.Ext(name), "."); parsed.Format != ext {
+		parsed.Warnings = append(parsed.Warnings, fmt.Sprintf("扩展名为 .%s 但实际为 %s，引擎按扩展名解码", ext, strings.ToUpper(parsed.Format)))
+	}
+	return parsed
+}
+
+// ReadSoundHeader 根据文件头识别 WAV 或 MP3，返回时长、采样率、声道并检查起源引擎的兼容性
+func ReadSoundHeader(r *bufio.Reader, size int64) (SoundFileInfo, error) {
+	magic, err := r.Peek(4)
+	if err != nil {
+		return SoundFileInfo{}, fmt.Errorf("文件过短")
+	}
+	switch {
+	case string(magic) == "RIFF":
+		return readWAVHeader(r)
+	case string(magic[:3]) == "ID3" || (magic[0] == 0xFF && magic[1]&0xE0 == 0xE0):
+		return readMP3Header(r, size)
+	default:
+		return SoundFileInfo{}, fmt.Errorf("无法识别的文件头")
+	}
+}
+
+var wavFormatNames = map[uint16]string{
+	0x0001: "PCM",
+	0x0002: "MS ADPCM",
+	0x0003: "IEEE float",
+	0x0011: "IMA ADPCM",
+	0x0055: "MP3",
+	0xFFFE: "Extensible",
+}
+
+func readWAVHeader(r io.Reader) (SoundFileInfo, error) {
+	info := SoundFileInfo{Format: "wav"}
+	var riff [12]byte
+	if _, err := io.ReadFull(r, riff[:]); err != nil || string(riff[8:12]) != "WAVE" {
+		return info, fmt.Errorf("WAV 文件头无效")
+	}
+
+	var byteRate uint32
+	haveFmt := false
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			if !haveFmt {
+				return info, fmt.Errorf("WAV 缺少 fmt 块")
+			}
+			info.Warnings = append(info.Warnings, "WAV 缺少 data 块")
+			return info, nil
+		}
+		id := string(chunk[:4])
+		size := binary.LittleEndian.Uint32(chunk[4:])
+
+		switch id {
+		case "fmt ":
+			if size < 16 || size > 1024 {
+				return info, fmt.Errorf("WAV fmt 块大小无效")
+			}
+			fmtData := make([]byte, size+size%2)
+			if _, err := io.ReadFull(r, fmtData); err != nil {
+				return info, fmt.Errorf("WAV fmt 块不完整")
+			}
+			format := binary.LittleEndian.Uint16(fmtData[0:])
+			info.Channels = int(binary.LittleEndian.Uint16(fmtData[2:]))
+			info.SampleRate = int(binary.LittleEndian.Uint32(fmtData[4:]))
+			byteRate = binary.LittleEndian.Uint32(fmtData[8:])
+			info.BitsPerSample = int(binary.LittleEndian.Uint16(fmtData[14:]))
+			info.Codec = wavFormatNames[format]
+			if info.Codec == "" {
+				info.Codec = fmt.Sprintf("0x%04X", format)
+			}
+			info.Warnings = append(info.Warnings, wavCompatibilityWarnings(format, info)...)
+			haveFmt = true
+		case "data":
+			if !haveFmt {
+				return info, fmt.Errorf("WAV data 块位于 fmt 块之前")
+			}
+			if byteRate > 0 {
+				info.Duration = float64(size) / float64(byteRate)
+			}
+			return info, nil
+		default:
+			// 跳过 LIST、cue 等其他块
+			if _, err := io.CopyN(io.Discard, r, int64(size)+int64(size%2)); err != nil {
+				return info, fmt.Errorf("WAV %q 块不完整", strings.TrimSpace(id))
+			}
+		}
+	}
+}
+
+// wavCompatibilityWarnings 起源引擎只支持 8/16 位 PCM 和 MS ADPCM，采样率需为 44100 的约数
+func wavCompatibilityWarnings(format uint16, info SoundFileInfo) []string {
+	var warnings []string
+	switch format {
+	case 0x0001:
+		if info.BitsPerSample != 8 && info.BitsPerSample != 16 {
+			warnings = append(warnings, fmt.Sprintf("%d 位 PCM 不受支持，请转换为 16 位", info.BitsPerSample))
+		}
+	case 0x0002:
+		// MS ADPCM 受支持
+	default:
+		warnings = append(warnings, fmt.Sprintf("%s 编码不受支持，请转换为 16 位 PCM", info.Codec))
+	}
+	if warning := sampleRateWarning(info.SampleRate); warning != "" {
+		warnings = append(warnings, warning)
+	}
+	if info.Channels > 2 {
+		warnings = append(warnings, fmt.Sprintf("%d 声道不受支持，请转换为单声道或立体声", info.Channels))
+	}
+	return warnings
+}
+
+func sampleRateWarning(sampleRate int) string {
+	switch sampleRate {
+	case 11025, 22050, 44100:
+		return ""
+	}
+	return fmt.Sprintf("采样率 %d Hz，引擎按 44100 Hz 系列播放，可能变调或无声", sampleRate)
+}
+
+var (
+	mp3BitratesV1 = [3][16]int{
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448}, // Layer I
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},    // Layer II
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},     // Layer III
+	}
+	mp3BitratesV2 = [3][16]int{
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256}, // Layer I
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},      // Layer II
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},      // Layer III
+	}
+	mp3SampleRates = map[int][3]int{
+		1: {44100, 48000, 32000},
+		2: {22050, 24000, 16000},
+		3: {11025, 12000, 8000}, // MPEG-2.5
+	}
+	mp3VersionNames = map[int]string{1: "MPEG-1", 2: "MPEG-2", 3: "MPEG-2.5"}
+	mp3LayerNames   = [3]string{"Layer I", "Layer II", "Layer III"}
+)
+
+// mp3Frame MPEG 音频帧头
+type mp3Frame struct {
+	version    int // 1, 2, 3 (2.5)
+	layer      int // 1, 2, 3
+	bitrate    int // kbps
+	sampleRate int
+	channels   int
+}
+
+func parseMP3FrameHeader(h []byte) (mp3Frame, bool) {
+	if len(h) < 4 || h[0] != 0xFF || h[1]&0xE0 != 0xE0 {
+		return mp3Frame{}, false
+	}
+	var frame mp3Frame
+	switch (h[1] >> 3) & 0x03 {
+	case 0:
+		frame.version = 3
+	case 2:
+		frame.version = 2
+	case 3:
+		frame.version = 1
+	default:
+		return mp3Frame{}, false
+	}
+	layerBits := (h[1] >> 1) & 0x03
+	if layerBits == 0 {
+		return mp3Frame{}, false
+	}
+	frame.layer = 4 - int(layerBits)
+	bitrateIndex, rateIndex := int(h[2]>>4), int((h[2]>>2)&0x03)
+	if bitrateIndex == 0 || bitrateIndex == 15 || rateIndex == 3 {
+		return mp3Frame{}, false
+	}
+	if frame.version == 1 {
+		frame.bitrate = mp3BitratesV1[frame.layer-1][bitrateIndex]
+	} else {
+		frame.bitrate = mp3BitratesV2[frame.layer-1][bitrateIndex]
+	}
+	frame.sampleRate = mp3SampleRates[frame.version][rateIndex]
+	frame.channels = 2
+	if h[3]>>6 == 3 {
+		frame.channels = 1
+	}
+	return frame, true
+}
+
+func (f mp3Frame) samplesPerFrame() int {
+	switch {
+	case f.layer == 1:
+		return 384
+	case f.layer == 3 && f.version != 1:
+		return 576
+	default:
+		return 1152
+	}
+}
+
+// xingOffset Layer III 帧中 Xing/Info 头相对帧头的偏移（跳过边信息）
+func (f mp3Frame) xingOffset() int {
+	switch {
+	case f.version == 1 && f.channels == 1:
+		return 4 + 17
+	case f.version == 1:
+		return 4 + 32
+	case f.channels == 1:
+		return 4 + 9
+	default:
+		return 4 + 17
+	}
+}
+
+func readMP3Header(r *bufio.Reader, size int64) (SoundFileInfo, error) {
+	info := SoundFileInfo{Format: "mp3"}
+	audioBytes := size
+
+	// 跳过 ID3v2 标签，标签大小为 synchsafe 整数
+	if head, err := r.Peek(10); err == nil && string(head[:3]) == "ID3" {
+		tagSize := int64(head[6]&0x7F)<<21 | int64(head[7]&0x7F)<<14 | int64(head[8]&0x7F)<<7 | int64(head[9]&0x7F)
+		tagSize += 10
+		if head[5]&0x10 != 0 {
+			tagSize += 10
+		}
+		if _, err := io.CopyN(io.Discard, r, tagSize); err != nil {
+			return info, fmt.Errorf("ID3 标签不完整")
+		}
+		audioBytes -= tagSize
+	}
+
+	var skipped int64
+	for {
+		head, err := r.Peek(4)
+		if err != nil || skipped > mp3SyncSearchLimit {
+			return info, fmt.Errorf("未找到 MPEG 音频帧")
+		}
+		if frame, ok := parseMP3FrameHeader(head); ok {
+			info.Codec = mp3VersionNames[frame.version] + " " + mp3LayerNames[frame.layer-1]
+			info.SampleRate = frame.sampleRate
+			info.Channels = frame.channels
+			info.Bitrate = frame.bitrate
+			audioBytes -= skipped
+			info.Duration = mp3Duration(r, frame, audioBytes)
+			if frame.version != 1 || frame.layer != 3 {
+				info.Warnings = append(info.Warnings, fmt.Sprintf("%s 不受支持，请使用 MPEG-1 Layer III", info.Codec))
+			}
+			if warning := sampleRateWarning(frame.sampleRate); warning != "" {
+				info.Warnings = append(info.Warnings, warning)
+			}
+			return info, nil
+		}
+		r.Discard(1)
+		skipped++
+	}
+}
+
+// mp3Duration 优先使用 Xing/Info 头中的总帧数（VBR），否则按固定码率估算
+func mp3Duration(r *bufio.Reader, frame mp3Frame, audioBytes int64) float64 {
+	if frame.layer == 3 {
+		offset := frame.xingOffset()
+		if data, err := r.Peek(offset + 12); err == nil {
+			tag := data[offset : offset+4]
+			flags := binary.BigEndian.Uint32(data[offset+4:])
+			if (bytes.Equal(tag, []byte("Xing")) || bytes.Equal(tag, []byte("Info"))) && flags&0x01 != 0 {
+				frames := binary.BigEndian.Uint32(data[offset+8:])
+				return float64(frames) * float64(frame.samplesPerFrame()) / float64(frame.sampleRate)
+			}
+		}
+	}
+	if frame.bitrate == 0 || audioBytes <= 0 {
+		return 0
+	}
+	return float64(audioBytes) * 8 / float64(frame.bitrate*1000)
+}
+
+// readSoundScript 列出声音脚本中定义的条目
+func readSoundScript(opener *vpk.Opener, file *vpk.File, name string) SoundScriptOverride {
+	override := SoundScriptOverride{File: name, Entries: []string{}}
+	reader, err := file.Open(opener)
+	if err != nil {
+		override.Error = err.Error()
+		return override
+	}
+	defer reader.Close()
+
+	kv, err := vdf.ReadTolerant(reader)
+	if err != nil {
+		override.Error = fmt.Sprintf("解析声音脚本失败: %v", err)
+		return override
+	}
+	for entry := kv; entry != nil; entry = entry.NextSubKey() {
+		// 清单文件列出引擎预加载的脚本
+		if strings.EqualFold(entry.Key, "game_sounds_manifest") {
+			for item := entry.FirstSubKey(); item != nil; item = item.NextSubKey() {
+				if item.HasValue {
+					override.Entries = append(override.Entries, item.Value)
+				}
+			}
+			continue
+		}
+		if entry.Key != "" && !entry.HasValue {
+			override.Entries = append(override.Entries, entry.Key)
+		}
+	}
+	return override
+}
@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"l4d2-manager-next/pkg/valve/vpk"
+)
+
+func testWAV(format uint16, channels uint16, sampleRate uint32, bits uint16, dataSize uint32) []byte {
+	var buf bytes.Buffer
+	blockAlign := channels * bits / 8
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(4+8+16+8+12+8+dataSize))
+	buf.WriteString("WAVE")
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, format)
+	binary.Write(&buf, binary.LittleEndian, channels)
+	binary.Write(&buf, binary.LittleEndian, sampleRate)
+	binary.Write(&buf, binary.LittleEndian, sampleRate*uint32(blockAlign))
+	binary.Write(&buf, binary.LittleEndian, blockAlign)
+	binary.Write(&buf, binary.LittleEndian, bits)
+	buf.WriteString("LIST")
+	binary.Write(&buf, binary.LittleEndian, uint32(11)) // 奇数长度的块需要补齐
+	buf.Write(make([]byte, 12))
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, dataSize)
+	buf.Write(make([]byte, dataSize))
+	return buf.Bytes()
+}
+
+// testMP3 生成 MPEG-1 Layer III 128kbps 44.1kHz 立体声的固定码率帧
+func testMP3(frames int, withID3 bool) []byte {
+	var buf bytes.Buffer
+	if withID3 {
+		buf.Write([]byte{'I', 'D', '3', 3, 0, 0, 0, 0, 1, 0}) // 128 字节标签
+		buf.Write(make([]byte, 128))
+	}
+	frame := make([]byte, 417)
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+	for i := 0; i < frames; i++ {
+		buf.Write(frame)
+	}
+	return buf.Bytes()
+}
+
+func readTestSoundHeader(t *testing.T, data []byte) SoundFileInfo {
+	t.Helper()
+	info, err := ReadSoundHeader(bufio.NewReader(bytes.NewReader(data)), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
+
+func TestReadSoundHeaderWAV(t *testing.T) {
+	info := readTestSoundHeader(t, testWAV(1, 2, 44100, 16, 44100*4))
+	if info.Format != "wav" || info.Codec != "PCM" || info.SampleRate != 44100 || info.Channels != 2 || info.Duration != 1 || len(info.Warnings) != 0 {
+		t.Fatalf("unexpected wav info: %+v", info)
+	}
+
+	info = readTestSoundHeader(t, testWAV(1, 1, 48000, 24, 48000*3))
+	if len(info.Warnings) != 2 || !strings.Contains(info.Warnings[0], "24 位") || !strings.Contains(info.Warnings[1], "48000 Hz") {
+		t.Fatalf("expected bit depth and sample rate warnings, got %+v", info.Warnings)
+	}
+
+	info = readTestSoundHeader(t, testWAV(3, 2, 22050, 32, 100))
+	if len(info.Warnings) != 1 || !strings.Contains(info.Warnings[0], "IEEE float") {
+		t.Fatalf("expected codec warning, got %+v", info.Warnings)
+	}
+}
+
+func TestReadSoundHeaderMP3(t *testing.T) {
+	data := testMP3(100, true)
+	info := readTestSoundHeader(t, data)
+	want := float64(417*100) * 8 / 128000
+	if info.Format != "mp3" || info.Codec != "MPEG-1 Layer III" || info.SampleRate != 44100 || info.Channels != 2 || info.Bitrate != 128 ||
+		math.Abs(info.Duration-want) > 1e-9 || len(info.Warnings) != 0 {
+		t.Fatalf("unexpected mp3 info: %+v (want duration %f)", info, want)
+	}
+
+	// Xing 头中的帧数优先于码率估算
+	data = testMP3(3, false)
+	copy(data[36:], []byte{'X', 'i', 'n', 'g', 0, 0, 0, 1, 0, 0, 0x03, 0xE8})
+	if info := readTestSoundHeader(t, data); math.Abs(info.Duration-1000*1152/44100.0) > 1e-9 {
+		t.Fatalf("expected xing duration, got %f", info.Duration)
+	}
+
+	// MPEG-2 Layer II, 64kbps, 22050 Hz
+	layer2 := []byte{0xFF, 0xF5, 0x80, 0xC0, 0, 0, 0, 0}
+	info = readTestSoundHeader(t, layer2)
+	if info.Codec != "MPEG-2 Layer II" || info.Channels != 1 || len(info.Warnings) != 1 || !strings.Contains(info.Warnings[0], "MPEG-1 Layer III") {
+		t.Fatalf("expected layer warning, got %+v", info)
+	}
+
+	if _, err := ReadSoundHeader(bufio.NewReader(strings.NewReader("OggS....")), 8); err == nil {
+		t.Fatal("expected unknown header error")
+	}
+}
+
+func TestAnalyzeSoundsSummarizesFilesAndSoundScripts(t *testing.T) {
+	dir := t.TempDir()
+	vpkPath := filepath.Join(dir, "sounds.vpk")
+	writeParserTestVPK(t, vpkPath, map[string][]byte{
+		"sound/weapons/ak47/gunfire/rifle_fire_1.wav": testWAV(1, 1, 48000, 16, 96000),
+		"sound/weapons/ak47/gunfire/rifle_fire_2.wav": testMP3(10, false),
+		"sound/music/tank/taank.mp3":                  testMP3(10, false),
+		"scripts/game_sounds_weapons.txt": []byte(`"AK47.Fire" { "channel" "CHAN_WEAPON" "wave" ")weapons/ak47/gunfire/rifle_fire_1.wav" }
+"AK47.Deploy" { "wave" "weapons/ak47/deploy.wav" }`),
+		"scripts/game_sounds_manifest.txt": []byte(`"game_sounds_manifest" { "precache_file" "scripts/game_sounds_weapons.txt" }`),
+	})
+
+	opener := vpk.Single(vpkPath)
+	defer opener.Close()
+	archive, err := opener.ReadArchive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary := AnalyzeSounds(opener, archive)
+	if summary == nil || summary.FileCount != 3 || summary.WarningCount != 2 || summary.ScriptCount != 2 || len(summary.Directories) != 2 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+
+	details, err := ReadVPKSoundDetails(vpkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(details.Files) != 3 || details.Files[0].Path != "sound/weapons/ak47/gunfire/rifle_fire_1.wav" || details.Files[1].Format != "mp3" ||
+		!strings.Contains(details.Files[1].Warnings[0], "扩展名为 .wav") {
+		t.Fatalf("expected warned files first: %+v", details.Files)
+	}
+	if len(details.SoundScripts) != 2 || strings.Join(details.SoundScripts[0].Entries, ",") != "scripts/game_sounds_weapons.txt" ||
+		strings.Join(details.SoundScripts[1].Entries, ",") != "AK47.Fire,AK47.Deploy" {
+		t.Fatalf("unexpected sound scripts: %+v", details.SoundScripts)
+	}
+}
@@ -11,14 +11,15 @@ type VPKFile struct {
 	Name          string                 `json:"name"`
 	Path          string                 `json:"path"`
 	Size          int64                  `json:"size"`
-	PrimaryTag    string                 `json:"primaryTag"`    // 一级标签: "地图", "人物", "武器", "其他"
+	PrimaryTag    string                 `json:"primaryTag"`    // 一级标签: "地图", "人物", "武器", "其他" 等，见分类规则
 	SecondaryTags []string               `json:"secondaryTags"` // 二级标签: ["ellis", "ak47", "versus"] 等
 	Location      string                 `json:"location"`      // "root", "workshop", "disabled"
 	Enabled       bool                   `json:"enabled"`
 	Campaign      string                 `json:"campaign"`
 	Chapters      map[string]ChapterInfo `json:"chapters"` // key: 章节代码, value: 章节信息
 	Mode          string                 `json:"mode"`
-	PreviewImage  string                 `json:"previewImage"` // Base64编码的预览图
+	PreviewImage  string                 `json:"previewImage"` // 预览图地址（本地预览图服务），没有预览图时为空
+	PreviewSource string                 `json:"-"`            // 预览图来源：外部同名图片的扩展名（sidecar: 前缀）或 VPK 内部文件名
 	LastModified  string                 `json:"lastModified"`
 	// addoninfo.txt 相关信息
 	Title      string `json:"title"`      // addontitle (必有)
@@ -28,6 +29,12 @@ type VPKFile struct {
 	AddonURL0  string `json:"addonURL0"`  // addonURL0 (若有)
 	WorkshopID string `json:"workshopId"` // 工坊ID (从meta文件读取)
 	HasUpdate  bool   `json:"hasUpdate"`   // 远端更新时间 > 下载时间且开启了更新检测
+	// 声音文件和声音脚本汇总，不含声音内容时为 nil
+	Sound *SoundSummary `json:"sound,omitempty"`
+	// 地图 BSP 摘要（不含数据块、模型和 pakfile 明细），非地图为 nil
+	Maps []BSPMapInfo `json:"maps,omitempty"`
+	// 战役完整性和 mission 文件格式问题，见 ValidateMissionContent
+	MissionWarnings []MissionWarning `json:"missionWarnings,omitempty"`
 }
 
 // Campaign 战役信息
@@ -0,0 +1,87 @@
+package parser
+
+import (
+	"bytes"
+	"hash/crc32"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+
+	"l4d2-manager-next/pkg/valve/vpk"
+)
+
+// writeParserTestVPK 写出单文件VPK（数据紧跟目录树），供解析测试使用
+func writeParserTestVPK(t *testing.T, filePath string, entries map[string][]byte) {
+	t.Helper()
+	writeParserTestVPKWithPreload(t, filePath, entries, 0)
+}
+
+// writeParserTestVPKWithPreload 每个文件的前 preload 字节作为预载数据写入目录树，其余写入数据区
+func writeParserTestVPKWithPreload(t *testing.T, filePath string, entries map[string][]byte, preload int) {
+	t.Helper()
+
+	type parts struct{ dir, base, ext string }
+	split := func(name string) parts {
+		name = strings.ReplaceAll(name, "\\", "/")
+		p := parts{
+			dir:  path.Dir(name),
+			base: strings.TrimSuffix(path.Base(name), path.Ext(name)),
+			ext:  strings.TrimPrefix(path.Ext(name), "."),
+		}
+		if p.dir == "." {
+			p.dir = " "
+		}
+		if p.ext == "" {
+			p.ext = " "
+		}
+		return p
+	}
+
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		left, right := split(names[i]), split(names[j])
+		if left.ext != right.ext {
+			return left.ext < right.ext
+		}
+		if left.dir != right.dir {
+			return left.dir < right.dir
+		}
+		return left.base < right.base
+	})
+
+	archive := &vpk.Archive{Header: vpk.Header{Magic: vpk.Magic, Version: 1}}
+	var offset uint32
+	for _, name := range names {
+		p := split(name)
+		data := entries[name]
+		metadata := data[:min(preload, len(data))]
+		archive.Files = append(archive.Files, vpk.File{
+			Dir:  p.dir,
+			Base: p.base,
+			Ext:  p.ext,
+			DirEntry: vpk.DirEntry{
+				CRC:           crc32.ChecksumIEEE(data),
+				MetadataBytes: uint16(len(metadata)),
+				DataLocation:  []vpk.DataChunk{{ArchiveIndex: 0x7fff, EntryOffset: offset, EntryLength: uint32(len(data) - len(metadata))}},
+			},
+			Metadata: metadata,
+		})
+		offset += uint32(len(data) - len(metadata))
+	}
+
+	var buffer bytes.Buffer
+	if err := vpk.WriteDirectory(&buffer, archive); err != nil {
+		t.Fatalf("write vpk directory: %v", err)
+	}
+	for _, name := range names {
+		buffer.Write(entries[name][min(preload, len(entries[name])):])
+	}
+	if err := os.WriteFile(filePath, buffer.Bytes(), 0644); err != nil {
+		t.Fatalf("write test vpk: %v", err)
+	}
+}
@@ -0,0 +1,654 @@
+package parser
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"l4d2-manager-next/pkg/valve/vpk"
+)
+
+const (
+	// vtfMaxHeaderSize 头部（含资源表）允许的最大字节数，防止异常文件导致大量读取
+	vtfMaxHeaderSize = 64 * 1024
+	// vtfMaxDimension 允许的最大宽高，引擎本身不支持更大的贴图
+	vtfMaxDimension = 16384
+	// vtfPreviewMaxSize 贴图作为预览图时解码的 mip 级别最长边
+	vtfPreviewMaxSize = 1024
+)
+
+// VTF 贴图标志位
+const (
+	VTFFlagPointSample   uint32 = 0x00000001
+	VTFFlagTrilinear     uint32 = 0x00000002
+	VTFFlagClampS        uint32 = 0x00000004
+	VTFFlagClampT        uint32 = 0x00000008
+	VTFFlagNormal        uint32 = 0x00000080
+	VTFFlagNoMip         uint32 = 0x00000100
+	VTFFlagNoLOD         uint32 = 0x00000200
+	VTFFlagOneBitAlpha   uint32 = 0x00001000
+	VTFFlagEightBitAlpha uint32 = 0x00002000
+	VTFFlagEnvMap        uint32 = 0x00004000
+)
+
+// VTFFormat VTF 图像数据格式，数值与引擎的 ImageFormat 枚举一致
+type VTFFormat int32
+
+const (
+	VTFFormatRGBA8888         VTFFormat = 0
+	VTFFormatABGR8888         VTFFormat = 1
+	VTFFormatRGB888           VTFFormat = 2
+	VTFFormatBGR888           VTFFormat = 3
+	VTFFormatRGB565           VTFFormat = 4
+	VTFFormatI8               VTFFormat = 5
+	VTFFormatIA88             VTFFormat = 6
+	VTFFormatP8               VTFFormat = 7
+	VTFFormatA8               VTFFormat = 8
+	VTFFormatRGB888BlueScreen VTFFormat = 9
+	VTFFormatBGR888BlueScreen VTFFormat = 10
+	VTFFormatARGB8888         VTFFormat = 11
+	VTFFormatBGRA8888         VTFFormat = 12
+	VTFFormatDXT1             VTFFormat = 13
+	VTFFormatDXT3             VTFFormat = 14
+	VTFFormatDXT5             VTFFormat = 15
+	VTFFormatBGRX8888         VTFFormat = 16
+	VTFFormatBGR565           VTFFormat = 17
+	VTFFormatBGRX5551         VTFFormat = 18
+	VTFFormatBGRA4444         VTFFormat = 19
+	VTFFormatDXT1OneBitAlpha  VTFFormat = 20
+	VTFFormatBGRA5551         VTFFormat = 21
+	VTFFormatUV88             VTFFormat = 22
+	VTFFormatUVWQ8888         VTFFormat = 23
+	VTFFormatRGBA16161616F    VTFFormat = 24
+	VTFFormatRGBA16161616     VTFFormat = 25
+	VTFFormatUVLX8888         VTFFormat = 26
+	VTFFormatR32F             VTFFormat = 27
+	VTFFormatRGB323232F       VTFFormat = 28
+	VTFFormatRGBA32323232F    VTFFormat = 29
+	VTFFormatRGBX8888         VTFFormat = 32
+	VTFFormatNone             VTFFormat = -1
+)
+
+var vtfFormatNames = map[VTFFormat]string{
+	VTFFormatRGBA8888:         "RGBA8888",
+	VTFFormatABGR8888:         "ABGR8888",
+	VTFFormatRGB888:           "RGB888",
+	VTFFormatBGR888:           "BGR888",
+	VTFFormatRGB565:           "RGB565",
+	VTFFormatI8:               "I8",
+	VTFFormatIA88:             "IA88",
+	VTFFormatP8:               "P8",
+	VTFFormatA8:               "A8",
+	VTFFormatRGB888BlueScreen: "RGB888_BLUESCREEN",
+	VTFFormatBGR888BlueScreen: "BGR888_BLUESCREEN",
+	VTFFormatARGB8888:         "ARGB8888",
+	VTFFormatBGRA8888:         "BGRA8888",
+	VTFFormatDXT1:             "DXT1",
+	VTFFormatDXT3:             "DXT3",
+	VTFFormatDXT5:             "DXT5",
+	VTFFormatBGRX8888:         "BGRX8888",
+	VTFFormatBGR565:           "BGR565",
+	VTFFormatBGRX5551:         "BGRX5551",
+	VTFFormatBGRA4444:         "BGRA4444",
+	VTFFormatDXT1OneBitAlpha:  "DXT1_ONEBITALPHA",
+	VTFFormatBGRA5551:         "BGRA5551",
+	VTFFormatUV88:             "UV88",
+	VTFFormatUVWQ8888:         "UVWQ8888",
+	VTFFormatRGBA16161616F:    "RGBA16161616F",
+	VTFFormatRGBA16161616:     "RGBA16161616",
+	VTFFormatUVLX8888:         "UVLX8888",
+	VTFFormatR32F:             "R32F",
+	VTFFormatRGB323232F:       "RGB323232F",
+	VTFFormatRGBA32323232F:    "RGBA32323232F",
+	VTFFormatRGBX8888:         "RGBX8888",
+	VTFFormatNone:             "NONE",
+}
+
+func (f VTFFormat) String() string {
+	if name, ok := vtfFormatNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("FORMAT_%d", int32(f))
+}
+
+// vtfFormatInfo 格式的存储大小和像素解码方式
+type vtfFormatInfo struct {
+	bytes int                      // 每像素字节数，块压缩格式为每个 4x4 块的字节数
+	block bool                     // 是否为 DXT 块压缩格式
+	pixel func([]byte) color.NRGBA // 非压缩格式的单像素解码
+}
+
+// vtfFormats 支持解码的格式，浮点 HDR 格式和调色板格式只统计大小不解码
+var vtfFormats = map[VTFFormat]vtfFormatInfo{
+	VTFFormatRGBA8888: {4, false, func(p []byte) color.NRGBA { return color.NRGBA{p[0], p[1], p[2], p[3]} }},
+	VTFFormatABGR8888: {4, false, func(p []byte) color.NRGBA { return color.NRGBA{p[3], p[2], p[1], p[0]} }},
+	VTFFormatRGB888:   {3, false, func(p []byte) color.NRGBA { return color.NRGBA{p[0], p[1], p[2], 0xff} }},
+	VTFFormatBGR888:   {3, false, func(p []byte) color.NRGBA { return color.NRGBA{p[2], p[1], p[0], 0xff} }},
+	VTFFormatRGB565: {2, false, func(p []byte) color.NRGBA {
+		v := binary.LittleEndian.Uint16(p)
+		return color.NRGBA{expand5(v), expand6(v >> 5), expand5(v >> 11), 0xff}
+	}},
+	VTFFormatI8:   {1, false, func(p []byte) color.NRGBA { return color.NRGBA{p[0], p[0], p[0], 0xff} }},
+	VTFFormatIA88: {2, false, func(p []byte) color.NRGBA { return color.NRGBA{p[0], p[0], p[0], p[1]} }},
+	VTFFormatA8:   {1, false, func(p []byte) color.NRGBA { return color.NRGBA{0, 0, 0, p[0]} }},
+	VTFFormatRGB888BlueScreen: {3, false, func(p []byte) color.NRGBA {
+		if p[0] == 0 && p[1] == 0 && p[2] == 0xff {
+			return color.NRGBA{}
+		}
+		return color.NRGBA{p[0], p[1], p[2], 0xff}
+	}},
+	VTFFormatBGR888BlueScreen: {3, false, func(p []byte) color.NRGBA {
+		if p[0] == 0xff && p[1] == 0 && p[2] == 0 {
+			return color.NRGBA{}
+		}
+		return color.NRGBA{p[2], p[1], p[0], 0xff}
+	}},
+	VTFFormatARGB8888:        {4, false, func(p []byte) color.NRGBA { return color.NRGBA{p[1], p[2], p[3], p[0]} }},
+	VTFFormatBGRA8888:        {4, false, func(p []byte) color.NRGBA { return color.NRGBA{p[2], p[1], p[0], p[3]} }},
+	VTFFormatDXT1:            {8, true, nil},
+	VTFFormatDXT3:            {16, true, nil},
+	VTFFormatDXT5:            {16, true, nil},
+	VTFFormatBGRX8888:        {4, false, func(p []byte) color.NRGBA { return color.NRGBA{p[2], p[1], p[0], 0xff} }},
+	VTFFormatBGR565:          {2, false, func(p []byte) color.NRGBA { return rgb565(binary.LittleEndian.Uint16(p)) }},
+	VTFFormatDXT1OneBitAlpha: {8, true, nil},
+	VTFFormatBGRX5551: {2, false, func(p []byte) color.NRGBA {
+		v := binary.LittleEndian.Uint16(p)
+		return color.NRGBA{expand5(v >> 10), expand5(v >> 5), expand5(v), 0xff}
+	}},
+	VTFFormatBGRA5551: {2, false, func(p []byte) color.NRGBA {
+		v := binary.LittleEndian.Uint16(p)
+		return color.NRGBA{expand5(v >> 10), expand5(v >> 5), expand5(v), uint8(v>>15) * 0xff}
+	}},
+	VTFFormatBGRA4444: {2, false, func(p []byte) color.NRGBA {
+		v := binary.LittleEndian.Uint16(p)
+		return color.NRGBA{expand4(v >> 8), expand4(v >> 4), expand4(v), expand4(v >> 12)}
+	}},
+	VTFFormatUV88:         {2, false, func(p []byte) color.NRGBA { return color.NRGBA{p[0], p[1], 0, 0xff} }},
+	VTFFormatUVWQ8888:     {4, false, func(p []byte) color.NRGBA { return color.NRGBA{p[0], p[1], p[2], p[3]} }},
+	VTFFormatRGBA16161616: {8, false, func(p []byte) color.NRGBA { return color.NRGBA{p[1], p[3], p[5], p[7]} }},
+	VTFFormatUVLX8888:     {4, false, func(p []byte) color.NRGBA { return color.NRGBA{p[0], p[1], p[2], 0xff} }},
+	VTFFormatRGBX8888:     {4, false, func(p []byte) color.NRGBA { return color.NRGBA{p[0], p[1], p[2], 0xff} }},
+}
+
+// vtfFormatSizes 不能解码但可以计算大小的格式的每像素字节数
+var vtfFormatSizes = map[VTFFormat]int{
+	VTFFormatP8:            1,
+	VTFFormatRGBA16161616F: 8,
+	VTFFormatR32F:          4,
+	VTFFormatRGB323232F:    12,
+	VTFFormatRGBA32323232F: 16,
+}
+
+// VTFHeader VTF 文件头，支持 7.0 到 7.5 版本
+type VTFHeader struct {
+	MajorVersion int
+	MinorVersion int
+	Width        int
+	Height       int
+	Depth        int
+	Frames       int
+	StartFrame   int
+	Faces        int // 普通贴图为 1，立方体贴图为 6 或 7（旧版本带球面贴图）
+	MipCount     int
+	Flags        uint32
+	Format       VTFFormat
+	LowResFormat VTFFormat
+	LowResWidth  int
+	LowResHeight int
+
+	imageOffset int64 // 高清图像数据的偏移
+}
+
+// Version 返回 "7.x" 形式的版本号
+func (h *VTFHeader) Version() string {
+	return fmt.Sprintf("%d.%d", h.MajorVersion, h.MinorVersion)
+}
+
+// Decodable 当前格式能否解码为图片
+func (h *VTFHeader) Decodable() bool {
+	_, ok := vtfFormats[h.Format]
+	return ok
+}
+
+// IsNormalMap 是否为法线贴图，不适合作为预览图
+func (h *VTFHeader) IsNormalMap() bool {
+	return h.Flags&VTFFlagNormal != 0
+}
+
+// IsEnvMap 是否为立方体环境贴图
+func (h *VTFHeader) IsEnvMap() bool {
+	return h.Flags&VTFFlagEnvMap != 0
+}
+
+// MipSize 返回指定 mip 级别的宽高，0 为最大的级别
+func (h *VTFHeader) MipSize(level int) (int, int) {
+	return max(h.Width>>level, 1), max(h.Height>>level, 1)
+}
+
+// MipForSize 返回最长边不小于 maxSize 的最小 mip 级别，用于生成缩略图时避免解码完整贴图
+func (h *VTFHeader) MipForSize(maxSize int) int {
+	level := 0
+	for level+1 < h.MipCount {
+		w, hh := h.MipSize(level + 1)
+		if max(w, hh) < maxSize {
+			break
+		}
+		level++
+	}
+	return level
+}
+
+// mipBytes 返回一个 mip 级别中单个帧、单个面的全部深度切片的字节数
+func (h *VTFHeader) mipBytes(level int) int64 {
+	w, hh := h.MipSize(level)
+	return vtfImageSize(h.Format, w, hh) * int64(max(h.Depth>>level, 1))
+}
+
+// ImageDataSize 高清图像数据的字节数，包含所有 mip、帧和面，即加载到显存中的大小
+func (h *VTFHeader) ImageDataSize() int64 {
+	var total int64
+	for level := 0; level < h.MipCount; level++ {
+		total += h.mipBytes(level)
+	}
+	return total * int64(h.Frames) * int64(h.Faces)
+}
+
+// vtfImageSize 返回单张二维图像的字节数，未知格式返回 0
+func vtfImageSize(format VTFFormat, width, height int) int64 {
+	if info, ok := vtfFormats[format]; ok {
+		if info.block {
+			return int64((width+3)/4) * int64((height+3)/4) * int64(info.bytes)
+		}
+		return int64(width) * int64(height) * int64(info.bytes)
+	}
+	return int64(width) * int64(height) * int64(vtfFormatSizes[format])
+}
+
+// ReadVTFHeader 从流中只读取 VTF 头部和资源表
+func ReadVTFHeader(r io.Reader) (*VTFHeader, error) {
+	prefix := make([]byte, 16)
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		return nil, fmt.Errorf("VTF 头部不完整: %w", err)
+	}
+	if string(prefix[:4]) != "VTF\x00" {
+		return nil, fmt.Errorf("不是 VTF 文件")
+	}
+	headerSize := int(binary.LittleEndian.Uint32(prefix[12:]))
+	if headerSize < 16 || headerSize > vtfMaxHeaderSize {
+		return nil, fmt.Errorf("VTF 头部大小异常: %d", headerSize)
+	}
+
+	data := make([]byte, headerSize)
+	copy(data, prefix)
+	if _, err := io.ReadFull(r, data[16:]); err != nil {
+		return nil, fmt.Errorf("VTF 头部不完整: %w", err)
+	}
+	return ParseVTFHeader(data)
+}
+
+// ParseVTFHeader 解析 VTF 头部，data 至少包含完整的头部和资源表
+func ParseVTFHeader(data []byte) (*VTFHeader, error) {
+	if len(data) < 16 || string(data[:4]) != "VTF\x00" {
+		return nil, fmt.Errorf("不是 VTF 文件")
+	}
+	h := &VTFHeader{
+		MajorVersion: int(binary.LittleEndian.Uint32(data[4:])),
+		MinorVersion: int(binary.LittleEndian.Uint32(data[8:])),
+	}
+	if h.MajorVersion != 7 || h.MinorVersion > 5 {
+		return nil, fmt.Errorf("不支持的 VTF 版本: %s", h.Version())
+	}
+	headerSize := int(binary.LittleEndian.Uint32(data[12:]))
+	// 7.0/7.1 头部 64 字节，7.2 在偏移 63 处增加 2 字节深度，7.3 起增加资源表
+	minSize := 64
+	switch {
+	case h.MinorVersion >= 3:
+		minSize = 80
+	case h.MinorVersion == 2:
+		minSize = 65
+	}
+	if headerSize < minSize || len(data) < minSize {
+		return nil, fmt.Errorf("VTF 头部不完整")
+	}
+
+	h.Width = int(binary.LittleEndian.Uint16(data[16:]))
+	h.Height = int(binary.LittleEndian.Uint16(data[18:]))
+	h.Flags = binary.LittleEndian.Uint32(data[20:])
+	h.Frames = max(int(binary.LittleEndian.Uint16(data[24:])), 1)
+	h.StartFrame = int(binary.LittleEndian.Uint16(data[26:]))
+	h.Format = VTFFormat(binary.LittleEndian.Uint32(data[52:]))
+	h.MipCount = max(int(data[56]), 1)
+	h.LowResFormat = VTFFormat(binary.LittleEndian.Uint32(data[57:]))
+	h.LowResWidth = int(data[61])
+	h.LowResHeight = int(data[62])
+	h.Depth = 1
+	if h.MinorVersion >= 2 {
+		h.Depth = max(int(binary.LittleEndian.Uint16(data[63:])), 1)
+	}
+
+	if h.Width == 0 || h.Height == 0 || h.Width > vtfMaxDimension || h.Height > vtfMaxDimension || h.Depth > vtfMaxDimension {
+		return nil, fmt.Errorf("VTF 尺寸异常: %dx%dx%d", h.Width, h.Height, h.Depth)
+	}
+	if h.MipCount > vtfMipLevels(h.Width, h.Height, h.Depth) {
+		return nil, fmt.Errorf("VTF mip 级别数异常: %d", h.MipCount)
+	}
+	if _, ok := vtfFormats[h.Format]; !ok {
+		if _, ok := vtfFormatSizes[h.Format]; !ok {
+			return nil, fmt.Errorf("未知的 VTF 格式: %s", h.Format)
+		}
+	}
+
+	h.Faces = 1
+	if h.IsEnvMap() {
+		h.Faces = 6
+		if h.MinorVersion < 5 && h.StartFrame != 0xffff {
+			h.Faces = 7
+		}
+	}
+
+	if h.MinorVersion < 3 {
+		// 旧版本的低清缩略图紧跟头部，之后是高清图像
+		h.imageOffset = int64(headerSize)
+		if h.LowResFormat != VTFFormatNone {
+			h.imageOffset += vtfImageSize(h.LowResFormat, h.LowResWidth, h.LowResHeight)
+		}
+		return h, nil
+	}
+
+	count := int(binary.LittleEndian.Uint32(data[68:]))
+	if count > (vtfMaxHeaderSize-80)/8 || len(data) < 80+count*8 {
+		return nil, fmt.Errorf("VTF 资源表不完整")
+	}
+	for i := 0; i < count; i++ {
+		entry := data[80+i*8:]
+		if entry[0] == 0x30 && entry[1] == 0 && entry[2] == 0 {
+			h.imageOffset = int64(binary.LittleEndian.Uint32(entry[4:]))
+			return h, nil
+		}
+	}
+	return nil, fmt.Errorf("VTF 缺少图像数据")
+}
+
+// vtfMipLevels 返回完整 mip 链的级别数
+func vtfMipLevels(width, height, depth int) int {
+	levels := 1
+	for width > 1 || height > 1 || depth > 1 {
+		width, height, depth = width>>1, height>>1, depth>>1
+		levels++
+	}
+	return levels
+}
+
+// DecodeVTF 将 VTF 第一帧、第一个面的指定 mip 级别解码为图片，0 为最大的级别
+func DecodeVTF(data []byte, mip int) (image.Image, error) {
+	h, err := ParseVTFHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	return decodeVTFImage(h, data, mip, 0)
+}
+
+// decodeVTFImage 解码指定 mip 级别和帧。图像数据按 mip 从小到大存储，每个 mip 内依次为帧、面、深度切片
+func decodeVTFImage(h *VTFHeader, data []byte, mip int, frame int) (image.Image, error) {
+	info, ok := vtfFormats[h.Format]
+	if !ok {
+		return nil, fmt.Errorf("不支持解码的 VTF 格式: %s", h.Format)
+	}
+	if mip < 0 || mip >= h.MipCount {
+		return nil, fmt.Errorf("mip 级别超出范围: %d", mip)
+	}
+	if frame < 0 || frame >= h.Frames {
+		return nil, fmt.Errorf("帧超出范围: %d", frame)
+	}
+
+	offset := h.imageOffset
+	for level := h.MipCount - 1; level > mip; level-- {
+		offset += h.mipBytes(level) * int64(h.Frames) * int64(h.Faces)
+	}
+	offset += h.mipBytes(mip) * int64(frame) * int64(h.Faces)
+
+	w, hh := h.MipSize(mip)
+	size := vtfImageSize(h.Format, w, hh)
+	if offset < 0 || offset+size > int64(len(data)) {
+		return nil, fmt.Errorf("VTF 图像数据不完整")
+	}
+	src := data[offset : offset+size]
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, hh))
+	if info.block {
+		decodeDXT(img, h.Format, src)
+		return img, nil
+	}
+	for y := 0; y < hh; y++ {
+		for x := 0; x < w; x++ {
+			p := src[(y*w+x)*info.bytes:]
+			c := info.pixel(p)
+			i := img.PixOffset(x, y)
+			img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+		}
+	}
+	return img, nil
+}
+
+// decodeDXT 解码 DXT1/DXT3/DXT5 块，超出图片边界的像素丢弃
+func decodeDXT(img *image.NRGBA, format VTFFormat, src []byte) {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	blockSize := vtfFormats[format].bytes
+	blocksX := (w + 3) / 4
+	var colors [4]color.NRGBA
+	var alphas [16]uint8
+
+	for by := 0; by < (h+3)/4; by++ {
+		for bx := 0; bx < blocksX; bx++ {
+			block := src[(by*blocksX+bx)*blockSize:]
+			colorBlock := block
+			switch format {
+			case VTFFormatDXT3:
+				for i := 0; i < 16; i++ {
+					alphas[i] = expand4(uint16(block[i/2] >> (4 * (i % 2))))
+				}
+				colorBlock = block[8:]
+			case VTFFormatDXT5:
+				dxt5Alphas(block, &alphas)
+				colorBlock = block[8:]
+			}
+			// DXT1 在 c0 <= c1 时使用三色加透明模式，DXT3/DXT5 总是四色
+			dxtColors(colorBlock, format == VTFFormatDXT1 || format == VTFFormatDXT1OneBitAlpha, &colors)
+
+			indices := binary.LittleEndian.Uint32(colorBlock[4:])
+			for py := 0; py < 4; py++ {
+				y := by*4 + py
+				if y >= h {
+					break
+				}
+				for px := 0; px < 4; px++ {
+					x := bx*4 + px
+					if x >= w {
+						break
+					}
+					n := py*4 + px
+					c := colors[indices>>(2*n)&3]
+					if format == VTFFormatDXT3 || format == VTFFormatDXT5 {
+						c.A = alphas[n]
+					}
+					i := img.PixOffset(x, y)
+					img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+				}
+			}
+		}
+	}
+}
+
+// dxtColors 计算颜色块的四个调色板颜色
+func dxtColors(block []byte, allowAlpha bool, colors *[4]color.NRGBA) {
+	v0 := binary.LittleEndian.Uint16(block)
+	v1 := binary.LittleEndian.Uint16(block[2:])
+	c0, c1 := rgb565(v0), rgb565(v1)
+	colors[0], colors[1] = c0, c1
+	if v0 > v1 || !allowAlpha {
+		colors[2] = mixNRGBA(c0, c1, 2, 1)
+		colors[3] = mixNRGBA(c0, c1, 1, 2)
+	} else {
+		colors[2] = mixNRGBA(c0, c1, 1, 1)
+		colors[3] = color.NRGBA{}
+	}
+}
+
+// dxt5Alphas 解码 DXT5 的插值 alpha 块
+func dxt5Alphas(block []byte, alphas *[16]uint8) {
+	a0, a1 := int(block[0]), int(block[1])
+	var palette [8]uint8
+	palette[0], palette[1] = uint8(a0), uint8(a1)
+	if a0 > a1 {
+		for i := 1; i < 7; i++ {
+			palette[i+1] = uint8(((7-i)*a0 + i*a1) / 7)
+		}
+	} else {
+		for i := 1; i < 5; i++ {
+			palette[i+1] = uint8(((5-i)*a0 + i*a1) / 5)
+		}
+		palette[6], palette[7] = 0, 0xff
+	}
+
+	var bits uint64
+	for i := 0; i < 6; i++ {
+		bits |= uint64(block[2+i]) << (8 * i)
+	}
+	for i := 0; i < 16; i++ {
+		alphas[i] = palette[bits>>(3*i)&7]
+	}
+}
+
+func mixNRGBA(a, b color.NRGBA, wa, wb int) color.NRGBA {
+	total := wa + wb
+	return color.NRGBA{
+		R: uint8((int(a.R)*wa + int(b.R)*wb) / total),
+		G: uint8((int(a.G)*wa + int(b.G)*wb) / total),
+		B: uint8((int(a.B)*wa + int(b.B)*wb) / total),
+		A: 0xff,
+	}
+}
+
+// rgb565 解码红色在高位的 16 位颜色
+func rgb565(v uint16) color.NRGBA {
+	return color.NRGBA{expand5(v >> 11), expand6(v >> 5), expand5(v), 0xff}
+}
+
+func expand4(v uint16) uint8 {
+	v &= 0xf
+	return uint8(v<<4 | v)
+}
+
+func expand5(v uint16) uint8 {
+	v &= 0x1f
+	return uint8(v<<3 | v>>2)
+}
+
+func expand6(v uint16) uint8 {
+	v &= 0x3f
+	return uint8(v<<2 | v>>4)
+}
+
+// VTFTextureInfo VPK中单个贴图的信息，用于贴图浏览
+type VTFTextureInfo struct {
+	Path       string `json:"path"`
+	Version    string `json:"version,omitempty"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	Depth      int    `json:"depth"`
+	Frames     int    `json:"frames"`
+	Faces      int    `json:"faces"`
+	MipCount   int    `json:"mipCount"`
+	Format     string `json:"format,omitempty"`
+	Flags      uint32 `json:"flags"`
+	FileSize   int64  `json:"fileSize"`
+	MemorySize int64  `json:"memorySize"` // 图像数据大小，包含所有 mip、帧和面
+	Decodable  bool   `json:"decodable"`  // 能否生成预览
+	PreviewURL string `json:"previewUrl"` // 预览地址（本地预览图服务），不能预览时为空
+	Error      string `json:"error,omitempty"`
+}
+
+// ListVTFTextures 读取VPK中所有 VTF 的头部信息，按路径排序
+func ListVTFTextures(opener *vpk.Opener, archive *vpk.Archive) []VTFTextureInfo {
+	return ListVTFTexturesInDir(opener, archive, "")
+}
+
+// ListVTFTexturesInDir 只列出 dir 目录（含子目录，不区分大小写）下的贴图，dir 为空时列出全部
+func ListVTFTexturesInDir(opener *vpk.Opener, archive *vpk.Archive, dir string) []VTFTextureInfo {
+	prefix := strings.ToLower(strings.Trim(dir, "/"))
+	if prefix != "" {
+		prefix += "/"
+	}
+	textures := make([]VTFTextureInfo, 0)
+	for i := range archive.Files {
+		file := &archive.Files[i]
+		if !strings.EqualFold(path.Ext(file.Name()), ".vtf") || !strings.HasPrefix(strings.ToLower(file.Name()), prefix) {
+			continue
+		}
+		info := VTFTextureInfo{Path: file.Name(), FileSize: int64(file.Size())}
+		header, err := readVTFFileHeader(opener, file)
+		if err != nil {
+			info.Error = err.Error()
+		} else {
+			info.Version = header.Version()
+			info.Width, info.Height, info.Depth = header.Width, header.Height, header.Depth
+			info.Frames, info.Faces, info.MipCount = header.Frames, header.Faces, header.MipCount
+			info.Format = header.Format.String()
+			info.Flags = header.Flags
+			info.MemorySize = header.ImageDataSize()
+			info.Decodable = header.Decodable()
+		}
+		textures = append(textures, info)
+	}
+	sort.Slice(textures, func(i, j int) bool { return textures[i].Path < textures[j].Path })
+	return textures
+}
+
+// readVTFFileHeader 只读取VPK内 VTF 的文件头。
+// 不调用 Close：Close 会读完整个文件校验 CRC，贴图列表、预览图和模型统计对每个贴图都会读取文件头；文件句柄由 opener 持有
+func readVTFFileHeader(opener *vpk.Opener, file *vpk.File) (*VTFHeader, error) {
+	reader, err := file.Open(opener)
+	if err != nil {
+		return nil, err
+	}
+	return ReadVTFHeader(reader)
+}
+
+// ReadVTFImage 读取VPK内的 VTF 并解码，选择最长边不小于 maxSize 的最小 mip 级别；maxSize 为 0 时解码完整大小
+func ReadVTFImage(vpkFilePath string, entry string, maxSize int) (image.Image, error) {
+	opener := vpk.Single(vpkFilePath)
+	defer opener.Close()
+
+	archive, err := opener.ReadArchive()
+	if err != nil {
+		return nil, err
+	}
+	file := findFileInArchive(archive, entry)
+	if file == nil {
+		return nil, fmt.Errorf("VPK 中未找到贴图: %s", entry)
+	}
+	reader, err := file.Open(opener)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := ParseVTFHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	mip := 0
+	if maxSize > 0 {
+		mip = header.MipForSize(maxSize)
+	}
+	return decodeVTFImage(header, data, mip, 0)
+}
@@ -0,0 +1,378 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// vtfEncodeHeaderSize 编码输出使用 7.2 版本头部，不带低清缩略图
+const vtfEncodeHeaderSize = 80
+
+// VTFEncodeOptions VTF 编码选项
+type VTFEncodeOptions struct {
+	Format  VTFFormat // 支持 DXT1、DXT5、BGRA8888、BGR888
+	Mipmaps bool      // 生成完整的 mip 链
+	Flags   uint32    // 额外写入的贴图标志，NoMip 由 Mipmaps 自动设置
+
+	// MipImages 按级别指定 mip 使用的图片，尺寸必须与该级别一致；nil 的级别由上一级缩小生成。
+	// 用于远近显示不同图案的渐变喷漆
+	MipImages []image.Image
+}
+
+// vtfEncodableFormats 可以编码输出的格式
+var vtfEncodableFormats = map[VTFFormat]bool{
+	VTFFormatDXT1:     true,
+	VTFFormatDXT5:     true,
+	VTFFormatBGRA8888: true,
+	VTFFormatBGR888:   true,
+}
+
+// VTFFileSize 返回按给定参数编码后的文件大小
+func VTFFileSize(width, height, frames int, format VTFFormat, mipmaps bool) int64 {
+	levels := 1
+	if mipmaps {
+		levels = vtfMipLevels(width, height, 1)
+	}
+	size := int64(vtfEncodeHeaderSize)
+	for level := 0; level < levels; level++ {
+		size += vtfImageSize(format, max(width>>level, 1), max(height>>level, 1)) * int64(frames)
+	}
+	return size
+}
+
+// EncodeVTF 将一帧或多帧同尺寸的图片编码为 VTF，多帧时按动画帧顺序写入
+func EncodeVTF(frames []image.Image, options VTFEncodeOptions) ([]byte, error) {
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("没有可编码的图像帧")
+	}
+	if len(frames) > 0xffff {
+		return nil, fmt.Errorf("帧数过多: %d", len(frames))
+	}
+	if !vtfEncodableFormats[options.Format] {
+		return nil, fmt.Errorf("不支持编码为 %s 格式", options.Format)
+	}
+	bounds := frames[0].Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= 0 || height <= 0 || width > vtfMaxDimension || height > vtfMaxDimension {
+		return nil, fmt.Errorf("图像尺寸异常: %dx%d", width, height)
+	}
+
+	levels := 1
+	flags := options.Flags &^ VTFFlagNoMip
+	if options.Mipmaps {
+		levels = vtfMipLevels(width, height, 1)
+	} else {
+		flags |= VTFFlagNoMip
+	}
+	if len(options.MipImages) > levels {
+		return nil, fmt.Errorf("指定的 mip 级别超出范围: %d", len(options.MipImages)-1)
+	}
+	mipImages := make([]*image.NRGBA, len(options.MipImages))
+	for level, img := range options.MipImages {
+		if img == nil || level == 0 {
+			continue
+		}
+		w, h := max(width>>level, 1), max(height>>level, 1)
+		if img.Bounds().Dx() != w || img.Bounds().Dy() != h {
+			return nil, fmt.Errorf("mip %d 的图片尺寸应为 %dx%d", level, w, h)
+		}
+		mipImages[level] = toNRGBA(img)
+	}
+
+	// chains[frame][level]
+	chains := make([][]*image.NRGBA, len(frames))
+	for i, frame := range frames {
+		if frame.Bounds().Dx() != width || frame.Bounds().Dy() != height {
+			return nil, fmt.Errorf("第 %d 帧尺寸与第一帧不一致", i+1)
+		}
+		chain := make([]*image.NRGBA, levels)
+		chain[0] = toNRGBA(frame)
+		for level := 1; level < levels; level++ {
+			if level < len(mipImages) && mipImages[level] != nil {
+				chain[level] = mipImages[level]
+			} else {
+				chain[level] = halveNRGBA(chain[level-1])
+			}
+		}
+		chains[i] = chain
+	}
+
+	var buf bytes.Buffer
+	buf.Write(vtfEncodeHeader(width, height, len(frames), levels, flags, options.Format, averageColor(chains[0][0])))
+	// 图像数据按 mip 从小到大存储，每个 mip 内依次为各帧
+	for level := levels - 1; level >= 0; level-- {
+		for _, chain := range chains {
+			buf.Write(encodeVTFImage(chain[level], options.Format))
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func vtfEncodeHeader(width, height, frames, levels int, flags uint32, format VTFFormat, reflectivity [3]float32) []byte {
+	header := make([]byte, vtfEncodeHeaderSize)
+	copy(header, "VTF\x00")
+	binary.LittleEndian.PutUint32(header[4:], 7)
+	binary.LittleEndian.PutUint32(header[8:], 2)
+	binary.LittleEndian.PutUint32(header[12:], vtfEncodeHeaderSize)
+	binary.LittleEndian.PutUint16(header[16:], uint16(width))
+	binary.LittleEndian.PutUint16(header[18:], uint16(height))
+	binary.LittleEndian.PutUint32(header[20:], flags)
+	binary.LittleEndian.PutUint16(header[24:], uint16(frames))
+	for i, v := range reflectivity {
+		binary.LittleEndian.PutUint32(header[32+i*4:], math.Float32bits(v))
+	}
+	binary.LittleEndian.PutUint32(header[48:], math.Float32bits(1))
+	binary.LittleEndian.PutUint32(header[52:], uint32(format))
+	header[56] = byte(levels)
+	// 不写低清缩略图：格式记为 DXT1、尺寸为 0，与常见喷漆工具一致
+	binary.LittleEndian.PutUint32(header[57:], uint32(VTFFormatDXT1))
+	binary.LittleEndian.PutUint16(header[63:], 1)
+	return header
+}
+
+func toNRGBA(src image.Image) *image.NRGBA {
+	if img, ok := src.(*image.NRGBA); ok && img.Rect.Min == (image.Point{}) {
+		return img
+	}
+	b := src.Bounds()
+	img := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(img, img.Rect, src, b.Min, draw.Src)
+	return img
+}
+
+// halveNRGBA 用 2x2 区域平均生成下一级 mip，按 alpha 加权避免透明边缘发黑
+func halveNRGBA(src *image.NRGBA) *image.NRGBA {
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	dw, dh := max(w/2, 1), max(h/2, 1)
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var r, g, b, a, n int
+			for sy := 2 * y; sy < min(2*y+2, h); sy++ {
+				for sx := 2 * x; sx < min(2*x+2, w); sx++ {
+					i := src.PixOffset(sx, sy)
+					pa := int(src.Pix[i+3])
+					r += int(src.Pix[i]) * pa
+					g += int(src.Pix[i+1]) * pa
+					b += int(src.Pix[i+2]) * pa
+					a += pa
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			if a > 0 {
+				dst.Pix[i], dst.Pix[i+1], dst.Pix[i+2] = uint8(r/a), uint8(g/a), uint8(b/a)
+			}
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// averageColor 计算反射率（0-1 的平均颜色），引擎用于辐射度计算
+func averageColor(img *image.NRGBA) [3]float32 {
+	var sum [3]float64
+	pixels := len(img.Pix) / 4
+	for i := 0; i < len(img.Pix); i += 4 {
+		for c := 0; c < 3; c++ {
+			sum[c] += float64(img.Pix[i+c]) / 255
+		}
+	}
+	var out [3]float32
+	for c := range out {
+		out[c] = float32(sum[c] / float64(max(pixels, 1)))
+	}
+	return out
+}
+
+func encodeVTFImage(img *image.NRGBA, format VTFFormat) []byte {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	switch format {
+	case VTFFormatBGRA8888, VTFFormatBGR888:
+		bpp := vtfFormats[format].bytes
+		out := make([]byte, 0, w*h*bpp)
+		for i := 0; i < len(img.Pix); i += 4 {
+			out = append(out, img.Pix[i+2], img.Pix[i+1], img.Pix[i])
+			if bpp == 4 {
+				out = append(out, img.Pix[i+3])
+			}
+		}
+		return out
+	default:
+		return encodeDXT(img, format)
+	}
+}
+
+// encodeDXT 压缩为 DXT1/DXT5。不足 4x4 的边缘块重复边缘像素
+func encodeDXT(img *image.NRGBA, format VTFFormat) []byte {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	out := make([]byte, 0, vtfImageSize(format, w, h))
+	var block [16]color.NRGBA
+	for by := 0; by < (h+3)/4; by++ {
+		for bx := 0; bx < (w+3)/4; bx++ {
+			for i := range block {
+				x := min(bx*4+i%4, w-1)
+				y := min(by*4+i/4, h-1)
+				p := img.Pix[img.PixOffset(x, y):]
+				block[i] = color.NRGBA{p[0], p[1], p[2], p[3]}
+			}
+			if format == VTFFormatDXT5 {
+				out = appendDXT5Alpha(out, &block)
+				out = appendDXTColor(out, &block, false)
+			} else {
+				out = appendDXTColor(out, &block, true)
+			}
+		}
+	}
+	return out
+}
+
+// appendDXTColor 压缩颜色块。端点取像素在主轴上投影的两端；
+// oneBitAlpha 时 alpha 小于 128 的像素使用三色模式的透明索引
+func appendDXTColor(out []byte, block *[16]color.NRGBA, oneBitAlpha bool) []byte {
+	var opaque [16]bool
+	transparent := false
+	var mean [3]float64
+	n := 0
+	for i, c := range block {
+		opaque[i] = !oneBitAlpha || c.A >= 128
+		if !opaque[i] {
+			transparent = true
+			continue
+		}
+		mean[0] += float64(c.R)
+		mean[1] += float64(c.G)
+		mean[2] += float64(c.B)
+		n++
+	}
+	if n == 0 {
+		// 全透明块：c0 <= c1 且全部使用索引 3
+		return append(out, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff)
+	}
+	for c := range mean {
+		mean[c] /= float64(n)
+	}
+
+	// 协方差矩阵，幂迭代求主轴
+	var cov [3][3]float64
+	for i, c := range block {
+		if !opaque[i] {
+			continue
+		}
+		d := [3]float64{float64(c.R) - mean[0], float64(c.G) - mean[1], float64(c.B) - mean[2]}
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				cov[j][k] += d[j] * d[k]
+			}
+		}
+	}
+	axis := [3]float64{1, 1, 1}
+	for iter := 0; iter < 8; iter++ {
+		var next [3]float64
+		for j := 0; j < 3; j++ {
+			next[j] = cov[j][0]*axis[0] + cov[j][1]*axis[1] + cov[j][2]*axis[2]
+		}
+		length := math.Sqrt(next[0]*next[0] + next[1]*next[1] + next[2]*next[2])
+		if length < 1e-9 {
+			break
+		}
+		for j := range axis {
+			axis[j] = next[j] / length
+		}
+	}
+
+	minP, maxP := math.Inf(1), math.Inf(-1)
+	for i, c := range block {
+		if !opaque[i] {
+			continue
+		}
+		p := (float64(c.R)-mean[0])*axis[0] + (float64(c.G)-mean[1])*axis[1] + (float64(c.B)-mean[2])*axis[2]
+		minP, maxP = math.Min(minP, p), math.Max(maxP, p)
+	}
+	e0 := quantize565(mean[0]+axis[0]*maxP, mean[1]+axis[1]*maxP, mean[2]+axis[2]*maxP)
+	e1 := quantize565(mean[0]+axis[0]*minP, mean[1]+axis[1]*minP, mean[2]+axis[2]*minP)
+
+	// 四色模式要求 c0 > c1，三色加透明模式要求 c0 <= c1
+	if transparent == (e0 > e1) {
+		e0, e1 = e1, e0
+	}
+	var palette [4]color.NRGBA
+	dxtColors([]byte{byte(e0), byte(e0 >> 8), byte(e1), byte(e1 >> 8)}, oneBitAlpha, &palette)
+	colorCount := 4
+	if e0 <= e1 {
+		colorCount = 3
+	}
+
+	var indices uint32
+	for i, c := range block {
+		index := 3
+		if opaque[i] {
+			index = nearestColor(c, palette[:colorCount])
+		}
+		indices |= uint32(index) << (2 * i)
+	}
+	out = binary.LittleEndian.AppendUint16(out, e0)
+	out = binary.LittleEndian.AppendUint16(out, e1)
+	return binary.LittleEndian.AppendUint32(out, indices)
+}
+
+// appendDXT5Alpha 压缩 DXT5 alpha 块，使用八级插值模式
+func appendDXT5Alpha(out []byte, block *[16]color.NRGBA) []byte {
+	lo, hi := uint8(255), uint8(0)
+	for _, c := range block {
+		lo, hi = min(lo, c.A), max(hi, c.A)
+	}
+	out = append(out, hi, lo)
+
+	// hi == lo 时只使用索引 0
+	palette := []uint8{hi}
+	if hi > lo {
+		palette = append(palette, lo)
+		for i := 1; i < 7; i++ {
+			palette = append(palette, uint8(((7-i)*int(hi)+i*int(lo))/7))
+		}
+	}
+
+	var bits uint64
+	for i, c := range block {
+		best, bestDiff := 0, 256
+		for j, a := range palette {
+			diff := int(c.A) - int(a)
+			if diff < 0 {
+				diff = -diff
+			}
+			if diff < bestDiff {
+				best, bestDiff = j, diff
+			}
+		}
+		bits |= uint64(best) << (3 * i)
+	}
+	for i := 0; i < 6; i++ {
+		out = append(out, byte(bits>>(8*i)))
+	}
+	return out
+}
+
+func quantize565(r, g, b float64) uint16 {
+	q := func(v float64, maxValue float64) uint16 {
+		return uint16(math.Round(math.Max(0, math.Min(255, v)) * maxValue / 255))
+	}
+	return q(r, 31)<<11 | q(g, 63)<<5 | q(b, 31)
+}
+
+func nearestColor(c color.NRGBA, palette []color.NRGBA) int {
+	best, bestDist := 0, math.MaxInt
+	for i, p := range palette {
+		dr, dg, db := int(c.R)-int(p.R), int(c.G)-int(p.G), int(c.B)-int(p.B)
+		dist := dr*dr + dg*dg + db*db
+		if dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return best
+}
@@ -0,0 +1,157 @@
+package parser
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func testGradient(width, height int, alpha func(x, y int) uint8) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x * 255 / width), uint8(y * 255 / height), 128, alpha(x, y)})
+		}
+	}
+	return img
+}
+
+func absDiff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
+
+func TestEncodeVTFRoundTrip(t *testing.T) {
+	opaque := func(x, y int) uint8 { return 255 }
+	cutout := func(x, y int) uint8 {
+		if x < 8 {
+			return 0
+		}
+		return 255
+	}
+	fade := func(x, y int) uint8 { return uint8(x * 255 / 31) }
+
+	cases := []struct {
+		format    VTFFormat
+		alpha     func(x, y int) uint8
+		tolerance int
+	}{
+		{VTFFormatBGRA8888, fade, 0},
+		{VTFFormatBGR888, opaque, 0},
+		{VTFFormatDXT1, cutout, 24},
+		{VTFFormatDXT5, fade, 24},
+	}
+	for _, tc := range cases {
+		src := testGradient(32, 16, tc.alpha)
+		data, err := EncodeVTF([]image.Image{src}, VTFEncodeOptions{Format: tc.format, Mipmaps: true, Flags: VTFFlagClampS})
+		if err != nil {
+			t.Fatalf("%s: %v", tc.format, err)
+		}
+		if int64(len(data)) != VTFFileSize(32, 16, 1, tc.format, true) {
+			t.Fatalf("%s: size %d does not match estimate %d", tc.format, len(data), VTFFileSize(32, 16, 1, tc.format, true))
+		}
+
+		header, err := ParseVTFHeader(data)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.format, err)
+		}
+		if header.MipCount != 6 || header.Format != tc.format || header.Flags&VTFFlagClampS == 0 || header.Flags&VTFFlagNoMip != 0 {
+			t.Fatalf("%s: unexpected header %+v", tc.format, header)
+		}
+
+		img, err := DecodeVTF(data, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.format, err)
+		}
+		for y := 0; y < 16; y++ {
+			for x := 0; x < 32; x++ {
+				want := src.NRGBAAt(x, y)
+				got := nrgbaAt(t, img, x, y)
+				if absDiff(want.A, got.A) > tc.tolerance {
+					t.Fatalf("%s: alpha at %d,%d: want %d got %d", tc.format, x, y, want.A, got.A)
+				}
+				if want.A < 128 && tc.format == VTFFormatDXT1 {
+					continue
+				}
+				if absDiff(want.R, got.R) > tc.tolerance || absDiff(want.G, got.G) > tc.tolerance || absDiff(want.B, got.B) > tc.tolerance {
+					t.Fatalf("%s: color at %d,%d: want %v got %v", tc.format, x, y, want, got)
+				}
+			}
+		}
+
+		// 最小的 mip 为 1x1
+		if img, err := DecodeVTF(data, 5); err != nil || img.Bounds().Dx() != 1 {
+			t.Fatalf("%s: smallest mip: %v", tc.format, err)
+		}
+	}
+}
+
+func TestEncodeVTFFramesAndValidation(t *testing.T) {
+	frames := []image.Image{
+		testGradient(8, 8, func(x, y int) uint8 { return 255 }),
+		image.NewNRGBA(image.Rect(0, 0, 8, 8)),
+	}
+	data, err := EncodeVTF(frames, VTFEncodeOptions{Format: VTFFormatBGRA8888})
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, err := ParseVTFHeader(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Frames != 2 || header.MipCount != 1 || header.Flags&VTFFlagNoMip == 0 {
+		t.Fatalf("unexpected header %+v", header)
+	}
+	second, err := decodeVTFImage(header, data, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nrgbaAt(t, second, 0, 0).A != 0 {
+		t.Fatal("expected second frame to be transparent")
+	}
+
+	if _, err := EncodeVTF(nil, VTFEncodeOptions{Format: VTFFormatDXT1}); err == nil {
+		t.Error("expected error without frames")
+	}
+	if _, err := EncodeVTF(frames[:1], VTFEncodeOptions{Format: VTFFormatRGB565}); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+	if _, err := EncodeVTF([]image.Image{frames[0], image.NewNRGBA(image.Rect(0, 0, 4, 4))}, VTFEncodeOptions{Format: VTFFormatDXT1}); err == nil {
+		t.Error("expected error for mismatched frame size")
+	}
+}
+
+func TestEncodeVTFMipImages(t *testing.T) {
+	near := testGradient(8, 8, func(x, y int) uint8 { return 255 })
+	far := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for i := range far.Pix {
+		far.Pix[i] = 255
+	}
+
+	data, err := EncodeVTF([]image.Image{near}, VTFEncodeOptions{Format: VTFFormatBGRA8888, Mipmaps: true, MipImages: []image.Image{nil, far}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// mip 1 使用指定图片，之后的级别由它缩小生成
+	for _, level := range []int{1, 2, 3} {
+		img, err := DecodeVTF(data, level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c := nrgbaAt(t, img, 0, 0); c != (color.NRGBA{255, 255, 255, 255}) {
+			t.Fatalf("mip %d: expected far image, got %v", level, c)
+		}
+	}
+	if img, _ := DecodeVTF(data, 0); nrgbaAt(t, img, 7, 7) != near.NRGBAAt(7, 7) {
+		t.Fatal("expected near image at mip 0")
+	}
+
+	if _, err := EncodeVTF([]image.Image{near}, VTFEncodeOptions{Format: VTFFormatBGRA8888, Mipmaps: true, MipImages: []image.Image{nil, near}}); err == nil {
+		t.Error("expected error for mip image with wrong size")
+	}
+	if _, err := EncodeVTF([]image.Image{near}, VTFEncodeOptions{Format: VTFFormatBGRA8888, MipImages: []image.Image{nil, far}}); err == nil {
+		t.Error("expected error for mip image without mipmaps")
+	}
+}
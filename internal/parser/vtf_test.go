@@ -0,0 +1,254 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"path/filepath"
+	"testing"
+
+	"l4d2-manager-next/pkg/valve/vpk"
+)
+
+// buildTestVTF 按版本生成 VTF，mips 从最大级别开始排列。7.3 起使用资源表，旧版本带 4x4 DXT1 低清图
+func buildTestVTF(minor int, format VTFFormat, width, height int, flags uint32, mips ...[]byte) []byte {
+	headerSize := 80
+	if minor < 2 {
+		headerSize = 64
+	}
+	if minor >= 3 {
+		headerSize = 80 + 8*2
+	}
+	header := make([]byte, headerSize)
+	copy(header, "VTF\x00")
+	binary.LittleEndian.PutUint32(header[4:], 7)
+	binary.LittleEndian.PutUint32(header[8:], uint32(minor))
+	binary.LittleEndian.PutUint32(header[12:], uint32(headerSize))
+	binary.LittleEndian.PutUint16(header[16:], uint16(width))
+	binary.LittleEndian.PutUint16(header[18:], uint16(height))
+	binary.LittleEndian.PutUint32(header[20:], flags)
+	binary.LittleEndian.PutUint16(header[24:], 1)
+	binary.LittleEndian.PutUint32(header[52:], uint32(format))
+	header[56] = byte(len(mips))
+	binary.LittleEndian.PutUint32(header[57:], uint32(VTFFormatDXT1))
+	header[61], header[62] = 4, 4
+	if minor >= 2 {
+		binary.LittleEndian.PutUint16(header[63:], 1)
+	}
+
+	lowRes := make([]byte, 8)
+	var buf bytes.Buffer
+	buf.Write(header)
+	if minor >= 3 {
+		binary.LittleEndian.PutUint32(buf.Bytes()[68:], 2)
+		entries := buf.Bytes()[80:]
+		entries[0] = 0x01
+		binary.LittleEndian.PutUint32(entries[4:], uint32(headerSize))
+		entries[8] = 0x30
+		binary.LittleEndian.PutUint32(entries[12:], uint32(headerSize+len(lowRes)))
+	}
+	buf.Write(lowRes)
+	for i := len(mips) - 1; i >= 0; i-- {
+		buf.Write(mips[i])
+	}
+	return buf.Bytes()
+}
+
+func nrgbaAt(t *testing.T, img image.Image, x, y int) color.NRGBA {
+	t.Helper()
+	return color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+}
+
+func TestDecodeVTFUncompressedMips(t *testing.T) {
+	mip0 := []byte{
+		0, 0, 255, 255, 0, 255, 0, 255, 255, 0, 0, 255, 10, 20, 30, 128,
+		1, 1, 1, 255, 2, 2, 2, 255, 3, 3, 3, 255, 4, 4, 4, 255,
+	}
+	mip1 := []byte{50, 60, 70, 255, 80, 90, 100, 255}
+	mip2 := []byte{7, 8, 9, 255}
+
+	for _, minor := range []int{0, 1, 2, 3, 4, 5} {
+		data := buildTestVTF(minor, VTFFormatBGRA8888, 4, 2, 0, mip0, mip1, mip2)
+		header, err := ParseVTFHeader(data)
+		if err != nil {
+			t.Fatalf("7.%d: %v", minor, err)
+		}
+		if header.Width != 4 || header.Height != 2 || header.MipCount != 3 || header.Version() != "7."+string(rune('0'+minor)) {
+			t.Fatalf("7.%d: unexpected header %+v", minor, header)
+		}
+		if got := header.ImageDataSize(); got != int64(len(mip0)+len(mip1)+len(mip2)) {
+			t.Fatalf("7.%d: expected image size %d, got %d", minor, len(mip0)+len(mip1)+len(mip2), got)
+		}
+
+		img, err := DecodeVTF(data, 0)
+		if err != nil {
+			t.Fatalf("7.%d: %v", minor, err)
+		}
+		if img.Bounds().Dx() != 4 || img.Bounds().Dy() != 2 {
+			t.Fatalf("7.%d: unexpected bounds %v", minor, img.Bounds())
+		}
+		if c := nrgbaAt(t, img, 0, 0); c != (color.NRGBA{255, 0, 0, 255}) {
+			t.Fatalf("7.%d: expected red, got %v", minor, c)
+		}
+		if c := nrgbaAt(t, img, 3, 0); c != (color.NRGBA{30, 20, 10, 128}) {
+			t.Fatalf("7.%d: expected swizzled BGRA, got %v", minor, c)
+		}
+
+		img, err = DecodeVTF(data, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if img.Bounds().Dx() != 2 || img.Bounds().Dy() != 1 || nrgbaAt(t, img, 1, 0) != (color.NRGBA{100, 90, 80, 255}) {
+			t.Fatalf("7.%d: unexpected mip 1: %v %v", minor, img.Bounds(), nrgbaAt(t, img, 1, 0))
+		}
+	}
+
+	header, _ := ParseVTFHeader(buildTestVTF(5, VTFFormatBGRA8888, 4, 2, 0, mip0, mip1, mip2))
+	for maxSize, want := range map[int]int{4: 0, 3: 0, 2: 1, 1: 2, 0: 2} {
+		if got := header.MipForSize(maxSize); got != want {
+			t.Errorf("MipForSize(%d) = %d, want %d", maxSize, got, want)
+		}
+	}
+	if _, err := DecodeVTF(buildTestVTF(5, VTFFormatBGRA8888, 4, 2, 0, mip0, mip1, mip2), 3); err == nil {
+		t.Fatal("expected error for mip out of range")
+	}
+}
+
+func TestDecodeVTFBlockCompressed(t *testing.T) {
+	// 红色 (c0) 和蓝色 (c1)，第一行依次使用索引 0,1,2,3
+	colorBlock := []byte{0x00, 0xf8, 0x1f, 0x00, 0xe4, 0, 0, 0}
+
+	img, err := DecodeVTF(buildTestVTF(5, VTFFormatDXT1, 4, 4, 0, colorBlock), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for x, want := range []color.NRGBA{{255, 0, 0, 255}, {0, 0, 255, 255}, {170, 0, 85, 255}, {85, 0, 170, 255}} {
+		if c := nrgbaAt(t, img, x, 0); c != want {
+			t.Errorf("DXT1 pixel %d: expected %v, got %v", x, want, c)
+		}
+	}
+
+	// c0 <= c1 时为三色加透明
+	transparent := []byte{0x1f, 0x00, 0x00, 0xf8, 0x03, 0, 0, 0}
+	img, err = DecodeVTF(buildTestVTF(5, VTFFormatDXT1OneBitAlpha, 4, 4, 0, transparent), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := nrgbaAt(t, img, 0, 0); c.A != 0 {
+		t.Errorf("expected transparent pixel, got %v", c)
+	}
+
+	dxt3 := append([]byte{0x0f, 0, 0, 0, 0, 0, 0, 0}, colorBlock...)
+	img, err = DecodeVTF(buildTestVTF(5, VTFFormatDXT3, 4, 4, 0, dxt3), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a0, a1 := nrgbaAt(t, img, 0, 0).A, nrgbaAt(t, img, 1, 0).A; a0 != 255 || a1 != 0 {
+		t.Errorf("DXT3 alpha: expected 255/0, got %d/%d", a0, a1)
+	}
+
+	// alpha 端点 255 和 0，第二个像素使用索引 1，第三个像素插值索引 2
+	dxt5 := append([]byte{255, 0, 0x88, 0, 0, 0, 0, 0}, colorBlock...)
+	img, err = DecodeVTF(buildTestVTF(5, VTFFormatDXT5, 4, 4, 0, dxt5), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a0, a1, a2 := nrgbaAt(t, img, 0, 0).A, nrgbaAt(t, img, 1, 0).A, nrgbaAt(t, img, 2, 0).A; a0 != 255 || a1 != 0 || a2 != 218 {
+		t.Errorf("DXT5 alpha: expected 255/0/218, got %d/%d/%d", a0, a1, a2)
+	}
+
+	// 小于 4x4 的 mip 仍占一个完整块
+	img, err = DecodeVTF(buildTestVTF(5, VTFFormatDXT1, 2, 2, 0, colorBlock), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 2 || nrgbaAt(t, img, 1, 0) != (color.NRGBA{0, 0, 255, 255}) {
+		t.Errorf("unexpected 2x2 DXT1 decode: %v", nrgbaAt(t, img, 1, 0))
+	}
+}
+
+func TestParseVTFHeaderRejectsInvalidFiles(t *testing.T) {
+	valid := buildTestVTF(5, VTFFormatBGR888, 1, 1, 0, []byte{1, 2, 3})
+
+	badMagic := append([]byte{}, valid...)
+	badMagic[0] = 'X'
+	badVersion := append([]byte{}, valid...)
+	badVersion[8] = 6
+	badSize := append([]byte{}, valid...)
+	binary.LittleEndian.PutUint16(badSize[16:], 0)
+	// 7.2 头部包含偏移 63 处的深度，64 字节不够
+	shortV72 := append([]byte{}, valid[:64]...)
+	binary.LittleEndian.PutUint32(shortV72[8:], 2)
+	binary.LittleEndian.PutUint32(shortV72[12:], 64)
+
+	for name, data := range map[string][]byte{
+		"magic":     badMagic,
+		"version":   badVersion,
+		"size":      badSize,
+		"truncated": valid[:40],
+		"7.2 depth": shortV72,
+	} {
+		if _, err := ParseVTFHeader(data); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	if _, err := DecodeVTF(valid[:len(valid)-1], 0); err == nil {
+		t.Error("expected error for truncated image data")
+	}
+	if _, err := ReadVTFHeader(bytes.NewReader(valid)); err != nil {
+		t.Errorf("ReadVTFHeader: %v", err)
+	}
+}
+
+func TestVTFTexturesAndFallbackPreview(t *testing.T) {
+	vpkPath := filepath.Join(t.TempDir(), "skin.vpk")
+	white := bytes.Repeat([]byte{255, 255, 255}, 4)
+	writeParserTestVPK(t, vpkPath, map[string][]byte{
+		"materials/models/weapons/rifle.vtf":        buildTestVTF(4, VTFFormatBGR888, 2, 2, 0, white, []byte{255, 255, 255}),
+		"materials/models/weapons/rifle_normal.vtf": buildTestVTF(4, VTFFormatBGR888, 4, 4, VTFFlagNormal, bytes.Repeat([]byte{128, 128, 255}, 16)),
+		"materials/models/weapons/broken.vtf":       []byte("not a vtf"),
+		"models/weapons/rifle.mdl":                  []byte("IDST"),
+	})
+
+	vpkFile, err := ParseVPKFile(vpkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpkFile.PreviewSource != "materials/models/weapons/rifle.vtf" {
+		t.Fatalf("expected color texture as preview source, got %q", vpkFile.PreviewSource)
+	}
+	data, err := ReadPreviewImage(vpkPath, vpkFile.PreviewSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("preview is not a png: %v", err)
+	}
+	if img.Bounds().Dx() != 2 || nrgbaAt(t, img, 0, 0) != (color.NRGBA{255, 255, 255, 255}) {
+		t.Fatalf("unexpected preview image %v", img.Bounds())
+	}
+
+	opener := vpk.Single(vpkPath)
+	defer opener.Close()
+	archive, err := opener.ReadArchive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	textures := ListVTFTextures(opener, archive)
+	if len(textures) != 3 || textures[0].Error == "" || textures[1].Format != "BGR888" || textures[1].MipCount != 2 ||
+		textures[1].MemorySize != 15 || !textures[1].Decodable || textures[2].Flags&VTFFlagNormal == 0 {
+		t.Fatalf("unexpected textures: %+v", textures)
+	}
+
+	img, err = ReadVTFImage(vpkPath, "MATERIALS/models/weapons/rifle.vtf", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 1 {
+		t.Fatalf("expected smallest mip for thumbnail, got %v", img.Bounds())
+	}
+}